@@ -0,0 +1,78 @@
+// Command address-merge normalizes stored addresses and merges the ones which
+// become the same, clients and suppliers are re-pointed to the kept address.
+// It is run once after db/migrations/006_address_normalization.sql and before
+// db/migrations/007_address_unique_normalized.sql.
+package main
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/config"
+	"CRUD-HOME-APPLIANCE-STORE/internal/database/connection"
+	repository "CRUD-HOME-APPLIANCE-STORE/internal/repositories"
+	"CRUD-HOME-APPLIANCE-STORE/internal/repositories/postgres"
+	"CRUD-HOME-APPLIANCE-STORE/internal/services"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report changes without applying them")
+	flag.Parse()
+
+	cfg := config.MustLoad()
+
+	log := logger.NewLogger(cfg.Env)
+	conn, err := connection.NewPostgresStorage(&cfg.PostgresConfig)
+	if err != nil {
+		log.Error("Error in connetion to postgres: ", logger.Err(err))
+		os.Exit(1)
+	}
+	defer conn.Close(context.Background())
+
+	unit := repository.NewUnitOfWork(conn, log)
+
+	generators := map[uow.RepositoryName]uow.RepositoryGenerator{
+		uow.AddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewAddressRepository(tx, log)
+		},
+		uow.ClientAddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewClientAddressRepository(tx, log)
+		},
+		uow.SupplierLocationRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewSupplierLocationRepository(tx, log)
+		},
+	}
+
+	for name, gen := range generators {
+		if err := unit.Register(name, gen); err != nil {
+			log.Error("Repository registration in uow is unable", "name", name, logger.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	var geocoder services.Geocoder
+	if cfg.AddressService.Geocoder == "local" {
+		geocoder = services.NewLocalGeocoder()
+	}
+
+	normalizer := services.NewAddressNormalizer(geocoder, log, services.DefaultAddressValidators()...)
+	merger := services.NewAddressMergeService(unit, normalizer, log)
+
+	report, err := merger.MergeDuplicates(context.Background(), *dryRun)
+	if err != nil {
+		log.Error("Address merge failed", logger.Err(err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("scanned: %d, updated: %d, merged: %d, invalid: %d, dry run: %t\n",
+		report.Scanned, report.Updated, report.Merged, len(report.Invalid), *dryRun)
+
+	for _, id := range report.Invalid {
+		fmt.Println("invalid address:", id)
+	}
+}
@@ -6,13 +6,20 @@ import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/consul"
 	"CRUD-HOME-APPLIANCE-STORE/internal/controllers"
 	"CRUD-HOME-APPLIANCE-STORE/internal/database/connection"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	repository "CRUD-HOME-APPLIANCE-STORE/internal/repositories"
 	"CRUD-HOME-APPLIANCE-STORE/internal/repositories/postgres"
 	"CRUD-HOME-APPLIANCE-STORE/internal/routes"
 	"CRUD-HOME-APPLIANCE-STORE/internal/services"
 	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -40,78 +47,386 @@ func main() {
 
 	log.Info("Connection is established")
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	go consul.RetryRegistration(cfg, log)
 
 	unit := repository.NewUnitOfWork(conn, log)
 
-	err = unit.Register("client", func(tx pgx.Tx, log *logger.Logger) uow.Repository {
-		return postgres.NewClientRepository(tx, log)
-	})
-	if err != nil {
-		log.Error("Client repository registration in uow is unable")
-		os.Exit(1)
-	}
-
-	err = unit.Register("address", func(tx pgx.Tx, log *logger.Logger) uow.Repository {
-		return postgres.NewAddressRepository(tx, log)
-	})
-	if err != nil {
-		log.Error("Address repository registration in uow is unable")
+	if err := registerRepositories(unit, log); err != nil {
 		os.Exit(1)
 	}
 
-	err = unit.Register("supplier", func(tx pgx.Tx, log *logger.Logger) uow.Repository {
-		return postgres.NewSupplierRepository(tx, log)
-	})
-	if err != nil {
-		log.Error("Supplier repository registration in uow is unable")
-		os.Exit(1)
+	var geocoder services.Geocoder
+	if cfg.AddressService.Geocoder == "local" {
+		geocoder = services.NewLocalGeocoder()
 	}
 
-	err = unit.Register("image", func(tx pgx.Tx, log *logger.Logger) uow.Repository {
-		return postgres.NewImageRepository(tx, log)
-	})
-	if err != nil {
-		log.Error("Image repository registration in uow is unable")
-		os.Exit(1)
-	}
-
-	err = unit.Register("product", func(tx pgx.Tx, log *logger.Logger) uow.Repository {
-		return postgres.NewProductRepository(tx, log)
-	})
-	if err != nil {
-		log.Error("Product repository registration in uow is unable")
-		os.Exit(1)
-	}
+	addressNormalizer := services.NewAddressNormalizer(geocoder, log, services.DefaultAddressValidators()...)
 
 	clientRepo := postgres.NewClientRepository(conn, log)
-	clientService := services.NewClientService(clientRepo, unit, log)
+	clientService := services.NewClientService(clientRepo, unit, addressNormalizer, log)
 	clientController := controllers.NewClientsController(clientService, log)
 
 	supplierRepo := postgres.NewSupplierRepository(conn, log)
-	supplierService := services.NewSupplierService(supplierRepo, unit, log)
+	supplierService := services.NewSupplierService(supplierRepo, unit, addressNormalizer, log)
 	supplierController := controllers.NewSupplierContoller(supplierService, log)
 
+	warehouseRepo := postgres.NewWarehouseRepository(conn, log)
+	warehouseService := services.NewWarehouseService(warehouseRepo, unit, addressNormalizer, log)
+	warehouseController := controllers.NewWarehouseController(warehouseService, log)
+
 	imageRepo := postgres.NewImageRepository(conn, log)
-	imageService := services.NewImageService(imageRepo, unit, log)
+	imageLimits := services.ImageLimits{
+		MaxWidth:  cfg.ImageService.MaxWidth,
+		MaxHeight: cfg.ImageService.MaxHeight,
+	}
+	imageService := services.NewImageService(imageRepo, unit, imageLimits, log)
 	imageController := controllers.NewImageController(imageService, log)
 
+	lowStockAlerts := services.NewLowStockAlerts(log)
+
 	productRepo := postgres.NewProductRepository(conn, log)
-	productService := services.NewProductService(productRepo, unit, log)
+	productService := services.NewProductService(productRepo, unit, lowStockAlerts, cfg.InventoryService.StockStrategy, log)
 	productController := controllers.NewProductController(productService, log)
 
+	cartRepo := postgres.NewCartRepository(conn, log)
+	cartService := services.NewCartService(cartRepo, unit, lowStockAlerts, cfg.CartService.TTL, cfg.InventoryService.StockStrategy, log)
+	cartController := controllers.NewCartController(cartService, log)
+
+	orderService := services.NewOrderService(postgres.NewOrderRepository(conn, log), log)
+	orderController := controllers.NewOrderController(orderService, log)
+
+	promotionService := services.NewPromotionService(postgres.NewPromotionRepository(conn, log), unit, log)
+	promotionController := controllers.NewPromotionController(promotionService, log)
+
+	inventoryRepo := postgres.NewInventoryRepository(conn, log)
+	inventoryService := services.NewInventoryService(inventoryRepo, log)
+	inventoryController := controllers.NewInventoryController(inventoryService, log)
+
+	categoryRepo := postgres.NewCategoryRepository(conn, log)
+	categoryService := services.NewCategoryService(categoryRepo, unit, log)
+	categoryController := controllers.NewCategoryController(categoryService, log)
+
+	addressRepo := postgres.NewAddressRepository(conn, log)
+	addressService := services.NewAddressService(addressRepo, log)
+	addressController := controllers.NewAddressController(addressService, log)
+
+	importService := services.NewImportService(unit, addressNormalizer, cfg.ImportService.BatchSize, log)
+	exportService := services.NewExportService(productRepo, supplierRepo, clientRepo, inventoryRepo, log)
+	transferController := controllers.NewTransferController(importService, exportService, cfg.ImportService.MaxBytes, log)
+
+	batchService := services.NewBatchService(unit, log)
+	batchController := controllers.NewBatchController(batchService, clientService, supplierService, productService, imageService, log)
+
+	idempotencyService := services.NewIdempotencyService(postgres.NewIdempotencyRepository(conn, log), cfg.IdempotencyService.TTL, cfg.IdempotencyService.Lease, log)
+	idempotencyMiddleware := controllers.NewIdempotencyMiddleware(idempotencyService, cfg.ImportService.MaxBytes, log)
+
+	// background tasks are waited for on shutdown like job workers
+	var background sync.WaitGroup
+
+	if cfg.AddressService.GCInterval > 0 {
+		gcConn, err := backgroundConn(cfg, "address collector", log)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		collector := services.NewAddressCollector(
+			postgres.NewAddressRepository(gcConn, log),
+			cfg.AddressService.GCInterval,
+			cfg.AddressService.GCBatchSize,
+			log,
+		)
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			collector.Run(ctx)
+		}()
+	}
+
+	if cfg.InventoryService.LowStockInterval > 0 {
+		lowStockConn, err := backgroundConn(cfg, "low stock evaluator", log)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		evaluator := services.NewLowStockEvaluator(
+			postgres.NewInventoryRepository(lowStockConn, log),
+			lowStockAlerts,
+			cfg.InventoryService.LowStockInterval,
+			log,
+		)
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			evaluator.Run(ctx)
+		}()
+	}
+
+	if cfg.IdempotencyService.CleanInterval > 0 {
+		idempotencyConn, err := backgroundConn(cfg, "idempotency cleaner", log)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		idempotencyCleaner := services.NewIdempotencyCleaner(
+			postgres.NewIdempotencyRepository(idempotencyConn, log),
+			cfg.IdempotencyService.CleanInterval,
+			log,
+		)
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			idempotencyCleaner.Run(ctx)
+		}()
+	}
+
+	if cfg.CartService.CleanInterval > 0 {
+		cartConn, err := backgroundConn(cfg, "cart cleaner", log)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		cartCleaner := services.NewCartCleaner(
+			postgres.NewCartRepository(cartConn, log),
+			cfg.CartService.CleanInterval,
+			log,
+		)
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			cartCleaner.Run(ctx)
+		}()
+	}
+
+	jobService := services.NewJobService(postgres.NewJobRepository(conn, log), log)
+	jobController := controllers.NewJobController(jobService, cfg.ImportService.MaxBytes, log)
+
+	if err := startJobWorkers(ctx, cfg, addressNormalizer, imageLimits, &background, log); err != nil {
+		os.Exit(1)
+	}
+
 	routerConfig := routes.RouterConfig{
-		ClientController:   clientController,
-		ProductController:  productController,
-		SupplierController: supplierController,
-		ImageController:    imageController,
+		ClientController:    clientController,
+		ProductController:   productController,
+		SupplierController:  supplierController,
+		CategoryController:  categoryController,
+		ImageController:     imageController,
+		AddressController:   addressController,
+		TransferController:  transferController,
+		JobController:       jobController,
+		BatchController:     batchController,
+		InventoryController: inventoryController,
+		WarehouseController: warehouseController,
+		CartController:      cartController,
+		OrderController:     orderController,
+		PromotionController: promotionController,
+
+		IdempotencyMiddleware: idempotencyMiddleware,
 	}
 
 	router := routes.NewRouter(routerConfig)
 	log.Info("The paths are laid")
 
-	log.Info("Server started")
-	if err := router.Run(":" + cfg.CrudService.Port); err != nil {
-		log.Error("Error in start server: ", logger.Err(err))
+	server := &http.Server{
+		Addr:    ":" + cfg.CrudService.Port,
+		Handler: router.Handler(),
+	}
+
+	go func() {
+		log.Info("Server started")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Error in start server: ", logger.Err(err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("Server is stopping")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.CrudService.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error("Error in stop server: ", logger.Err(err))
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		log.Info("Server stopped")
+	case <-shutdownCtx.Done():
+		log.Warn("Background workers are not stopped in time")
+	}
+}
+
+// registerRepositories registers repositories available in transactions.
+func registerRepositories(unit uow.UOW, log *logger.Logger) error {
+	generators := map[uow.RepositoryName]uow.RepositoryGenerator{
+		uow.ClientRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewClientRepository(tx, log)
+		},
+		uow.AddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewAddressRepository(tx, log)
+		},
+		uow.SupplierRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewSupplierRepository(tx, log)
+		},
+		uow.ImageRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewImageRepository(tx, log)
+		},
+		uow.ProductRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewProductRepository(tx, log)
+		},
+		uow.CategoryRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewCategoryRepository(tx, log)
+		},
+		uow.WarehouseRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewWarehouseRepository(tx, log)
+		},
+		uow.CartRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewCartRepository(tx, log)
+		},
+		uow.OrderRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewOrderRepository(tx, log)
+		},
+		uow.PromotionRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewPromotionRepository(tx, log)
+		},
+		uow.ProductImageRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewProductImageRepository(tx, log)
+		},
+		uow.ClientAddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewClientAddressRepository(tx, log)
+		},
+		uow.SupplierLocationRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewSupplierLocationRepository(tx, log)
+		},
+	}
+
+	for name, gen := range generators {
+		if err := unit.Register(name, gen); err != nil {
+			log.Error("Repository registration in uow is unable", "name", name, logger.Err(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backgroundConn opens the connection of a background task. Every task gets its
+// own one, pgx.Conn is not safe for concurrent use.
+func backgroundConn(cfg *config.Config, task string, log *logger.Logger) (*pgx.Conn, error) {
+	conn, err := connection.NewPostgresStorage(&cfg.PostgresConfig)
+	if err != nil {
+		log.Error("Error in connetion to postgres for "+task+": ", logger.Err(err))
+	}
+
+	return conn, err
+}
+
+// startJobWorkers starts job workers and the job cleaner, they stop when the
+// context is done. Every worker gets a connection for the queue and another
+// one for the work, pgx.Conn is not safe for concurrent use.
+func startJobWorkers(ctx context.Context, cfg *config.Config, normalizer *services.AddressNormalizer, imageLimits services.ImageLimits, wg *sync.WaitGroup, log *logger.Logger) error {
+	if cfg.JobService.Workers <= 0 {
+		return nil
+	}
+
+	connect := func() (*pgx.Conn, error) {
+		conn, err := connection.NewPostgresStorage(&cfg.PostgresConfig)
+		if err != nil {
+			log.Error("Error in connetion to postgres for job workers: ", logger.Err(err))
+		}
+
+		return conn, err
+	}
+
+	workerCfg := services.JobWorkerConfig{
+		Lease:        cfg.JobService.Lease,
+		PollInterval: cfg.JobService.PollInterval,
+		MaxAttempts:  cfg.JobService.MaxAttempts,
+	}
+
+	for i := 0; i < cfg.JobService.Workers; i++ {
+		queueConn, err := connect()
+		if err != nil {
+			return err
+		}
+
+		workConn, err := connect()
+		if err != nil {
+			return err
+		}
+
+		unit := repository.NewUnitOfWork(workConn, log)
+		if err := registerRepositories(unit, log); err != nil {
+			return err
+		}
+
+		handlers := services.JobHandlers{
+			domain.JobImport: services.NewImportJobHandler(
+				services.NewImportService(unit, normalizer, cfg.ImportService.BatchSize, log),
+			),
+			domain.JobExport: services.NewExportJobHandler(services.NewExportService(
+				postgres.NewProductRepository(workConn, log),
+				postgres.NewSupplierRepository(workConn, log),
+				postgres.NewClientRepository(workConn, log),
+				postgres.NewInventoryRepository(workConn, log),
+				log,
+			)),
+			domain.JobAddressPurge: services.NewAddressPurgeJobHandler(services.NewAddressCollector(
+				postgres.NewAddressRepository(workConn, log),
+				cfg.AddressService.GCInterval,
+				cfg.AddressService.GCBatchSize,
+				log,
+			)),
+			domain.JobImageReprocess: services.NewImageReprocessJobHandler(
+				postgres.NewImageRepository(workConn, log),
+				imageLimits,
+			),
+		}
+
+		worker := services.NewJobWorker(postgres.NewJobRepository(queueConn, log), handlers, workerCfg, log)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run(ctx)
+		}()
 	}
+
+	if cfg.JobService.CleanInterval > 0 {
+		cleanerConn, err := connect()
+		if err != nil {
+			return err
+		}
+
+		cleaner := services.NewJobCleaner(
+			postgres.NewJobRepository(cleanerConn, log),
+			cfg.JobService.CleanInterval,
+			cfg.JobService.Retention,
+			cfg.JobService.MaxAttempts,
+			log,
+		)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cleaner.Run(ctx)
+		}()
+	}
+
+	log.Info("Job workers are started", "workers", cfg.JobService.Workers)
+	return nil
 }
@@ -1,11 +1,24 @@
 CREATE EXTENSION IF NOT EXISTS pgcrypto;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
 
 CREATE TABLE IF NOT EXISTS address (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     country TEXT NOT NULL,
     city TEXT NOT NULL,
     street TEXT NOT NULL,
-    UNIQUE(country, city, street)
+    postal_code TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    building TEXT NOT NULL DEFAULT '',
+    apartment TEXT NOT NULL DEFAULT '',
+    line1 TEXT NOT NULL DEFAULT '',
+    line2 TEXT NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS address_unique_location ON address (
+    country, lower(region), lower(city), lower(postal_code), lower(street),
+    lower(building), lower(apartment), lower(line1), lower(line2)
 );
 
 CREATE TABLE IF NOT EXISTS client (
@@ -14,38 +27,313 @@ CREATE TABLE IF NOT EXISTS client (
     surname TEXT NOT NULL,
     birthday TIMESTAMP,
     gender TEXT CHECK (gender IN ('male', 'female')) NOT NULL,
+    email TEXT UNIQUE,
+    phone TEXT UNIQUE,
     registration_date TIMESTAMP DEFAULT now(),
-    address_id UUID NULL,
+    version BIGINT NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS client_full_name_trgm
+ON client USING GIN ((name || ' ' || surname) gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS client_address (
+    client_id UUID NOT NULL,
+    address_id UUID NOT NULL,
+    label TEXT CHECK (label IN ('billing', 'shipping', 'home')) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    version BIGINT NOT NULL DEFAULT 1,
+    PRIMARY KEY (client_id, address_id),
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE,
     FOREIGN KEY (address_id) REFERENCES address (id)
 );
 
+CREATE UNIQUE INDEX IF NOT EXISTS client_address_one_default
+ON client_address (client_id) WHERE is_default;
+
 CREATE TABLE IF NOT EXISTS image (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     title TEXT NOT NULL,
-    data BYTEA NOT NULL
+    data BYTEA NOT NULL,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    format TEXT NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    dominant_color TEXT NOT NULL DEFAULT '',
+    version BIGINT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE IF NOT EXISTS supplier (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name TEXT NOT NULL,
-    address_id UUID NOT NULL,
     phone_number TEXT NOT NULL,
-    FOREIGN KEY (address_id) REFERENCES address (id),
+    contact_person TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT '',
+    tax_id TEXT NOT NULL DEFAULT '',
+    version BIGINT NOT NULL DEFAULT 1,
     UNIQUE(name)
 );
 
+CREATE TABLE IF NOT EXISTS supplier_location (
+    supplier_id UUID NOT NULL,
+    address_id UUID NOT NULL,
+    label TEXT CHECK (label IN ('warehouse', 'office')) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    version BIGINT NOT NULL DEFAULT 1,
+    PRIMARY KEY (supplier_id, address_id),
+    FOREIGN KEY (supplier_id) REFERENCES supplier (id) ON DELETE CASCADE,
+    FOREIGN KEY (address_id) REFERENCES address (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS supplier_location_one_default
+ON supplier_location (supplier_id) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS category (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    parent_id UUID,
+    position INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (parent_id) REFERENCES category (id),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS category_parent ON category (parent_id, position);
+
+CREATE TABLE IF NOT EXISTS category_attribute (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('string', 'number', 'integer', 'boolean', 'enum')),
+    unit TEXT NOT NULL DEFAULT '',
+    options TEXT[] NOT NULL DEFAULT '{}',
+    min_value NUMERIC,
+    max_value NUMERIC,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (category_id, key),
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS product (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name TEXT NOT NULL,
-    category TEXT NOT NULL,
+    category_id UUID NOT NULL,
     price FLOAT NOT NULL,
     available_stock INT NOT NULL,
     last_update_date TIMESTAMP DEFAULT now(),
+    last_delivery_date TIMESTAMP,
     supplier_id UUID NOT NULL,
-    image_id UUID NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(attributes) = 'object'),
+    parent_id UUID,
+    sku TEXT,
+    barcode TEXT CHECK (barcode ~ '^[0-9]{13}$'),
+    reorder_threshold INT CHECK (reorder_threshold >= 0),
+    reorder_target INT,
+    low_stock_since TIMESTAMP,
+    version BIGINT NOT NULL DEFAULT 1,
     FOREIGN KEY (supplier_id) REFERENCES supplier (id),
-    FOREIGN KEY (image_id) REFERENCES image (id)
+    FOREIGN KEY (category_id) REFERENCES category (id),
+    FOREIGN KEY (parent_id) REFERENCES product (id) ON DELETE CASCADE,
+    CONSTRAINT product_not_own_parent CHECK (parent_id <> id),
+    CONSTRAINT product_reorder_target CHECK (
+        (reorder_threshold IS NULL) = (reorder_target IS NULL) AND reorder_target > reorder_threshold
+    )
+);
+
+CREATE INDEX IF NOT EXISTS product_supplier ON product (supplier_id);
+CREATE INDEX IF NOT EXISTS product_category ON product (category_id);
+CREATE INDEX IF NOT EXISTS product_attributes ON product USING GIN (attributes jsonb_path_ops);
+CREATE UNIQUE INDEX IF NOT EXISTS product_sku ON product (sku);
+CREATE UNIQUE INDEX IF NOT EXISTS product_barcode ON product (barcode);
+CREATE INDEX IF NOT EXISTS product_parent ON product (parent_id);
+CREATE INDEX IF NOT EXISTS product_low_stock ON product (supplier_id)
+WHERE available_stock <= reorder_threshold;
+
+CREATE TABLE IF NOT EXISTS product_image (
+    product_id UUID NOT NULL,
+    image_id UUID NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (product_id, image_id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (image_id) REFERENCES image (id),
+    CONSTRAINT product_image_position UNIQUE (product_id, position) DEFERRABLE INITIALLY IMMEDIATE
 );
 
+CREATE UNIQUE INDEX IF NOT EXISTS product_image_one_primary
+ON product_image (product_id) WHERE is_primary;
+
 ALTER TABLE product
-ADD CONSTRAINT product_stock_nonnegative CHECK (available_stock >= 0);
\ No newline at end of file
+ADD CONSTRAINT product_stock_nonnegative CHECK (available_stock >= 0);
+
+CREATE TABLE IF NOT EXISTS warehouse (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL UNIQUE,
+    address_id UUID NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (address_id) REFERENCES address (id)
+);
+
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    PRIMARY KEY (warehouse_id, product_id),
+    FOREIGN KEY (warehouse_id) REFERENCES warehouse (id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS warehouse_stock_product ON warehouse_stock (product_id);
+
+CREATE TABLE IF NOT EXISTS stock_movement (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    operation VARCHAR(20) NOT NULL,
+    quantity BIGINT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    stock_before BIGINT NOT NULL,
+    stock_after BIGINT NOT NULL,
+    warehouse_id UUID,
+    to_warehouse_id UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouse (id) ON DELETE SET NULL,
+    FOREIGN KEY (to_warehouse_id) REFERENCES warehouse (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS stock_movement_product ON stock_movement (product_id, created_at);
+
+CREATE TABLE IF NOT EXISTS cart (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id UUID UNIQUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cart_expires ON cart (expires_at);
+
+CREATE TABLE IF NOT EXISTS cart_item (
+    cart_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (cart_id, product_id),
+    FOREIGN KEY (cart_id) REFERENCES cart (id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS promotion (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+    value FLOAT NOT NULL CHECK (value > 0),
+    product_id UUID,
+    category_id UUID,
+    supplier_id UUID,
+    min_quantity INT NOT NULL DEFAULT 1 CHECK (min_quantity > 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    usage_limit INT CHECK (usage_limit > 0),
+    per_client_limit INT CHECK (per_client_limit > 0),
+    coupon_code TEXT UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE,
+    FOREIGN KEY (supplier_id) REFERENCES supplier (id) ON DELETE CASCADE,
+    CHECK (kind <> 'percentage' OR value <= 100),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE INDEX IF NOT EXISTS promotion_automatic ON promotion (ends_at)
+    WHERE coupon_code IS NULL AND active;
+
+CREATE TABLE IF NOT EXISTS client_order (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id UUID,
+    subtotal FLOAT NOT NULL,
+    discount FLOAT NOT NULL DEFAULT 0,
+    total FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS client_order_client ON client_order (client_id, created_at);
+
+CREATE TABLE IF NOT EXISTS order_item (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    product_id UUID,
+    name TEXT NOT NULL,
+    sku TEXT,
+    price FLOAT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    warehouse_id UUID,
+    discount FLOAT NOT NULL DEFAULT 0,
+    promotion_id UUID,
+    FOREIGN KEY (order_id) REFERENCES client_order (id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE SET NULL,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouse (id) ON DELETE SET NULL,
+    FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_item_order ON order_item (order_id);
+
+CREATE TABLE IF NOT EXISTS promotion_redemption (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id UUID NOT NULL,
+    client_id UUID,
+    order_id UUID NOT NULL,
+    discount FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (promotion_id, order_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE CASCADE,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL,
+    FOREIGN KEY (order_id) REFERENCES client_order (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS promotion_redemption_client ON promotion_redemption (promotion_id, client_id);
+
+CREATE TABLE IF NOT EXISTS job (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    params JSONB NOT NULL DEFAULT '{}',
+    input BYTEA,
+    progress BIGINT NOT NULL DEFAULT 0,
+    result JSONB,
+    output BYTEA,
+    output_type TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS job_pending ON job (created_at) WHERE status IN ('queued', 'running');
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint CHAR(64) NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    response_status INT,
+    response_type TEXT NOT NULL DEFAULT '',
+    response_body BYTEA,
+    response_etag TEXT NOT NULL DEFAULT '',
+    response_location TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_key_expires ON idempotency_key (expires_at);
@@ -0,0 +1,23 @@
+-- Moves the single product.image_id reference into the product_image gallery table.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS product_image (
+    product_id UUID NOT NULL,
+    image_id UUID NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (product_id, image_id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (image_id) REFERENCES image (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_image_one_primary
+ON product_image (product_id) WHERE is_primary;
+
+INSERT INTO product_image (product_id, image_id, position, is_primary)
+SELECT id, image_id, 0, TRUE FROM product WHERE image_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE product DROP COLUMN IF EXISTS image_id;
+
+COMMIT;
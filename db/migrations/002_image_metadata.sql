@@ -0,0 +1,13 @@
+-- Adds extracted image metadata. Existing rows keep zero values until the image is updated.
+BEGIN;
+
+ALTER TABLE image
+    ADD COLUMN IF NOT EXISTS width INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS height INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS format TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS dominant_color TEXT NOT NULL DEFAULT '';
+
+UPDATE image SET size = octet_length(data) WHERE size = 0;
+
+COMMIT;
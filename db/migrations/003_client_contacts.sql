@@ -0,0 +1,8 @@
+-- Adds unique client contacts.
+BEGIN;
+
+ALTER TABLE client
+    ADD COLUMN IF NOT EXISTS email TEXT UNIQUE,
+    ADD COLUMN IF NOT EXISTS phone TEXT UNIQUE;
+
+COMMIT;
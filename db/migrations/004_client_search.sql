@@ -0,0 +1,9 @@
+-- Enables fuzzy client search by name and surname.
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS client_full_name_trgm
+ON client USING GIN ((name || ' ' || surname) gin_trgm_ops);
+
+COMMIT;
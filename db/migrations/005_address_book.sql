@@ -0,0 +1,53 @@
+-- Extends addresses and moves client.address_id and supplier.address_id into address books.
+BEGIN;
+
+ALTER TABLE address
+    ADD COLUMN IF NOT EXISTS postal_code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS building TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS apartment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS line1 TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS line2 TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE address DROP CONSTRAINT IF EXISTS address_country_city_street_key;
+ALTER TABLE address ADD CONSTRAINT address_unique_location
+    UNIQUE(country, region, city, postal_code, street, building, apartment, line1, line2);
+
+CREATE TABLE IF NOT EXISTS client_address (
+    client_id UUID NOT NULL,
+    address_id UUID NOT NULL,
+    label TEXT CHECK (label IN ('billing', 'shipping', 'home')) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (client_id, address_id),
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE,
+    FOREIGN KEY (address_id) REFERENCES address (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS client_address_one_default
+ON client_address (client_id) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS supplier_location (
+    supplier_id UUID NOT NULL,
+    address_id UUID NOT NULL,
+    label TEXT CHECK (label IN ('warehouse', 'office')) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (supplier_id, address_id),
+    FOREIGN KEY (supplier_id) REFERENCES supplier (id) ON DELETE CASCADE,
+    FOREIGN KEY (address_id) REFERENCES address (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS supplier_location_one_default
+ON supplier_location (supplier_id) WHERE is_default;
+
+INSERT INTO client_address (client_id, address_id, label, is_default)
+SELECT id, address_id, 'home', TRUE FROM client WHERE address_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO supplier_location (supplier_id, address_id, label, is_default)
+SELECT id, address_id, 'office', TRUE FROM supplier WHERE address_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE client DROP COLUMN IF EXISTS address_id;
+ALTER TABLE supplier DROP COLUMN IF EXISTS address_id;
+
+COMMIT;
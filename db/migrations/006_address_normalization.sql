@@ -0,0 +1,10 @@
+-- Adds address coordinates and drops the exact text uniqueness, run cmd/address-merge next.
+BEGIN;
+
+ALTER TABLE address
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+ALTER TABLE address DROP CONSTRAINT IF EXISTS address_unique_location;
+
+COMMIT;
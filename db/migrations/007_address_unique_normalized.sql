@@ -0,0 +1,33 @@
+-- Deduplicates addresses on the normalized form, applied after cmd/address-merge.
+-- Duplicates left by a skipped or failed merge abort the migration with a
+-- message naming them instead of a bare unique violation.
+BEGIN;
+
+DO $$
+DECLARE
+    duplicates BIGINT;
+    sample TEXT;
+BEGIN
+    SELECT COUNT(*), string_agg(ids, '; ') FILTER (WHERE n <= 5)
+    INTO duplicates, sample
+    FROM (
+        SELECT string_agg(id::TEXT, ', ') AS ids, row_number() OVER () AS n
+        FROM address
+        GROUP BY country, lower(region), lower(city), lower(postal_code), lower(street),
+            lower(building), lower(apartment), lower(line1), lower(line2)
+        HAVING COUNT(*) > 1
+    ) d;
+
+    IF duplicates > 0 THEN
+        RAISE EXCEPTION '% groups of duplicate addresses remain, run cmd/address-merge before this migration', duplicates
+            USING DETAIL = 'duplicate address ids: ' || sample;
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS address_unique_location ON address (
+    country, lower(region), lower(city), lower(postal_code), lower(street),
+    lower(building), lower(apartment), lower(line1), lower(line2)
+);
+
+COMMIT;
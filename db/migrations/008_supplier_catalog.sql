@@ -0,0 +1,9 @@
+-- Tracks product deliveries and indexes products by supplier for the supplier catalog.
+BEGIN;
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS last_delivery_date TIMESTAMP DEFAULT now();
+UPDATE product SET last_delivery_date = last_update_date;
+
+CREATE INDEX IF NOT EXISTS product_supplier ON product (supplier_id);
+
+COMMIT;
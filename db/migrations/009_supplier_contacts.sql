@@ -0,0 +1,17 @@
+-- Adds supplier contacts and converts international phone numbers to E.164.
+BEGIN;
+
+ALTER TABLE supplier ADD COLUMN IF NOT EXISTS contact_person TEXT NOT NULL DEFAULT '';
+ALTER TABLE supplier ADD COLUMN IF NOT EXISTS email TEXT NOT NULL DEFAULT '';
+ALTER TABLE supplier ADD COLUMN IF NOT EXISTS website TEXT NOT NULL DEFAULT '';
+ALTER TABLE supplier ADD COLUMN IF NOT EXISTS tax_id TEXT NOT NULL DEFAULT '';
+
+UPDATE supplier
+SET phone_number = '+' || regexp_replace(phone_number, '[^0-9]', '', 'g')
+WHERE phone_number ~ '^\s*\+';
+
+UPDATE supplier
+SET phone_number = '+' || regexp_replace(regexp_replace(phone_number, '^\s*00', ''), '[^0-9]', '', 'g')
+WHERE phone_number ~ '^\s*00';
+
+COMMIT;
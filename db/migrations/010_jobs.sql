@@ -0,0 +1,25 @@
+-- Adds the queue of background jobs.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS job (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    params JSONB NOT NULL DEFAULT '{}',
+    input BYTEA,
+    progress BIGINT NOT NULL DEFAULT 0,
+    result JSONB,
+    output BYTEA,
+    output_type TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS job_pending ON job (created_at) WHERE status IN ('queued', 'running');
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Adds stored responses of requests with the Idempotency-Key header.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint CHAR(64) NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    response_status INT,
+    response_type TEXT NOT NULL DEFAULT '',
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_key_expires ON idempotency_key (expires_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Adds versions for optimistic concurrency control, every update increments
+-- the version and a request with If-Match is rejected when it differs.
+BEGIN;
+
+ALTER TABLE client ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE supplier ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE image ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
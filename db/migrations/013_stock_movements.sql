@@ -0,0 +1,20 @@
+-- Adds the log of stock changes, every decrease, increase or set of the
+-- product stock is recorded with its reason.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS stock_movement (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    operation VARCHAR(20) NOT NULL,
+    quantity BIGINT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    stock_before BIGINT NOT NULL,
+    stock_after BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS stock_movement_product ON stock_movement (product_id, created_at);
+
+COMMIT;
@@ -0,0 +1,51 @@
+-- Moves product categories to the category table. Every distinct category
+-- string becomes a top level category, strings with the same slug ("Fridge",
+-- " fridge ") are merged into one category named by the most used spelling.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS category (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    parent_id UUID,
+    position INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (parent_id) REFERENCES category (id),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS category_parent ON category (parent_id, position);
+
+CREATE TEMPORARY TABLE category_spelling ON COMMIT DROP AS
+SELECT
+    trim(category) AS name,
+    COALESCE(
+        NULLIF(trim(BOTH '-' FROM regexp_replace(lower(trim(category)), '[^[:alnum:]]+', '-', 'g')), ''),
+        'category-' || substr(md5(category), 1, 8)
+    ) AS slug,
+    COUNT(*) AS used
+FROM product
+GROUP BY category;
+
+INSERT INTO category (name, slug, position)
+SELECT DISTINCT ON (slug) name, slug, 0
+FROM category_spelling
+ORDER BY slug, used DESC, name
+ON CONFLICT (slug) DO NOTHING;
+
+UPDATE category c SET position = ordered.position
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY name) - 1 AS position FROM category WHERE parent_id IS NULL) ordered
+WHERE c.id = ordered.id;
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES category (id);
+
+UPDATE product p SET category_id = c.id
+FROM category_spelling s
+JOIN category c ON c.slug = s.slug
+WHERE s.name = trim(p.category);
+
+ALTER TABLE product ALTER COLUMN category_id SET NOT NULL;
+ALTER TABLE product DROP COLUMN category;
+
+CREATE INDEX IF NOT EXISTS product_category ON product (category_id);
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Typed attribute definitions of categories and attribute values of products.
+-- A product is described by the attributes of its category and of the
+-- category ancestors.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS category_attribute (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('string', 'number', 'integer', 'boolean', 'enum')),
+    unit TEXT NOT NULL DEFAULT '',
+    options TEXT[] NOT NULL DEFAULT '{}',
+    min_value NUMERIC,
+    max_value NUMERIC,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (category_id, key),
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE
+);
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'
+    CHECK (jsonb_typeof(attributes) = 'object');
+
+CREATE INDEX IF NOT EXISTS product_attributes ON product USING GIN (attributes jsonb_path_ops);
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Adds variants of products: a variant is a product with a parent, SKUs and
+-- EAN-13 barcodes identify products and variants.
+BEGIN;
+
+ALTER TABLE product
+    ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES product (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS sku TEXT,
+    ADD COLUMN IF NOT EXISTS barcode TEXT CHECK (barcode ~ '^[0-9]{13}$');
+
+ALTER TABLE product ADD CONSTRAINT product_not_own_parent CHECK (parent_id <> id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_sku ON product (sku);
+CREATE UNIQUE INDEX IF NOT EXISTS product_barcode ON product (barcode);
+CREATE INDEX IF NOT EXISTS product_parent ON product (parent_id);
+
+COMMIT;
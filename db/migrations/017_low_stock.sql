@@ -0,0 +1,17 @@
+-- Adds reorder thresholds of products: a product with the stock at or below
+-- its threshold is low on stock and is reordered up to its target.
+BEGIN;
+
+ALTER TABLE product
+    ADD COLUMN IF NOT EXISTS reorder_threshold INT CHECK (reorder_threshold >= 0),
+    ADD COLUMN IF NOT EXISTS reorder_target INT,
+    ADD COLUMN IF NOT EXISTS low_stock_since TIMESTAMP;
+
+ALTER TABLE product ADD CONSTRAINT product_reorder_target CHECK (
+    (reorder_threshold IS NULL) = (reorder_target IS NULL) AND reorder_target > reorder_threshold
+);
+
+CREATE INDEX IF NOT EXISTS product_low_stock ON product (supplier_id)
+WHERE available_stock <= reorder_threshold;
+
+COMMIT;
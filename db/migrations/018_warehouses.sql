@@ -0,0 +1,30 @@
+-- Adds warehouses and the stock of products per warehouse. The product stock
+-- is the sum of its warehouse stocks once it is stocked in a warehouse, stock
+-- movements record the warehouse they change and transfers between warehouses.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS warehouse (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL UNIQUE,
+    address_id UUID NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (address_id) REFERENCES address (id)
+);
+
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    PRIMARY KEY (warehouse_id, product_id),
+    FOREIGN KEY (warehouse_id) REFERENCES warehouse (id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS warehouse_stock_product ON warehouse_stock (product_id);
+
+ALTER TABLE stock_movement
+    ADD COLUMN IF NOT EXISTS warehouse_id UUID REFERENCES warehouse (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS to_warehouse_id UUID REFERENCES warehouse (id) ON DELETE SET NULL;
+
+COMMIT;
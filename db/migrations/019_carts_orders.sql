@@ -0,0 +1,54 @@
+-- Adds shopping carts of clients and anonymous visitors and the orders placed
+-- by their checkout. A cart expires when it is not changed for the configured
+-- time, orders keep the name and the price of products at the checkout.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS cart (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id UUID UNIQUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cart_expires ON cart (expires_at);
+
+CREATE TABLE IF NOT EXISTS cart_item (
+    cart_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (cart_id, product_id),
+    FOREIGN KEY (cart_id) REFERENCES cart (id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS client_order (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    client_id UUID,
+    total FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS client_order_client ON client_order (client_id, created_at);
+
+CREATE TABLE IF NOT EXISTS order_item (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL,
+    product_id UUID,
+    name TEXT NOT NULL,
+    sku TEXT,
+    price FLOAT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    warehouse_id UUID,
+    FOREIGN KEY (order_id) REFERENCES client_order (id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE SET NULL,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouse (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_item_order ON order_item (order_id);
+
+COMMIT;
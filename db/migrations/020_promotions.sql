@@ -0,0 +1,60 @@
+-- Adds promotions discounting products of a product, a category subtree or a
+-- supplier, optionally behind a coupon code. Redemptions record promotions
+-- applied by checkouts to enforce usage limits, orders keep their discounts.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS promotion (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+    value FLOAT NOT NULL CHECK (value > 0),
+    product_id UUID,
+    category_id UUID,
+    supplier_id UUID,
+    min_quantity INT NOT NULL DEFAULT 1 CHECK (min_quantity > 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    usage_limit INT CHECK (usage_limit > 0),
+    per_client_limit INT CHECK (per_client_limit > 0),
+    coupon_code TEXT UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE,
+    FOREIGN KEY (supplier_id) REFERENCES supplier (id) ON DELETE CASCADE,
+    CHECK (kind <> 'percentage' OR value <= 100),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE INDEX IF NOT EXISTS promotion_automatic ON promotion (ends_at)
+    WHERE coupon_code IS NULL AND active;
+
+ALTER TABLE client_order
+    ADD COLUMN IF NOT EXISTS subtotal FLOAT,
+    ADD COLUMN IF NOT EXISTS discount FLOAT NOT NULL DEFAULT 0;
+
+UPDATE client_order SET subtotal = total WHERE subtotal IS NULL;
+
+ALTER TABLE client_order ALTER COLUMN subtotal SET NOT NULL;
+
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS discount FLOAT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promotion_id UUID REFERENCES promotion (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS promotion_redemption (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id UUID NOT NULL,
+    client_id UUID,
+    order_id UUID NOT NULL,
+    discount FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (promotion_id, order_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotion (id) ON DELETE CASCADE,
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL,
+    FOREIGN KEY (order_id) REFERENCES client_order (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS promotion_redemption_client ON promotion_redemption (promotion_id, client_id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Makes gallery positions unique per product. Images sharing a position are
+-- renumbered in their current order first. The constraint is deferrable so a
+-- single statement can shift positions.
+BEGIN;
+
+UPDATE product_image p SET position = o.position
+FROM (
+    SELECT product_id, image_id,
+        row_number() OVER (PARTITION BY product_id ORDER BY position, image_id) - 1 AS position
+    FROM product_image
+) o
+WHERE p.product_id = o.product_id AND p.image_id = o.image_id AND p.position <> o.position;
+
+ALTER TABLE product_image
+    ADD CONSTRAINT product_image_position UNIQUE (product_id, position)
+    DEFERRABLE INITIALLY IMMEDIATE;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- A product is delivered when it is created with stock or restocked, the
+-- creation time is no longer a delivery by default.
+BEGIN;
+
+ALTER TABLE product ALTER COLUMN last_delivery_date DROP DEFAULT;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Adds versions to address book entries of clients and suppliers, a change of
+-- the label or the default flag increments the version and a request with
+-- If-Match is rejected when it differs.
+BEGIN;
+
+ALTER TABLE client_address ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE supplier_location ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Stores the ETag and Location headers of idempotent responses, a replayed
+-- response carries them like the original one.
+BEGIN;
+
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS response_etag TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS response_location TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -11,10 +11,17 @@ import (
 )
 
 type Config struct {
-	Env            string `env:"env" env-default:"local"`
-	PostgresConfig connection.PostgresConfig
-	CrudService    CrudService
-	ConsulService  ConsulConfig
+	Env                string `env:"env" env-default:"local"`
+	PostgresConfig     connection.PostgresConfig
+	CrudService        CrudService
+	ConsulService      ConsulConfig
+	ImageService       ImageConfig
+	AddressService     AddressConfig
+	ImportService      ImportConfig
+	JobService         JobConfig
+	IdempotencyService IdempotencyConfig
+	InventoryService   InventoryConfig
+	CartService        CartConfig
 }
 
 type CrudService struct {
@@ -22,6 +29,8 @@ type CrudService struct {
 	Name    string `env:"crud_service_name" env-default:"crud-service"`
 	Address string `env:"crud_service_address" env-default:"localhost"`
 	Port    string `env:"crud_service_port" env-default:"8080"`
+	// ShutdownTimeout limits waiting for requests and jobs on stop
+	ShutdownTimeout time.Duration `env:"crud_service_shutdown_timeout" env-default:"15s"`
 }
 
 type ConsulConfig struct {
@@ -33,6 +42,62 @@ type ConsulConfig struct {
 	SurveyTimeout  string        `env:"consul_service_survey_timeout" env-default:"10s"`
 }
 
+type ImageConfig struct {
+	MaxWidth  int `env:"image_max_width" env-default:"8192"`
+	MaxHeight int `env:"image_max_height" env-default:"8192"`
+}
+
+type AddressConfig struct {
+	// Geocoder is "local" for the offline stub, "none" disables geocoding
+	Geocoder string `env:"address_geocoder" env-default:"none"`
+	// GCInterval is the period of orphan address collection, 0 disables it
+	GCInterval  time.Duration `env:"address_gc_interval" env-default:"1h"`
+	GCBatchSize int           `env:"address_gc_batch_size" env-default:"500"`
+}
+
+type ImportConfig struct {
+	// BatchSize is the number of rows written by one COPY
+	BatchSize int   `env:"import_batch_size" env-default:"500"`
+	MaxBytes  int64 `env:"import_max_bytes" env-default:"33554432"`
+}
+
+type JobConfig struct {
+	// Workers is the number of job workers, 0 disables them
+	Workers      int           `env:"job_workers" env-default:"2"`
+	Lease        time.Duration `env:"job_lease" env-default:"1m"`
+	PollInterval time.Duration `env:"job_poll_interval" env-default:"1s"`
+	MaxAttempts  int           `env:"job_max_attempts" env-default:"3"`
+	// Finished jobs are deleted after Retention, the check runs every
+	// CleanInterval, 0 disables it
+	CleanInterval time.Duration `env:"job_clean_interval" env-default:"10m"`
+	Retention     time.Duration `env:"job_retention" env-default:"168h"`
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed for its Idempotency-Key
+	TTL time.Duration `env:"idempotency_ttl" env-default:"24h"`
+	// Lease is how long a request in progress holds its Idempotency-Key, a
+	// request which is not completed by then may be retried with the key
+	Lease time.Duration `env:"idempotency_lease" env-default:"1m"`
+	// CleanInterval is the period of expired key deletion, 0 disables it
+	CleanInterval time.Duration `env:"idempotency_clean_interval" env-default:"1h"`
+}
+
+type InventoryConfig struct {
+	// LowStockInterval is the period of low stock evaluation, 0 disables it
+	LowStockInterval time.Duration `env:"inventory_low_stock_interval" env-default:"5m"`
+	// StockStrategy picks the warehouse of a decrease which does not name
+	// one: most_stock or nearest
+	StockStrategy string `env:"inventory_stock_strategy" env-default:"most_stock"`
+}
+
+type CartConfig struct {
+	// TTL is the inactivity after which a cart expires, expired carts are
+	// deleted every CleanInterval
+	TTL           time.Duration `env:"cart_ttl" env-default:"72h"`
+	CleanInterval time.Duration `env:"cart_clean_interval" env-default:"1h"`
+}
+
 func MustLoad() *Config {
 	op := "config.MustLoad"
 
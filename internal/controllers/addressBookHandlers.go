@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// addressBookHandlers serves address books of clients and locations of
+// suppliers, the owner controllers expose them as documented endpoints.
+type addressBookHandlers struct {
+	*BaseController
+	owner  string
+	get    func(ctx context.Context, id uuid.UUID) ([]domain.AddressBookEntry, error)
+	add    func(ctx context.Context, id uuid.UUID, entry *domain.AddressBookEntry) error
+	update func(ctx context.Context, id, addressId uuid.UUID, patch *domain.AddressBookPatch) error
+	remove func(ctx context.Context, id, addressId uuid.UUID, version int64) error
+}
+
+func (h *addressBookHandlers) list(c *gin.Context, op string) {
+	id, ok := h.parseId(c, op, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := h.get(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, op, err)
+		return
+	}
+
+	output := make([]dto.AddressBookEntryResponse, len(entries))
+
+	for i, entry := range entries {
+		output[i] = mapper.AddressBookEntryToResponse(entry)
+	}
+
+	h.logger.Debug("Address book retrieved", "owner", h.owner, "id", id, "op", op)
+	h.responce(c, http.StatusOK, output)
+}
+
+func (h *addressBookHandlers) create(c *gin.Context, op string) {
+	id, ok := h.parseId(c, op, "id")
+	if !ok {
+		return
+	}
+
+	var input dto.AddressBookEntryRequest
+
+	if !h.bind(c, op, &input) {
+		return
+	}
+
+	entry := mapper.AddressBookEntryRequestToDomain(input)
+
+	if err := h.add(c.Request.Context(), id, &entry); err != nil {
+		h.fail(c, op, err)
+		return
+	}
+
+	h.logger.Debug("Address added", "owner", h.owner, "id", id, "address id", entry.Address.Id, "op", op)
+	setETag(c, entry.Version)
+	h.responce(c, http.StatusCreated, mapper.AddressBookEntryToResponse(entry))
+}
+
+func (h *addressBookHandlers) change(c *gin.Context, op string) {
+	id, ok := h.parseId(c, op, "id")
+	if !ok {
+		return
+	}
+
+	addressId, ok := h.parseId(c, op, "address_id")
+	if !ok {
+		return
+	}
+
+	var input dto.AddressBookEntryUpdateRequest
+
+	if !h.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := h.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	patch := mapper.AddressBookEntryUpdateRequestToPatch(input)
+	patch.Version = version
+
+	if err := h.update(c.Request.Context(), id, addressId, &patch); err != nil {
+		h.fail(c, op, err)
+		return
+	}
+
+	h.logger.Debug("Address updated", "owner", h.owner, "id", id, "address id", addressId, "op", op)
+	setETag(c, patch.Version)
+	c.Status(http.StatusOK)
+}
+
+func (h *addressBookHandlers) delete(c *gin.Context, op string) {
+	id, ok := h.parseId(c, op, "id")
+	if !ok {
+		return
+	}
+
+	addressId, ok := h.parseId(c, op, "address_id")
+	if !ok {
+		return
+	}
+
+	version, ok := h.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := h.remove(c.Request.Context(), id, addressId, version); err != nil {
+		h.fail(c, op, err)
+		return
+	}
+
+	h.logger.Debug("Address removed", "owner", h.owner, "id", id, "address id", addressId, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *addressBookHandlers) parseId(c *gin.Context, op, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		h.logger.Warn("The received identifier is invalid", "param", param, logger.Err(err), "op", op)
+		h.problem(c, http.StatusBadRequest, "Invalud request payload: "+param+" is not valid")
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func (h *addressBookHandlers) fail(c *gin.Context, op string, err error) {
+	h.BaseController.fail(c, op, err, problemDetails{
+		crud_errors.ErrNotFound:          h.owner + " or address not found",
+		crud_errors.ErrAddressIsEmpty:    "Invalid request payload: invalid data received",
+		crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+		crud_errors.ErrInvalidParam:      "Invalid request payload: address is not valid, label is unknown or default flag is reset",
+		crud_errors.ErrDuplicateKeyValue: "Address is already added to " + h.owner,
+		crud_errors.ErrLastAddress:       "The only address of " + h.owner + " cannot be removed",
+		crud_errors.ErrVersionMismatch:   "Address is changed, get it again",
+	})
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type addressService interface {
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Address, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Address, error)
+	GetReferences(ctx context.Context, id uuid.UUID) ([]domain.AddressReference, error)
+}
+
+type AddressController struct {
+	*BaseController
+	service addressService
+}
+
+func NewAddressController(service addressService, logger *logger.Logger) *AddressController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Address controller is created")
+	return &AddressController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// GetAllAddress godoc
+//
+//	@Summary		Get all addresses
+//	@Description	That endpoint retrieve all stored addresses ordered by id
+//	@Tags			addresses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			limit	query		int	false	"limit get addresses"
+//	@Param			offset	query		int	false	"offset get addresses"
+//	@Success		200		{array}		dto.AddressResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/addresses [get]
+func (ctrl *AddressController) GetAll(c *gin.Context) {
+	op := "controllers.addressController.GetAll"
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	addresses, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
+		return
+	}
+
+	output := make([]dto.AddressResponse, len(addresses))
+
+	for i, address := range addresses {
+		output[i] = mapper.AddressToResponse(address)
+	}
+
+	ctrl.logger.Debug("Retrieved all addresses", "limit", limit, "offset", offset, "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// GetAddress godoc
+//
+//	@Summary		Get address by ID
+//	@Description	That endpoint retrieve stored address by ID
+//	@Tags			addresses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Address ID"
+//	@Success		200	{object}	dto.AddressResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/addresses/{id} [get]
+func (ctrl *AddressController) GetById(c *gin.Context) {
+	op := "controllers.addressController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	address, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "address not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Address retrieved", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.AddressToResponse(*address))
+}
+
+// GetAddressReferences godoc
+//
+//	@Summary		Get address references
+//	@Description	That endpoint retrieve clients, suppliers and warehouses which use the address, an empty list means the address is an orphan
+//	@Tags			addresses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Address ID"
+//	@Success		200	{array}		dto.AddressReferenceResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/addresses/{id}/references [get]
+func (ctrl *AddressController) GetReferences(c *gin.Context) {
+	op := "controllers.addressController.GetReferences"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	references, err := ctrl.service.GetReferences(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "address not found",
+		})
+		return
+	}
+
+	output := make([]dto.AddressReferenceResponse, len(references))
+
+	for i, reference := range references {
+		output[i] = mapper.AddressReferenceToResponse(reference)
+	}
+
+	ctrl.logger.Debug("Address references retrieved", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"bytes"
+	"io"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
 )
 
 type BaseControllerInterface interface {
@@ -19,10 +25,39 @@ func NewBaseContorller(logger *logger.Logger) *BaseController {
 	}
 }
 
+// responce writes the object in the media type negotiated for the route by
+// Negotiate or NegotiateList, routes without them answer JSON.
 func (ctrl *BaseController) responce(c *gin.Context, statusCode int, obj any) {
-	switch c.GetHeader("Accept") {
-	case "appication/xml":
+	switch c.GetString(ctxMediaType) {
+	case mimeXML:
+		if reflect.ValueOf(obj).Kind() == reflect.Slice {
+			obj = xmlList{Items: obj}
+		}
+
 		c.XML(statusCode, obj)
+	case mimeMsgPack:
+		ctrl.encode(c, statusCode, mimeMsgPack, obj, func(w io.Writer, value any) error {
+			handle := codec.MsgpackHandle{}
+			handle.Canonical = true
+			return codec.NewEncoder(w, &handle).Encode(value)
+		})
+	case mimeYAML:
+		ctrl.encode(c, statusCode, mimeYAML, obj, func(w io.Writer, value any) error {
+			encoder := yaml.NewEncoder(w)
+			if err := encoder.Encode(value); err != nil {
+				return err
+			}
+
+			return encoder.Close()
+		})
+	case mimeCSV:
+		var buf bytes.Buffer
+		if err := tabular.WriteStructs(&buf, tabular.CSV, obj); err != nil {
+			ctrl.fail(c, "controllers.baseController.responce", err, nil)
+			return
+		}
+
+		c.Data(statusCode, tabular.CSV.ContentType(), buf.Bytes())
 	default:
 		c.JSON(statusCode, obj)
 	}
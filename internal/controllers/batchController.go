@@ -0,0 +1,459 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/internal/services"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// Batch operation methods.
+const (
+	batchCreate = "create"
+	batchUpdate = "update"
+	batchDelete = "delete"
+)
+
+type batchService interface {
+	Run(ctx context.Context, steps []services.BatchStep, atomic bool) ([]services.BatchResult, error)
+}
+
+// batchHandler runs an operation on the entity with the id (uuid.Nil for create)
+// and the expected version (0 skips the check) and returns the id of the
+// changed entity.
+type batchHandler func(ctx context.Context, id uuid.UUID, version int64, body []byte) (uuid.UUID, error)
+
+// batchInputError is a rejected operation body.
+type batchInputError struct {
+	message string
+}
+
+func (e *batchInputError) Error() string {
+	return e.message
+}
+
+type BatchController struct {
+	*BaseController
+	service  batchService
+	handlers map[string]batchHandler
+}
+
+func NewBatchController(service batchService, clients clientService, suppliers supplierService, products productService, images imageService, logger *logger.Logger) *BatchController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Batch controller is created")
+	return &BatchController{
+		BaseController: controller,
+		service:        service,
+		handlers: map[string]batchHandler{
+			"clients." + batchCreate:   createClientHandler(clients),
+			"clients." + batchUpdate:   updateClientHandler(clients),
+			"clients." + batchDelete:   deleteHandler(clients.Delete),
+			"suppliers." + batchCreate: createSupplierHandler(suppliers),
+			"suppliers." + batchUpdate: updateSupplierHandler(suppliers),
+			"suppliers." + batchDelete: deleteHandler(suppliers.Delete),
+			"products." + batchCreate:  createProductHandler(products),
+			"products." + batchUpdate:  updateProductHandler(products),
+			"products." + batchDelete:  deleteHandler(products.Delete),
+			"images." + batchCreate:    createImageHandler(images),
+			"images." + batchUpdate:    updateImageHandler(images),
+			"images." + batchDelete:    deleteHandler(images.Delete),
+		},
+	}
+}
+
+// Batch godoc
+//
+//	@Summary		Run many operations atomically
+//	@Description	That endpoint runs create, update and delete operations on clients, suppliers, products and images in order in one transaction. Bodies are the same as in the entity endpoints, product update takes the changed fields. Operation id and string values of body equal to "$ref" are replaced by the id returned by the earlier operation with the ref. An atomic batch (default) is rolled back when any operation fails
+//	@Tags			batch
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			batch	body		dto.BatchRequest	true	"Operations"
+//	@Success		200		{object}	dto.BatchResponse
+//	@Success		207		{object}	dto.BatchResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		422		{object}	dto.BatchResponse
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/batch [post]
+func (ctrl *BatchController) Run(c *gin.Context) {
+	op := "controllers.batchController.Run"
+	var input dto.BatchRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	if len(input.Operations) == 0 || len(input.Operations) > maxBatchOperations {
+		ctrl.logger.Warn("Invalid number of batch operations", "count", len(input.Operations), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, fmt.Sprintf("Invalid request payload: batch takes from 1 to %d operations", maxBatchOperations))
+		return
+	}
+
+	atomic := input.Atomic == nil || *input.Atomic
+
+	steps, err := ctrl.steps(input.Operations)
+	if err != nil {
+		ctrl.logger.Warn("Invalid batch operation", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+
+	results, err := ctrl.service.Run(c.Request.Context(), steps, atomic)
+	if err != nil && !errors.Is(err, crud_errors.ErrBatchFailed) {
+		ctrl.fail(c, op, err, nil)
+		return
+	}
+
+	output := dto.BatchResponse{
+		Atomic:  atomic,
+		Results: make([]dto.BatchOperationResult, len(results)),
+	}
+
+	for i, result := range results {
+		operation := input.Operations[i]
+		output.Results[i] = dto.BatchOperationResult{Index: i, Ref: operation.Ref}
+
+		switch {
+		case result.Skipped:
+			output.Results[i].Status = http.StatusFailedDependency
+			output.Results[i].Error = "not executed, an earlier operation failed"
+		case result.RolledBack:
+			output.Results[i].Status = http.StatusFailedDependency
+			output.Results[i].Error = "rolled back, a later operation failed"
+		case result.Err != nil:
+			output.Results[i].Status, output.Results[i].Error = batchErrorStatus(result.Err)
+			output.Failed++
+		default:
+			id := result.Id
+			output.Results[i].Id = &id
+			output.Results[i].Status = batchSuccessStatus(operation.Method)
+			output.Succeeded++
+		}
+	}
+
+	switch {
+	case errors.Is(err, crud_errors.ErrBatchFailed):
+		ctrl.logger.Debug("Batch is rolled back", "op", op)
+		ctrl.responce(c, http.StatusUnprocessableEntity, output)
+	case output.Failed > 0:
+		ctrl.logger.Debug("Batch is partially executed", "failed", output.Failed, "op", op)
+		ctrl.responce(c, http.StatusMultiStatus, output)
+	default:
+		ctrl.logger.Debug("Batch is executed", "operations", len(results), "op", op)
+		ctrl.responce(c, http.StatusOK, output)
+	}
+}
+
+// steps checks operations and references between them before anything is run.
+func (ctrl *BatchController) steps(operations []dto.BatchOperation) ([]services.BatchStep, error) {
+	steps := make([]services.BatchStep, len(operations))
+	known := make(map[string]bool)
+
+	for i, operation := range operations {
+		handler, ok := ctrl.handlers[operation.Entity+"."+operation.Method]
+		if !ok {
+			return nil, fmt.Errorf("operation %d: %s of %s is not supported", i, operation.Method, operation.Entity)
+		}
+
+		if operation.Method == batchCreate && (operation.Id != "" || operation.Version != 0) {
+			return nil, fmt.Errorf("operation %d: create does not take id and version", i)
+		}
+
+		if operation.Version < 0 {
+			return nil, fmt.Errorf("operation %d: version is not valid", i)
+		}
+
+		if operation.Method != batchCreate {
+			if ref, isRef := strings.CutPrefix(operation.Id, "$"); isRef {
+				if !known[ref] {
+					return nil, fmt.Errorf("operation %d: reference %q is not defined by earlier operations", i, operation.Id)
+				}
+			} else if err := uuid.Validate(operation.Id); err != nil {
+				return nil, fmt.Errorf("operation %d: id is not valid", i)
+			}
+		}
+
+		if operation.Ref != "" {
+			if known[operation.Ref] {
+				return nil, fmt.Errorf("operation %d: ref %q is already used", i, operation.Ref)
+			}
+
+			known[operation.Ref] = true
+		}
+
+		steps[i] = services.BatchStep{
+			Ref: operation.Ref,
+			Run: func(ctx context.Context, refs map[string]uuid.UUID) (uuid.UUID, error) {
+				id := uuid.Nil
+				if operation.Id != "" {
+					resolved, err := resolveBatchId(operation.Id, refs)
+					if err != nil {
+						return uuid.Nil, err
+					}
+
+					id = resolved
+				}
+
+				body, err := resolveBatchRefs(operation.Body, refs)
+				if err != nil {
+					return uuid.Nil, err
+				}
+
+				return handler(ctx, id, operation.Version, body)
+			},
+		}
+	}
+
+	return steps, nil
+}
+
+func resolveBatchId(raw string, refs map[string]uuid.UUID) (uuid.UUID, error) {
+	if ref, isRef := strings.CutPrefix(raw, "$"); isRef {
+		id, ok := refs[ref]
+		if !ok {
+			return uuid.Nil, &batchInputError{fmt.Sprintf("reference %q is not resolved, its operation failed", raw)}
+		}
+
+		return id, nil
+	}
+
+	return uuid.Parse(raw)
+}
+
+// resolveBatchRefs replaces string values of the body equal to "$<ref>" of a
+// known ref with the id.
+func resolveBatchRefs(body []byte, refs map[string]uuid.UUID) ([]byte, error) {
+	if len(body) == 0 || len(refs) == 0 {
+		return body, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, &batchInputError{"body is not valid JSON"}
+	}
+
+	return json.Marshal(replaceBatchRefs(value, refs))
+}
+
+func replaceBatchRefs(value any, refs map[string]uuid.UUID) any {
+	switch v := value.(type) {
+	case string:
+		if ref, isRef := strings.CutPrefix(v, "$"); isRef {
+			if id, ok := refs[ref]; ok {
+				return id.String()
+			}
+		}
+	case map[string]any:
+		for key, item := range v {
+			v[key] = replaceBatchRefs(item, refs)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = replaceBatchRefs(item, refs)
+		}
+	}
+
+	return value
+}
+
+// decodeBatchBody decodes and validates the body like binding of a request.
+func decodeBatchBody(body []byte, out any) error {
+	if len(body) == 0 {
+		return &batchInputError{"body is required"}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return &batchInputError{"invalid data received"}
+	}
+
+	if err := binding.Validator.ValidateStruct(out); err != nil {
+		var invalid validator.ValidationErrors
+		if !errors.As(err, &invalid) {
+			return &batchInputError{"invalid data received"}
+		}
+
+		messages := make([]string, len(invalid))
+		for i, field := range invalid {
+			messages[i] = fieldMessage(field)
+		}
+
+		return &batchInputError{strings.Join(messages, "; ")}
+	}
+
+	return nil
+}
+
+func batchSuccessStatus(method string) int {
+	switch method {
+	case batchCreate:
+		return http.StatusCreated
+	case batchDelete:
+		return http.StatusNoContent
+	}
+
+	return http.StatusOK
+}
+
+// batchErrorStatus maps the error of an operation to a status and a message
+// without internal details.
+func batchErrorStatus(err error) (int, string) {
+	var inputErr *batchInputError
+	if errors.As(err, &inputErr) {
+		return http.StatusBadRequest, inputErr.message
+	}
+
+	kind := problemOf(err)
+	if kind.err == nil {
+		return kind.status, "Server is busy"
+	}
+
+	return kind.status, kind.err.Error()
+}
+
+func deleteHandler(remove func(ctx context.Context, id uuid.UUID, version int64) error) batchHandler {
+	return func(ctx context.Context, id uuid.UUID, version int64, _ []byte) (uuid.UUID, error) {
+		return id, remove(ctx, id, version)
+	}
+}
+
+func createClientHandler(service clientService) batchHandler {
+	return func(ctx context.Context, _ uuid.UUID, _ int64, body []byte) (uuid.UUID, error) {
+		var input dto.ClientRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		client, err := mapper.ClientRequestToDomain(input)
+		if err != nil {
+			return uuid.Nil, &batchInputError{"invalid birthday date"}
+		}
+
+		if err := service.Create(ctx, &client); err != nil {
+			return uuid.Nil, err
+		}
+
+		return client.Id, nil
+	}
+}
+
+func updateClientHandler(service clientService) batchHandler {
+	return func(ctx context.Context, id uuid.UUID, version int64, body []byte) (uuid.UUID, error) {
+		var input dto.ClientUpdateRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		patch, err := mapper.ClientUpdateRequestToPatch(input)
+		if err != nil {
+			return uuid.Nil, &batchInputError{"invalid birthday date"}
+		}
+
+		patch.Version = version
+		return id, service.Update(ctx, id, &patch)
+	}
+}
+
+func createSupplierHandler(service supplierService) batchHandler {
+	return func(ctx context.Context, _ uuid.UUID, _ int64, body []byte) (uuid.UUID, error) {
+		var input dto.SupplierRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		supplier := mapper.SupplierRequestToDomain(input)
+		if err := service.Create(ctx, &supplier); err != nil {
+			return uuid.Nil, err
+		}
+
+		return supplier.Id, nil
+	}
+}
+
+func updateSupplierHandler(service supplierService) batchHandler {
+	return func(ctx context.Context, id uuid.UUID, version int64, body []byte) (uuid.UUID, error) {
+		var input dto.SupplierUpdateRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		patch := mapper.SupplierUpdateRequestToPatch(input)
+		patch.Version = version
+		return id, service.Update(ctx, id, &patch)
+	}
+}
+
+func createProductHandler(service productService) batchHandler {
+	return func(ctx context.Context, _ uuid.UUID, _ int64, body []byte) (uuid.UUID, error) {
+		var input dto.ProductRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		product := mapper.ProductRequestToDomain(input)
+		if err := service.Create(ctx, &product); err != nil {
+			return uuid.Nil, err
+		}
+
+		return product.Id, nil
+	}
+}
+
+func updateProductHandler(service productService) batchHandler {
+	return func(ctx context.Context, id uuid.UUID, version int64, body []byte) (uuid.UUID, error) {
+		var input dto.ProductUpdateRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		patch := mapper.ProductUpdateRequestToPatch(input)
+		patch.Version = version
+		return id, service.Update(ctx, id, &patch)
+	}
+}
+
+func createImageHandler(service imageService) batchHandler {
+	return func(ctx context.Context, _ uuid.UUID, _ int64, body []byte) (uuid.UUID, error) {
+		var input dto.ImageRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		image := mapper.ImageRequestToDomain(input)
+		if err := service.Create(ctx, &image); err != nil {
+			return uuid.Nil, err
+		}
+
+		return image.Id, nil
+	}
+}
+
+func updateImageHandler(service imageService) batchHandler {
+	return func(ctx context.Context, id uuid.UUID, version int64, body []byte) (uuid.UUID, error) {
+		var input dto.ImageRequest
+		if err := decodeBatchBody(body, &input); err != nil {
+			return uuid.Nil, err
+		}
+
+		image := mapper.ImageRequestToDomain(input)
+		image.Id = id
+		image.Version = version
+
+		return id, service.Update(ctx, &image)
+	}
+}
@@ -0,0 +1,447 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type cartService interface {
+	Create(ctx context.Context, cart *domain.Cart) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Cart, error)
+	GetByClient(ctx context.Context, clientId uuid.UUID) (*domain.Cart, error)
+	AddItem(ctx context.Context, cartId, productId uuid.UUID, quantity, version int64) (*domain.Cart, error)
+	SetItem(ctx context.Context, cartId, productId uuid.UUID, quantity, version int64) (*domain.Cart, error)
+	RemoveItem(ctx context.Context, cartId, productId uuid.UUID, version int64) (*domain.Cart, error)
+	Merge(ctx context.Context, id, clientId uuid.UUID) (*domain.Cart, error)
+	Checkout(ctx context.Context, id uuid.UUID, pick domain.WarehousePick, coupons []string, version int64) (*domain.Order, error)
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+type CartController struct {
+	*BaseController
+	service cartService
+}
+
+func NewCartController(service cartService, logger *logger.Logger) *CartController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Cart controller is created")
+	return &CartController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// cartItemProblems are details of problems of cart item changes.
+var cartItemProblems = problemDetails{
+	crud_errors.ErrNotFound:          "cart or product not found",
+	crud_errors.ErrVersionMismatch:   "cart is changed, get it again",
+	crud_errors.ErrInsufficientStock: "quantity in the cart exceeds the product stock",
+	crud_errors.ErrVariantStock:      "product with variants is added by its variants",
+}
+
+// CreateCart godoc
+//
+//	@Summary		Create cart
+//	@Description	That endpoint creates an empty cart of the client, an anonymous cart without client_id. A client has one cart, the cart expires when it is not changed for the configured time
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			cart	body		dto.CartRequest	false	"Cart owner"
+//	@Success		201		{object}	dto.CartResponse
+//	@Header			201		{string}	ETag	"Cart version"
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/carts [post]
+func (ctrl *CartController) Create(c *gin.Context) {
+	op := "controllers.cartController.Create"
+	var input dto.CartRequest
+
+	// an anonymous cart is created without a body
+	if c.Request.ContentLength != 0 && !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	cart := domain.Cart{ClientId: input.ClientId}
+
+	if err := ctrl.service.Create(c.Request.Context(), &cart); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:          "client not found",
+			crud_errors.ErrDuplicateKeyValue: "client has a cart already",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Cart created", "id", cart.Id, "op", op)
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusCreated, mapper.CartToResponse(cart))
+}
+
+// GetCart godoc
+//
+//	@Summary		Get cart by ID
+//	@Description	That endpoint retrieve the cart with current prices, subtotals and stock of its products. Expired carts are not found
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Cart ID"
+//	@Success		200	{object}	dto.CartResponse
+//	@Header			200	{string}	ETag	"Cart version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/carts/{id} [get]
+func (ctrl *CartController) GetById(c *gin.Context) {
+	op := "controllers.cartController.GetById"
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return
+	}
+
+	cart, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "cart not found",
+		})
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// GetClientCart godoc
+//
+//	@Summary		Get client cart
+//	@Description	That endpoint retrieve the cart of the client with current prices, subtotals and stock of its products
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Client ID"
+//	@Success		200	{object}	dto.CartResponse
+//	@Header			200	{string}	ETag	"Cart version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/cart [get]
+func (ctrl *CartController) GetByClient(c *gin.Context) {
+	op := "controllers.cartController.GetByClient"
+	clientId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	cart, err := ctrl.service.GetByClient(c.Request.Context(), clientId)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "client has no cart",
+		})
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// AddCartItem godoc
+//
+//	@Summary		Add product to cart
+//	@Description	That endpoint adds the quantity of the product to the cart, the quantity in the cart cannot exceed the product stock
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID			true	"Cart ID"
+//	@Param			item		body		dto.CartItemRequest	true	"Product and quantity"
+//	@Param			If-Match	header		string				false	"ETag of the cart, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.CartResponse
+//	@Header			200			{string}	ETag	"Cart version"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/carts/{id}/items [post]
+func (ctrl *CartController) AddItem(c *gin.Context) {
+	op := "controllers.cartController.AddItem"
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return
+	}
+
+	var input dto.CartItemRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	cart, err := ctrl.service.AddItem(c.Request.Context(), id, input.ProductId, input.Quantity, version)
+	if err != nil {
+		ctrl.fail(c, op, err, cartItemProblems)
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// SetCartItem godoc
+//
+//	@Summary		Set product quantity in cart
+//	@Description	That endpoint sets the quantity of the product in the cart, the product is added when the cart has no such item
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID					true	"Cart ID"
+//	@Param			product_id	path		uuid.UUID					true	"Product ID"
+//	@Param			item		body		dto.CartItemUpdateRequest	true	"Quantity"
+//	@Param			If-Match	header		string						false	"ETag of the cart, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.CartResponse
+//	@Header			200			{string}	ETag	"Cart version"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/carts/{id}/items/{product_id} [put]
+func (ctrl *CartController) SetItem(c *gin.Context) {
+	op := "controllers.cartController.SetItem"
+	id, productId, ok := ctrl.itemIds(c, op)
+	if !ok {
+		return
+	}
+
+	var input dto.CartItemUpdateRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	cart, err := ctrl.service.SetItem(c.Request.Context(), id, productId, input.Quantity, version)
+	if err != nil {
+		ctrl.fail(c, op, err, cartItemProblems)
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// RemoveCartItem godoc
+//
+//	@Summary		Remove product from cart
+//	@Description	That endpoint removes the product from the cart
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID	true	"Cart ID"
+//	@Param			product_id	path		uuid.UUID	true	"Product ID"
+//	@Param			If-Match	header		string		false	"ETag of the cart, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.CartResponse
+//	@Header			200			{string}	ETag	"Cart version"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/carts/{id}/items/{product_id} [delete]
+func (ctrl *CartController) RemoveItem(c *gin.Context) {
+	op := "controllers.cartController.RemoveItem"
+	id, productId, ok := ctrl.itemIds(c, op)
+	if !ok {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	cart, err := ctrl.service.RemoveItem(c.Request.Context(), id, productId, version)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:        "cart or item not found",
+			crud_errors.ErrVersionMismatch: "cart is changed, get it again",
+		})
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// MergeCart godoc
+//
+//	@Summary		Merge anonymous cart into client cart
+//	@Description	That endpoint gives the anonymous cart to the identified client. It becomes the client cart when the client has none, otherwise its items are added to the client cart limited by the product stock and the anonymous cart is deleted. The client cart is returned, items lowered to the stock or removed when it is out are listed in adjustments
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID				true	"Anonymous cart ID"
+//	@Param			client	body		dto.CartMergeRequest	true	"Identified client"
+//	@Success		200		{object}	dto.CartResponse
+//	@Header			200		{string}	ETag	"Cart version"
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/carts/{id}/merge [post]
+func (ctrl *CartController) Merge(c *gin.Context) {
+	op := "controllers.cartController.Merge"
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return
+	}
+
+	var input dto.CartMergeRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	cart, err := ctrl.service.Merge(c.Request.Context(), id, input.ClientId)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "cart or client not found",
+		})
+		return
+	}
+
+	setETag(c, cart.Version)
+	ctrl.responce(c, http.StatusOK, mapper.CartToResponse(*cart))
+}
+
+// CheckoutCart godoc
+//
+//	@Summary		Checkout cart
+//	@Description	That endpoint turns the client cart into an order in one transaction: the stock of every product is decreased, the order keeps the current prices with the discounts of promotions and the cart is deleted. The body is optional, strategy picks the warehouses as for a stock decrease and coupons redeem promotions, a coupon discounting no item rejects the checkout
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID			true	"Cart ID"
+//	@Param			checkout	body		dto.CheckoutRequest	false	"Warehouse pick and coupons"
+//	@Param			If-Match	header		string				false	"ETag of the cart, the checkout is rejected when it is changed"
+//	@Success		201			{object}	dto.OrderResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/carts/{id}/checkout [post]
+func (ctrl *CartController) Checkout(c *gin.Context) {
+	op := "controllers.cartController.Checkout"
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return
+	}
+
+	var input dto.CheckoutRequest
+
+	// the body is optional, the configured strategy is used without it
+	if c.Request.ContentLength != 0 && !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	order, err := ctrl.service.Checkout(c.Request.Context(), id, mapper.CheckoutRequestToPick(input), input.Coupons, version)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:            "cart, product or destination address not found",
+			crud_errors.ErrVersionMismatch:     "cart is changed, get it again",
+			crud_errors.ErrInsufficientStock:   "stock of a product in the cart is not enough",
+			crud_errors.ErrVariantStock:        "product with variants is bought by its variants",
+			crud_errors.ErrCouponNotApplicable: "coupon does not discount any item, evaluate the promotions for the reason",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Cart checked out", "id", id, "order_id", order.Id, "op", op)
+	ctrl.responce(c, http.StatusCreated, mapper.OrderToResponse(*order))
+}
+
+// DeleteCart godoc
+//
+//	@Summary		Delete cart by ID
+//	@Description	That endpoint delete the cart with its items
+//	@Tags			carts
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Cart ID"
+//	@Param			If-Match	header	string		false	"ETag of the cart, the delete is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/carts/{id} [delete]
+func (ctrl *CartController) Delete(c *gin.Context) {
+	op := "controllers.cartController.Delete"
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "cart is changed, get it again",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Cart deleted", "id", id, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *CartController) cartId(c *gin.Context, op string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func (ctrl *CartController) itemIds(c *gin.Context, op string) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := ctrl.cartId(c, op)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	productId, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		ctrl.logger.Warn("The received product identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: product_id is not valid")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, productId, true
+}
@@ -0,0 +1,339 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type categoryService interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetAll(ctx context.Context) ([]domain.Category, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.CategoryPatch) (*domain.Category, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error)
+	CreateAttribute(ctx context.Context, definition *domain.AttributeDefinition) error
+	DeleteAttribute(ctx context.Context, categoryId uuid.UUID, key string) error
+}
+
+type CategoryController struct {
+	*BaseController
+	service categoryService
+}
+
+func NewCategoryController(service categoryService, logger *logger.Logger) *CategoryController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Category controller is created")
+	return &CategoryController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// CreateCategory godoc
+//
+//	@Summary		Create category
+//	@Description	Category created as a top level category or as a subcategory of parent_id. The slug is made from the name when it is not given, siblings are ordered by position
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			category	body		dto.CategoryRequest	true	"Category data"
+//	@Success		201			{object}	dto.CategoryResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/categories [post]
+func (ctrl *CategoryController) Create(c *gin.Context) {
+	op := "controllers.categoryController.Create"
+	var input dto.CategoryRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	category := mapper.CategoryRequestToDomain(input)
+
+	if err := ctrl.service.Create(c.Request.Context(), &category); err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			ctrl.logger.Debug("Parent category not found", "op", op)
+			ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: parent category not found")
+			return
+		}
+
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name or slug is not valid",
+			crud_errors.ErrDuplicateKeyValue: "category with the slug already exists",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category created", "id", category.Id, "op", op)
+	ctrl.responce(c, http.StatusCreated, mapper.CategoryToResponse(category))
+}
+
+// GetAllCategories godoc
+//
+//	@Summary		Get category tree
+//	@Description	The endpoint for retrieve all categories flattened depth-first: every category is followed by its subcategories ordered by position. depth and path of slugs show the place of the category in the tree
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Success		200	{array}		dto.CategoryResponse
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/categories [get]
+func (ctrl *CategoryController) GetAll(c *gin.Context) {
+	op := "controllers.categoryController.GetAll"
+
+	categories, err := ctrl.service.GetAll(c.Request.Context())
+	// an empty taxonomy is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, nil)
+		return
+	}
+
+	ctrl.logger.Debug("Retrieved all categories", "count", len(categories), "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.CategoriesToResponses(categories))
+}
+
+// GetCategory godoc
+//
+//	@Summary		Get category by id
+//	@Description	The endpoint for retrieve category by id with its depth and path in the tree
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Category ID"
+//	@Success		200	{object}	dto.CategoryResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/categories/{id} [get]
+func (ctrl *CategoryController) GetById(c *gin.Context) {
+	op := "controllers.categoryController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	category, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "category not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category retrieved", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.CategoryToResponse(*category))
+}
+
+// UpdateCategory godoc
+//
+//	@Summary		Update category
+//	@Description	That endpoint partially update category, only received fields are changed. parent_id moves the category with its subcategories, an empty parent_id moves it to the top level. A category cannot be moved under itself or its subcategory
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml,application/merge-patch+json
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID					true	"Category ID"
+//	@Param			category	body		dto.CategoryUpdateRequest	true	"Changed fields"
+//	@Success		200			{object}	dto.CategoryResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/categories/{id} [patch]
+func (ctrl *CategoryController) Update(c *gin.Context) {
+	op := "controllers.categoryController.Update"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	var input dto.CategoryUpdateRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	patch, err := mapper.CategoryUpdateRequestToPatch(input)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: parent_id is not valid")
+		return
+	}
+
+	category, err := ctrl.service.Update(c.Request.Context(), id, &patch)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name or slug is not valid, or the parent is the category or its subcategory",
+			crud_errors.ErrNotFound:          "category or parent category not found",
+			crud_errors.ErrDuplicateKeyValue: "category with the slug already exists",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category updated", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.CategoryToResponse(*category))
+}
+
+// DeleteCategory godoc
+//
+//	@Summary		Delete category
+//	@Description	The endpoint for deleting category, a category with subcategories or products is not deleted
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path	uuid.UUID	true	"Category ID"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/categories/{id} [delete]
+func (ctrl *CategoryController) Delete(c *gin.Context) {
+	op := "controllers.categoryController.Delete"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), id); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrForeignKeyViolation: "category has subcategories or products",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category deleted", "id", id, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
+// GetCategoryAttributes godoc
+//
+//	@Summary		Get category attributes
+//	@Description	The endpoint for retrieve the attribute schema of the category products: attributes defined by the category and by its ancestors, attributes of upper categories go first
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Category ID"
+//	@Success		200	{array}		dto.AttributeResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/categories/{id}/attributes [get]
+func (ctrl *CategoryController) GetAttributes(c *gin.Context) {
+	op := "controllers.categoryController.GetAttributes"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	definitions, err := ctrl.service.GetAttributes(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "category not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category attributes retrieved", "id", id, "count", len(definitions), "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.AttributeDefinitionsToResponses(definitions))
+}
+
+// CreateCategoryAttribute godoc
+//
+//	@Summary		Create category attribute
+//	@Description	Attribute defined for products of the category and of its subcategories. Type is string, number, integer, boolean or enum, enum values are one of options, min and max bound number and integer values. The key cannot be defined by an ancestor or a subcategory too
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID				true	"Category ID"
+//	@Param			attribute	body		dto.AttributeRequest	true	"Attribute definition"
+//	@Success		201			{object}	dto.AttributeResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/categories/{id}/attributes [post]
+func (ctrl *CategoryController) CreateAttribute(c *gin.Context) {
+	op := "controllers.categoryController.CreateAttribute"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	var input dto.AttributeRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	definition := mapper.AttributeRequestToDefinition(id, input)
+
+	if err := ctrl.service.CreateAttribute(c.Request.Context(), &definition); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: options or bounds do not match the type",
+			crud_errors.ErrNotFound:          "category not found",
+			crud_errors.ErrDuplicateKeyValue: "attribute with the key is already defined in the category tree",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category attribute created", "id", id, "key", definition.Key, "op", op)
+	ctrl.responce(c, http.StatusCreated, mapper.AttributeDefinitionToResponse(definition))
+}
+
+// DeleteCategoryAttribute godoc
+//
+//	@Summary		Delete category attribute
+//	@Description	The endpoint for deleting attribute of the category, values of the attribute are removed from products of the category and its subcategories
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path	uuid.UUID	true	"Category ID"
+//	@Param			key	path	string		true	"Attribute key"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/categories/{id}/attributes/{key} [delete]
+func (ctrl *CategoryController) DeleteAttribute(c *gin.Context) {
+	op := "controllers.categoryController.DeleteAttribute"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	key := c.Param("key")
+
+	if err := ctrl.service.DeleteAttribute(c.Request.Context(), id, key); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "attribute of the category not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Category attribute deleted", "id", id, "key", key, "op", op)
+	c.Status(http.StatusNoContent)
+}
@@ -18,14 +18,20 @@ import (
 type clientService interface {
 	Create(ctx context.Context, client *domain.Client) error
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Client, error)
-	GetByNameAndSurname(ctx context.Context, name, surname string) ([]domain.Client, error)
-	UpdateAddress(ctx context.Context, id uuid.UUID, address *domain.Address) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, filter domain.ClientFilter) ([]domain.Client, int, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Client, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.ClientPatch) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	GetAddresses(ctx context.Context, id uuid.UUID) ([]domain.AddressBookEntry, error)
+	AddAddress(ctx context.Context, id uuid.UUID, entry *domain.AddressBookEntry) error
+	UpdateAddress(ctx context.Context, id, addressId uuid.UUID, patch *domain.AddressBookPatch) error
+	RemoveAddress(ctx context.Context, id, addressId uuid.UUID, version int64) error
 }
 
 type ClientController struct {
 	*BaseController
-	service clientService
+	service   clientService
+	addresses *addressBookHandlers
 }
 
 func NewClientsController(service clientService, logger *logger.Logger) *ClientController {
@@ -33,6 +39,14 @@ func NewClientsController(service clientService, logger *logger.Logger) *ClientC
 	return &ClientController{
 		BaseController: controller,
 		service:        service,
+		addresses: &addressBookHandlers{
+			BaseController: controller,
+			owner:          "client",
+			get:            service.GetAddresses,
+			add:            service.AddAddress,
+			update:         service.UpdateAddress,
+			remove:         service.RemoveAddress,
+		},
 	}
 }
 
@@ -41,25 +55,26 @@ func NewClientsController(service clientService, logger *logger.Logger) *ClientC
 //	@Summary		Create client
 //	@Description	Client created from JSON or XML, for create endpoint required: name, surname, birthday, gender, address_id
 //	@Tags			clients
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			name		path	string		true	"Client name"
 //	@Param			surname		path	string		true	"Client surname"
 //	@Param			birthday	path	string		true	"Client birthday"
 //	@Param			gender		path	string		true	"Client gender"
 //	@Param			address_id	path	uuid.UUID	true	"Client living address"
+//	@Param			email		path	string		false	"Client email"
+//	@Param			phone		path	string		false	"Client phone"
 //	@Success		201
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/clients [post]
 func (ctrl *ClientController) Create(c *gin.Context) {
 	op := "controllers.clientController.Create"
 	var input dto.ClientRequest
 
-	if err := c.ShouldBind(&input); err != nil {
-		ctrl.logger.Warn("Failed to bind JSON/XML for create", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
+	if !ctrl.bind(c, op, &input) {
 		return
 	}
 
@@ -68,13 +83,16 @@ func (ctrl *ClientController) Create(c *gin.Context) {
 	client, err := mapper.ClientRequestToDomain(input)
 	if err != nil {
 		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid birthday date in request payload"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid birthday date in request payload")
 		return
 	}
 
 	if err := ctrl.service.Create(c.Request.Context(), &client); err != nil {
-		ctrl.logger.Error("Failed to add client", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrAddressIsEmpty:    "Invalid request payload: country, city and street are required",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: gender, birthday, email, phone or address is not valid",
+			crud_errors.ErrDuplicateKeyValue: "email or phone is already used",
+		})
 		return
 	}
 
@@ -87,13 +105,12 @@ func (ctrl *ClientController) Create(c *gin.Context) {
 //	@Summary		Get all client
 //	@Description	That endpoint retrieve all registered client in system
 //	@Tags			clients
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			limit	query		int	false	"limit get data"
 //	@Param			offset	query		int	false	"offset get data"
 //	@Success		200		{array}		dto.Client
 //	@Failure		400		{object}	domain.Error
-//	@Failure		404		{object}	domain.Error
 //	@Failure		500		{object}	domain.Error
 //	@Router			/api/v1/clients [get]
 func (ctrl *ClientController) GetAll(c *gin.Context) {
@@ -101,33 +118,23 @@ func (ctrl *ClientController) GetAll(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
 		return
 	}
 
 	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: offset is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
 		return
 	}
 
 	clients, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
-	if err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid limit or offset parameter", "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0"})
-			return
-		}
-
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("No content", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: no data is contains"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to retrieve clients", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
 		return
 	}
 
@@ -142,41 +149,55 @@ func (ctrl *ClientController) GetAll(c *gin.Context) {
 	ctrl.responce(c, http.StatusOK, output)
 }
 
-// GetClientByNameAndSurname godoc
+// SearchClients godoc
 //
-//	@Summary		Get client filtered by name and surname
-//	@Description	That endpoint retrieve all required registered client in system with gived name and surname
+//	@Summary		Search clients
+//	@Description	That endpoint retrieve registered clients matched by filters. Parameter q is matched partially and fuzzily against name and surname, name, surname and city are compared case-insensitively, country is a country code or an English name. Total count of matched clients is returned in X-Total-Count header
 //	@Tags			clients
-//	@Accept			json
-//	@Produce		json
-//	@Param			name	query		string	true	"client name"
-//	@Param			surname	query		string	true	"client surname"
-//	@Success		200		{array}		dto.Client
-//	@Failure		400		{object}	domain.Error
-//	@Failure		404		{object}	domain.Error
-//	@Failure		500		{object}	domain.Error
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			q				query		string	false	"part of name or surname"
+//	@Param			name			query		string	false	"client name"
+//	@Param			surname			query		string	false	"client surname"
+//	@Param			gender			query		string	false	"client gender"
+//	@Param			birthday_from	query		string	false	"birthday lower bound, YYYY-MM-DD"
+//	@Param			birthday_to		query		string	false	"birthday upper bound, YYYY-MM-DD"
+//	@Param			registered_from	query		string	false	"registration date lower bound, YYYY-MM-DD"
+//	@Param			registered_to	query		string	false	"registration date upper bound, YYYY-MM-DD"
+//	@Param			country			query		string	false	"address country code or name"
+//	@Param			city			query		string	false	"address city"
+//	@Param			sort			query		string	false	"relevance, name, surname, birthday or registration_date"
+//	@Param			order			query		string	false	"asc or desc"
+//	@Param			limit			query		int		false	"limit get data"
+//	@Param			offset			query		int		false	"offset get data"
+//	@Success		200				{array}		dto.ClientResponse
+//	@Header			200				{int}		X-Total-Count	"total count of matched clients"
+//	@Failure		400				{object}	domain.Error
+//	@Failure		500				{object}	domain.Error
 //	@Router			/api/v1/clients/search [get]
-func (ctrl *ClientController) GetByNameAndSurname(c *gin.Context) {
-	op := "controllers.clientController.getByNameAndSurname"
-	name := c.Query("name")
-	surname := c.Query("surname")
+func (ctrl *ClientController) Search(c *gin.Context) {
+	op := "controllers.clientController.Search"
+	var input dto.ClientSearchQuery
 
-	clients, err := ctrl.service.GetByNameAndSurname(c.Request.Context(), name, surname)
-	if err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid value name or surname", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: name and surname cannot be empty"})
-			return
-		}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		ctrl.logger.Warn("Failed to bind search query", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid query received")
+		return
+	}
 
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Client not found", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: client not found"})
-			return
-		}
+	filter, err := mapper.ClientSearchQueryToFilter(input)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid date or order received")
+		return
+	}
 
-		ctrl.logger.Error("Failed to get data from database", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	clients, total, err := ctrl.service.Search(c.Request.Context(), filter)
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit, offset, gender, country or sort is not valid",
+		})
 		return
 	}
 
@@ -187,59 +208,150 @@ func (ctrl *ClientController) GetByNameAndSurname(c *gin.Context) {
 		output[i] = dto
 	}
 
-	ctrl.logger.Debug("Client retrieved", "name", name, "surname", surname, "op", op)
+	ctrl.logger.Debug("Clients found", "total", total, "op", op)
+	c.Header(headerXTotalCount, strconv.Itoa(total))
 	ctrl.responce(c, http.StatusOK, output)
 }
 
+// GetClientById godoc
+//
+//	@Summary		Get client by id
+//	@Description	That endpoint retrieve registered client in system by id
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Client ID"
+//	@Success		200	{object}	dto.ClientResponse
+//	@Header			200	{string}	ETag	"Client version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id} [get]
+func (ctrl *ClientController) GetById(c *gin.Context) {
+	op := "controllers.clientController.GetById"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	client, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "client not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Client retrieved", "id", id, "op", op)
+	setETag(c, client.Version)
+	ctrl.responce(c, http.StatusOK, mapper.ClientDomainToClientResponse(*client))
+}
+
 // UpdateClient godoc
 //
-//	@Summary		Update address on client
-//	@Description	That endpoint update client data (change address on client)
+//	@Summary		Update client
+//	@Description	That endpoint partially update client data, only received fields are changed. Address fields change address on client
 //	@Tags			clients
-//	@Accept			json
-//	@Produce		json
-//	@Param			id		path	uuid.UUID	true	"Client ID"
-//	@Param			address	body	dto.Address	true	"Change address"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path	uuid.UUID				true	"Client ID"
+//	@Param			client		body	dto.ClientUpdateRequest	true	"Changed fields"
+//	@Param			If-Match	header	string					false	"ETag of the client, the update is rejected when it is changed"
 //	@Success		200
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/clients/{id} [patch]
-func (ctrl *ClientController) UpdateAddress(c *gin.Context) {
-	op := "controllers.clientController.UpdateAddress"
+func (ctrl *ClientController) Update(c *gin.Context) {
+	op := "controllers.clientController.Update"
 	rawId := c.Param("id")
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
-	var input dto.Address
+	var input dto.ClientUpdateRequest
 
-	if err := c.ShouldBind(&input); err != nil {
-		ctrl.logger.Warn("Failed to bind JSON/XML for update address", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
+	if !ctrl.bind(c, op, &input) {
 		return
 	}
 
-	newAddress := mapper.AddressToDomain(input)
+	patch, err := mapper.ClientUpdateRequestToPatch(input)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid birthday date in request payload")
+		return
+	}
 
-	if err := ctrl.service.UpdateAddress(c.Request.Context(), id, &newAddress); err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Client not found", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: client not found for update"})
-			return
-		}
+	ctrl.update(c, op, id, &patch)
+}
 
-		if errors.Is(err, crud_errors.ErrAddressIsEmpty) {
-			ctrl.logger.Debug("Payload is empty", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
-			return
-		}
+// ReplaceClient godoc
+//
+//	@Summary		Replace client
+//	@Description	That endpoint replace all client profile fields, for replace endpoint required: name, surname, birthday, gender
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path	uuid.UUID			true	"Client ID"
+//	@Param			client		body	dto.ClientRequest	true	"Client data"
+//	@Param			If-Match	header	string				false	"ETag of the client, the update is rejected when it is changed"
+//	@Success		200
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id} [put]
+func (ctrl *ClientController) Replace(c *gin.Context) {
+	op := "controllers.clientController.Replace"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ClientRequest
 
-		ctrl.logger.Error("Failed to update address ID", "id", id, logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	patch, err := mapper.ClientRequestToPatch(input)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid birthday date in request payload")
+		return
+	}
+
+	ctrl.update(c, op, id, &patch)
+}
+
+func (ctrl *ClientController) update(c *gin.Context, op string, id uuid.UUID, patch *domain.ClientPatch) {
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	patch.Version = version
+	if err := ctrl.service.Update(c.Request.Context(), id, patch); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:          "client not found for update",
+			crud_errors.ErrAddressIsEmpty:    "Invalid request payload: invalid data received",
+			crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: gender, birthday, email, phone or address is not valid",
+			crud_errors.ErrDuplicateKeyValue: "email or phone is already used",
+			crud_errors.ErrVersionMismatch:   "client is changed, get it again",
+		})
 		return
 	}
 
@@ -252,11 +364,13 @@ func (ctrl *ClientController) UpdateAddress(c *gin.Context) {
 //	@Summary		Delete client from system
 //	@Description	That methods deleting registered client in system by id
 //	@Tags			clients
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path	uuid.UUID	true	"client id"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"client id"
+//	@Param			If-Match	header	string		false	"ETag of the client, the delete is rejected when it is changed"
 //	@Success		204
 //	@Failure		400	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/clients/{id} [delete]
 func (ctrl *ClientController) Delete(c *gin.Context) {
@@ -265,22 +379,108 @@ func (ctrl *ClientController) Delete(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
-	if err := ctrl.service.Delete(c.Request.Context(), id); err != nil {
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
 		if errors.Is(err, crud_errors.ErrNotFound) {
 			ctrl.logger.Debug("Client not found", "op", op)
 			c.Status(http.StatusNoContent)
 			return
 		}
 
-		ctrl.logger.Error("Failed delete client by id", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "client is changed, get it again",
+		})
 		return
 	}
 
 	ctrl.logger.Debug("Client deleted", "id", id, "op", op)
 	c.Status(http.StatusNoContent)
 }
+
+// GetClientAddresss godoc
+//
+//	@Summary		Get client addresss
+//	@Description	That endpoint retrieve address book of client, default address goes first
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Client ID"
+//	@Success		200	{array}		dto.AddressBookEntryResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/addresses [get]
+func (ctrl *ClientController) GetAddresses(c *gin.Context) {
+	ctrl.addresses.list(c, "controllers.clientController.GetAddresses")
+}
+
+// AddClientAddress godoc
+//
+//	@Summary		Add client address
+//	@Description	That endpoint add address to client, the first address becomes default. Same address is reused
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID					true	"Client ID"
+//	@Param			address	body		dto.AddressBookEntryRequest	true	"Labeled address"
+//	@Success		201		{object}	dto.AddressBookEntryResponse
+//	@Header			201		{string}	ETag	"Client address version"
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/addresses [post]
+func (ctrl *ClientController) AddAddress(c *gin.Context) {
+	ctrl.addresses.create(c, "controllers.clientController.AddAddress")
+}
+
+// UpdateClientAddress godoc
+//
+//	@Summary		Update client address
+//	@Description	That endpoint change label of address or make it default
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID							true	"Client ID"
+//	@Param			address_id	path	uuid.UUID							true	"Address ID"
+//	@Param			address		body	dto.AddressBookEntryUpdateRequest	true	"Changed fields"
+//	@Param			If-Match	header	string								false	"ETag of the address, the update is rejected when it is changed"
+//	@Success		200
+//	@Header			200	{string}	ETag	"Client address version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/addresses/{address_id} [patch]
+func (ctrl *ClientController) UpdateAddress(c *gin.Context) {
+	ctrl.addresses.change(c, "controllers.clientController.UpdateAddress")
+}
+
+// RemoveClientAddress godoc
+//
+//	@Summary		Remove client address
+//	@Description	That endpoint remove address from client, address itself is deleted when nobody uses it
+//	@Tags			clients
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Client ID"
+//	@Param			address_id	path	uuid.UUID	true	"Address ID"
+//	@Param			If-Match	header	string		false	"ETag of the address, the removal is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/addresses/{address_id} [delete]
+func (ctrl *ClientController) RemoveAddress(c *gin.Context) {
+	ctrl.addresses.delete(c, "controllers.clientController.RemoveAddress")
+}
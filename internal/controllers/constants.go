@@ -2,7 +2,30 @@ package controllers
 
 const (
 	contentTypeOctetStream = "application/octet-stream"
+	contentTypeProblemJSON = "application/problem+json"
+	contentTypeProblemXML  = "application/problem+xml"
+	mimeJSON               = "application/json"
+	mimeMergePatch         = "application/merge-patch+json"
+	mimeXML                = "application/xml"
+	mimeMsgPack            = "application/msgpack"
+	mimeYAML               = "application/yaml"
+	mimeYAMLText           = "text/yaml"
+	mimeCSV                = "text/csv"
+	problemTypeBase        = "/problems/"
+	problemTypeBlank       = "about:blank"
+	headerXRequestId       = "X-Request-Id"
+	ctxRequestId           = "request_id"
+	ctxMediaType           = "media_type"
+	maxRequestIdLen        = 128
 	headerXImageTitle      = "X-Image-Title"
+	headerXTotalCount      = "X-Total-Count"
+	headerIdempotencyKey   = "Idempotency-Key"
+	headerReplayed         = "Idempotent-Replayed"
+	headerETag             = "ETag"
+	headerLocation         = "Location"
+	headerIfMatch          = "If-Match"
 	defaultLimit           = "10"
 	defaultOffset          = "0"
+	maxBatchOperations     = 100
+	maxIdempotencyKeyLen   = 255
 )
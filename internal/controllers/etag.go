@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match entity tag")
+
+// setETag puts the entity version to the ETag header as a strong tag.
+func setETag(c *gin.Context, version int64) {
+	c.Header(headerETag, `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// parseIfMatch returns the version from the If-Match header, 0 when the
+// header is missing or "*" and the version is not checked.
+func parseIfMatch(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	tag, ok := strings.CutPrefix(value, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// expectedVersion reads If-Match of the request, a malformed header is
+// answered with 400 and false is returned.
+func (ctrl *BaseController) expectedVersion(c *gin.Context, op string) (int64, bool) {
+	version, err := parseIfMatch(c.GetHeader(headerIfMatch))
+	if err != nil {
+		ctrl.logger.Warn("Invalid If-Match header", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request: If-Match is not a valid entity tag")
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type idempotencyService interface {
+	Fingerprint(method, path string, body []byte) string
+	Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotentResponse, error)
+	Complete(ctx context.Context, key string, response domain.IdempotentResponse) error
+	Abort(ctx context.Context, key string) error
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes POST and PATCH requests with the Idempotency-Key
+// header safe to retry: the first response is stored and replayed for repeated
+// requests with the key. The body is hashed up to maxBytes, the largest body
+// accepted by any route.
+type IdempotencyMiddleware struct {
+	*BaseController
+	service  idempotencyService
+	maxBytes int64
+}
+
+func NewIdempotencyMiddleware(service idempotencyService, maxBytes int64, logger *logger.Logger) *IdempotencyMiddleware {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Idempotency middleware is created", "max bytes", maxBytes)
+	return &IdempotencyMiddleware{
+		BaseController: controller,
+		service:        service,
+		maxBytes:       maxBytes,
+	}
+}
+
+func (m *IdempotencyMiddleware) Handle(c *gin.Context) {
+	op := "controllers.idempotencyMiddleware.Handle"
+
+	key := c.GetHeader(headerIdempotencyKey)
+	if key == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+		c.Next()
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLen {
+		m.logger.Warn("Idempotency key is too long", "op", op)
+		m.problem(c, http.StatusBadRequest, "Invalid request: Idempotency-Key is longer than 255 characters")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, m.maxBytes))
+	if err != nil {
+		m.logger.Warn("Failed to read request body", logger.Err(err), "op", op)
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			m.problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Invalid request payload: body exceeds %d bytes", m.maxBytes))
+			return
+		}
+
+		m.problem(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	fingerprint := m.service.Fingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+	stored, err := m.service.Begin(c.Request.Context(), key, fingerprint)
+	if err != nil {
+		m.fail(c, op, err, problemDetails{
+			crud_errors.ErrIdempotencyKeyReused:     "Idempotency-Key is already used with another request",
+			crud_errors.ErrIdempotencyKeyInProgress: "request with the Idempotency-Key is in progress",
+		})
+		return
+	}
+
+	if stored != nil {
+		c.Header(headerReplayed, "true")
+		if stored.ETag != "" {
+			c.Header(headerETag, stored.ETag)
+		}
+
+		if stored.Location != "" {
+			c.Header(headerLocation, stored.Location)
+		}
+
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+		return
+	}
+
+	writer := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+
+	// the outcome is saved even when the client is gone, it retries then
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	defer func() {
+		// a panicking handler releases the key before the recovery answers 500
+		if r := recover(); r != nil {
+			m.abort(ctx, key)
+			panic(r)
+		}
+	}()
+
+	c.Next()
+
+	// a request rejected by Accept has changed nothing, it may be retried with
+	// another Accept
+	if writer.Status() >= http.StatusInternalServerError || writer.Status() == http.StatusNotAcceptable {
+		m.abort(ctx, key)
+		return
+	}
+
+	response := domain.IdempotentResponse{
+		Status:      writer.Status(),
+		ContentType: writer.Header().Get("Content-Type"),
+		ETag:        writer.Header().Get(headerETag),
+		Location:    writer.Header().Get(headerLocation),
+		Body:        writer.body.Bytes(),
+	}
+
+	if err := m.service.Complete(ctx, key, response); err != nil {
+		m.logger.Error("Failed to store idempotent response", logger.Err(err), "op", op)
+	}
+}
+
+// abort releases the key, so the request can be retried with it.
+func (m *IdempotencyMiddleware) abort(ctx context.Context, key string) {
+	if err := m.service.Abort(ctx, key); err != nil {
+		m.logger.Error("Failed to release idempotency key", logger.Err(err), "op", "controllers.idempotencyMiddleware.abort")
+	}
+}
@@ -21,7 +21,7 @@ type imageService interface {
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Image, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Image, error)
 	Update(ctx context.Context, image *domain.Image) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
 }
 
 // func getImageBuffer(file multipart.File) ([]byte, error) {
@@ -49,14 +49,15 @@ func NewImageController(service imageService, logger *logger.Logger) *ImageContr
 // CreateImage godoc
 //
 //	@Summary		Create image
-//	@Description	Image created from JSON or XML, for create endpoint required: image
+//	@Description	Image created from raw bytes, for create endpoint required: image. Metadata is extracted and EXIF is stripped from jpeg
 //	@Tags			images
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			image	body	domain.Image	true	"Image data"
 //	@Success		201
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		413	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/images [post]
 func (ctrl *ImageController) Create(c *gin.Context) {
@@ -66,7 +67,7 @@ func (ctrl *ImageController) Create(c *gin.Context) {
 
 	if c.ContentType() != contentTypeOctetStream {
 		ctrl.logger.Warn("Invalid content-type", "got", c.ContentType(), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"message": "Expected multipart/form-data"})
+		ctrl.problem(c, http.StatusBadRequest, "Expected multipart/form-data")
 		return
 	}
 
@@ -74,21 +75,21 @@ func (ctrl *ImageController) Create(c *gin.Context) {
 
 	if title == "" {
 		ctrl.logger.Warn("Empty title", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "MetaData \"X-Image-Title\" is empty"})
+		ctrl.problem(c, http.StatusBadRequest, "MetaData \"X-Image-Title\" is empty")
 		return
 	}
 
 	rawImage, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		ctrl.logger.Warn("Failed to read image bytes", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid image in request body"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid image in request body")
 		return
 	}
 
 	// rawImage, err := c.FormFile("image")
 	// if err != nil {
 	// 	ctrl.logger.Warn("Failed to read form file", logger.Err(err), "op", op)
-	// 	ctrl.responce(c, http.StatusBadRequest, gin.H{"message": "Image not found in form-data"})
+	// 	ctrl.problem(c, http.StatusBadRequest, "Image not found in form-data")
 	// 	return
 	// }
 
@@ -113,14 +114,10 @@ func (ctrl *ImageController) Create(c *gin.Context) {
 	}
 
 	if err := ctrl.service.Create(c.Request.Context(), &image); err != nil {
-		if errors.Is(err, crud_errors.ErrImageCorruption) {
-			ctrl.logger.Warn("Image data is corrupted", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid payload or image is corrapted"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to add image", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrImageCorruption: "Invalid payload or image is corrapted",
+			crud_errors.ErrImageTooLarge:   "Invalid payload: image dimensions are too large",
+		})
 		return
 	}
 
@@ -133,13 +130,12 @@ func (ctrl *ImageController) Create(c *gin.Context) {
 //	@Summary		Get all images
 //	@Description	The endpoint for retrieve all registered images in system
 //	@Tags			images
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			limit	query		int	true	"limit get images"
 //	@Param			offset	query		int	true	"offset get images"
 //	@Success		200		{array}		dto.Image
 //	@Failure		400		{object}	domain.Error
-//	@Failure		404		{object}	domain.Error
 //	@Failure		500		{object}	domain.Error
 //	@Router			/api/v1/images [get]
 func (ctrl *ImageController) GetAll(c *gin.Context) {
@@ -147,33 +143,23 @@ func (ctrl *ImageController) GetAll(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
 		return
 	}
 
 	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: offset is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
 		return
 	}
 
 	images, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
-	if err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid limit or offset parameter", "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0"})
-			return
-		}
-
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("No content", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNoContent, gin.H{"massage": "404: no data is contains"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to retrive images", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
 		return
 	}
 
@@ -193,10 +179,11 @@ func (ctrl *ImageController) GetAll(c *gin.Context) {
 //	@Summary		Get all images
 //	@Description	The endpoint for retrieve registered image in system by id
 //	@Tags			images
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			id	path		uuid.UUID	true	"Image ID"
 //	@Success		200	{object}	dto.Image
+//	@Header			200	{string}	ETag	"Image version"
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
@@ -207,25 +194,21 @@ func (ctrl *ImageController) GetById(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
 		return
 	}
 
 	image, err := ctrl.service.GetById(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("image not found", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: image not found"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to get data from database", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "image not found",
+		})
 		return
 	}
 
 	output := mapper.ImageDomainToImageResponse(*image)
 	ctrl.logger.Debug("Image retrieved", "id", id, "op", op)
+	setETag(c, image.Version)
 	ctrl.responce(c, http.StatusOK, output)
 }
 
@@ -234,13 +217,16 @@ func (ctrl *ImageController) GetById(c *gin.Context) {
 //	@Summary		Update image
 //	@Description	The endpoint for updating image data by ID to a new image given by the user
 //	@Tags			images
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			id		path	uuid.UUID		true	"Image ID"
-//	@Param			image	body	domain.Image	true	"New image data"
+//	@Param			image		body	domain.Image	true	"New image data"
+//	@Param			If-Match	header	string			false	"ETag of the image, the update is rejected when it is changed"
 //	@Success		200
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		413	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/images/{id} [patch]
 func (ctrl *ImageController) Update(c *gin.Context) {
@@ -248,7 +234,7 @@ func (ctrl *ImageController) Update(c *gin.Context) {
 
 	if c.ContentType() != contentTypeOctetStream {
 		ctrl.logger.Warn("Invalid content-type", "got", c.ContentType(), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"message": "Expected multipart/form-data"})
+		ctrl.problem(c, http.StatusBadRequest, "Expected multipart/form-data")
 		return
 	}
 
@@ -256,7 +242,12 @@ func (ctrl *ImageController) Update(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
 		return
 	}
 
@@ -264,20 +255,20 @@ func (ctrl *ImageController) Update(c *gin.Context) {
 	rawImage, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		ctrl.logger.Warn("Failed to read image bytes", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid image in request body"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid image in request body")
 		return
 	}
 
 	if len(rawImage) == 0 {
 		ctrl.logger.Warn("Image not recived", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: empty image data"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: empty image data")
 		return
 	}
 
 	// rawImage, err := c.FormFile("image")
 	// if err != nil {
 	// 	ctrl.logger.Warn("Failed to read form file", logger.Err(err), "op", op)
-	// 	ctrl.responce(c, http.StatusBadRequest, gin.H{"message": "Image not found in form-data"})
+	// 	ctrl.problem(c, http.StatusBadRequest, "Image not found in form-data")
 	// 	return
 	// }
 
@@ -297,26 +288,19 @@ func (ctrl *ImageController) Update(c *gin.Context) {
 	// }
 
 	image := domain.Image{
-		Id:    id,
-		Title: title,
-		Data:  rawImage,
+		Id:      id,
+		Title:   title,
+		Data:    rawImage,
+		Version: version,
 	}
 
 	if err := ctrl.service.Update(c.Request.Context(), &image); err != nil {
-		if errors.Is(err, crud_errors.ErrImageCorruption) {
-			ctrl.logger.Warn("Invalid image data", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid image or image is corrupted"})
-			return
-		}
-
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Image not found", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: image not found for update"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to update image", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrImageCorruption: "Invalid request payload: invalid image or image is corrupted",
+			crud_errors.ErrImageTooLarge:   "Invalid request payload: image dimensions are too large",
+			crud_errors.ErrNotFound:        "image not found for update",
+			crud_errors.ErrVersionMismatch: "image is changed, get it again",
+		})
 		return
 	}
 
@@ -329,12 +313,14 @@ func (ctrl *ImageController) Update(c *gin.Context) {
 //	@Summary		Delete image
 //	@Description	The endpoint for deleting image data by ID
 //	@Tags			images
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path	uuid.UUID	true	"Image ID"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Image ID"
+//	@Param			If-Match	header	string		false	"ETag of the image, the delete is rejected when it is changed"
 //	@Success		204
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/images/{id} [delete]
 func (ctrl *ImageController) Delete(c *gin.Context) {
@@ -343,19 +329,25 @@ func (ctrl *ImageController) Delete(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
 		return
 	}
 
-	if err := ctrl.service.Delete(c.Request.Context(), id); err != nil {
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
 		if errors.Is(err, crud_errors.ErrNotFound) {
 			ctrl.logger.Debug("No content for this id", "id", id, "op", op)
 			c.Status(http.StatusNoContent)
 			return
 		}
 
-		ctrl.logger.Error("Failed to delete image from database", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "image is changed, get it again",
+		})
 		return
 	}
 
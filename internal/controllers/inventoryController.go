@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type inventoryService interface {
+	GetLowStock(ctx context.Context, supplierId *uuid.UUID, limit, offset int) ([]domain.LowStockItem, int, error)
+	GetReorderSuggestions(ctx context.Context, supplierId *uuid.UUID) ([]domain.ReorderSuggestion, error)
+}
+
+type InventoryController struct {
+	*BaseController
+	service inventoryService
+}
+
+func NewInventoryController(service inventoryService, logger *logger.Logger) *InventoryController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Inventory controller is created")
+	return &InventoryController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// GetLowStock godoc
+//
+//	@Summary		Get products low on stock
+//	@Description	That endpoint retrieve products with the stock at or below their reorder threshold ordered by supplier and name, with the quantity bringing the stock to the reorder target. low_since is set when a stock change or the background evaluator flagged the product. Total count is returned in X-Total-Count header
+//	@Tags			inventory
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml,text/csv
+//	@Param			supplier_id	query		uuid.UUID	false	"only products of the supplier"
+//	@Param			limit		query		int			false	"limit get data"
+//	@Param			offset		query		int			false	"offset get data"
+//	@Success		200			{array}		dto.LowStockItemResponse
+//	@Header			200			{int}		X-Total-Count	"total count of products low on stock"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/inventory/low-stock [get]
+func (ctrl *InventoryController) GetLowStock(c *gin.Context) {
+	op := "controllers.inventoryController.GetLowStock"
+	var input dto.LowStockQuery
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		ctrl.logger.Warn("Failed to bind low stock query", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid query received")
+		return
+	}
+
+	supplierId, ok := ctrl.supplierId(c, op, input.SupplierId)
+	if !ok {
+		return
+	}
+
+	items, total, err := ctrl.service.GetLowStock(c.Request.Context(), supplierId, input.Limit, input.Offset)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+			crud_errors.ErrNotFound:     "supplier not found",
+		})
+		return
+	}
+
+	output := make([]dto.LowStockItemResponse, len(items))
+
+	for i, item := range items {
+		output[i] = mapper.LowStockItemToResponse(item)
+	}
+
+	ctrl.logger.Debug("Low stock retrieved", "total", total, "op", op)
+	c.Header(headerXTotalCount, strconv.Itoa(total))
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// GetReorderSuggestions godoc
+//
+//	@Summary		Get reorder suggestions
+//	@Description	That endpoint drafts purchase orders: products low on stock grouped by supplier with the quantity bringing each of them to the reorder target. The lines are exported as CSV or JSON Lines by /api/v1/export/purchase-orders
+//	@Tags			inventory
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			supplier_id	query		uuid.UUID	false	"only the order of the supplier"
+//	@Success		200			{array}		dto.ReorderSuggestionResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/inventory/reorder-suggestions [get]
+func (ctrl *InventoryController) GetReorderSuggestions(c *gin.Context) {
+	op := "controllers.inventoryController.GetReorderSuggestions"
+	var input dto.ReorderSuggestionQuery
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		ctrl.logger.Warn("Failed to bind reorder suggestion query", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid query received")
+		return
+	}
+
+	supplierId, ok := ctrl.supplierId(c, op, input.SupplierId)
+	if !ok {
+		return
+	}
+
+	suggestions, err := ctrl.service.GetReorderSuggestions(c.Request.Context(), supplierId)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "supplier not found",
+		})
+		return
+	}
+
+	output := make([]dto.ReorderSuggestionResponse, len(suggestions))
+
+	for i, suggestion := range suggestions {
+		output[i] = mapper.ReorderSuggestionToResponse(suggestion)
+	}
+
+	ctrl.logger.Debug("Reorder suggestions retrieved", "suppliers", len(output), "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// supplierId parses the optional supplier_id query, nil is returned when it
+// is not given.
+func (ctrl *InventoryController) supplierId(c *gin.Context, op, raw string) (*uuid.UUID, bool) {
+	if raw == "" {
+		return nil, true
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		ctrl.logger.Warn("The received supplier identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: supplier_id is not valid")
+		return nil, false
+	}
+
+	return &id, true
+}
@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const jobsPath = "/api/v1/jobs/"
+
+type jobService interface {
+	Submit(ctx context.Context, job *domain.Job) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+	GetOutput(ctx context.Context, id uuid.UUID) ([]byte, string, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+}
+
+type JobController struct {
+	*BaseController
+	service  jobService
+	maxBytes int64
+}
+
+func NewJobController(service jobService, maxBytes int64, logger *logger.Logger) *JobController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Job controller is created")
+	return &JobController{
+		BaseController: controller,
+		service:        service,
+		maxBytes:       maxBytes,
+	}
+}
+
+func jobToResponse(job domain.Job) dto.JobResponse {
+	output := mapper.JobToResponse(job)
+	if job.HasOutput {
+		output.ResultUrl = jobsPath + job.Id.String() + "/output"
+	}
+
+	return output
+}
+
+// SubmitImport godoc
+//
+//	@Summary		Queue import of catalog data
+//	@Description	That endpoint stores the uploaded CSV or JSON Lines file and queues its import, the import report is the job result
+//	@Tags			jobs
+//	@Accept			text/csv,application/x-ndjson
+//	@Produce		json
+//	@Param			entity	path		string	true	"products, suppliers or clients"
+//	@Param			dry_run	query		bool	false	"validate rows without saving"
+//	@Success		202		{object}	dto.JobResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		413		{object}	domain.Error
+//	@Failure		415		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/jobs/import/{entity} [post]
+func (ctrl *JobController) SubmitImport(c *gin.Context) {
+	op := "controllers.jobController.SubmitImport"
+
+	format, ok := tabular.FormatFromContentType(c.GetHeader("Content-Type"))
+	if !ok {
+		ctrl.logger.Warn("Invalid content-type", "got", c.ContentType(), "op", op)
+		ctrl.problem(c, http.StatusUnsupportedMediaType, "Expected text/csv or application/x-ndjson")
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert dry_run value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: dry_run is not valid")
+		return
+	}
+
+	input, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, ctrl.maxBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctrl.logger.Warn("Import data is too large", "op", op)
+			ctrl.problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Invalid request payload: body exceeds %d bytes", ctrl.maxBytes))
+			return
+		}
+
+		ctrl.logger.Warn("Failed read import data", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	ctrl.submit(c, op, &domain.Job{
+		Kind: domain.JobImport,
+		Params: map[string]string{
+			"entity":  c.Param("entity"),
+			"format":  string(format),
+			"dry_run": strconv.FormatBool(dryRun),
+		},
+		Input: input,
+	})
+}
+
+// SubmitExport godoc
+//
+//	@Summary		Queue export of catalog data
+//	@Description	That endpoint queues export of all products, suppliers or clients or the purchase order lines of products low on stock, the file is downloaded from result_url of the finished job
+//	@Tags			jobs
+//	@Produce		json
+//	@Param			entity	path		string	true	"products, suppliers, clients or purchase-orders"
+//	@Param			format	query		string	false	"csv, ndjson or jsonl"
+//	@Success		202		{object}	dto.JobResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/jobs/export/{entity} [post]
+func (ctrl *JobController) SubmitExport(c *gin.Context) {
+	op := "controllers.jobController.SubmitExport"
+
+	ctrl.submit(c, op, &domain.Job{
+		Kind: domain.JobExport,
+		Params: map[string]string{
+			"entity": c.Param("entity"),
+			"format": c.DefaultQuery("format", string(tabular.CSV)),
+		},
+	})
+}
+
+// SubmitAddressPurge godoc
+//
+//	@Summary		Queue deletion of orphan addresses
+//	@Description	That endpoint queues deletion of addresses no client or supplier refers to
+//	@Tags			jobs
+//	@Produce		json
+//	@Success		202	{object}	dto.JobResponse
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/jobs/address-purge [post]
+func (ctrl *JobController) SubmitAddressPurge(c *gin.Context) {
+	op := "controllers.jobController.SubmitAddressPurge"
+
+	ctrl.submit(c, op, &domain.Job{Kind: domain.JobAddressPurge})
+}
+
+// SubmitImageReprocess godoc
+//
+//	@Summary		Queue reprocessing of stored images
+//	@Description	That endpoint queues stripping of metadata and extraction of width, height, format, size and dominant color of every stored image. The result counts updated images, skipped images which are unchanged or replaced meanwhile and failed images which cannot be decoded
+//	@Tags			jobs
+//	@Produce		json
+//	@Success		202	{object}	dto.JobResponse
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/jobs/image-reprocess [post]
+func (ctrl *JobController) SubmitImageReprocess(c *gin.Context) {
+	op := "controllers.jobController.SubmitImageReprocess"
+
+	ctrl.submit(c, op, &domain.Job{Kind: domain.JobImageReprocess})
+}
+
+func (ctrl *JobController) submit(c *gin.Context, op string, job *domain.Job) {
+	if err := ctrl.service.Submit(c.Request.Context(), job); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:    "Invalid request payload: body is empty",
+			crud_errors.ErrInvalidParam: "Invalid request payload: entity or format is not valid",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Job is queued", "id", job.Id, "kind", job.Kind, "op", op)
+	c.Header(headerLocation, jobsPath+job.Id.String())
+	ctrl.responce(c, http.StatusAccepted, jobToResponse(*job))
+}
+
+// GetJob godoc
+//
+//	@Summary		Get job by ID
+//	@Description	That endpoint retrieve status, progress, result and error of the job
+//	@Tags			jobs
+//	@Produce		json
+//	@Param			id	path		uuid.UUID	true	"Job ID"
+//	@Success		200	{object}	dto.JobResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/jobs/{id} [get]
+func (ctrl *JobController) GetById(c *gin.Context) {
+	op := "controllers.jobController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	job, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "job not found",
+		})
+		return
+	}
+
+	ctrl.responce(c, http.StatusOK, jobToResponse(*job))
+}
+
+// GetJobOutput godoc
+//
+//	@Summary		Download job output
+//	@Description	That endpoint returns the file produced by the finished job
+//	@Tags			jobs
+//	@Produce		text/csv,application/x-ndjson
+//	@Param			id	path		uuid.UUID	true	"Job ID"
+//	@Success		200	{string}	string
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/jobs/{id}/output [get]
+func (ctrl *JobController) GetOutput(c *gin.Context) {
+	op := "controllers.jobController.GetOutput"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	output, contentType, err := ctrl.service.GetOutput(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "job has no output",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, output)
+}
+
+// CancelJob godoc
+//
+//	@Summary		Cancel job
+//	@Description	That endpoint cancels the queued job at once and asks the worker to stop the running one, 202 is returned then
+//	@Tags			jobs
+//	@Produce		json
+//	@Param			id	path		uuid.UUID	true	"Job ID"
+//	@Success		200	{object}	dto.JobResponse
+//	@Success		202	{object}	dto.JobResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/jobs/{id}/cancel [post]
+func (ctrl *JobController) Cancel(c *gin.Context) {
+	op := "controllers.jobController.Cancel"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	job, err := ctrl.service.Cancel(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:    "job not found",
+			crud_errors.ErrJobFinished: "job is already finished",
+		})
+		return
+	}
+
+	if job.Status == domain.JobCanceled {
+		ctrl.responce(c, http.StatusOK, jobToResponse(*job))
+		return
+	}
+
+	ctrl.responce(c, http.StatusAccepted, jobToResponse(*job))
+}
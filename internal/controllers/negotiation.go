@@ -0,0 +1,354 @@
+package controllers
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// responseTypes are media types of responses in order of preference, list
+// routes serve CSV too.
+var (
+	responseTypes     = []string{mimeJSON, mimeXML, mimeMsgPack, mimeYAML}
+	listResponseTypes = append(responseTypes[:len(responseTypes):len(responseTypes)], mimeCSV)
+)
+
+// mediaRange is a range of the Accept header.
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// parseAccept returns ranges of the Accept header, ranges with a malformed
+// q-value are skipped.
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(raw, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+
+	return ranges
+}
+
+// quality returns the q-value of the most specific range matched by the media
+// type and the specificity of the range, -1 when nothing is matched.
+func quality(ranges []mediaRange, mediaType string) (float64, int) {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+
+	for _, r := range ranges {
+		current := -1
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			current = 2
+		case r.typ == typ && r.subtype == "*":
+			current = 1
+		case r.typ == "*" && r.subtype == "*":
+			current = 0
+		}
+
+		if current > specificity {
+			q, specificity = r.q, current
+		}
+	}
+
+	return q, specificity
+}
+
+// negotiate picks the offered media type with the highest q-value in the
+// Accept header, the order of offers breaks ties. A missing header accepts
+// the first offer.
+func negotiate(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	ranges := parseAccept(accept)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := quality(ranges, offer)
+		if specificity >= 0 && q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, best != ""
+}
+
+// Negotiate picks the response type of a route serving one entity before its
+// handler runs, requests which accept none of the types get 406 and change
+// nothing.
+func Negotiate(c *gin.Context) {
+	negotiateRoute(c, responseTypes)
+}
+
+// NegotiateList picks the response type of a route serving a list, CSV is
+// offered too.
+func NegotiateList(c *gin.Context) {
+	negotiateRoute(c, listResponseTypes)
+}
+
+func negotiateRoute(c *gin.Context, offers []string) {
+	c.Header("Vary", "Accept")
+
+	mediaType, ok := negotiate(c.GetHeader("Accept"), offers)
+	if !ok {
+		writeProblem(c, notAcceptable(offers))
+		return
+	}
+
+	c.Set(ctxMediaType, mediaType)
+	c.Next()
+}
+
+// encode writes the object with the encoder after a JSON round trip, so names
+// of fields and formats of values are the same as in JSON.
+func (ctrl *BaseController) encode(c *gin.Context, statusCode int, mediaType string, obj any, encoder func(w io.Writer, value any) error) {
+	op := "controllers.baseController.encode"
+
+	value, err := plainValue(obj)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = encoder(&buf, value); err == nil {
+			c.Data(statusCode, mediaType, buf.Bytes())
+			return
+		}
+	}
+
+	ctrl.fail(c, op, err, nil)
+}
+
+// xmlList is the root element of lists served as XML.
+type xmlList struct {
+	XMLName xml.Name `xml:"items"`
+	Items   any      `xml:"item"`
+}
+
+// plainValue converts the object to maps, slices and scalars by its JSON,
+// integers stay integers.
+func plainValue(obj any) (any, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return plainNumbers(value), nil
+}
+
+func plainNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = plainNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = plainNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		f, _ := v.Float64()
+		return f
+	}
+
+	return value
+}
+
+// bind decodes the request body by its Content-Type and validates it. An
+// unsupported type is answered with 415 and a malformed body with 400, false
+// is returned in both cases.
+func (ctrl *BaseController) bind(c *gin.Context, op string, obj any) bool {
+	var err error
+
+	switch c.ContentType() {
+	case "", mimeJSON, mimeMergePatch:
+		err = c.ShouldBindWith(obj, binding.JSON)
+	case mimeXML, binding.MIMEXML2:
+		err = c.ShouldBindWith(obj, binding.XML)
+	case mimeMsgPack, binding.MIMEMSGPACK:
+		err = ctrl.bindConverted(c, obj, func(r io.Reader, value *any) error {
+			handle := codec.MsgpackHandle{}
+			handle.RawToString = true
+			return codec.NewDecoder(r, &handle).Decode(value)
+		})
+	case mimeYAML, binding.MIMEYAML, mimeYAMLText:
+		err = ctrl.bindConverted(c, obj, func(r io.Reader, value *any) error {
+			var node yaml.Node
+			if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+				return err
+			}
+
+			*value, err = yamlValue(&node)
+			return err
+		})
+	default:
+		ctrl.logger.Warn("Unsupported content type", "got", c.ContentType(), "op", op)
+		ctrl.problem(c, http.StatusUnsupportedMediaType, "Expected application/json, application/xml, application/msgpack or application/yaml")
+		return false
+	}
+
+	if err != nil {
+		ctrl.invalidPayload(c, op, err)
+		return false
+	}
+
+	return true
+}
+
+// bindConverted decodes the body to plain values, converts them to JSON and
+// binds it, so the body has the same names of fields as JSON.
+func (ctrl *BaseController) bindConverted(c *gin.Context, obj any, decode func(r io.Reader, value *any) error) error {
+	var value any
+	if err := decode(c.Request.Body, &value); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(stringKeys(value))
+	if err != nil {
+		return err
+	}
+
+	return binding.JSON.BindBody(raw, obj)
+}
+
+// stringKeys replaces maps with interface keys made by msgpack with string keyed
+// ones, JSON does not encode them.
+func stringKeys(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[toString(key)] = stringKeys(item)
+		}
+
+		return converted
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringKeys(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+	case []byte:
+		return string(v)
+	}
+
+	return value
+}
+
+// yamlValue converts the node to plain values, timestamps stay strings as the
+// dto parse dates themselves.
+func yamlValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.MappingNode:
+		mapping := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			item, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			mapping[node.Content[i].Value] = item
+		}
+
+		return mapping, nil
+	case yaml.SequenceNode:
+		sequence := make([]any, len(node.Content))
+		for i, child := range node.Content {
+			item, err := yamlValue(child)
+			if err != nil {
+				return nil, err
+			}
+
+			sequence[i] = item
+		}
+
+		return sequence, nil
+	}
+
+	if node.ShortTag() == "!!timestamp" {
+		return node.Value, nil
+	}
+
+	var value any
+	err := node.Decode(&value)
+	return value, err
+}
+
+func toString(key any) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case []byte:
+		return string(k)
+	}
+
+	raw, _ := json.Marshal(key)
+	return string(raw)
+}
+
+func notAcceptable(offers []string) domain.Error {
+	last := len(offers) - 1
+	return domain.Error{
+		Type:   problemTypeBlank,
+		Title:  http.StatusText(http.StatusNotAcceptable),
+		Status: http.StatusNotAcceptable,
+		Detail: "Expected " + strings.Join(offers[:last], ", ") + " or " + offers[last],
+	}
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type orderService interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	GetByClient(ctx context.Context, clientId uuid.UUID, limit, offset int) ([]domain.Order, error)
+}
+
+type OrderController struct {
+	*BaseController
+	service orderService
+}
+
+func NewOrderController(service orderService, logger *logger.Logger) *OrderController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Order controller is created")
+	return &OrderController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// GetOrder godoc
+//
+//	@Summary		Get order by ID
+//	@Description	That endpoint retrieve the order placed by a checkout with the prices of the checkout
+//	@Tags			orders
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Order ID"
+//	@Success		200	{object}	dto.OrderResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/orders/{id} [get]
+func (ctrl *OrderController) GetById(c *gin.Context) {
+	op := "controllers.orderController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	order, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "order not found",
+		})
+		return
+	}
+
+	ctrl.responce(c, http.StatusOK, mapper.OrderToResponse(*order))
+}
+
+// GetClientOrders godoc
+//
+//	@Summary		Get client orders
+//	@Description	That endpoint retrieve orders of the client, the latest first
+//	@Tags			orders
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID	true	"Client ID"
+//	@Param			limit	query		int			false	"limit get orders"
+//	@Param			offset	query		int			false	"offset get orders"
+//	@Success		200		{array}		dto.OrderResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/clients/{id}/orders [get]
+func (ctrl *OrderController) GetByClient(c *gin.Context) {
+	op := "controllers.orderController.GetByClient"
+	clientId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	orders, err := ctrl.service.GetByClient(c.Request.Context(), clientId, limit, offset)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+			crud_errors.ErrNotFound:     "client not found",
+		})
+		return
+	}
+
+	output := make([]dto.OrderResponse, len(orders))
+
+	for i, order := range orders {
+		output[i] = mapper.OrderToResponse(order)
+	}
+
+	ctrl.responce(c, http.StatusOK, output)
+}
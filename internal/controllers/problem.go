@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemKind is the problem type of a sentinel error.
+type problemKind struct {
+	err    error
+	status int
+	typ    string
+	title  string
+}
+
+// problemKinds maps sentinel errors to problem types, the first matched kind wins.
+var problemKinds = []problemKind{
+	{crud_errors.ErrVersionMismatch, http.StatusPreconditionFailed, "version-mismatch", "Resource version does not match"},
+	{crud_errors.ErrInvalidParam, http.StatusBadRequest, "invalid-parameter", "Invalid parameter"},
+	{crud_errors.ErrNoContent, http.StatusBadRequest, "empty-payload", "Request payload is empty"},
+	{crud_errors.ErrAddressIsEmpty, http.StatusBadRequest, "empty-address", "Address is empty"},
+	{crud_errors.ErrImageCorruption, http.StatusBadRequest, "invalid-image", "Image is corrupted"},
+	{crud_errors.ErrImageTooLarge, http.StatusRequestEntityTooLarge, "image-too-large", "Image is too large"},
+	{crud_errors.ErrNotFound, http.StatusNotFound, "not-found", "Resource not found"},
+	{crud_errors.ErrDuplicateKeyValue, http.StatusConflict, "duplicate", "Resource already exists"},
+	{crud_errors.ErrForeignKeyViolation, http.StatusConflict, "in-use", "Resource is in use"},
+	{crud_errors.ErrLastAddress, http.StatusConflict, "last-address", "The only address cannot be removed"},
+	{crud_errors.ErrJobFinished, http.StatusConflict, "job-finished", "Job is already finished"},
+	{crud_errors.ErrInsufficientStock, http.StatusConflict, "insufficient-stock", "Stock is not enough"},
+	{crud_errors.ErrVariantStock, http.StatusConflict, "variant-stock", "Stock is kept by variants"},
+	{crud_errors.ErrWarehouseRequired, http.StatusConflict, "warehouse-required", "Stock is kept by warehouses"},
+	{crud_errors.ErrCartEmpty, http.StatusConflict, "cart-empty", "Cart is empty"},
+	{crud_errors.ErrCartClientRequired, http.StatusConflict, "cart-client-required", "Cart has no client"},
+	{crud_errors.ErrCartOwned, http.StatusConflict, "cart-owned", "Cart belongs to another client"},
+	{crud_errors.ErrCouponNotApplicable, http.StatusConflict, "coupon-not-applicable", "Coupon is not applicable"},
+	{crud_errors.ErrIdempotencyKeyInProgress, http.StatusConflict, "idempotency-key-in-progress", "Request with the key is in progress"},
+	{crud_errors.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency-key-reused", "Idempotency key is reused"},
+	{crud_errors.ErrImportRejected, http.StatusUnprocessableEntity, "import-rejected", "Import is rejected"},
+	{crud_errors.ErrBatchFailed, http.StatusUnprocessableEntity, "batch-failed", "Batch is rolled back"},
+}
+
+var internalProblem = problemKind{nil, http.StatusInternalServerError, "internal", "Internal server error"}
+
+// problemOf returns the problem kind of the error, unknown errors are internal.
+func problemOf(err error) problemKind {
+	for _, kind := range problemKinds {
+		if errors.Is(err, kind.err) {
+			return kind
+		}
+	}
+
+	return internalProblem
+}
+
+// problemDetails overrides the detail of problems by their sentinel errors.
+type problemDetails map[error]string
+
+// fail answers with the problem mapped from the error. The detail is taken
+// from details by the sentinel error, internal errors are logged and their
+// detail is never shown.
+func (ctrl *BaseController) fail(c *gin.Context, op string, err error, details problemDetails) {
+	kind := problemOf(err)
+	if kind.err == nil {
+		ctrl.logger.Error("Request failed", logger.Err(err), "op", op)
+		writeProblem(c, domain.Error{
+			Type:   problemTypeBase + kind.typ,
+			Title:  kind.title,
+			Status: kind.status,
+			Detail: "Server is busy",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Request is rejected", "status", kind.status, logger.Err(err), "op", op)
+
+	detail, ok := details[kind.err]
+	if !ok {
+		detail = kind.err.Error()
+	}
+
+	// fields rejected by services are listed as binding errors are
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		ctrl.invalidFields(c, detail, invalid.Fields)
+		return
+	}
+
+	writeProblem(c, domain.Error{
+		Type:   problemTypeBase + kind.typ,
+		Title:  kind.title,
+		Status: kind.status,
+		Detail: detail,
+	})
+}
+
+// problem answers with the problem of the status without a specific type,
+// it is used for requests rejected before any service is called.
+func (ctrl *BaseController) problem(c *gin.Context, status int, detail string) {
+	writeProblem(c, domain.Error{
+		Type:   problemTypeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// invalidPayload answers the request which payload is not bound, failed
+// binding rules are listed as field errors.
+func (ctrl *BaseController) invalidPayload(c *gin.Context, op string, err error) {
+	ctrl.logger.Warn("Failed to bind request payload", logger.Err(err), "op", op)
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid data received")
+		return
+	}
+
+	fields := make([]domain.FieldError, len(invalid))
+	for i, field := range invalid {
+		fields[i] = domain.FieldError{
+			Field:   field.Field(),
+			Code:    field.Tag(),
+			Message: fieldMessage(field),
+		}
+	}
+
+	ctrl.invalidFields(c, "Invalid request payload: invalid data received", fields)
+}
+
+// invalidFields answers with 400 listing invalid fields of the request.
+func (ctrl *BaseController) invalidFields(c *gin.Context, detail string, fields []domain.FieldError) {
+	writeProblem(c, domain.Error{
+		Type:   problemTypeBase + "validation",
+		Title:  "Request is not valid",
+		Status: http.StatusBadRequest,
+		Detail: detail,
+		Errors: fields,
+	})
+}
+
+// writeProblem completes the problem with the request data and aborts the
+// request with it.
+func writeProblem(c *gin.Context, problem domain.Error) {
+	problem.Instance = c.Request.URL.Path
+	problem.RequestId = c.GetString(ctxRequestId)
+
+	if mediaType, _ := negotiate(c.GetHeader("Accept"), []string{mimeJSON, mimeXML}); mediaType == mimeXML {
+		c.Header("Content-Type", contentTypeProblemXML)
+		c.XML(problem.Status, problem)
+		c.Abort()
+		return
+	}
+
+	c.Header("Content-Type", contentTypeProblemJSON)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// NoRoute answers unknown paths with the not found problem.
+func NoRoute(c *gin.Context) {
+	writeProblem(c, domain.Error{
+		Type:   problemTypeBlank,
+		Title:  http.StatusText(http.StatusNotFound),
+		Status: http.StatusNotFound,
+		Detail: "path is not found",
+	})
+}
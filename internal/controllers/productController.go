@@ -19,8 +19,22 @@ type productService interface {
 	Create(ctx context.Context, product *domain.Product) error
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Product, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Product, error)
-	Update(ctx context.Context, id uuid.UUID, decrease int) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	GetBySku(ctx context.Context, sku string) (*domain.Product, error)
+	GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	CreateVariant(ctx context.Context, parentId uuid.UUID, variant *domain.Product) error
+	GetBySupplier(ctx context.Context, supplierId uuid.UUID, filter domain.ProductFilter) ([]domain.Product, int, error)
+	GetByCategory(ctx context.Context, categoryId uuid.UUID, includeSubcategories bool, filter domain.ProductFilter) ([]domain.Product, int, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.ProductPatch) error
+	ChangeStock(ctx context.Context, movement *domain.StockMovement, pick domain.WarehousePick, version int64) error
+	TransferStock(ctx context.Context, movement *domain.StockMovement, version int64) error
+	GetStockMovements(ctx context.Context, productId uuid.UUID, limit, offset int) ([]domain.StockMovement, error)
+	GetWarehouseStock(ctx context.Context, productId uuid.UUID) ([]domain.WarehouseStock, error)
+	SetReorder(ctx context.Context, id uuid.UUID, policy *domain.ReorderPolicy, version int64) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	GetImages(ctx context.Context, productId uuid.UUID) ([]domain.ProductImage, error)
+	AttachImage(ctx context.Context, productId uuid.UUID, productImage *domain.ProductImage) error
+	DetachImage(ctx context.Context, productId, imageId uuid.UUID) error
+	ReorderImages(ctx context.Context, productId uuid.UUID, imageIds []uuid.UUID) error
 }
 
 type ProductController struct {
@@ -40,10 +54,10 @@ func NewProductController(service productService, logger *logger.Logger) *Produc
 // CreateProduct godoc
 //
 //	@Summary		Create product
-//	@Description	Product created from JSON or XML, for create endpoint required: name, category, price, available_stock, supplier_name, supplier_phone_number, image
+//	@Description	Product created from JSON or XML, for create endpoint required: name, category_id, price, available_stock, supplier_id. Optional image_id becomes the primary gallery image, attributes are validated against the attributes defined by the category and its ancestors
 //	@Tags			products
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			product	body	domain.Product	true	"Product data"
 //	@Success		201
 //	@Failure		400	{object}	domain.Error
@@ -54,27 +68,19 @@ func (ctrl *ProductController) Create(c *gin.Context) {
 	op := "controllers.productController.Create"
 	var input dto.ProductRequest
 
-	if err := c.ShouldBind(&input); err != nil {
-		ctrl.logger.Warn("Failed to bind JSON/XML for AddProduct", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
+	if !ctrl.bind(c, op, &input) {
 		return
 	}
 
 	if err := uuid.Validate(input.SupplierId.String()); err != nil {
 		ctrl.logger.Warn("Invalid payload: supplier uuid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid supplier id"})
-		return
-	}
-
-	if err := uuid.Validate(input.ImageId.String()); err != nil {
-		ctrl.logger.Warn("Invalid payload: image uuid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid image id"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid supplier id")
 		return
 	}
 
 	if input.AvailableStock < 0 {
 		ctrl.logger.Warn("Invalid payload: stock is nagative", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid available stock (cannot be negative)"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid available stock (cannot be negative)")
 		return
 	}
 
@@ -82,13 +88,15 @@ func (ctrl *ProductController) Create(c *gin.Context) {
 
 	if err := ctrl.service.Create(c.Request.Context(), &product); err != nil {
 		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Invalid supplier data with create product", "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid supplier data"})
+			ctrl.logger.Debug("Invalid supplier, category or image data with create product", "op", op)
+			ctrl.problem(c, http.StatusBadRequest, "Invalid supplier, category or image data")
 			return
 		}
 
-		ctrl.logger.Error("Failed to add client", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name, price, sku, barcode or attributes is not valid",
+			crud_errors.ErrDuplicateKeyValue: "product with the sku or barcode already exists",
+		})
 		return
 	}
 
@@ -103,13 +111,12 @@ func (ctrl *ProductController) Create(c *gin.Context) {
 //	@Summary		Get all product
 //	@Description	The endpoint for retrieve all registered product in system
 //	@Tags			products
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			limit	query		int	false	"limit get product"
 //	@Param			offset	query		int	false	"offset get product"
 //	@Success		200		{array}		dto.Product
 //	@Failure		400		{object}	domain.Error
-//	@Failure		404		{object}	domain.Error
 //	@Failure		500		{object}	domain.Error
 //	@Router			/api/v1/products [get]
 func (ctrl *ProductController) GetAll(c *gin.Context) {
@@ -117,33 +124,23 @@ func (ctrl *ProductController) GetAll(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
 		return
 	}
 
 	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: offset is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
 		return
 	}
 
 	product, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
-	if err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid limit or offset parameter", "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0"})
-			return
-		}
-
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Product's not found: no content", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: no data is contains"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to retrieve product", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
 		return
 	}
 
@@ -158,15 +155,152 @@ func (ctrl *ProductController) GetAll(c *gin.Context) {
 	ctrl.responce(c, http.StatusOK, output)
 }
 
+// GetSupplierProducts godoc
+//
+//	@Summary		Get supplier products
+//	@Description	That endpoint retrieve products of the supplier matched by filters. Parameter q is matched partially against product name, category is the id or slug of a category and matches its subcategories too. attr filters by attribute values: = matches a value, >=, <=, > and < compare numbers. Total count of matched products is returned in X-Total-Count header
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID	true	"Supplier ID"
+//	@Param			q			query		string		false	"part of product name"
+//	@Param			category	query		string		false	"category id or slug"
+//	@Param			price_min	query		number		false	"price lower bound"
+//	@Param			price_max	query		number		false	"price upper bound"
+//	@Param			in_stock	query		bool		false	"only products in stock (true) or out of stock (false)"
+//	@Param			attr		query		[]string	false	"attribute filters like energy_class=A++ or capacity>=300, all have to match"	collectionFormat(multi)
+//	@Param			sort		query		string		false	"name, category, price, available_stock or last_update_date"
+//	@Param			order		query		string		false	"asc or desc"
+//	@Param			limit		query		int			false	"limit get data"
+//	@Param			offset		query		int			false	"offset get data"
+//	@Success		200			{array}		dto.ProductResponse
+//	@Header			200			{int}		X-Total-Count	"total count of matched products"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/products [get]
+func (ctrl *ProductController) GetBySupplier(c *gin.Context) {
+	op := "controllers.productController.GetBySupplier"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ProductListQuery
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		ctrl.logger.Warn("Failed to bind product query", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid query received")
+		return
+	}
+
+	filter, err := mapper.ProductListQueryToFilter(input)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid order or attribute filter received")
+		return
+	}
+
+	products, total, err := ctrl.service.GetBySupplier(c.Request.Context(), id, filter)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit, offset, price range or sort is not valid",
+			crud_errors.ErrNotFound:     "supplier not found",
+		})
+		return
+	}
+
+	output := make([]dto.ProductResponse, len(products))
+
+	for i, item := range products {
+		output[i] = mapper.ProductDomainToProductResponse(item)
+	}
+
+	ctrl.logger.Debug("Supplier products retrieved", "id", id, "total", total, "op", op)
+	c.Header(headerXTotalCount, strconv.Itoa(total))
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// GetCategoryProducts godoc
+//
+//	@Summary		Get category products
+//	@Description	That endpoint retrieve products of the category matched by filters, products of its subcategories are included when include_subcategories is true. attr filters by attribute values: = matches a value, >=, <=, > and < compare numbers. Total count of matched products is returned in X-Total-Count header
+//	@Tags			categories
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id						path		uuid.UUID	true	"Category ID"
+//	@Param			include_subcategories	query		bool		false	"include products of subcategories"
+//	@Param			q						query		string		false	"part of product name"
+//	@Param			price_min				query		number		false	"price lower bound"
+//	@Param			price_max				query		number		false	"price upper bound"
+//	@Param			in_stock				query		bool		false	"only products in stock (true) or out of stock (false)"
+//	@Param			attr					query		[]string	false	"attribute filters like energy_class=A++ or capacity>=300, all have to match"	collectionFormat(multi)
+//	@Param			sort					query		string		false	"name, category, price, available_stock or last_update_date"
+//	@Param			order					query		string		false	"asc or desc"
+//	@Param			limit					query		int			false	"limit get data"
+//	@Param			offset					query		int			false	"offset get data"
+//	@Success		200						{array}		dto.ProductResponse
+//	@Header			200						{int}		X-Total-Count	"total count of matched products"
+//	@Failure		400						{object}	domain.Error
+//	@Failure		404						{object}	domain.Error
+//	@Failure		500						{object}	domain.Error
+//	@Router			/api/v1/categories/{id}/products [get]
+func (ctrl *ProductController) GetByCategory(c *gin.Context) {
+	op := "controllers.productController.GetByCategory"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.CategoryProductsQuery
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		ctrl.logger.Warn("Failed to bind product query", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid query received")
+		return
+	}
+
+	filter, err := mapper.ProductListQueryToFilter(input.ProductListQuery)
+	if err != nil {
+		ctrl.logger.Warn("Failed mapping dto to domain", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: invalid order or attribute filter received")
+		return
+	}
+
+	products, total, err := ctrl.service.GetByCategory(c.Request.Context(), id, input.IncludeSubcategories, filter)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit, offset, price range or sort is not valid",
+			crud_errors.ErrNotFound:     "category not found",
+		})
+		return
+	}
+
+	output := make([]dto.ProductResponse, len(products))
+
+	for i, item := range products {
+		output[i] = mapper.ProductDomainToProductResponse(item)
+	}
+
+	ctrl.logger.Debug("Category products retrieved", "id", id, "total", total, "op", op)
+	c.Header(headerXTotalCount, strconv.Itoa(total))
+	ctrl.responce(c, http.StatusOK, output)
+}
+
 // GetProduct godoc
 //
 //	@Summary		Get product by id
 //	@Description	The endpoint for retrieve registered product in system by id
 //	@Tags			products
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			id	path		uuid.UUID	true	"Product ID"
 //	@Success		200	{object}	dto.Product
+//	@Header			200	{string}	ETag	"Product version"
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
@@ -177,92 +311,537 @@ func (ctrl *ProductController) GetById(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
 	product, err := ctrl.service.GetById(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Product not found", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: product not found"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to get prodcut with id", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "product not found",
+		})
 		return
 	}
 
 	output := mapper.ProductDomainToProductResponse(*product)
 	ctrl.logger.Debug("Product retrieved", "id", id, "op", op)
+	setETag(c, product.Version)
 	ctrl.responce(c, http.StatusOK, output)
 }
 
+// GetProductBySku godoc
+//
+//	@Summary		Get product by SKU
+//	@Description	The endpoint for retrieve the product or the variant with the SKU, SKUs are compared in upper case
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			sku	path		string	true	"SKU"
+//	@Success		200	{object}	dto.ProductResponse
+//	@Header			200	{string}	ETag	"Product version"
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/by-sku/{sku} [get]
+func (ctrl *ProductController) GetBySku(c *gin.Context) {
+	op := "controllers.productController.GetBySku"
+	sku := c.Param("sku")
+
+	product, err := ctrl.service.GetBySku(c.Request.Context(), sku)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "product with the sku not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product retrieved by sku", "id", product.Id, "op", op)
+	setETag(c, product.Version)
+	ctrl.responce(c, http.StatusOK, mapper.ProductDomainToProductResponse(*product))
+}
+
+// GetProductByBarcode godoc
+//
+//	@Summary		Get product by barcode
+//	@Description	The endpoint for retrieve the product or the variant with the EAN-13 barcode
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			code	path		string	true	"EAN-13 barcode"
+//	@Success		200		{object}	dto.ProductResponse
+//	@Header			200		{string}	ETag	"Product version"
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/products/by-barcode/{code} [get]
+func (ctrl *ProductController) GetByBarcode(c *gin.Context) {
+	op := "controllers.productController.GetByBarcode"
+	code := c.Param("code")
+
+	product, err := ctrl.service.GetByBarcode(c.Request.Context(), code)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: barcode is not an EAN-13 code",
+			crud_errors.ErrNotFound:     "product with the barcode not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product retrieved by barcode", "id", product.Id, "op", op)
+	setETag(c, product.Version)
+	ctrl.responce(c, http.StatusOK, mapper.ProductDomainToProductResponse(*product))
+}
+
+// CreateProductVariant godoc
+//
+//	@Summary		Create product variant
+//	@Description	Variant created for the product with its own sku, barcode, price, stock and image. The variant takes the category and the supplier of the product, the product attributes not set by the variant and the product name when name is not given. The stock of the product becomes the sum of its variants
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID					true	"Product ID"
+//	@Param			variant	body		dto.ProductVariantRequest	true	"Variant data"
+//	@Success		201		{object}	dto.ProductResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/products/{id}/variants [post]
+func (ctrl *ProductController) CreateVariant(c *gin.Context) {
+	op := "controllers.productController.CreateVariant"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ProductVariantRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	variant := mapper.ProductVariantRequestToDomain(input)
+
+	if err := ctrl.service.CreateVariant(c.Request.Context(), id, &variant); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: sku, barcode or attributes is not valid, or the product is a variant",
+			crud_errors.ErrNotFound:          "product or image not found",
+			crud_errors.ErrDuplicateKeyValue: "product with the sku or barcode already exists",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product variant created", "id", variant.Id, "parent_id", id, "op", op)
+	ctrl.responce(c, http.StatusCreated, mapper.ProductDomainToProductResponse(variant))
+}
+
 // UpdateProduct godoc
 //
-//	@Summary		Update product by ID
-//	@Description	The endpoint for updating product data (avaliable stock) by ID to a decrease avalible stock
+//	@Summary		Update product
+//	@Description	That endpoint partially update product data as JSON Merge Patch, only received fields are changed. image_id becomes the primary gallery image, attributes are merged and null removes an attribute. Attributes are validated against the schema of the resulting category. Stock is changed by the stock endpoints
 //	@Tags			products
-//	@Accept			json
-//	@Produce		json
-//	@Param			id			path	uuid.UUID	true	"Product ID"
-//	@Param			decrease	query	int			true	"Decrease value"
-//	@Success		201
+//	@Accept			json,xml,application/msgpack,application/yaml,application/merge-patch+json
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID					true	"Product ID"
+//	@Param			product		body	dto.ProductUpdateRequest	true	"Changed fields"
+//	@Param			If-Match	header	string						false	"ETag of the product, the update is rejected when it is changed"
+//	@Success		200
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/products/{id} [patch]
 func (ctrl *ProductController) Update(c *gin.Context) {
-	op := "controllers.productController.DecreaseStock"
+	op := "controllers.productController.Update"
 	rawId := c.Param("id")
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
-	value, err := strconv.Atoi(c.Query("decrease"))
+	var input dto.ProductUpdateRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	patch := mapper.ProductUpdateRequestToPatch(input)
+	patch.Version = version
+
+	if err := ctrl.service.Update(c.Request.Context(), id, &patch); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name, category, price, sku, barcode or attributes is not valid, or category or supplier of a variant is changed",
+			crud_errors.ErrNotFound:          "product, supplier, category or image not found",
+			crud_errors.ErrDuplicateKeyValue: "image is already attached, or sku or barcode is taken",
+			crud_errors.ErrVersionMismatch:   "product is changed, get it again",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product updated", "id", id, "op", op)
+	c.Status(http.StatusOK)
+}
+
+// DecreaseStock godoc
+//
+//	@Summary		Decrease product stock
+//	@Description	That endpoint decrease available stock of the product by quantity and record the change with its reason. Stock cannot become negative. The stock of a product stocked in warehouses is taken from warehouse_id or from the warehouse holding the whole quantity picked by strategy: most_stock or nearest to destination_address_id, the configured strategy by default
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID				true	"Product ID"
+//	@Param			stock		body		dto.ProductStockRequest	true	"Quantity and reason: sale, return, restock, damage, loss or correction"
+//	@Param			If-Match	header		string					false	"ETag of the product, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.StockMovementResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/decrease [post]
+func (ctrl *ProductController) DecreaseStock(c *gin.Context) {
+	ctrl.changeStock(c, "controllers.productController.DecreaseStock", domain.StockDecrease)
+}
+
+// IncreaseStock godoc
+//
+//	@Summary		Increase product stock
+//	@Description	That endpoint increase available stock of the product by quantity and record the change with its reason. Restock also sets the last delivery date. A product stocked in warehouses requires warehouse_id, the first warehouse of a product takes its stock
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID				true	"Product ID"
+//	@Param			stock		body		dto.ProductStockRequest	true	"Quantity and reason: sale, return, restock, damage, loss or correction"
+//	@Param			If-Match	header		string					false	"ETag of the product, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.StockMovementResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/increase [post]
+func (ctrl *ProductController) IncreaseStock(c *gin.Context) {
+	ctrl.changeStock(c, "controllers.productController.IncreaseStock", domain.StockIncrease)
+}
+
+// SetStock godoc
+//
+//	@Summary		Set product stock
+//	@Description	That endpoint set available stock of the product to quantity, for example after stocktaking, and record the change with its reason. A product stocked in warehouses requires warehouse_id, the stock of the warehouse is set then
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID				true	"Product ID"
+//	@Param			stock		body		dto.ProductStockRequest	true	"New stock and reason: sale, return, restock, damage, loss or correction"
+//	@Param			If-Match	header		string					false	"ETag of the product, the change is rejected when it is changed"
+//	@Success		200			{object}	dto.StockMovementResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/set [post]
+func (ctrl *ProductController) SetStock(c *gin.Context) {
+	ctrl.changeStock(c, "controllers.productController.SetStock", domain.StockSet)
+}
+
+func (ctrl *ProductController) changeStock(c *gin.Context, op string, operation domain.StockOperation) {
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
 	if err != nil {
-		ctrl.logger.Warn("Failed convert decrease value", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: decrease value is invalid"})
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
-	if err := ctrl.service.Update(c.Request.Context(), id, value); err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid deacrease value", "value", value, "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid payload: decrease value cannot be less than 0"})
-			return
-		}
+	var input dto.ProductStockRequest
 
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Product not found", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: product not found for update"})
-			return
-		}
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	movement := mapper.ProductStockRequestToMovement(id, operation, input)
+	pick := mapper.ProductStockRequestToPick(input)
+	if err := ctrl.service.ChangeStock(c.Request.Context(), &movement, pick, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: quantity must be greater than 0",
+			crud_errors.ErrNotFound:          "product, warehouse or destination address not found for stock change",
+			crud_errors.ErrInsufficientStock: "available stock is less than quantity",
+			crud_errors.ErrVersionMismatch:   "product is changed, get it again",
+			crud_errors.ErrVariantStock:      "product has variants, change the stock of a variant",
+			crud_errors.ErrWarehouseRequired: "product is stocked in warehouses, warehouse_id is required",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product stock changed", "id", id, "operation", operation, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.StockMovementToResponse(movement))
+}
+
+// TransferStock godoc
+//
+//	@Summary		Transfer product stock
+//	@Description	That endpoint move quantity of the product from one warehouse to another and record the transfer as a stock movement. Available stock of the product is kept
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID							true	"Product ID"
+//	@Param			transfer	body		dto.ProductStockTransferRequest	true	"Source and destination warehouses and quantity"
+//	@Param			If-Match	header		string								false	"ETag of the product, the transfer is rejected when it is changed"
+//	@Success		200			{object}	dto.StockMovementResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/transfer [post]
+func (ctrl *ProductController) TransferStock(c *gin.Context) {
+	op := "controllers.productController.TransferStock"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ProductStockTransferRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
 
-		ctrl.logger.Error("Failed to update avalilable stock", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	movement := mapper.ProductStockTransferRequestToMovement(id, input)
+	if err := ctrl.service.TransferStock(c.Request.Context(), &movement, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:          "product or warehouse not found for stock transfer",
+			crud_errors.ErrInsufficientStock: "stock of the source warehouse is less than quantity",
+			crud_errors.ErrVersionMismatch:   "product is changed, get it again",
+			crud_errors.ErrVariantStock:      "product has variants, transfer the stock of a variant",
+		})
 		return
 	}
 
-	ctrl.logger.Debug("Product updated", "op", op)
+	ctrl.logger.Debug("Product stock transferred", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.StockMovementToResponse(movement))
+}
+
+// GetWarehouseStock godoc
+//
+//	@Summary		Get product stock per warehouse
+//	@Description	That endpoint retrieve the stock of the product in every warehouse it is stocked in, an empty list means the product is not stocked in warehouses
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Product ID"
+//	@Success		200	{array}		dto.WarehouseStockResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/warehouses [get]
+func (ctrl *ProductController) GetWarehouseStock(c *gin.Context) {
+	op := "controllers.productController.GetWarehouseStock"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	stock, err := ctrl.service.GetWarehouseStock(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "product not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Retrieved warehouse stock", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.WarehouseStockToResponse(stock))
+}
+
+// GetStockMovements godoc
+//
+//	@Summary		Get product stock movements
+//	@Description	That endpoint retrieve recorded changes of the product stock, the latest first
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID	true	"Product ID"
+//	@Param			limit	query		int			false	"limit get movements"
+//	@Param			offset	query		int			false	"offset get movements"
+//	@Success		200		{array}		dto.StockMovementResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/products/{id}/stock/movements [get]
+func (ctrl *ProductController) GetStockMovements(c *gin.Context) {
+	op := "controllers.productController.GetStockMovements"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	movements, err := ctrl.service.GetStockMovements(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+			crud_errors.ErrNotFound:     "product not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Retrieved stock movements", "id", id, "limit", limit, "offset", offset, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.StockMovementsToResponse(movements))
+}
+
+// SetReorder godoc
+//
+//	@Summary		Set product reorder threshold
+//	@Description	That endpoint sets the reorder threshold and target of the product. The product is low on stock when the stock is at or below the threshold, it is reordered up to the target. Products with variants have no threshold, their variants have
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID					true	"Product ID"
+//	@Param			reorder		body	dto.ReorderPolicyRequest	true	"Threshold and target above it"
+//	@Param			If-Match	header	string						false	"ETag of the product, the change is rejected when it is changed"
+//	@Success		200
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/reorder [put]
+func (ctrl *ProductController) SetReorder(c *gin.Context) {
+	op := "controllers.productController.SetReorder"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ReorderPolicyRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	policy := mapper.ReorderPolicyRequestToDomain(input)
+	if err := ctrl.service.SetReorder(c.Request.Context(), id, &policy, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:    "Invalid request payload: target must be greater than threshold",
+			crud_errors.ErrNotFound:        "product not found",
+			crud_errors.ErrVersionMismatch: "product is changed, get it again",
+			crud_errors.ErrVariantStock:    "product has variants, set the threshold of a variant",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product reorder policy set", "id", id, "op", op)
 	c.Status(http.StatusOK)
 }
 
+// RemoveReorder godoc
+//
+//	@Summary		Remove product reorder threshold
+//	@Description	That endpoint removes the reorder threshold and target of the product, it is not low on stock any more
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Product ID"
+//	@Param			If-Match	header	string		false	"ETag of the product, the change is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/reorder [delete]
+func (ctrl *ProductController) RemoveReorder(c *gin.Context) {
+	op := "controllers.productController.RemoveReorder"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.SetReorder(c.Request.Context(), id, nil, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound:        "product not found",
+			crud_errors.ErrVersionMismatch: "product is changed, get it again",
+			crud_errors.ErrVariantStock:    "product has variants, it has no threshold",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product reorder policy removed", "id", id, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
 // Delete Product godoc
 //
 //	@Summary		Delete product by ID
 //	@Description	The endpoint for deleting product data by ID
 //	@Tags			products
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path	uuid.UUID	true	"Product ID"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Product ID"
+//	@Param			If-Match	header	string		false	"ETag of the product, the delete is rejected when it is changed"
 //	@Success		204
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/products/{id} [delete]
 func (ctrl *ProductController) Delete(c *gin.Context) {
@@ -271,22 +850,197 @@ func (ctrl *ProductController) Delete(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
 		return
 	}
 
-	if err := ctrl.service.Delete(c.Request.Context(), id); err != nil {
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
 		if errors.Is(err, crud_errors.ErrNotFound) {
 			ctrl.logger.Debug("Product not found", "op", op)
 			c.Status(http.StatusNoContent)
 			return
 		}
 
-		ctrl.logger.Error("Failed to delete product by id", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "product is changed, get it again",
+		})
 		return
 	}
 
 	ctrl.logger.Debug("Product deleted", "id", id, "op", op)
 	c.Status(http.StatusNoContent)
 }
+
+// GetProductImages godoc
+//
+//	@Summary		Get product gallery
+//	@Description	The endpoint for retrieve ordered images attached to product
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Product ID"
+//	@Success		200	{array}		dto.ProductImageResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/images [get]
+func (ctrl *ProductController) GetImages(c *gin.Context) {
+	op := "controllers.productController.GetImages"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	images, err := ctrl.service.GetImages(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "product not found",
+		})
+		return
+	}
+
+	output := make([]dto.ProductImageResponse, len(images))
+
+	for i, item := range images {
+		output[i] = mapper.ProductImageDomainToResponse(item)
+	}
+
+	ctrl.logger.Debug("Product gallery retrieved", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// AttachProductImage godoc
+//
+//	@Summary		Attach image to product
+//	@Description	The endpoint for attaching existing image to product gallery. Without position image is appended to the end
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID				true	"Product ID"
+//	@Param			image	body		dto.ProductImageRequest	true	"Attached image"
+//	@Success		201		{object}	dto.ProductImageResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/products/{id}/images [post]
+func (ctrl *ProductController) AttachImage(c *gin.Context) {
+	op := "controllers.productController.AttachImage"
+	rawId := c.Param("id")
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ProductImageRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	productImage := mapper.ProductImageRequestToDomain(input)
+
+	if err := ctrl.service.AttachImage(c.Request.Context(), id, &productImage); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: position cannot be negative",
+			crud_errors.ErrNotFound:          "product or image not found",
+			crud_errors.ErrDuplicateKeyValue: "Image is already attached to product",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Image attached", "id", id, "image id", productImage.Image.Id, "op", op)
+	ctrl.responce(c, http.StatusCreated, mapper.ProductImageDomainToResponse(productImage))
+}
+
+// DetachProductImage godoc
+//
+//	@Summary		Detach image from product
+//	@Description	The endpoint for removing image from product gallery, image itself is not deleted
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Product ID"
+//	@Param			image_id	path	uuid.UUID	true	"Image ID"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/images/{image_id} [delete]
+func (ctrl *ProductController) DetachImage(c *gin.Context) {
+	op := "controllers.productController.DetachImage"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	imageId, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		ctrl.logger.Warn("The received image identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: image id is not valid")
+		return
+	}
+
+	if err := ctrl.service.DetachImage(c.Request.Context(), id, imageId); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "image is not attached to product",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Image detached", "id", id, "image id", imageId, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderProductImages godoc
+//
+//	@Summary		Reorder product gallery
+//	@Description	The endpoint for changing image order in product gallery, all attached image ids are required
+//	@Tags			products
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path	uuid.UUID						true	"Product ID"
+//	@Param			order	body	dto.ProductImageOrderRequest	true	"Ordered image ids"
+//	@Success		200
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/products/{id}/images [put]
+func (ctrl *ProductController) ReorderImages(c *gin.Context) {
+	op := "controllers.productController.ReorderImages"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.ProductImageOrderRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	if err := ctrl.service.ReorderImages(c.Request.Context(), id, input.ImageIds); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: image ids must match product gallery",
+			crud_errors.ErrNotFound:     "product gallery not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Product gallery reordered", "id", id, "op", op)
+	c.Status(http.StatusOK)
+}
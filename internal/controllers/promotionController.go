@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type promotionService interface {
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Promotion, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Promotion, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.PromotionPatch) (*domain.Promotion, error)
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	Evaluate(ctx context.Context, items []domain.PromotionItem, clientId *uuid.UUID, coupons []string) (*domain.PromotionEvaluation, error)
+}
+
+type PromotionController struct {
+	*BaseController
+	service promotionService
+}
+
+func NewPromotionController(service promotionService, logger *logger.Logger) *PromotionController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Promotion controller is created")
+	return &PromotionController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// CreatePromotion godoc
+//
+//	@Summary		Create promotion
+//	@Description	Promotion created from JSON or XML, required: name, kind (percentage or fixed amount off every unit) and value. At most one of product_id, category_id (with subcategories) and supplier_id scopes it, none applies it to every product. A promotion with coupon_code applies only when the coupon is given
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			promotion	body		dto.PromotionRequest	true	"Promotion Data"
+//	@Success		201			{object}	dto.PromotionResponse
+//	@Header			201			{string}	ETag	"Promotion version"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/promotions [post]
+func (ctrl *PromotionController) Create(c *gin.Context) {
+	op := "controllers.promotionController.Create"
+	var input dto.PromotionRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	promotion := mapper.PromotionRequestToDomain(input)
+
+	if err := ctrl.service.Create(c.Request.Context(), &promotion); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam:      "Invalid request payload: promotion is not valid",
+			crud_errors.ErrNotFound:          "product, category or supplier of the promotion not found",
+			crud_errors.ErrDuplicateKeyValue: "coupon code is already used",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Promotion created", "id", promotion.Id, "op", op)
+	setETag(c, promotion.Version)
+	ctrl.responce(c, http.StatusCreated, mapper.PromotionToResponse(promotion))
+}
+
+// GetAllPromotions godoc
+//
+//	@Summary		Get all promotions
+//	@Description	That endpoint retrieve promotions, the latest first
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			limit	query		int	false	"limit get promotions"
+//	@Param			offset	query		int	false	"offset get promotions"
+//	@Success		200		{array}		dto.PromotionResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/promotions [get]
+func (ctrl *PromotionController) GetAll(c *gin.Context) {
+	op := "controllers.promotionController.GetAll"
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	promotions, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
+		return
+	}
+
+	output := make([]dto.PromotionResponse, len(promotions))
+
+	for i, promotion := range promotions {
+		output[i] = mapper.PromotionToResponse(promotion)
+	}
+
+	ctrl.logger.Debug("Retrieved promotions", "limit", limit, "offset", offset, "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// GetPromotion godoc
+//
+//	@Summary		Get promotion by ID
+//	@Description	That endpoint retrieve the promotion by ID
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Promotion ID"
+//	@Success		200	{object}	dto.PromotionResponse
+//	@Header			200	{string}	ETag	"Promotion version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/promotions/{id} [get]
+func (ctrl *PromotionController) GetById(c *gin.Context) {
+	op := "controllers.promotionController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	promotion, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "promotion not found",
+		})
+		return
+	}
+
+	setETag(c, promotion.Version)
+	ctrl.responce(c, http.StatusOK, mapper.PromotionToResponse(*promotion))
+}
+
+// UpdatePromotion godoc
+//
+//	@Summary		Update promotion by ID
+//	@Description	That endpoint update set promotion fields: name, active, the window and the limits. The discount, the scope and the coupon code are not changed
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path		uuid.UUID					true	"Promotion ID"
+//	@Param			promotion	body		dto.PromotionUpdateRequest	true	"Promotion fields to change"
+//	@Param			If-Match	header		string						false	"ETag of the promotion, the update is rejected when it is changed"
+//	@Success		200			{object}	dto.PromotionResponse
+//	@Header			200			{string}	ETag	"Promotion version"
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		412			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/promotions/{id} [patch]
+func (ctrl *PromotionController) Update(c *gin.Context) {
+	op := "controllers.promotionController.Update"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.PromotionUpdateRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	patch := mapper.PromotionUpdateRequestToPatch(input)
+	patch.Version = version
+
+	promotion, err := ctrl.service.Update(c.Request.Context(), id, &patch)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:       "Invalid request payload: invalid data received",
+			crud_errors.ErrInvalidParam:    "Invalid request payload: promotion is not valid",
+			crud_errors.ErrNotFound:        "promotion not found for update",
+			crud_errors.ErrVersionMismatch: "promotion is changed, get it again",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Promotion updated", "id", id, "op", op)
+	setETag(c, promotion.Version)
+	ctrl.responce(c, http.StatusOK, mapper.PromotionToResponse(*promotion))
+}
+
+// DeletePromotion godoc
+//
+//	@Summary		Delete promotion by ID
+//	@Description	That endpoint delete the promotion with its redemptions, placed orders keep their discounts
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Promotion ID"
+//	@Param			If-Match	header	string		false	"ETag of the promotion, the delete is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/promotions/{id} [delete]
+func (ctrl *PromotionController) Delete(c *gin.Context) {
+	op := "controllers.promotionController.Delete"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "promotion is changed, get it again",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Promotion deleted", "id", id, "op", op)
+	c.Status(http.StatusNoContent)
+}
+
+// EvaluatePromotions godoc
+//
+//	@Summary		Evaluate promotions
+//	@Description	That endpoint discounts the items by the running automatic promotions and the promotions of the coupons without redeeming them. Every item gets the one promotion with the largest discount and its explanation, notes explain the coupons and the promotions which are not applied. Limits per client are checked for client_id
+//	@Tags			promotions
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			evaluation	body		dto.PromotionEvaluationRequest	true	"Items, client and coupons"
+//	@Success		200			{object}	dto.PromotionEvaluationResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		404			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/promotions/evaluate [post]
+func (ctrl *PromotionController) Evaluate(c *gin.Context) {
+	op := "controllers.promotionController.Evaluate"
+	var input dto.PromotionEvaluationRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	evaluation, err := ctrl.service.Evaluate(c.Request.Context(), mapper.PromotionItemsToDomain(input.Items), input.ClientId, input.Coupons)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: items or coupons are not valid",
+			crud_errors.ErrNotFound:     "client or product not found",
+		})
+		return
+	}
+
+	ctrl.responce(c, http.StatusOK, mapper.PromotionEvaluationToResponse(*evaluation))
+}
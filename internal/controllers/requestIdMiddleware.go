@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestId takes the request id from X-Request-Id or generates a new one, the
+// id is returned in the same header and put to every problem response.
+func RequestId(c *gin.Context) {
+	id := c.GetHeader(headerXRequestId)
+	if !validRequestId(id) {
+		id = uuid.NewString()
+	}
+
+	c.Set(ctxRequestId, id)
+	c.Header(headerXRequestId, id)
+	c.Next()
+}
+
+// validRequestId accepts short ids of visible ASCII characters, other ids are
+// not echoed back.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,13 +20,20 @@ type supplierService interface {
 	Create(ctx context.Context, supplier *domain.Supplier) error
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Supplier, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Supplier, error)
-	UpdateAddress(ctx context.Context, id uuid.UUID, address *domain.Address) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	GetByName(ctx context.Context, name string) (*domain.Supplier, error)
+	GetStats(ctx context.Context, id uuid.UUID) (*domain.SupplierStats, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.SupplierPatch) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	GetLocations(ctx context.Context, id uuid.UUID) ([]domain.AddressBookEntry, error)
+	AddLocation(ctx context.Context, id uuid.UUID, entry *domain.AddressBookEntry) error
+	UpdateLocation(ctx context.Context, id, addressId uuid.UUID, patch *domain.AddressBookPatch) error
+	RemoveLocation(ctx context.Context, id, addressId uuid.UUID, version int64) error
 }
 
 type SupplierController struct {
 	*BaseController
-	service supplierService
+	service   supplierService
+	locations *addressBookHandlers
 }
 
 func NewSupplierContoller(service supplierService, logger *logger.Logger) *SupplierController {
@@ -34,6 +42,14 @@ func NewSupplierContoller(service supplierService, logger *logger.Logger) *Suppl
 	return &SupplierController{
 		BaseController: controller,
 		service:        service,
+		locations: &addressBookHandlers{
+			BaseController: controller,
+			owner:          "supplier",
+			get:            service.GetLocations,
+			add:            service.AddLocation,
+			update:         service.UpdateLocation,
+			remove:         service.RemoveLocation,
+		},
 	}
 }
 
@@ -42,21 +58,20 @@ func NewSupplierContoller(service supplierService, logger *logger.Logger) *Suppl
 //	@Summary		Create supplier
 //	@Description	Supplier created from JSON or XML, for create endpoint required: name, phone_number, country, city, street
 //	@Tags			suppliers
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			supplier	body	dto.Supplier	true	"Supplier Data"
 //	@Success		201
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/suppliers [post]
 func (ctrl *SupplierController) Create(c *gin.Context) {
 	op := "controllers.supplierController.Create"
 	var input dto.SupplierRequest
 
-	if err := c.ShouldBind(&input); err != nil {
-		ctrl.logger.Warn("Failed to bind JSON/XML for create", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
+	if !ctrl.bind(c, op, &input) {
 		return
 	}
 
@@ -64,21 +79,18 @@ func (ctrl *SupplierController) Create(c *gin.Context) {
 
 	if input.Address == nil {
 		ctrl.logger.Warn("Address cannot be empty", "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: address cannot be empty"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: address cannot be empty")
 		return
 	}
 
 	supplier := mapper.SupplierRequestToDomain(input)
 
 	if err := ctrl.service.Create(c, &supplier); err != nil {
-		if errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
-			ctrl.logger.Warn("Failed create supplier: duplicate supplier received", "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Cannot create duplicate"})
-			return
-		}
-
-		ctrl.logger.Error("Failed create supplier", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrAddressIsEmpty:    "Invalid request payload: phone number, email, website, tax id or address is not valid",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: phone number, email, website, tax id or address is not valid",
+			crud_errors.ErrDuplicateKeyValue: "supplier name is already used",
+		})
 		return
 	}
 
@@ -91,13 +103,12 @@ func (ctrl *SupplierController) Create(c *gin.Context) {
 //	@Summary		Get all supplier
 //	@Description	That endpoint retrieve all registered supplier in system
 //	@Tags			suppliers
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			limit	query		int	false	"limit get supplier"
 //	@Param			offset	query		int	false	"offset get supplier"
 //	@Success		200		{array}		dto.Supplier
 //	@Failure		400		{object}	domain.Error
-//	@Failure		404		{object}	domain.Error
 //	@Failure		500		{object}	domain.Error
 //	@Router			/api/v1/suppliers [get]
 func (ctrl *SupplierController) GetAll(c *gin.Context) {
@@ -106,33 +117,23 @@ func (ctrl *SupplierController) GetAll(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
 		return
 	}
 
 	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
 	if err != nil {
 		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: offset is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
 		return
 	}
 
 	supplier, err := ctrl.service.GetAll(c, limit, offset)
-	if err != nil {
-		if errors.Is(err, crud_errors.ErrInvalidParam) {
-			ctrl.logger.Warn("Invalid value limit or offset", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0"})
-			return
-		}
-
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("No supplier data", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: no data is contains"})
-			return
-		}
-
-		ctrl.logger.Error("Failed retrieved data", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
 		return
 	}
 
@@ -151,10 +152,11 @@ func (ctrl *SupplierController) GetAll(c *gin.Context) {
 //	@Summary		Get supplier by ID
 //	@Description	That endpoint retrieve registered supplier in system by ID
 //	@Tags			suppliers
-//	@Accept			json
-//	@Produce		json
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
 //	@Param			id	path		uuid.UUID	true	"Supplier ID"
 //	@Success		200	{object}	dto.Supplier
+//	@Header			200	{string}	ETag	"Supplier version"
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
@@ -165,73 +167,142 @@ func (ctrl *SupplierController) GetById(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
 	supplier, err := ctrl.service.GetById(c, id)
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Supplier not found", "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: supplier not found"})
-			return
-		}
-
-		ctrl.logger.Error("Failed to get supplier with id", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "supplier not found",
+		})
 		return
 	}
 
 	output := mapper.SupplierDomainToSupplierResponse(*supplier)
 	ctrl.logger.Debug("Supplier retrieved", "id", id, "op", op)
+	setETag(c, supplier.Version)
 	ctrl.responce(c, http.StatusOK, output)
 }
 
+// SearchSupplier godoc
+//
+//	@Summary		Search supplier by name
+//	@Description	That endpoint retrieve registered supplier by exact name
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			name	query		string	true	"Supplier name"
+//	@Success		200		{object}	dto.Supplier
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/suppliers/search [get]
+func (ctrl *SupplierController) Search(c *gin.Context) {
+	op := "controllers.SupplierController.Search"
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		ctrl.logger.Warn("Empty supplier name", "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: name is required")
+		return
+	}
+
+	supplier, err := ctrl.service.GetByName(c.Request.Context(), name)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "supplier not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Supplier found", "id", supplier.Id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.SupplierDomainToSupplierResponse(*supplier))
+}
+
+// GetSupplierStats godoc
+//
+//	@Summary		Get supplier stats
+//	@Description	That endpoint retrieve product count, total stock units, total stock value, out-of-stock count and last delivery time of the supplier
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Supplier ID"
+//	@Success		200	{object}	dto.SupplierStatsResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/stats [get]
+func (ctrl *SupplierController) GetStats(c *gin.Context) {
+	op := "controllers.SupplierController.GetStats"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	stats, err := ctrl.service.GetStats(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "supplier not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Supplier stats retrieved", "id", id, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.SupplierStatsToResponse(*stats))
+}
+
 // UpdateSupplier godoc
 //
 //	@Summary		Update supplier by ID
-//	@Description	That endpoint update supplier data (change address on supplier)
+//	@Description	That endpoint update set supplier fields, address fields replace the default location. Phone number is stored in E.164 format
 //	@Tags			suppliers
-//	@Accept			json
-//	@Produce		json
-//	@Param			id		path	uuid.UUID	true	"Supplier ID"
-//	@Param			address	body	dto.Address	true	"New address"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID					true	"Supplier ID"
+//	@Param			supplier	body	dto.SupplierUpdateRequest	true	"Supplier fields to change"
+//	@Param			If-Match	header	string						false	"ETag of the supplier, the update is rejected when it is changed"
 //	@Success		200
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/suppliers/{id} [patch]
-func (ctrl *SupplierController) UpdateAddress(c *gin.Context) {
-	op := "controllers.supplierController.UpdateAddress"
+func (ctrl *SupplierController) Update(c *gin.Context) {
+	op := "controllers.supplierController.Update"
 	rawId := c.Param("id")
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
 		return
 	}
 
-	var input dto.Address
+	var input dto.SupplierUpdateRequest
 
-	if err := c.ShouldBind(&input); err != nil {
-		ctrl.logger.Warn("Failed to bind JSON/XML for update address", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalid request payload: invalid data received"})
+	if !ctrl.bind(c, op, &input) {
 		return
 	}
 
-	ctrl.logger.Debug("data received", "input", input, "op", op)
-
-	address := mapper.AddressToDomain(input)
-
-	if err := ctrl.service.UpdateAddress(c, id, &address); err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			ctrl.logger.Debug("Supplier not found", logger.Err(err), "op", op)
-			ctrl.responce(c, http.StatusNotFound, gin.H{"massage": "404: supplier not found for update"})
-			return
-		}
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
 
-		ctrl.logger.Error("Failed to update address ID", "id", id, logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+	patch := mapper.SupplierUpdateRequestToPatch(input)
+	patch.Version = version
+
+	if err := ctrl.service.Update(c.Request.Context(), id, &patch); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+			crud_errors.ErrAddressIsEmpty:    "Invalid request payload: name, phone number, email, website, tax id or address is not valid",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name, phone number, email, website, tax id or address is not valid",
+			crud_errors.ErrNotFound:          "supplier not found for update",
+			crud_errors.ErrDuplicateKeyValue: "supplier name is already used",
+			crud_errors.ErrVersionMismatch:   "supplier is changed, get it again",
+		})
 		return
 	}
 
@@ -244,12 +315,14 @@ func (ctrl *SupplierController) UpdateAddress(c *gin.Context) {
 //	@Summary		Delete supplier by ID
 //	@Description	That endpoint delete supplier data by id
 //	@Tags			suppliers
-//	@Accept			json
-//	@Produce		json
-//	@Param			id	path	uuid.UUID	true	"Supplier ID"
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Supplier ID"
+//	@Param			If-Match	header	string		false	"ETag of the supplier, the delete is rejected when it is changed"
 //	@Success		204
 //	@Failure		400	{object}	domain.Error
 //	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
 //	@Failure		500	{object}	domain.Error
 //	@Router			/api/v1/suppliers/{id} [delete]
 func (ctrl *SupplierController) Delete(c *gin.Context) {
@@ -258,22 +331,108 @@ func (ctrl *SupplierController) Delete(c *gin.Context) {
 	id, err := uuid.Parse(rawId)
 	if err != nil {
 		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusBadRequest, gin.H{"massage": "Invalud request payload: id is not valid"})
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
 		return
 	}
 
-	if err := ctrl.service.Delete(c.Request.Context(), id); err != nil {
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
 		if errors.Is(err, crud_errors.ErrNotFound) {
 			ctrl.logger.Debug("Supplier not found", "op", op)
 			c.Status(http.StatusNoContent)
 			return
 		}
 
-		ctrl.logger.Error("Failed delete supplier by id", logger.Err(err), "op", op)
-		ctrl.responce(c, http.StatusInternalServerError, gin.H{"error": "Server is busy"})
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch: "supplier is changed, get it again",
+		})
 		return
 	}
 
 	ctrl.logger.Debug("Supplier deleted", "id", id, "op", op)
 	c.Status(http.StatusNoContent)
 }
+
+// GetSupplierLocations godoc
+//
+//	@Summary		Get supplier locations
+//	@Description	That endpoint retrieve location book of supplier, default location goes first
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Supplier ID"
+//	@Success		200	{array}		dto.AddressBookEntryResponse
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/locations [get]
+func (ctrl *SupplierController) GetLocations(c *gin.Context) {
+	ctrl.locations.list(c, "controllers.supplierController.GetLocations")
+}
+
+// AddSupplierLocation godoc
+//
+//	@Summary		Add supplier location
+//	@Description	That endpoint add location to supplier, the first location becomes default. Same address is reused
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID					true	"Supplier ID"
+//	@Param			address	body		dto.AddressBookEntryRequest	true	"Labeled address"
+//	@Success		201		{object}	dto.AddressBookEntryResponse
+//	@Header			201		{string}	ETag	"Supplier location version"
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		409		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/locations [post]
+func (ctrl *SupplierController) AddLocation(c *gin.Context) {
+	ctrl.locations.create(c, "controllers.supplierController.AddLocation")
+}
+
+// UpdateSupplierLocation godoc
+//
+//	@Summary		Update supplier location
+//	@Description	That endpoint change label of location or make it default
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID							true	"Supplier ID"
+//	@Param			address_id	path	uuid.UUID							true	"Address ID"
+//	@Param			address		body	dto.AddressBookEntryUpdateRequest	true	"Changed fields"
+//	@Param			If-Match	header	string								false	"ETag of the location, the update is rejected when it is changed"
+//	@Success		200
+//	@Header			200	{string}	ETag	"Supplier location version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/locations/{address_id} [patch]
+func (ctrl *SupplierController) UpdateLocation(c *gin.Context) {
+	ctrl.locations.change(c, "controllers.supplierController.UpdateLocation")
+}
+
+// RemoveSupplierLocation godoc
+//
+//	@Summary		Remove supplier location
+//	@Description	That endpoint remove location from supplier, address itself is deleted when nobody uses it
+//	@Tags			suppliers
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Supplier ID"
+//	@Param			address_id	path	uuid.UUID	true	"Address ID"
+//	@Param			If-Match	header	string		false	"ETag of the location, the removal is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/suppliers/{id}/locations/{address_id} [delete]
+func (ctrl *SupplierController) RemoveLocation(c *gin.Context) {
+	ctrl.locations.delete(c, "controllers.supplierController.RemoveLocation")
+}
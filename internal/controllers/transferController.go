@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type importService interface {
+	Import(ctx context.Context, entity string, reader tabular.Reader, dryRun bool) (*domain.ImportReport, error)
+}
+
+type exportService interface {
+	Columns(entity string) ([]string, error)
+	Export(ctx context.Context, entity string, writer tabular.Writer) error
+}
+
+type TransferController struct {
+	*BaseController
+	importer importService
+	exporter exportService
+	maxBytes int64
+}
+
+func NewTransferController(importer importService, exporter exportService, maxBytes int64, logger *logger.Logger) *TransferController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Transfer controller is created")
+	return &TransferController{
+		BaseController: controller,
+		importer:       importer,
+		exporter:       exporter,
+		maxBytes:       maxBytes,
+	}
+}
+
+// Import godoc
+//
+//	@Summary		Import catalog data
+//	@Description	That endpoint imports products, suppliers or clients from CSV with a header row or from JSON Lines. Rows are written in one transaction, nothing is written when any row is rejected
+//	@Tags			transfer
+//	@Accept			text/csv,application/x-ndjson
+//	@Produce		json
+//	@Param			entity	path		string	true	"products, suppliers or clients"
+//	@Param			dry_run	query		bool	false	"validate rows without saving"
+//	@Success		200		{object}	dto.ImportReportResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		413		{object}	domain.Error
+//	@Failure		415		{object}	domain.Error
+//	@Failure		422		{object}	dto.ImportReportResponse
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/import/{entity} [post]
+func (ctrl *TransferController) Import(c *gin.Context) {
+	op := "controllers.transferController.Import"
+	entity := c.Param("entity")
+
+	format, ok := tabular.FormatFromContentType(c.GetHeader("Content-Type"))
+	if !ok {
+		ctrl.logger.Warn("Invalid content-type", "got", c.ContentType(), "op", op)
+		ctrl.problem(c, http.StatusUnsupportedMediaType, "Expected text/csv or application/x-ndjson")
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert dry_run value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: dry_run is not valid")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, ctrl.maxBytes)
+
+	reader, err := tabular.NewReader(body, format)
+	if err != nil {
+		ctrl.logger.Warn("Failed read import header", logger.Err(err), "op", op)
+		ctrl.importReadError(c, err)
+		return
+	}
+
+	report, err := ctrl.importer.Import(c.Request.Context(), entity, reader, dryRun)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrImportRejected) {
+			ctrl.logger.Debug("Import is rejected", "entity", entity, "failed", report.Failed, "op", op)
+			ctrl.responce(c, http.StatusUnprocessableEntity, mapper.ImportReportToResponse(*report))
+			return
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || errors.Is(err, bufio.ErrTooLong) {
+			ctrl.logger.Warn("Failed read import data", logger.Err(err), "op", op)
+			ctrl.importReadError(c, err)
+			return
+		}
+
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "entity is not importable",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Data is imported", "entity", entity, "imported", report.Imported, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.ImportReportToResponse(*report))
+}
+
+func (ctrl *TransferController) importReadError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		ctrl.problem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Invalid request payload: body exceeds %d bytes", ctrl.maxBytes))
+		return
+	}
+
+	if errors.Is(err, bufio.ErrTooLong) {
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: line is too long")
+		return
+	}
+
+	ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+}
+
+// Export godoc
+//
+//	@Summary		Export catalog data
+//	@Description	That endpoint streams all products, suppliers or clients or the purchase order lines of products low on stock as CSV with a header row or as JSON Lines. The format is taken from the format query or the Accept header, CSV by default
+//	@Tags			transfer
+//	@Produce		text/csv,application/x-ndjson
+//	@Param			entity	path		string	true	"products, suppliers, clients or purchase-orders"
+//	@Param			format	query		string	false	"csv, ndjson or jsonl"
+//	@Success		200		{string}	string
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/export/{entity} [get]
+func (ctrl *TransferController) Export(c *gin.Context) {
+	op := "controllers.transferController.Export"
+	entity := c.Param("entity")
+
+	format := tabular.CSV
+	if name := c.Query("format"); name != "" {
+		parsed, ok := tabular.ParseFormat(name)
+		if !ok {
+			ctrl.logger.Warn("Unknown export format", "format", name, "op", op)
+			ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: format is not valid")
+			return
+		}
+
+		format = parsed
+	} else if parsed, ok := tabular.FormatFromContentType(c.GetHeader("Accept")); ok {
+		format = parsed
+	}
+
+	columns, err := ctrl.exporter.Columns(entity)
+	if err != nil {
+		ctrl.logger.Debug("Unknown export entity", "entity", entity, "op", op)
+		ctrl.problem(c, http.StatusNotFound, "entity is not exportable")
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", entity+"."+string(format)))
+	c.Status(http.StatusOK)
+
+	writer, err := tabular.NewWriter(c.Writer, format, columns)
+	if err != nil {
+		ctrl.logger.Error("Failed to start export", logger.Err(err), "op", op)
+		return
+	}
+
+	// the status is already sent, a failed export ends with a truncated body
+	if err := ctrl.exporter.Export(c.Request.Context(), entity, writer); err != nil {
+		ctrl.logger.Error("Failed to export data", logger.Err(err), "op", op)
+		c.Abort()
+		return
+	}
+
+	ctrl.logger.Debug("Data is exported", "entity", entity, "format", format, "op", op)
+}
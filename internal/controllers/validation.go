@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/services"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// dateLayout is the layout of dates in requests.
+const dateLayout = "2006-01-02"
+
+// rules are validation tags of dtos in addition to the built-in ones.
+var rules = map[string]validator.Func{
+	"date":          isDate,
+	"notfuture":     isNotFuture,
+	"phone":         isPhone,
+	"intl_phone":    isInternationalPhone,
+	"country":       isCountry,
+	"slug":          isSlug,
+	"attribute_key": isAttributeKey,
+	"sku":           isSku,
+	"ean13":         isEAN13,
+}
+
+// SetupValidation registers rules of dtos and makes binding errors name fields
+// as they are named in JSON.
+func SetupValidation() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	for tag, rule := range rules {
+		if err := engine.RegisterValidation(tag, rule); err != nil {
+			panic(fmt.Sprintf("controllers: register validation %s: %v", tag, err))
+		}
+	}
+}
+
+func isDate(fl validator.FieldLevel) bool {
+	_, err := time.Parse(dateLayout, fl.Field().String())
+	return err == nil
+}
+
+// isNotFuture accepts dates up to today, a malformed date is left to the date rule.
+func isNotFuture(fl validator.FieldLevel) bool {
+	date, err := time.Parse(dateLayout, fl.Field().String())
+	if err != nil {
+		return true
+	}
+
+	return !date.After(time.Now().UTC())
+}
+
+func isPhone(fl validator.FieldLevel) bool {
+	return services.IsPhone(fl.Field().String())
+}
+
+func isInternationalPhone(fl validator.FieldLevel) bool {
+	return services.IsInternationalPhone(fl.Field().String())
+}
+
+func isCountry(fl validator.FieldLevel) bool {
+	return services.IsCountry(fl.Field().String())
+}
+
+func isSlug(fl validator.FieldLevel) bool {
+	return services.IsSlug(fl.Field().String())
+}
+
+func isAttributeKey(fl validator.FieldLevel) bool {
+	return services.IsAttributeKey(fl.Field().String())
+}
+
+// isSku accepts an empty value, it removes the SKU of a patch.
+func isSku(fl validator.FieldLevel) bool {
+	return fl.Field().String() == "" || services.IsSku(fl.Field().String())
+}
+
+// isEAN13 accepts an empty value, it removes the barcode of a patch.
+func isEAN13(fl validator.FieldLevel) bool {
+	return fl.Field().String() == "" || services.IsEAN13(fl.Field().String())
+}
+
+// fieldMessage describes the failed rule of the field for people, the tag of
+// the rule is its machine-readable code.
+func fieldMessage(field validator.FieldError) string {
+	name, param := field.Field(), field.Param()
+
+	switch field.Tag() {
+	case "required", "required_if":
+		return name + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", name, strings.Join(strings.Fields(param), ", "))
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", name, param)
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", name, param)
+	case "lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", name, param)
+	case "max":
+		if field.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters long", name, param)
+		}
+
+		return fmt.Sprintf("%s must be at most %s", name, param)
+	case "email":
+		return name + " must be a valid email address"
+	case "date":
+		return name + " must be a date in format YYYY-MM-DD"
+	case "notfuture":
+		return name + " must not be in the future"
+	case "phone":
+		return name + " must contain from 7 to 15 digits"
+	case "intl_phone":
+		return name + " must be an international number starting with + or 00"
+	case "country":
+		return name + " must be an ISO 3166-1 country code or an English country name"
+	case "slug":
+		return name + " must contain lowercase letters and digits separated by single hyphens"
+	case "sku":
+		return name + " must contain up to 64 letters, digits, dots, slashes, underscores and hyphens"
+	case "ean13":
+		return name + " must be an EAN-13 code of 13 digits with a valid check digit"
+	case "attribute_key":
+		return name + " must start with a lowercase letter and contain lowercase letters, digits and underscores"
+	}
+
+	return fmt.Sprintf("%s does not satisfy %s", name, field.Tag())
+}
@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type warehouseService interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Warehouse, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	GetStock(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.WarehouseStock, error)
+	Update(ctx context.Context, id uuid.UUID, patch *domain.WarehousePatch) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+type WarehouseController struct {
+	*BaseController
+	service warehouseService
+}
+
+func NewWarehouseController(service warehouseService, logger *logger.Logger) *WarehouseController {
+	controller := NewBaseContorller(logger)
+	logger.Debug("Warehouse controller is created")
+	return &WarehouseController{
+		BaseController: controller,
+		service:        service,
+	}
+}
+
+// CreateWarehouse godoc
+//
+//	@Summary		Create warehouse
+//	@Description	Warehouse created from JSON or XML, for create endpoint required: name, country, city, street. The address is normalized and shared with equal addresses
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			warehouse	body		dto.WarehouseRequest	true	"Warehouse Data"
+//	@Success		201			{object}	dto.WarehouseResponse
+//	@Failure		400			{object}	domain.Error
+//	@Failure		409			{object}	domain.Error
+//	@Failure		500			{object}	domain.Error
+//	@Router			/api/v1/warehouses [post]
+func (ctrl *WarehouseController) Create(c *gin.Context) {
+	op := "controllers.warehouseController.Create"
+	var input dto.WarehouseRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	if input.Address == nil {
+		ctrl.logger.Warn("Address cannot be empty", "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: address cannot be empty")
+		return
+	}
+
+	warehouse := mapper.WarehouseRequestToDomain(input)
+
+	if err := ctrl.service.Create(c.Request.Context(), &warehouse); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrAddressIsEmpty:    "Invalid request payload: address cannot be empty",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name or address is not valid",
+			crud_errors.ErrDuplicateKeyValue: "warehouse name is already used",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Warehouse created", "id", warehouse.Id, "op", op)
+	setETag(c, warehouse.Version)
+	ctrl.responce(c, http.StatusCreated, mapper.WarehouseToResponse(warehouse))
+}
+
+// GetAllWarehouses godoc
+//
+//	@Summary		Get all warehouses
+//	@Description	That endpoint retrieve warehouses ordered by name
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			limit	query		int	false	"limit get warehouses"
+//	@Param			offset	query		int	false	"offset get warehouses"
+//	@Success		200		{array}		dto.WarehouseResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/warehouses [get]
+func (ctrl *WarehouseController) GetAll(c *gin.Context) {
+	op := "controllers.warehouseController.GetAll"
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	warehouses, err := ctrl.service.GetAll(c.Request.Context(), limit, offset)
+	// an empty page is not an error
+	if err != nil && !errors.Is(err, crud_errors.ErrNotFound) {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+		})
+		return
+	}
+
+	output := make([]dto.WarehouseResponse, len(warehouses))
+
+	for i, warehouse := range warehouses {
+		output[i] = mapper.WarehouseToResponse(warehouse)
+	}
+
+	ctrl.logger.Debug("Retrieved warehouses", "limit", limit, "offset", offset, "op", op)
+	ctrl.responce(c, http.StatusOK, output)
+}
+
+// GetWarehouse godoc
+//
+//	@Summary		Get warehouse by ID
+//	@Description	That endpoint retrieve the warehouse with its address by ID
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id	path		uuid.UUID	true	"Warehouse ID"
+//	@Success		200	{object}	dto.WarehouseResponse
+//	@Header			200	{string}	ETag	"Warehouse version"
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/warehouses/{id} [get]
+func (ctrl *WarehouseController) GetById(c *gin.Context) {
+	op := "controllers.warehouseController.GetById"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	warehouse, err := ctrl.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNotFound: "warehouse not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Warehouse retrieved", "id", id, "op", op)
+	setETag(c, warehouse.Version)
+	ctrl.responce(c, http.StatusOK, mapper.WarehouseToResponse(*warehouse))
+}
+
+// GetWarehouseStock godoc
+//
+//	@Summary		Get warehouse stock
+//	@Description	That endpoint retrieve products stocked in the warehouse with their quantities ordered by product name
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id		path		uuid.UUID	true	"Warehouse ID"
+//	@Param			limit	query		int			false	"limit get products"
+//	@Param			offset	query		int			false	"offset get products"
+//	@Success		200		{array}		dto.WarehouseStockResponse
+//	@Failure		400		{object}	domain.Error
+//	@Failure		404		{object}	domain.Error
+//	@Failure		500		{object}	domain.Error
+//	@Router			/api/v1/warehouses/{id}/stock [get]
+func (ctrl *WarehouseController) GetStock(c *gin.Context) {
+	op := "controllers.warehouseController.GetStock"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultLimit))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert limit value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: limit is not valid")
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", defaultOffset))
+	if err != nil {
+		ctrl.logger.Warn("Failed convert offset value", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalid request payload: offset is not valid")
+		return
+	}
+
+	stock, err := ctrl.service.GetStock(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrInvalidParam: "Invalid request payload: limit cannot be less or equal 0, offset cannot be less than 0",
+			crud_errors.ErrNotFound:     "warehouse not found",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Retrieved warehouse stock", "id", id, "limit", limit, "offset", offset, "op", op)
+	ctrl.responce(c, http.StatusOK, mapper.WarehouseStockToResponse(stock))
+}
+
+// UpdateWarehouse godoc
+//
+//	@Summary		Update warehouse by ID
+//	@Description	That endpoint update set warehouse fields, address fields replace the address of the warehouse
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID					true	"Warehouse ID"
+//	@Param			warehouse	body	dto.WarehouseUpdateRequest	true	"Warehouse fields to change"
+//	@Param			If-Match	header	string						false	"ETag of the warehouse, the update is rejected when it is changed"
+//	@Success		200
+//	@Failure		400	{object}	domain.Error
+//	@Failure		404	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/warehouses/{id} [patch]
+func (ctrl *WarehouseController) Update(c *gin.Context) {
+	op := "controllers.warehouseController.Update"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	var input dto.WarehouseUpdateRequest
+
+	if !ctrl.bind(c, op, &input) {
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	patch := mapper.WarehouseUpdateRequestToPatch(input)
+	patch.Version = version
+
+	if err := ctrl.service.Update(c.Request.Context(), id, &patch); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrNoContent:         "Invalid request payload: invalid data received",
+			crud_errors.ErrInvalidParam:      "Invalid request payload: name or address is not valid",
+			crud_errors.ErrNotFound:          "warehouse not found for update",
+			crud_errors.ErrDuplicateKeyValue: "warehouse name is already used",
+			crud_errors.ErrVersionMismatch:   "warehouse is changed, get it again",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Warehouse updated", "id", id, "op", op)
+	c.Status(http.StatusOK)
+}
+
+// DeleteWarehouse godoc
+//
+//	@Summary		Delete warehouse by ID
+//	@Description	That endpoint delete the warehouse, a warehouse still holding stock is not deleted: transfer or decrease its stock first
+//	@Tags			warehouses
+//	@Accept			json,xml,application/msgpack,application/yaml
+//	@Produce		json,xml,application/msgpack,application/yaml
+//	@Param			id			path	uuid.UUID	true	"Warehouse ID"
+//	@Param			If-Match	header	string		false	"ETag of the warehouse, the delete is rejected when it is changed"
+//	@Success		204
+//	@Failure		400	{object}	domain.Error
+//	@Failure		409	{object}	domain.Error
+//	@Failure		412	{object}	domain.Error
+//	@Failure		500	{object}	domain.Error
+//	@Router			/api/v1/warehouses/{id} [delete]
+func (ctrl *WarehouseController) Delete(c *gin.Context) {
+	op := "controllers.warehouseController.Delete"
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ctrl.logger.Warn("The received identifier is invalid", logger.Err(err), "op", op)
+		ctrl.problem(c, http.StatusBadRequest, "Invalud request payload: id is not valid")
+		return
+	}
+
+	version, ok := ctrl.expectedVersion(c, op)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), id, version); err != nil {
+		ctrl.fail(c, op, err, problemDetails{
+			crud_errors.ErrVersionMismatch:     "warehouse is changed, get it again",
+			crud_errors.ErrForeignKeyViolation: "warehouse holds stock, transfer it first",
+		})
+		return
+	}
+
+	ctrl.logger.Debug("Warehouse deleted", "id", id, "op", op)
+	c.Status(http.StatusNoContent)
+}
@@ -11,10 +11,25 @@ var (
 	ErrRepoIsNotExitst            = errors.New("repository is not exitst")
 	ErrAddressIsExist             = errors.New("address is exists")
 	ErrAddressIsEmpty             = errors.New("address is empty")
+	ErrLastAddress                = errors.New("the only address cannot be removed")
 	ErrForeignKeyViolation        = errors.New("foragin key violation, someone links is alive")
 	ErrDuplicateKeyValue          = errors.New("duplicate key value in unique field")
 	ErrImageCorruption            = errors.New("image is corrupted or input data is not image")
+	ErrImageTooLarge              = errors.New("image dimensions exceed allowed limits")
 	ErrConversionProblem          = errors.New("conversion problem, panic awoided")
 	ErrProductImageDataEmpty      = errors.New("image data in product data is empty")
 	ErrProductSupplerAddressEmpty = errors.New("supplier address data in product data is empty")
+	ErrImportRejected             = errors.New("import contains invalid rows")
+	ErrJobFinished                = errors.New("job is already finished")
+	ErrBatchFailed                = errors.New("batch operation failed")
+	ErrIdempotencyKeyReused       = errors.New("idempotency key is used with another request")
+	ErrIdempotencyKeyInProgress   = errors.New("request with the idempotency key is in progress")
+	ErrVersionMismatch            = errors.New("entity version does not match")
+	ErrInsufficientStock          = errors.New("stock is not enough")
+	ErrVariantStock               = errors.New("stock of a product with variants is kept by the variants")
+	ErrWarehouseRequired          = errors.New("stock of a product kept in warehouses is changed in a warehouse")
+	ErrCartEmpty                  = errors.New("cart has no items")
+	ErrCartClientRequired         = errors.New("anonymous cart cannot be checked out")
+	ErrCartOwned                  = errors.New("cart belongs to another client")
+	ErrCouponNotApplicable        = errors.New("coupon does not discount any item")
 )
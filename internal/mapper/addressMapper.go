@@ -7,16 +7,73 @@ import (
 
 func AddressToDomain(dto dto.Address) domain.Address {
 	return domain.Address{
-		Country: dto.Country,
-		City:    dto.City,
-		Street:  dto.Street,
+		Country:    dto.Country,
+		City:       dto.City,
+		Street:     dto.Street,
+		PostalCode: dto.PostalCode,
+		Region:     dto.Region,
+		Building:   dto.Building,
+		Apartment:  dto.Apartment,
+		Line1:      dto.Line1,
+		Line2:      dto.Line2,
+		Latitude:   dto.Latitude,
+		Longitude:  dto.Longitude,
 	}
 }
 
 func AddressToDto(domain domain.Address) dto.Address {
 	return dto.Address{
-		Country: domain.Country,
-		City:    domain.City,
-		Street:  domain.Street,
+		Country:    domain.Country,
+		City:       domain.City,
+		Street:     domain.Street,
+		PostalCode: domain.PostalCode,
+		Region:     domain.Region,
+		Building:   domain.Building,
+		Apartment:  domain.Apartment,
+		Line1:      domain.Line1,
+		Line2:      domain.Line2,
+		Latitude:   domain.Latitude,
+		Longitude:  domain.Longitude,
+	}
+}
+
+func AddressBookEntryRequestToDomain(dto dto.AddressBookEntryRequest) domain.AddressBookEntry {
+	return domain.AddressBookEntry{
+		Address:   AddressToDomain(dto.Address),
+		Label:     dto.Label,
+		IsDefault: dto.IsDefault,
+	}
+}
+
+func AddressBookEntryUpdateRequestToPatch(dto dto.AddressBookEntryUpdateRequest) domain.AddressBookPatch {
+	return domain.AddressBookPatch{
+		Label:     dto.Label,
+		IsDefault: dto.IsDefault,
+	}
+}
+
+func AddressBookEntryToResponse(entry domain.AddressBookEntry) dto.AddressBookEntryResponse {
+	return dto.AddressBookEntryResponse{
+		Id:        entry.Address.Id,
+		Label:     entry.Label,
+		IsDefault: entry.IsDefault,
+		Version:   entry.Version,
+		Address:   AddressToDto(entry.Address),
+	}
+}
+
+func AddressToResponse(address domain.Address) dto.AddressResponse {
+	return dto.AddressResponse{
+		Id:      address.Id,
+		Address: AddressToDto(address),
+	}
+}
+
+func AddressReferenceToResponse(reference domain.AddressReference) dto.AddressReferenceResponse {
+	return dto.AddressReferenceResponse{
+		OwnerType: reference.OwnerType,
+		OwnerId:   reference.OwnerId,
+		Label:     reference.Label,
+		IsDefault: reference.IsDefault,
 	}
 }
@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+)
+
+func CartToResponse(cart domain.Cart) dto.CartResponse {
+	output := dto.CartResponse{
+		Id:        cart.Id,
+		ClientId:  cart.ClientId,
+		Items:     make([]dto.CartItemResponse, len(cart.Items)),
+		Total:     cart.Total(),
+		CreatedAt: cart.CreatedAt,
+		UpdatedAt: cart.UpdatedAt,
+		ExpiresAt: cart.ExpiresAt,
+	}
+
+	for i, item := range cart.Items {
+		output.Items[i] = dto.CartItemResponse{
+			ProductId:      item.ProductId,
+			Name:           item.Name,
+			Sku:            item.Sku,
+			Price:          item.Price,
+			Quantity:       item.Quantity,
+			Subtotal:       item.Subtotal(),
+			AvailableStock: item.AvailableStock,
+		}
+	}
+
+	for _, adjustment := range cart.Adjustments {
+		output.Adjustments = append(output.Adjustments, dto.CartAdjustmentResponse{
+			ProductId: adjustment.ProductId,
+			Requested: adjustment.Requested,
+			Quantity:  adjustment.Quantity,
+		})
+	}
+
+	return output
+}
+
+func CheckoutRequestToPick(request dto.CheckoutRequest) domain.WarehousePick {
+	return domain.WarehousePick{
+		Strategy:      request.Strategy,
+		DestinationId: request.DestinationAddressId,
+	}
+}
+
+func OrderToResponse(order domain.Order) dto.OrderResponse {
+	output := dto.OrderResponse{
+		Id:        order.Id,
+		ClientId:  order.ClientId,
+		Items:     make([]dto.OrderItemResponse, len(order.Items)),
+		Subtotal:  order.Subtotal,
+		Discount:  order.Discount,
+		Total:     order.Total,
+		CreatedAt: order.CreatedAt,
+	}
+
+	for i, item := range order.Items {
+		output.Items[i] = dto.OrderItemResponse{
+			ProductId:   item.ProductId,
+			Name:        item.Name,
+			Sku:         item.Sku,
+			Price:       item.Price,
+			Quantity:    item.Quantity,
+			Subtotal:    item.Subtotal(),
+			Discount:    item.Discount,
+			PromotionId: item.PromotionId,
+			WarehouseId: item.WarehouseId,
+		}
+	}
+
+	return output
+}
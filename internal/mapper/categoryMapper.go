@@ -0,0 +1,109 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func CategoryRequestToDomain(dto dto.CategoryRequest) domain.Category {
+	return domain.Category{
+		Name:     dto.Name,
+		Slug:     dto.Slug,
+		ParentId: dto.ParentId,
+		Position: dto.Position,
+	}
+}
+
+func CategoryUpdateRequestToPatch(dto dto.CategoryUpdateRequest) (domain.CategoryPatch, error) {
+	patch := domain.CategoryPatch{
+		Name:     dto.Name,
+		Slug:     dto.Slug,
+		Position: dto.Position,
+	}
+
+	if dto.ParentId != nil {
+		parentId := uuid.Nil
+		if *dto.ParentId != "" {
+			var err error
+			if parentId, err = uuid.Parse(*dto.ParentId); err != nil {
+				return domain.CategoryPatch{}, fmt.Errorf("category mapper: %v", err)
+			}
+		}
+
+		patch.ParentId = &parentId
+	}
+
+	return patch, nil
+}
+
+func CategoryToResponse(category domain.Category) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		Id:       category.Id,
+		Name:     category.Name,
+		Slug:     category.Slug,
+		ParentId: category.ParentId,
+		Position: category.Position,
+		Depth:    category.Depth,
+		Path:     category.Path,
+	}
+}
+
+func CategoriesToResponses(categories []domain.Category) []dto.CategoryResponse {
+	responses := make([]dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		responses = append(responses, CategoryToResponse(category))
+	}
+
+	return responses
+}
+
+func CategoryToReference(category domain.Category) dto.CategoryReferenceResponse {
+	return dto.CategoryReferenceResponse{
+		Id:   category.Id,
+		Name: category.Name,
+		Slug: category.Slug,
+	}
+}
+
+func AttributeRequestToDefinition(categoryId uuid.UUID, dto dto.AttributeRequest) domain.AttributeDefinition {
+	return domain.AttributeDefinition{
+		CategoryId: categoryId,
+		Key:        dto.Key,
+		Name:       dto.Name,
+		Type:       domain.AttributeType(dto.Type),
+		Unit:       dto.Unit,
+		Options:    dto.Options,
+		Min:        dto.Min,
+		Max:        dto.Max,
+		Required:   dto.Required,
+		Position:   dto.Position,
+	}
+}
+
+func AttributeDefinitionToResponse(definition domain.AttributeDefinition) dto.AttributeResponse {
+	return dto.AttributeResponse{
+		Id:         definition.Id,
+		CategoryId: definition.CategoryId,
+		Key:        definition.Key,
+		Name:       definition.Name,
+		Type:       string(definition.Type),
+		Unit:       definition.Unit,
+		Options:    definition.Options,
+		Min:        definition.Min,
+		Max:        definition.Max,
+		Required:   definition.Required,
+		Position:   definition.Position,
+	}
+}
+
+func AttributeDefinitionsToResponses(definitions []domain.AttributeDefinition) []dto.AttributeResponse {
+	responses := make([]dto.AttributeResponse, 0, len(definitions))
+	for _, definition := range definitions {
+		responses = append(responses, AttributeDefinitionToResponse(definition))
+	}
+
+	return responses
+}
@@ -4,6 +4,7 @@ import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,8 @@ func ClientDomainToClientResponse(client domain.Client) dto.ClientResponse {
 		Surname:  client.Surname,
 		Birthday: client.Birthday.Format(dateFormat),
 		Gender:   client.Gender,
+		Email:    client.Email,
+		Phone:    client.Phone,
 	}
 
 	if client.Address != nil {
@@ -37,6 +40,8 @@ func ClientRequestToDomain(dto dto.ClientRequest) (domain.Client, error) {
 		Surname:  dto.Surname,
 		Birthday: dtoBirthday,
 		Gender:   dto.Gender,
+		Email:    dto.Email,
+		Phone:    dto.Phone,
 	}
 
 	if dto.Address != nil {
@@ -46,3 +51,97 @@ func ClientRequestToDomain(dto dto.ClientRequest) (domain.Client, error) {
 
 	return client, nil
 }
+
+func ClientUpdateRequestToPatch(dto dto.ClientUpdateRequest) (domain.ClientPatch, error) {
+	patch := domain.ClientPatch{
+		Name:    dto.Name,
+		Surname: dto.Surname,
+		Gender:  dto.Gender,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+	}
+
+	if dto.Birthday != nil {
+		birthday, err := time.Parse(dateFormat, *dto.Birthday)
+		if err != nil {
+			return domain.ClientPatch{}, fmt.Errorf("clinet mapper: %v", err)
+		}
+
+		patch.Birthday = &birthday
+	}
+
+	if dto.Address != nil {
+		address := AddressToDomain(*dto.Address)
+		patch.Address = &address
+	}
+
+	return patch, nil
+}
+
+// ClientRequestToPatch builds a patch replacing every profile field, used by PUT.
+func ClientRequestToPatch(dto dto.ClientRequest) (domain.ClientPatch, error) {
+	client, err := ClientRequestToDomain(dto)
+	if err != nil {
+		return domain.ClientPatch{}, err
+	}
+
+	return domain.ClientPatch{
+		Name:     &client.Name,
+		Surname:  &client.Surname,
+		Birthday: &client.Birthday,
+		Gender:   &client.Gender,
+		Email:    &client.Email,
+		Phone:    &client.Phone,
+		Address:  client.Address,
+	}, nil
+}
+
+func ClientSearchQueryToFilter(dto dto.ClientSearchQuery) (domain.ClientFilter, error) {
+	filter := domain.ClientFilter{
+		Query:    strings.TrimSpace(dto.Query),
+		Name:     strings.TrimSpace(dto.Name),
+		Surname:  strings.TrimSpace(dto.Surname),
+		Gender:   dto.Gender,
+		Country:  strings.TrimSpace(dto.Country),
+		City:     strings.TrimSpace(dto.City),
+		SortBy:   dto.Sort,
+		SortDesc: strings.EqualFold(dto.Order, "desc"),
+		Limit:    dto.Limit,
+		Offset:   dto.Offset,
+	}
+
+	if dto.Order != "" && !strings.EqualFold(dto.Order, "asc") && !filter.SortDesc {
+		return domain.ClientFilter{}, fmt.Errorf("clinet mapper: unknown order %q", dto.Order)
+	}
+
+	// the best match goes first unless the order is given
+	relevance := filter.SortBy == domain.ClientSortRelevance || (filter.SortBy == "" && filter.Query != "")
+	if dto.Order == "" && relevance {
+		filter.SortDesc = true
+	}
+
+	dates := []struct {
+		raw string
+		dst **time.Time
+	}{
+		{dto.BirthdayFrom, &filter.BirthdayFrom},
+		{dto.BirthdayTo, &filter.BirthdayTo},
+		{dto.RegisteredFrom, &filter.RegisteredFrom},
+		{dto.RegisteredTo, &filter.RegisteredTo},
+	}
+
+	for _, date := range dates {
+		if date.raw == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(dateFormat, date.raw)
+		if err != nil {
+			return domain.ClientFilter{}, fmt.Errorf("clinet mapper: %v", err)
+		}
+
+		*date.dst = &parsed
+	}
+
+	return filter, nil
+}
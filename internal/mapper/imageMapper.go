@@ -14,8 +14,19 @@ func ImageRequestToDomain(dto dto.ImageRequest) domain.Image {
 
 func ImageDomainToImageResponse(domain domain.Image) dto.ImageResponse {
 	return dto.ImageResponse{
-		Id:    domain.Id,
-		Title: domain.Title,
-		Image: domain.Data,
+		Id:       domain.Id,
+		Title:    domain.Title,
+		Image:    domain.Data,
+		Metadata: ImageMetadataToDto(domain.Metadata),
+	}
+}
+
+func ImageMetadataToDto(domain domain.ImageMetadata) dto.ImageMetadata {
+	return dto.ImageMetadata{
+		Width:         domain.Width,
+		Height:        domain.Height,
+		Format:        domain.Format,
+		Size:          domain.Size,
+		DominantColor: domain.DominantColor,
 	}
 }
@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+)
+
+func ImportReportToResponse(report domain.ImportReport) dto.ImportReportResponse {
+	output := dto.ImportReportResponse{
+		Entity:   report.Entity,
+		DryRun:   report.DryRun,
+		Total:    report.Total,
+		Valid:    report.Valid,
+		Imported: report.Imported,
+		Failed:   report.Failed,
+	}
+
+	for _, rowErr := range report.Errors {
+		output.Errors = append(output.Errors, dto.ImportRowError{
+			Row:     rowErr.Row,
+			Message: rowErr.Message,
+		})
+	}
+
+	return output
+}
@@ -0,0 +1,52 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+)
+
+func ReorderPolicyRequestToDomain(request dto.ReorderPolicyRequest) domain.ReorderPolicy {
+	return domain.ReorderPolicy{
+		Threshold: request.Threshold,
+		Target:    request.Target,
+	}
+}
+
+func LowStockItemToResponse(item domain.LowStockItem) dto.LowStockItemResponse {
+	return dto.LowStockItemResponse{
+		ProductId:         item.ProductId,
+		ParentId:          item.ParentId,
+		Name:              item.Name,
+		Sku:               item.Sku,
+		Barcode:           item.Barcode,
+		SupplierId:        item.Supplier.Id,
+		SupplierName:      item.Supplier.Name,
+		AvailableStock:    item.AvailableStock,
+		ReorderThreshold:  item.Reorder.Threshold,
+		ReorderTarget:     item.Reorder.Target,
+		SuggestedQuantity: item.SuggestedQuantity(),
+		LowSince:          item.LowSince,
+	}
+}
+
+func ReorderSuggestionToResponse(suggestion domain.ReorderSuggestion) dto.ReorderSuggestionResponse {
+	items := make([]dto.ReorderLineResponse, len(suggestion.Items))
+	for i, item := range suggestion.Items {
+		items[i] = dto.ReorderLineResponse{
+			ProductId:      item.ProductId,
+			Name:           item.Name,
+			Sku:            item.Sku,
+			Barcode:        item.Barcode,
+			AvailableStock: item.AvailableStock,
+			ReorderTarget:  item.Reorder.Target,
+			Quantity:       item.SuggestedQuantity(),
+		}
+	}
+
+	return dto.ReorderSuggestionResponse{
+		SupplierId:    suggestion.Supplier.Id,
+		SupplierName:  suggestion.Supplier.Name,
+		Items:         items,
+		TotalQuantity: suggestion.TotalQuantity(),
+	}
+}
@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+)
+
+func JobToResponse(job domain.Job) dto.JobResponse {
+	output := dto.JobResponse{
+		Id:              job.Id,
+		Kind:            job.Kind,
+		Status:          job.Status,
+		Params:          job.Params,
+		Progress:        job.Progress,
+		Result:          job.Result,
+		Error:           job.Error,
+		Attempts:        job.Attempts,
+		CancelRequested: job.CancelRequested,
+		CreatedAt:       job.CreatedAt,
+	}
+
+	if !job.StartedAt.IsZero() {
+		output.StartedAt = &job.StartedAt
+	}
+
+	if !job.FinishedAt.IsZero() {
+		output.FinishedAt = &job.FinishedAt
+	}
+
+	return output
+}
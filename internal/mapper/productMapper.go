@@ -3,11 +3,81 @@ package mapper
 import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
+const placeholderTitle = "placeholder"
+
+// placeholderData is a 1x1 grey PNG returned for products without images.
+var placeholderData = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 1, 1))
+	img.SetGray(0, 0, color.Gray{Y: 0xcc})
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil
+	}
+
+	return buf.Bytes()
+}()
+
+func PlaceholderImageResponse() dto.ImageResponse {
+	return dto.ImageResponse{
+		Id:    uuid.Nil,
+		Title: placeholderTitle,
+		Image: placeholderData,
+		Metadata: dto.ImageMetadata{
+			Width:         1,
+			Height:        1,
+			Format:        "png",
+			Size:          int64(len(placeholderData)),
+			DominantColor: "#cccccc",
+		},
+	}
+}
+
+func ProductImageDomainToResponse(productImage domain.ProductImage) dto.ProductImageResponse {
+	var image dto.ImageResponse
+
+	if productImage.Image.Data == nil {
+		image.Id = productImage.Image.Id
+
+	} else {
+		image = ImageDomainToImageResponse(productImage.Image)
+	}
+
+	return dto.ProductImageResponse{
+		ImageResponse: image,
+		Position:      productImage.Position,
+		IsPrimary:     productImage.IsPrimary,
+	}
+}
+
+func ProductImageRequestToDomain(request dto.ProductImageRequest) domain.ProductImage {
+	productImage := domain.ProductImage{
+		Image:     domain.Image{Id: request.ImageId},
+		Position:  domain.AppendPosition,
+		IsPrimary: request.IsPrimary,
+	}
+
+	if request.Position != nil {
+		productImage.Position = *request.Position
+	}
+
+	return productImage
+}
+
 func ProductDomainToProductResponse(product domain.Product) dto.ProductResponse {
 	var supplier dto.SupplierResponse
-	var image dto.ImageResponse
 
 	if product.Supplier.Address == nil {
 		supplier.Id = product.Supplier.Id
@@ -16,31 +86,234 @@ func ProductDomainToProductResponse(product domain.Product) dto.ProductResponse
 		supplier = SupplierDomainToSupplierResponse(product.Supplier)
 	}
 
-	if product.Image.Data == nil {
-		image.Id = product.Image.Id
+	gallery := make([]dto.ProductImageResponse, len(product.Images))
+	for i, item := range product.Images {
+		gallery[i] = ProductImageDomainToResponse(item)
+	}
+
+	image := PlaceholderImageResponse()
+	for _, item := range gallery {
+		if item.IsPrimary {
+			image = item.ImageResponse
+			break
+		}
+	}
 
-	} else {
-		image = ImageDomainToImageResponse(product.Image)
+	var variants []dto.ProductVariantResponse
+	for _, variant := range product.Variants {
+		variants = append(variants, ProductVariantToResponse(variant))
+	}
+
+	var reorder *dto.ReorderPolicyResponse
+	if product.Reorder != nil {
+		reorder = &dto.ReorderPolicyResponse{Threshold: product.Reorder.Threshold, Target: product.Reorder.Target}
 	}
 
 	return dto.ProductResponse{
 		Id:             product.Id,
+		ParentId:       product.ParentId,
 		Name:           product.Name,
-		Category:       product.Category,
+		Sku:            product.Sku,
+		Barcode:        product.Barcode,
+		Category:       CategoryToReference(product.Category),
 		Price:          product.Price,
 		AvailableStock: product.AvailableStock,
 		Supplier:       supplier,
 		Image:          image,
+		Gallery:        gallery,
+		Attributes:     AttributesToResponse(product.Attributes),
+		Reorder:        reorder,
+		Variants:       variants,
+	}
+}
+
+func ProductVariantToResponse(variant domain.Product) dto.ProductVariantResponse {
+	response := dto.ProductVariantResponse{
+		Id:             variant.Id,
+		Name:           variant.Name,
+		Sku:            variant.Sku,
+		Barcode:        variant.Barcode,
+		Price:          variant.Price,
+		AvailableStock: variant.AvailableStock,
+		Attributes:     AttributesToResponse(variant.Attributes),
 	}
+
+	if image := variant.PrimaryImage(); image != nil {
+		response.ImageId = &image.Id
+	}
+
+	return response
+}
+
+func ProductVariantRequestToDomain(request dto.ProductVariantRequest) domain.Product {
+	variant := domain.Product{
+		Name:           request.Name,
+		Sku:            request.Sku,
+		Barcode:        request.Barcode,
+		Price:          request.Price,
+		AvailableStock: request.AvailableStock,
+		Attributes:     request.Attributes,
+	}
+
+	if request.ImageId != uuid.Nil {
+		variant.Images = []domain.ProductImage{
+			{Image: domain.Image{Id: request.ImageId}, Position: 0, IsPrimary: true},
+		}
+	}
+
+	return variant
+}
+
+func AttributesToResponse(attributes map[string]any) dto.Attributes {
+	if attributes == nil {
+		return dto.Attributes{}
+	}
+
+	return dto.Attributes(attributes)
 }
 
 func ProductRequestToDomain(request dto.ProductRequest) domain.Product {
-	return domain.Product{
+	product := domain.Product{
 		Name:           request.Name,
-		Category:       request.Category,
+		Category:       domain.Category{Id: request.CategoryId},
 		Price:          request.Price,
 		AvailableStock: request.AvailableStock,
 		Supplier:       domain.Supplier{Id: request.SupplierId},
-		Image:          domain.Image{Id: request.ImageId},
+		Sku:            request.Sku,
+		Barcode:        request.Barcode,
+		Attributes:     request.Attributes,
+	}
+
+	if request.ImageId != uuid.Nil {
+		product.Images = []domain.ProductImage{
+			{Image: domain.Image{Id: request.ImageId}, Position: 0, IsPrimary: true},
+		}
 	}
+
+	return product
+}
+
+func ProductUpdateRequestToPatch(request dto.ProductUpdateRequest) domain.ProductPatch {
+	return domain.ProductPatch{
+		Name:       request.Name,
+		CategoryId: request.CategoryId,
+		Price:      request.Price,
+		SupplierId: request.SupplierId,
+		ImageId:    request.ImageId,
+		Sku:        request.Sku,
+		Barcode:    request.Barcode,
+		Attributes: request.Attributes,
+	}
+}
+
+func ProductStockRequestToMovement(productId uuid.UUID, operation domain.StockOperation, request dto.ProductStockRequest) domain.StockMovement {
+	return domain.StockMovement{
+		ProductId:   productId,
+		Operation:   operation,
+		Quantity:    request.Quantity,
+		Reason:      request.Reason,
+		Note:        request.Note,
+		WarehouseId: request.WarehouseId,
+	}
+}
+
+func ProductStockRequestToPick(request dto.ProductStockRequest) domain.WarehousePick {
+	return domain.WarehousePick{
+		Strategy:      request.Strategy,
+		DestinationId: request.DestinationAddressId,
+	}
+}
+
+func ProductStockTransferRequestToMovement(productId uuid.UUID, request dto.ProductStockTransferRequest) domain.StockMovement {
+	return domain.StockMovement{
+		ProductId:     productId,
+		Operation:     domain.StockTransfer,
+		Quantity:      request.Quantity,
+		Reason:        domain.StockReasonTransfer,
+		Note:          request.Note,
+		WarehouseId:   &request.FromWarehouseId,
+		ToWarehouseId: &request.ToWarehouseId,
+	}
+}
+
+func StockMovementToResponse(movement domain.StockMovement) dto.StockMovementResponse {
+	return dto.StockMovementResponse{
+		Id:            movement.Id,
+		ProductId:     movement.ProductId,
+		Operation:     string(movement.Operation),
+		Quantity:      movement.Quantity,
+		Reason:        movement.Reason,
+		Note:          movement.Note,
+		StockBefore:   movement.StockBefore,
+		StockAfter:    movement.StockAfter,
+		WarehouseId:   movement.WarehouseId,
+		ToWarehouseId: movement.ToWarehouseId,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
+
+func StockMovementsToResponse(movements []domain.StockMovement) []dto.StockMovementResponse {
+	output := make([]dto.StockMovementResponse, len(movements))
+	for i, movement := range movements {
+		output[i] = StockMovementToResponse(movement)
+	}
+
+	return output
+}
+
+func ProductListQueryToFilter(dto dto.ProductListQuery) (domain.ProductFilter, error) {
+	filter := domain.ProductFilter{
+		Query:    strings.TrimSpace(dto.Query),
+		Category: strings.TrimSpace(dto.Category),
+		PriceMin: dto.PriceMin,
+		PriceMax: dto.PriceMax,
+		InStock:  dto.InStock,
+		SortBy:   dto.Sort,
+		SortDesc: strings.EqualFold(dto.Order, "desc"),
+		Limit:    dto.Limit,
+		Offset:   dto.Offset,
+	}
+
+	if dto.Order != "" && !strings.EqualFold(dto.Order, "asc") && !filter.SortDesc {
+		return domain.ProductFilter{}, fmt.Errorf("product mapper: unknown order %q", dto.Order)
+	}
+
+	for _, raw := range dto.Attributes {
+		attribute, err := AttributeFilterFromQuery(raw)
+		if err != nil {
+			return domain.ProductFilter{}, err
+		}
+
+		filter.Attributes = append(filter.Attributes, attribute)
+	}
+
+	return filter, nil
+}
+
+// attributeFilterPattern splits a filter like capacity>=300 into the key, the
+// operator and the value, longer operators go first.
+var attributeFilterPattern = regexp.MustCompile(`^([a-z][a-z0-9_]*)(>=|<=|=|>|<)(.+)$`)
+
+// AttributeFilterFromQuery parses an attribute filter of a product listing.
+// The value is a number, true or false, or a string otherwise, comparisons
+// other than equality take numbers only.
+func AttributeFilterFromQuery(raw string) (domain.AttributeFilter, error) {
+	match := attributeFilterPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		return domain.AttributeFilter{}, fmt.Errorf("product mapper: invalid attribute filter %q", raw)
+	}
+
+	filter := domain.AttributeFilter{Key: match[1], Operator: match[2], Value: match[3]}
+
+	if number, err := strconv.ParseFloat(match[3], 64); err == nil {
+		filter.Value = number
+	} else if match[3] == "true" || match[3] == "false" {
+		filter.Value = match[3] == "true"
+	}
+
+	if _, ok := filter.Value.(float64); !ok && filter.Operator != domain.AttributeEq {
+		return domain.AttributeFilter{}, fmt.Errorf("product mapper: attribute %q is compared with a number only", filter.Key)
+	}
+
+	return filter, nil
 }
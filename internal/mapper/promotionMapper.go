@@ -0,0 +1,130 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"time"
+)
+
+func PromotionToResponse(promotion domain.Promotion) dto.PromotionResponse {
+	return dto.PromotionResponse{
+		Id:             promotion.Id,
+		Name:           promotion.Name,
+		Kind:           promotion.Kind,
+		Value:          promotion.Value,
+		ProductId:      promotion.ProductId,
+		CategoryId:     promotion.CategoryId,
+		SupplierId:     promotion.SupplierId,
+		MinQuantity:    promotion.MinQuantity,
+		StartsAt:       promotion.StartsAt,
+		EndsAt:         promotion.EndsAt,
+		UsageLimit:     promotion.UsageLimit,
+		PerClientLimit: promotion.PerClientLimit,
+		CouponCode:     promotion.CouponCode,
+		Active:         promotion.Active,
+		CreatedAt:      promotion.CreatedAt,
+	}
+}
+
+func PromotionRequestToDomain(request dto.PromotionRequest) domain.Promotion {
+	promotion := domain.Promotion{
+		Name:           request.Name,
+		Kind:           request.Kind,
+		Value:          request.Value,
+		ProductId:      request.ProductId,
+		CategoryId:     request.CategoryId,
+		SupplierId:     request.SupplierId,
+		MinQuantity:    request.MinQuantity,
+		StartsAt:       utcTime(request.StartsAt),
+		EndsAt:         utcTime(request.EndsAt),
+		UsageLimit:     request.UsageLimit,
+		PerClientLimit: request.PerClientLimit,
+		CouponCode:     request.CouponCode,
+		Active:         true,
+	}
+
+	if request.Active != nil {
+		promotion.Active = *request.Active
+	}
+
+	return promotion
+}
+
+func PromotionUpdateRequestToPatch(request dto.PromotionUpdateRequest) domain.PromotionPatch {
+	return domain.PromotionPatch{
+		Name:           request.Name,
+		Active:         request.Active,
+		StartsAt:       utcTime(request.StartsAt),
+		EndsAt:         utcTime(request.EndsAt),
+		UsageLimit:     request.UsageLimit,
+		PerClientLimit: request.PerClientLimit,
+	}
+}
+
+func PromotionItemsToDomain(items []dto.PromotionItemRequest) []domain.PromotionItem {
+	output := make([]domain.PromotionItem, len(items))
+	for i, item := range items {
+		output[i] = domain.PromotionItem{
+			ProductId: item.ProductId,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return output
+}
+
+func PromotionEvaluationToResponse(evaluation domain.PromotionEvaluation) dto.PromotionEvaluationResponse {
+	output := dto.PromotionEvaluationResponse{
+		ClientId: evaluation.ClientId,
+		Items:    make([]dto.PromotionLineResponse, len(evaluation.Lines)),
+		Subtotal: evaluation.Subtotal(),
+		Discount: evaluation.Discount(),
+		Total:    evaluation.Total(),
+		Notes:    make([]dto.PromotionNoteResponse, len(evaluation.Notes)),
+	}
+
+	for i, line := range evaluation.Lines {
+		output.Items[i] = dto.PromotionLineResponse{
+			ProductId: line.ProductId,
+			Name:      line.Name,
+			Price:     line.Price,
+			Quantity:  line.Quantity,
+			Subtotal:  line.Subtotal(),
+			Discount:  line.Discount,
+			Total:     line.Total(),
+		}
+
+		if line.Promotion != nil {
+			output.Items[i].Promotion = &dto.AppliedPromotionResponse{
+				Id:          line.Promotion.Id,
+				Name:        line.Promotion.Name,
+				CouponCode:  line.Promotion.CouponCode,
+				Explanation: line.Promotion.Explanation,
+			}
+		}
+	}
+
+	for i, note := range evaluation.Notes {
+		output.Notes[i] = dto.PromotionNoteResponse{
+			PromotionId: note.PromotionId,
+			Name:        note.Name,
+			CouponCode:  note.CouponCode,
+			ProductId:   note.ProductId,
+			Reason:      note.Reason,
+			Message:     note.Message,
+		}
+	}
+
+	return output
+}
+
+// utcTime keeps the instant of the time in UTC, timestamps are stored without
+// a time zone.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,262 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Columns of imported and exported files. Ids and dates assigned by the store
+// are exported only, they are ignored on import.
+var (
+	addressRecordColumns = []string{
+		"country", "region", "city", "postal_code", "street",
+		"building", "apartment", "line1", "line2", "latitude", "longitude",
+	}
+
+	ProductRecordColumns = []string{
+		"id", "name", "category", "price", "available_stock",
+		"supplier_id", "supplier_name", "last_update_date", "attributes",
+	}
+
+	SupplierRecordColumns = append([]string{
+		"id", "name", "phone_number", "contact_person", "email", "website", "tax_id",
+	}, addressRecordColumns...)
+
+	ClientRecordColumns = append([]string{
+		"id", "name", "surname", "birthday", "gender", "email", "phone", "registration_date",
+	}, addressRecordColumns...)
+
+	PurchaseOrderRecordColumns = []string{
+		"supplier_id", "supplier_name", "product_id", "sku", "barcode", "name",
+		"available_stock", "reorder_threshold", "reorder_target", "quantity",
+	}
+)
+
+func ProductToRecord(product domain.Product) []any {
+	return []any{
+		product.Id,
+		product.Name,
+		product.Category.Slug,
+		product.Price,
+		product.AvailableStock,
+		product.Supplier.Id,
+		product.Supplier.Name,
+		product.LastUpdateDate,
+		attributesToRecord(product.Attributes),
+	}
+}
+
+// LowStockItemToRecord writes the item as a line of the purchase order of its
+// supplier.
+func LowStockItemToRecord(item domain.LowStockItem) []any {
+	return []any{
+		item.Supplier.Id,
+		item.Supplier.Name,
+		item.ProductId,
+		item.Sku,
+		item.Barcode,
+		item.Name,
+		item.AvailableStock,
+		item.Reorder.Threshold,
+		item.Reorder.Target,
+		item.SuggestedQuantity(),
+	}
+}
+
+// attributesToRecord writes attributes as a JSON object, no attributes give an
+// empty value.
+func attributesToRecord(attributes map[string]any) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		return ""
+	}
+
+	return string(raw)
+}
+
+// RecordToProduct reads the product, the supplier is referenced by
+// supplier_id or supplier_name.
+func RecordToProduct(record map[string]string) (domain.Product, error) {
+	product := domain.Product{
+		Name:     record["name"],
+		Category: domain.Category{Slug: record["category"]},
+		Supplier: domain.Supplier{Name: record["supplier_name"]},
+	}
+
+	price, err := strconv.ParseFloat(record["price"], 32)
+	if err != nil {
+		return domain.Product{}, fmt.Errorf("price is not a number")
+	}
+
+	stock, err := strconv.ParseInt(record["available_stock"], 10, 64)
+	if err != nil {
+		return domain.Product{}, fmt.Errorf("available_stock is not an integer")
+	}
+
+	product.Price = float32(price)
+	product.AvailableStock = stock
+
+	if raw := record["supplier_id"]; raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return domain.Product{}, fmt.Errorf("supplier_id is not valid")
+		}
+
+		product.Supplier.Id = id
+	}
+
+	if raw := record["attributes"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &product.Attributes); err != nil || product.Attributes == nil {
+			return domain.Product{}, fmt.Errorf("attributes is not a JSON object")
+		}
+	}
+
+	return product, nil
+}
+
+func SupplierToRecord(supplier domain.Supplier) []any {
+	return append([]any{
+		supplier.Id,
+		supplier.Name,
+		supplier.PhoneNumber,
+		supplier.ContactPerson,
+		supplier.Email,
+		supplier.Website,
+		supplier.TaxId,
+	}, addressToRecord(supplier.Address)...)
+}
+
+func RecordToSupplier(record map[string]string) (domain.Supplier, error) {
+	address, err := recordToAddress(record)
+	if err != nil {
+		return domain.Supplier{}, err
+	}
+
+	return domain.Supplier{
+		Name:          record["name"],
+		PhoneNumber:   record["phone_number"],
+		ContactPerson: record["contact_person"],
+		Email:         record["email"],
+		Website:       record["website"],
+		TaxId:         record["tax_id"],
+		Address:       address,
+	}, nil
+}
+
+func ClientToRecord(client domain.Client) []any {
+	var birthday string
+	if !client.Birthday.IsZero() {
+		birthday = client.Birthday.Format(dateFormat)
+	}
+
+	return append([]any{
+		client.Id,
+		client.Name,
+		client.Surname,
+		birthday,
+		client.Gender,
+		client.Email,
+		client.Phone,
+		client.RegistrationDate,
+	}, addressToRecord(client.Address)...)
+}
+
+func RecordToClient(record map[string]string) (domain.Client, error) {
+	birthday, err := time.Parse(dateFormat, record["birthday"])
+	if err != nil {
+		return domain.Client{}, fmt.Errorf("birthday is not a YYYY-MM-DD date")
+	}
+
+	address, err := recordToAddress(record)
+	if err != nil {
+		return domain.Client{}, err
+	}
+
+	return domain.Client{
+		Name:     record["name"],
+		Surname:  record["surname"],
+		Birthday: birthday,
+		Gender:   record["gender"],
+		Email:    record["email"],
+		Phone:    record["phone"],
+		Address:  address,
+	}, nil
+}
+
+func addressToRecord(address *domain.Address) []any {
+	if address == nil {
+		return make([]any, len(addressRecordColumns))
+	}
+
+	return []any{
+		address.Country,
+		address.Region,
+		address.City,
+		address.PostalCode,
+		address.Street,
+		address.Building,
+		address.Apartment,
+		address.Line1,
+		address.Line2,
+		address.Latitude,
+		address.Longitude,
+	}
+}
+
+// recordToAddress returns nil when no address column is filled.
+func recordToAddress(record map[string]string) (*domain.Address, error) {
+	empty := true
+	for _, column := range addressRecordColumns {
+		if record[column] != "" {
+			empty = false
+			break
+		}
+	}
+
+	if empty {
+		return nil, nil
+	}
+
+	address := &domain.Address{
+		Country:    record["country"],
+		Region:     record["region"],
+		City:       record["city"],
+		PostalCode: record["postal_code"],
+		Street:     record["street"],
+		Building:   record["building"],
+		Apartment:  record["apartment"],
+		Line1:      record["line1"],
+		Line2:      record["line2"],
+	}
+
+	for _, coordinate := range []struct {
+		column string
+		target **float64
+	}{
+		{"latitude", &address.Latitude},
+		{"longitude", &address.Longitude},
+	} {
+		raw := record[coordinate.column]
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", coordinate.column)
+		}
+
+		*coordinate.target = &value
+	}
+
+	return address, nil
+}
@@ -6,20 +6,61 @@ import (
 )
 
 func SupplierDomainToSupplierResponse(supplier domain.Supplier) dto.SupplierResponse {
-	address := AddressToDto(*supplier.Address)
-	return dto.SupplierResponse{
-		Id:          supplier.Id,
-		Name:        supplier.Name,
-		PhoneNumber: supplier.PhoneNumber,
-		Address:     &address,
+	output := dto.SupplierResponse{
+		Id:            supplier.Id,
+		Name:          supplier.Name,
+		PhoneNumber:   supplier.PhoneNumber,
+		ContactPerson: supplier.ContactPerson,
+		Email:         supplier.Email,
+		Website:       supplier.Website,
+		TaxId:         supplier.TaxId,
 	}
+
+	if supplier.Address != nil {
+		address := AddressToDto(*supplier.Address)
+		output.Address = &address
+	}
+
+	return output
 }
 
 func SupplierRequestToDomain(supplier dto.SupplierRequest) domain.Supplier {
 	address := AddressToDomain(*supplier.Address)
 	return domain.Supplier{
-		Name:        supplier.Name,
-		PhoneNumber: supplier.PhoneNumber,
-		Address:     &address,
+		Name:          supplier.Name,
+		PhoneNumber:   supplier.PhoneNumber,
+		ContactPerson: supplier.ContactPerson,
+		Email:         supplier.Email,
+		Website:       supplier.Website,
+		TaxId:         supplier.TaxId,
+		Address:       &address,
+	}
+}
+
+func SupplierUpdateRequestToPatch(dto dto.SupplierUpdateRequest) domain.SupplierPatch {
+	patch := domain.SupplierPatch{
+		Name:          dto.Name,
+		PhoneNumber:   dto.PhoneNumber,
+		ContactPerson: dto.ContactPerson,
+		Email:         dto.Email,
+		Website:       dto.Website,
+		TaxId:         dto.TaxId,
+	}
+
+	if dto.Address != nil {
+		address := AddressToDomain(*dto.Address)
+		patch.Address = &address
+	}
+
+	return patch
+}
+
+func SupplierStatsToResponse(stats domain.SupplierStats) dto.SupplierStatsResponse {
+	return dto.SupplierStatsResponse{
+		ProductCount:    stats.ProductCount,
+		TotalStockUnits: stats.TotalStockUnits,
+		TotalStockValue: stats.TotalStockValue,
+		OutOfStockCount: stats.OutOfStockCount,
+		LastDeliveryAt:  stats.LastDeliveryAt,
 	}
 }
@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+)
+
+func WarehouseToResponse(warehouse domain.Warehouse) dto.WarehouseResponse {
+	output := dto.WarehouseResponse{
+		Id:        warehouse.Id,
+		Name:      warehouse.Name,
+		CreatedAt: warehouse.CreatedAt,
+	}
+
+	if warehouse.Address != nil {
+		address := AddressToDto(*warehouse.Address)
+		output.AddressId = warehouse.Address.Id
+		output.Address = &address
+	}
+
+	return output
+}
+
+func WarehouseRequestToDomain(request dto.WarehouseRequest) domain.Warehouse {
+	address := AddressToDomain(*request.Address)
+	return domain.Warehouse{
+		Name:    request.Name,
+		Address: &address,
+	}
+}
+
+func WarehouseUpdateRequestToPatch(request dto.WarehouseUpdateRequest) domain.WarehousePatch {
+	patch := domain.WarehousePatch{
+		Name: request.Name,
+	}
+
+	if request.Address != nil {
+		address := AddressToDomain(*request.Address)
+		patch.Address = &address
+	}
+
+	return patch
+}
+
+func WarehouseStockToResponse(stock []domain.WarehouseStock) []dto.WarehouseStockResponse {
+	output := make([]dto.WarehouseStockResponse, len(stock))
+	for i, item := range stock {
+		output[i] = dto.WarehouseStockResponse{
+			WarehouseId:   item.WarehouseId,
+			WarehouseName: item.WarehouseName,
+			ProductId:     item.ProductId,
+			ProductName:   item.ProductName,
+			Sku:           item.Sku,
+			Quantity:      item.Quantity,
+		}
+	}
+
+	return output
+}
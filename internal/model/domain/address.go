@@ -3,8 +3,32 @@ package domain
 import "github.com/google/uuid"
 
 type Address struct {
-	Id      uuid.UUID `json:"id,omitempty" bson:"_id,omitempty"`
-	Country string    `json:"country" bson:"country"`
-	City    string    `json:"city" bson:"city"`
-	Street  string    `json:"street" bson:"street"`
+	Id         uuid.UUID `json:"id,omitempty" bson:"_id,omitempty"`
+	Country    string    `json:"country" bson:"country"`
+	City       string    `json:"city" bson:"city"`
+	Street     string    `json:"street" bson:"street"`
+	PostalCode string    `json:"postal_code,omitempty" bson:"postal_code,omitempty"`
+	Region     string    `json:"region,omitempty" bson:"region,omitempty"`
+	Building   string    `json:"building,omitempty" bson:"building,omitempty"`
+	Apartment  string    `json:"apartment,omitempty" bson:"apartment,omitempty"`
+	Line1      string    `json:"line1,omitempty" bson:"line1,omitempty"`
+	Line2      string    `json:"line2,omitempty" bson:"line2,omitempty"`
+	Latitude   *float64  `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty" bson:"longitude,omitempty"`
+}
+
+const (
+	AddressOwnerClient    = "client"
+	AddressOwnerSupplier  = "supplier"
+	AddressOwnerWarehouse = "warehouse"
+)
+
+// AddressReference is an address book entry of a client or supplier pointing
+// to the address or a warehouse located at it, the label of a warehouse is its
+// name.
+type AddressReference struct {
+	OwnerType string    `json:"owner_type" bson:"owner_type"`
+	OwnerId   uuid.UUID `json:"owner_id" bson:"owner_id"`
+	Label     string    `json:"label" bson:"label"`
+	IsDefault bool      `json:"is_default" bson:"is_default"`
 }
@@ -0,0 +1,31 @@
+package domain
+
+// Client address labels.
+const (
+	AddressLabelBilling  = "billing"
+	AddressLabelShipping = "shipping"
+	AddressLabelHome     = "home"
+)
+
+// Supplier location labels.
+const (
+	AddressLabelWarehouse = "warehouse"
+	AddressLabelOffice    = "office"
+)
+
+// AddressBookEntry is an address linked to a client or a supplier. The owner has
+// at most one default entry, it is exposed as the owner address.
+type AddressBookEntry struct {
+	Address   Address
+	Label     string
+	IsDefault bool
+	Version   int64
+}
+
+// AddressBookPatch holds the entry fields to change, nil fields are left as is.
+type AddressBookPatch struct {
+	Label     *string
+	IsDefault *bool
+	// Version is the expected version of the entry, 0 skips the check
+	Version int64
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "github.com/google/uuid"
+
+// AttributeType is the type of attribute values.
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeInteger AttributeType = "integer"
+	AttributeBoolean AttributeType = "boolean"
+	// AttributeEnum values are strings from the options of the attribute
+	AttributeEnum AttributeType = "enum"
+)
+
+// AttributeDefinition describes an attribute of the products of a category and
+// of its subcategories.
+type AttributeDefinition struct {
+	Id         uuid.UUID
+	CategoryId uuid.UUID
+	Key        string
+	Name       string
+	Type       AttributeType
+	Unit       string
+	Options    []string
+	// Min and Max bound number and integer values
+	Min      *float64
+	Max      *float64
+	Required bool
+	Position int
+}
+
+// Operators of attribute filters.
+const (
+	AttributeEq  = "="
+	AttributeGte = ">="
+	AttributeLte = "<="
+	AttributeGt  = ">"
+	AttributeLt  = "<"
+)
+
+// AttributeFilter matches products by an attribute value. Value is a string,
+// a float64 or a bool, comparisons other than equality take numbers only.
+type AttributeFilter struct {
+	Key      string
+	Operator string
+	Value    any
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cart holds products a client or an anonymous visitor is going to buy. The
+// cart expires when it is not changed until ExpiresAt, an anonymous cart has
+// no ClientId.
+type Cart struct {
+	Id       uuid.UUID
+	ClientId *uuid.UUID
+	Items    []CartItem
+	Version  int64
+	// UpdatedAt is the last change of the cart or its items
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	// Adjustments are the items a merge has lowered to the product stock, they
+	// are not stored
+	Adjustments []CartAdjustment
+}
+
+// CartAdjustment is an item whose merged quantity exceeds the product stock,
+// the quantity is lowered to the stock and a zero quantity removes the item.
+type CartAdjustment struct {
+	ProductId uuid.UUID
+	Requested int64
+	Quantity  int64
+}
+
+// CartItem is a product in the cart with its current name, price and stock.
+type CartItem struct {
+	ProductId      uuid.UUID
+	Name           string
+	Sku            string
+	Price          float32
+	Quantity       int64
+	AvailableStock int64
+	AddedAt        time.Time
+}
+
+// Subtotal is the price of the item quantity rounded to cents.
+func (i CartItem) Subtotal() float64 {
+	return roundCents(float64(i.Price) * float64(i.Quantity))
+}
+
+// Item returns the item of the product or nil when the cart has no such
+// product.
+func (c *Cart) Item(productId uuid.UUID) *CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductId == productId {
+			return &c.Items[i]
+		}
+	}
+
+	return nil
+}
+
+// Total is the sum of the item subtotals.
+func (c *Cart) Total() float64 {
+	var total float64
+	for _, item := range c.Items {
+		total += item.Subtotal()
+	}
+
+	return roundCents(total)
+}
+
+// Order is placed by the checkout of a client cart. Items keep the name, the
+// price and the discount of products at the checkout.
+type Order struct {
+	Id uuid.UUID
+	// ClientId is nil when the client is deleted
+	ClientId *uuid.UUID
+	Items    []OrderItem
+	Subtotal float64
+	Discount float64
+	// Total is the subtotal less the discount
+	Total     float64
+	CreatedAt time.Time
+}
+
+// OrderItem is a product sold by the order.
+type OrderItem struct {
+	// ProductId is nil when the product is deleted
+	ProductId *uuid.UUID
+	Name      string
+	Sku       string
+	Price     float32
+	Quantity  int64
+	// WarehouseId is the warehouse the stock is taken from, nil for products
+	// not stocked in warehouses
+	WarehouseId *uuid.UUID
+	Discount    float64
+	// PromotionId is the promotion giving the discount, nil without one or
+	// when the promotion is deleted
+	PromotionId *uuid.UUID
+}
+
+// Subtotal is the price of the item quantity rounded to cents.
+func (i OrderItem) Subtotal() float64 {
+	return roundCents(float64(i.Price) * float64(i.Quantity))
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
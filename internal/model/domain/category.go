@@ -0,0 +1,54 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Category is a node of the product taxonomy. Depth and Path are filled in when
+// categories are read as a tree.
+type Category struct {
+	Id       uuid.UUID
+	Name     string
+	Slug     string
+	ParentId *uuid.UUID
+	Position int
+	// Depth is 0 for top level categories
+	Depth int
+	// Path is slugs from the top level category joined by "/"
+	Path string
+}
+
+// CategoryPatch holds the category fields to change, nil fields are left as is.
+type CategoryPatch struct {
+	Name *string
+	Slug *string
+	// ParentId moves the category, uuid.Nil moves it to the top level
+	ParentId *uuid.UUID
+	Position *int
+}
+
+// Apply copies the set fields into the category.
+func (p *CategoryPatch) Apply(category *Category) {
+	if p.Name != nil {
+		category.Name = *p.Name
+	}
+
+	if p.Slug != nil {
+		category.Slug = *p.Slug
+	}
+
+	if p.ParentId != nil {
+		category.ParentId = nil
+		if *p.ParentId != uuid.Nil {
+			parentId := *p.ParentId
+			category.ParentId = &parentId
+		}
+	}
+
+	if p.Position != nil {
+		category.Position = *p.Position
+	}
+}
+
+// IsEmpty reports whether no field is set.
+func (p *CategoryPatch) IsEmpty() bool {
+	return p.Name == nil && p.Slug == nil && p.ParentId == nil && p.Position == nil
+}
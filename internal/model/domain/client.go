@@ -7,11 +7,61 @@ import (
 )
 
 type Client struct {
-	Id               uuid.UUID `json:"id,omitempty" bson:"_id,omitempty"`
-	Name             string    `json:"client_name" bson:"client_name"`
-	Surname          string    `json:"client_surname" bson:"client_surname"`
-	Birthday         time.Time `json:"birthday" bson:"birthday"`
-	Gender           string    `json:"gender" bson:"gender"`
-	RegistrationDate time.Time `json:"registration_date" bson:"registration_date"`
-	Address          *Address  `json:"address,omitempty" bson:"address,omitempty"`
+	Id               uuid.UUID          `json:"id,omitempty" bson:"_id,omitempty"`
+	Name             string             `json:"client_name" bson:"client_name"`
+	Surname          string             `json:"client_surname" bson:"client_surname"`
+	Birthday         time.Time          `json:"birthday" bson:"birthday"`
+	Gender           string             `json:"gender" bson:"gender"`
+	Email            string             `json:"email,omitempty" bson:"email,omitempty"`
+	Phone            string             `json:"phone,omitempty" bson:"phone,omitempty"`
+	RegistrationDate time.Time          `json:"registration_date" bson:"registration_date"`
+	Address          *Address           `json:"address,omitempty" bson:"address,omitempty"`
+	Addresses        []AddressBookEntry `json:"addresses,omitempty" bson:"addresses,omitempty"`
+	Version          int64              `json:"version" bson:"version"`
+}
+
+// ClientPatch holds the client fields to change, nil fields are left as is.
+type ClientPatch struct {
+	Name     *string
+	Surname  *string
+	Birthday *time.Time
+	Gender   *string
+	Email    *string
+	Phone    *string
+	Address  *Address
+	// Version is the expected version of the client, 0 skips the check
+	Version int64
+}
+
+// Apply copies the set profile fields into the client. Address is not touched,
+// it is stored separately.
+func (p *ClientPatch) Apply(client *Client) {
+	if p.Name != nil {
+		client.Name = *p.Name
+	}
+
+	if p.Surname != nil {
+		client.Surname = *p.Surname
+	}
+
+	if p.Birthday != nil {
+		client.Birthday = *p.Birthday
+	}
+
+	if p.Gender != nil {
+		client.Gender = *p.Gender
+	}
+
+	if p.Email != nil {
+		client.Email = *p.Email
+	}
+
+	if p.Phone != nil {
+		client.Phone = *p.Phone
+	}
+}
+
+// HasProfile reports whether any field besides address is set.
+func (p *ClientPatch) HasProfile() bool {
+	return p.Name != nil || p.Surname != nil || p.Birthday != nil || p.Gender != nil || p.Email != nil || p.Phone != nil
 }
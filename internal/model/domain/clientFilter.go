@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	ClientSortRelevance        = "relevance"
+	ClientSortName             = "name"
+	ClientSortSurname          = "surname"
+	ClientSortBirthday         = "birthday"
+	ClientSortRegistrationDate = "registration_date"
+)
+
+// ClientFilter describes the client search, zero fields are not applied.
+// Query is matched partially and fuzzily against name and surname, Name and
+// Surname are compared case-insensitively, Country is an alpha-2 code.
+type ClientFilter struct {
+	Query          string
+	Name           string
+	Surname        string
+	Gender         string
+	BirthdayFrom   *time.Time
+	BirthdayTo     *time.Time
+	RegisteredFrom *time.Time
+	RegisteredTo   *time.Time
+	Country        string
+	City           string
+	SortBy         string
+	SortDesc       bool
+	Limit          int
+	Offset         int
+}
@@ -1,6 +1,24 @@
 package domain
 
+import "encoding/xml"
+
+// Error is the problem details body of RFC 7807, every error response of the
+// API is the Error served as application/problem+json or application/problem+xml.
 type Error struct {
-	Key string `json:"key"`
-	Val string `json:"value"`
+	XMLName xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	// Type is the URI of the problem type, about:blank when only status matters
+	Type      string       `json:"type" xml:"type"`
+	Title     string       `json:"title" xml:"title"`
+	Status    int          `json:"status" xml:"status"`
+	Detail    string       `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty" xml:"instance,omitempty"`
+	RequestId string       `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// FieldError describes an invalid field of the request.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
 }
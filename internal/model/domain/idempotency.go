@@ -0,0 +1,21 @@
+package domain
+
+// IdempotentResponse is a stored response replayed for a repeated request.
+// ETag and Location are kept, so a replayed response can be followed up like
+// the original one.
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	ETag        string
+	Location    string
+	Body        []byte
+}
+
+// IdempotencyRecord is a request made with an Idempotency-Key. The response is
+// set when the request is completed.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	Response    IdempotentResponse
+}
@@ -3,8 +3,18 @@ package domain
 import "github.com/google/uuid"
 
 type Image struct {
-	Id    uuid.UUID `json:"id,omitempty" bson:"_id,omitempty"`
-	Title string    `json:"title" bson:"title"`
-	Data  []byte    `json:"data" bson:"data"`
+	Id       uuid.UUID     `json:"id,omitempty" bson:"_id,omitempty"`
+	Title    string        `json:"title" bson:"title"`
+	Data     []byte        `json:"data" bson:"data"`
+	Metadata ImageMetadata `json:"metadata" bson:"metadata"`
+	Version  int64         `json:"version" bson:"version"`
 	// Hash string    `json:"hash" bson:"hash"`
 }
+
+type ImageMetadata struct {
+	Width         int    `json:"width" bson:"width"`
+	Height        int    `json:"height" bson:"height"`
+	Format        string `json:"format" bson:"format"`
+	Size          int64  `json:"size" bson:"size"`
+	DominantColor string `json:"dominant_color" bson:"dominant_color"`
+}
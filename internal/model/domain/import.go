@@ -0,0 +1,41 @@
+package domain
+
+// Entities accepted by import and export.
+const (
+	ImportProducts  = "products"
+	ImportSuppliers = "suppliers"
+	ImportClients   = "clients"
+)
+
+// ExportPurchaseOrders is exported only, it is the reorder suggestion of
+// every supplier as order lines.
+const ExportPurchaseOrders = "purchase-orders"
+
+// ImportRowError describes why a row of an imported file is rejected. Rows are
+// numbered from 1 without the CSV header.
+type ImportRowError struct {
+	Row     int    `json:"row" bson:"row"`
+	Message string `json:"message" bson:"message"`
+}
+
+// ImportReport describes the result of an import. Nothing is written when any
+// row is rejected or the import is a dry run.
+type ImportReport struct {
+	Entity   string `json:"entity" bson:"entity"`
+	DryRun   bool   `json:"dry_run" bson:"dry_run"`
+	Total    int    `json:"total" bson:"total"`
+	Valid    int    `json:"valid" bson:"valid"`
+	Imported int    `json:"imported" bson:"imported"`
+	Failed   int    `json:"failed" bson:"failed"`
+	// Errors holds the first rejected rows, the rest are only counted by Failed
+	Errors []ImportRowError `json:"errors,omitempty" bson:"errors,omitempty"`
+}
+
+// AddError counts the rejected row and keeps its error while the report holds
+// less than limit errors.
+func (r *ImportReport) AddError(row int, message string, limit int) {
+	r.Failed++
+	if len(r.Errors) < limit {
+		r.Errors = append(r.Errors, ImportRowError{Row: row, Message: message})
+	}
+}
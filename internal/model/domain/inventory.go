@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReorderPolicy tells when a product is low on stock and how much to order:
+// the stock at or below Threshold is low, it is reordered up to Target.
+type ReorderPolicy struct {
+	Threshold int64
+	Target    int64
+}
+
+// Sources of low stock events.
+const (
+	LowStockSourceStockChange = "stock_change"
+	LowStockSourceEvaluator   = "evaluator"
+)
+
+// LowStockItem is a product with the stock at or below its reorder threshold.
+// Products with variants are not listed, their variants are.
+type LowStockItem struct {
+	ProductId      uuid.UUID
+	ParentId       *uuid.UUID
+	Name           string
+	Sku            string
+	Barcode        string
+	Supplier       Supplier
+	AvailableStock int64
+	Reorder        ReorderPolicy
+	// LowSince is set when a stock change or the evaluator flags the product,
+	// nil until then
+	LowSince *time.Time
+}
+
+// SuggestedQuantity returns the quantity which brings the stock to the
+// reorder target.
+func (i *LowStockItem) SuggestedQuantity() int64 {
+	return i.Reorder.Target - i.AvailableStock
+}
+
+// ReorderSuggestion is a draft of the purchase order of one supplier.
+type ReorderSuggestion struct {
+	Supplier Supplier
+	Items    []LowStockItem
+}
+
+// TotalQuantity returns the suggested quantity of all items.
+func (s *ReorderSuggestion) TotalQuantity() int64 {
+	var total int64
+	for i := range s.Items {
+		total += s.Items[i].SuggestedQuantity()
+	}
+
+	return total
+}
+
+// LowStockEvent is fired once when a product becomes low on stock, the next
+// one follows after the stock is above the threshold again.
+type LowStockEvent struct {
+	Item LowStockItem
+	// Source is LowStockSourceStockChange for a stock change crossing the
+	// threshold and LowStockSourceEvaluator for products found by the evaluator
+	Source string
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of background jobs.
+const (
+	JobImport       = "import"
+	JobExport       = "export"
+	JobAddressPurge = "address_purge"
+	// JobImageReprocess strips metadata from stored images and extracts their
+	// metadata again, images stored before the extraction get it filled
+	JobImageReprocess = "image_reprocess"
+)
+
+// Job statuses. A job is queued until a worker claims it, finished jobs are
+// succeeded, failed or canceled.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// Job is an operation run by a background worker.
+type Job struct {
+	Id     uuid.UUID
+	Kind   string
+	Status string
+	Params map[string]string
+	// Input is the uploaded data, it is dropped when the job is finished
+	Input    []byte
+	Progress int64
+	// Result is a JSON document describing the outcome
+	Result []byte
+	// Output is a file produced by the job
+	Output          []byte
+	OutputType      string
+	HasOutput       bool
+	Error           string
+	Attempts        int
+	CancelRequested bool
+	CreatedAt       time.Time
+	StartedAt       time.Time
+	FinishedAt      time.Time
+}
+
+func (j *Job) IsFinished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCanceled
+}
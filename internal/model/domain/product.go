@@ -6,13 +6,110 @@ import (
 	"github.com/google/uuid"
 )
 
+// Product is sold as is or through its variants. A variant is a product with
+// a parent, it has the category and the supplier of the parent and its own
+// SKU, barcode, price, stock and images. The stock of a product with variants
+// is the sum of their stocks.
 type Product struct {
-	Id             uuid.UUID `json:"id,omitempty" bson:"_id,omitempty"`
-	Name           string    `json:"name" bson:"name"`
-	Category       string    `json:"category" bson:"category"`
-	Price          float32   `json:"price" bson:"price"`
-	AvailableStock int64     `json:"available_stock" bson:"available_stock"`
-	LastUpdateDate time.Time `json:"last_update_date" bson:"last_update_date"`
-	Supplier       Supplier  `json:"supplier" bson:"supplier"`
-	Image          Image     `json:"image" bson:"image"`
+	Id             uuid.UUID      `json:"id,omitempty" bson:"_id,omitempty"`
+	ParentId       *uuid.UUID     `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Name           string         `json:"name" bson:"name"`
+	Sku            string         `json:"sku,omitempty" bson:"sku,omitempty"`
+	Barcode        string         `json:"barcode,omitempty" bson:"barcode,omitempty"`
+	Category       Category       `json:"category" bson:"category"`
+	Price          float32        `json:"price" bson:"price"`
+	AvailableStock int64          `json:"available_stock" bson:"available_stock"`
+	LastUpdateDate time.Time      `json:"last_update_date" bson:"last_update_date"`
+	Supplier       Supplier       `json:"supplier" bson:"supplier"`
+	Images         []ProductImage `json:"images" bson:"images"`
+	// Attributes are values of the attributes defined by the category by key
+	Attributes map[string]any `json:"attributes" bson:"attributes"`
+	// Reorder is nil when the product has no reorder threshold
+	Reorder *ReorderPolicy `json:"reorder,omitempty" bson:"reorder,omitempty"`
+	Version int64          `json:"version" bson:"version"`
+	// Variants are filled for products without parent
+	Variants []Product `json:"variants,omitempty" bson:"variants,omitempty"`
+}
+
+// PrimaryImage returns the image marked as primary in the gallery or nil
+// when the product has no images yet.
+func (p *Product) PrimaryImage() *Image {
+	for i := range p.Images {
+		if p.Images[i].IsPrimary {
+			return &p.Images[i].Image
+		}
+	}
+
+	return nil
+}
+
+// ProductPatch holds the product fields to change, nil fields are left as is.
+type ProductPatch struct {
+	Name *string
+	// Sku and Barcode are removed by empty values
+	Sku        *string
+	Barcode    *string
+	CategoryId *uuid.UUID
+	Price      *float32
+	SupplierId *uuid.UUID
+	// ImageId becomes the primary image, it is attached when the product has
+	// no such image yet
+	ImageId *uuid.UUID
+	// Attributes are merged into the product attributes, a nil value removes
+	// the attribute
+	Attributes map[string]any
+	// Version is the expected version of the product, 0 skips the check
+	Version int64
+}
+
+// Apply copies the set fields into the product. Image is not touched, it is
+// stored in the gallery.
+func (p *ProductPatch) Apply(product *Product) {
+	if p.Name != nil {
+		product.Name = *p.Name
+	}
+
+	if p.CategoryId != nil {
+		product.Category = Category{Id: *p.CategoryId}
+	}
+
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+
+	if p.SupplierId != nil {
+		product.Supplier = Supplier{Id: *p.SupplierId}
+	}
+
+	if p.Sku != nil {
+		product.Sku = *p.Sku
+	}
+
+	if p.Barcode != nil {
+		product.Barcode = *p.Barcode
+	}
+
+	if p.Attributes != nil {
+		attributes := make(map[string]any, len(product.Attributes)+len(p.Attributes))
+		for key, value := range product.Attributes {
+			attributes[key] = value
+		}
+
+		for key, value := range p.Attributes {
+			if value == nil {
+				delete(attributes, key)
+				continue
+			}
+
+			attributes[key] = value
+		}
+
+		product.Attributes = attributes
+	}
+}
+
+// IsEmpty reports whether no field is set.
+func (p *ProductPatch) IsEmpty() bool {
+	return p.Name == nil && p.CategoryId == nil && p.Price == nil && p.SupplierId == nil && p.ImageId == nil &&
+		p.Attributes == nil && p.Sku == nil && p.Barcode == nil
 }
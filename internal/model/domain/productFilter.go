@@ -0,0 +1,26 @@
+package domain
+
+const (
+	ProductSortName           = "name"
+	ProductSortCategory       = "category"
+	ProductSortPrice          = "price"
+	ProductSortStock          = "available_stock"
+	ProductSortLastUpdateDate = "last_update_date"
+)
+
+// ProductFilter describes a product listing, zero fields are not applied.
+// Query is matched partially against the name, Category is the id or slug of
+// a category, products of its subcategories are matched too. All attribute
+// filters have to match.
+type ProductFilter struct {
+	Query      string
+	Category   string
+	PriceMin   *float64
+	PriceMax   *float64
+	InStock    *bool
+	Attributes []AttributeFilter
+	SortBy     string
+	SortDesc   bool
+	Limit      int
+	Offset     int
+}
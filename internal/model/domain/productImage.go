@@ -0,0 +1,10 @@
+package domain
+
+// AppendPosition places an attached image after the last image in the gallery.
+const AppendPosition = -1
+
+type ProductImage struct {
+	Image     Image `json:"image" bson:"image"`
+	Position  int   `json:"position" bson:"position"`
+	IsPrimary bool  `json:"is_primary" bson:"is_primary"`
+}
@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of promotion discounts.
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+)
+
+// Promotion discounts products matching its scope. An automatic promotion
+// applies to every eligible item, a promotion with a coupon code applies only
+// when the code is given.
+type Promotion struct {
+	Id   uuid.UUID
+	Name string
+	// Kind is a percentage off the item or a fixed amount off every unit
+	Kind  string
+	Value float64
+	// ProductId, CategoryId and SupplierId scope the promotion, at most one is
+	// set. A category scope includes its subcategories, none of them scopes
+	// every product.
+	ProductId  *uuid.UUID
+	CategoryId *uuid.UUID
+	SupplierId *uuid.UUID
+	// MinQuantity is the quantity of an item required for the discount
+	MinQuantity int64
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	// UsageLimit caps the orders the promotion is applied to, nil is unlimited
+	UsageLimit *int64
+	// PerClientLimit caps the orders of one client, nil is unlimited
+	PerClientLimit *int64
+	// CouponCode is empty for an automatic promotion
+	CouponCode string
+	Active     bool
+	Version    int64
+	CreatedAt  time.Time
+}
+
+// Matches tells whether the product is in the promotion scope. A variant
+// matches the promotion of its parent product.
+func (p *Promotion) Matches(product PromotionProduct) bool {
+	switch {
+	case p.ProductId != nil:
+		return *p.ProductId == product.Id || (product.ParentId != nil && *p.ProductId == *product.ParentId)
+	case p.CategoryId != nil:
+		for _, id := range product.CategoryIds {
+			if id == *p.CategoryId {
+				return true
+			}
+		}
+
+		return false
+	case p.SupplierId != nil:
+		return *p.SupplierId == product.SupplierId
+	}
+
+	return true
+}
+
+// Discount is the amount taken off the quantity of a product at the price,
+// a fixed discount never exceeds the price.
+func (p *Promotion) Discount(price float32, quantity int64) float64 {
+	if p.Kind == PromotionFixed {
+		return roundCents(min(p.Value, float64(price)) * float64(quantity))
+	}
+
+	return roundCents(float64(price) * float64(quantity) * p.Value / 100)
+}
+
+// Describe tells the discount of the promotion in words.
+func (p *Promotion) Describe() string {
+	if p.Kind == PromotionFixed {
+		return fmt.Sprintf("%.2f off each unit", p.Value)
+	}
+
+	return fmt.Sprintf("%g%% off", p.Value)
+}
+
+type PromotionPatch struct {
+	Name           *string
+	Active         *bool
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	UsageLimit     *int64
+	PerClientLimit *int64
+	// Version is the expected version of the promotion, 0 skips the check
+	Version int64
+}
+
+// Apply copies the set fields into the promotion.
+func (p *PromotionPatch) Apply(promotion *Promotion) {
+	if p.Name != nil {
+		promotion.Name = *p.Name
+	}
+
+	if p.Active != nil {
+		promotion.Active = *p.Active
+	}
+
+	if p.StartsAt != nil {
+		promotion.StartsAt = p.StartsAt
+	}
+
+	if p.EndsAt != nil {
+		promotion.EndsAt = p.EndsAt
+	}
+
+	if p.UsageLimit != nil {
+		promotion.UsageLimit = p.UsageLimit
+	}
+
+	if p.PerClientLimit != nil {
+		promotion.PerClientLimit = p.PerClientLimit
+	}
+}
+
+// PromotionCandidate is a promotion which may apply to an evaluation with its
+// state at the evaluation time.
+type PromotionCandidate struct {
+	Promotion
+	Started bool
+	Ended   bool
+	// Used is the number of orders the promotion is applied to
+	Used int64
+	// UsedByClient is the number of orders of the evaluated client
+	UsedByClient int64
+}
+
+// PromotionProduct is a product as promotions see it.
+type PromotionProduct struct {
+	Id         uuid.UUID
+	ParentId   *uuid.UUID
+	Name       string
+	Price      float32
+	SupplierId uuid.UUID
+	// CategoryIds are the product category and all its ancestors
+	CategoryIds []uuid.UUID
+}
+
+// PromotionItem is a quantity of a product to evaluate promotions for.
+type PromotionItem struct {
+	ProductId uuid.UUID
+	Quantity  int64
+}
+
+// Reasons a promotion is not applied.
+const (
+	PromotionReasonUnknownCoupon   = "unknown_coupon"
+	PromotionReasonInactive        = "inactive"
+	PromotionReasonNotStarted      = "not_started"
+	PromotionReasonEnded           = "ended"
+	PromotionReasonUsageLimit      = "usage_limit"
+	PromotionReasonClientRequired  = "client_required"
+	PromotionReasonClientLimit     = "client_limit"
+	PromotionReasonMinQuantity     = "min_quantity"
+	PromotionReasonBetterDiscount  = "better_discount"
+	PromotionReasonNoMatchingItems = "no_matching_items"
+)
+
+// PromotionEvaluation is the discount of items by promotions. Every line gets
+// the one promotion with the largest discount, notes explain promotions which
+// are not applied.
+type PromotionEvaluation struct {
+	ClientId *uuid.UUID
+	Lines    []PromotionLine
+	Notes    []PromotionNote
+}
+
+// Line returns the line of the product or nil when there is no such line.
+func (e *PromotionEvaluation) Line(productId uuid.UUID) *PromotionLine {
+	for i := range e.Lines {
+		if e.Lines[i].ProductId == productId {
+			return &e.Lines[i]
+		}
+	}
+
+	return nil
+}
+
+// CouponApplied tells whether the promotion of the coupon discounts a line.
+func (e *PromotionEvaluation) CouponApplied(code string) bool {
+	for _, line := range e.Lines {
+		if line.Promotion != nil && line.Promotion.CouponCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subtotal is the sum of the line subtotals.
+func (e *PromotionEvaluation) Subtotal() float64 {
+	var subtotal float64
+	for _, line := range e.Lines {
+		subtotal += line.Subtotal()
+	}
+
+	return roundCents(subtotal)
+}
+
+// Discount is the sum of the line discounts.
+func (e *PromotionEvaluation) Discount() float64 {
+	var discount float64
+	for _, line := range e.Lines {
+		discount += line.Discount
+	}
+
+	return roundCents(discount)
+}
+
+// Total is the subtotal less the discount.
+func (e *PromotionEvaluation) Total() float64 {
+	return roundCents(e.Subtotal() - e.Discount())
+}
+
+// PromotionLine is an evaluated item.
+type PromotionLine struct {
+	ProductId uuid.UUID
+	Name      string
+	Price     float32
+	Quantity  int64
+	Discount  float64
+	// Promotion is nil when no promotion is applied
+	Promotion *AppliedPromotion
+}
+
+// Subtotal is the price of the line quantity rounded to cents.
+func (l PromotionLine) Subtotal() float64 {
+	return roundCents(float64(l.Price) * float64(l.Quantity))
+}
+
+// Total is the subtotal less the discount.
+func (l PromotionLine) Total() float64 {
+	return roundCents(l.Subtotal() - l.Discount)
+}
+
+// AppliedPromotion is the promotion discounting a line.
+type AppliedPromotion struct {
+	Id          uuid.UUID
+	Name        string
+	CouponCode  string
+	Explanation string
+}
+
+// PromotionNote explains why a promotion or a coupon is not applied.
+type PromotionNote struct {
+	// PromotionId is nil for an unknown coupon
+	PromotionId *uuid.UUID
+	Name        string
+	CouponCode  string
+	// ProductId is set when the reason concerns one line
+	ProductId *uuid.UUID
+	Reason    string
+	Message   string
+}
+
+// PromotionRedemption records a promotion applied to an order.
+type PromotionRedemption struct {
+	PromotionId uuid.UUID
+	ClientId    *uuid.UUID
+	OrderId     uuid.UUID
+	Discount    float64
+}
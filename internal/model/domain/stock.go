@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockOperation is the kind of change of the product stock.
+type StockOperation string
+
+const (
+	StockDecrease StockOperation = "decrease"
+	StockIncrease StockOperation = "increase"
+	StockSet      StockOperation = "set"
+	// StockTransfer moves stock between warehouses, the product stock is kept
+	StockTransfer StockOperation = "transfer"
+)
+
+// Reasons of stock changes.
+const (
+	StockReasonSale       = "sale"
+	StockReasonReturn     = "return"
+	StockReasonRestock    = "restock"
+	StockReasonDamage     = "damage"
+	StockReasonLoss       = "loss"
+	StockReasonCorrection = "correction"
+	StockReasonTransfer   = "transfer"
+)
+
+// StockMovement is a recorded change of the product stock.
+type StockMovement struct {
+	Id        uuid.UUID
+	ProductId uuid.UUID
+	Operation StockOperation
+	// Quantity is the amount of decrease or increase or the new stock for set
+	Quantity    int64
+	Reason      string
+	Note        string
+	StockBefore int64
+	StockAfter  int64
+	// WarehouseId is the changed warehouse, the source of a transfer. It is
+	// nil for a product not stocked in warehouses
+	WarehouseId *uuid.UUID
+	// ToWarehouseId is the destination of a transfer
+	ToWarehouseId *uuid.UUID
+	CreatedAt     time.Time
+}
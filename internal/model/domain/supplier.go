@@ -1,10 +1,76 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Supplier struct {
-	Id          uuid.UUID `json:"id" bson:"_id"`
-	Name        string    `json:"name" bson:"name"`
-	PhoneNumber string    `json:"phone_number" bson:"phone_number"`
-	Address     *Address  `json:"address" bson:"address"`
+	Id            uuid.UUID          `json:"id" bson:"_id"`
+	Name          string             `json:"name" bson:"name"`
+	PhoneNumber   string             `json:"phone_number" bson:"phone_number"`
+	ContactPerson string             `json:"contact_person,omitempty" bson:"contact_person,omitempty"`
+	Email         string             `json:"email,omitempty" bson:"email,omitempty"`
+	Website       string             `json:"website,omitempty" bson:"website,omitempty"`
+	TaxId         string             `json:"tax_id,omitempty" bson:"tax_id,omitempty"`
+	Address       *Address           `json:"address" bson:"address"`
+	Locations     []AddressBookEntry `json:"locations,omitempty" bson:"locations,omitempty"`
+	Version       int64              `json:"version" bson:"version"`
+}
+
+// SupplierPatch holds the supplier fields to change, nil fields are left as is.
+type SupplierPatch struct {
+	Name          *string
+	PhoneNumber   *string
+	ContactPerson *string
+	Email         *string
+	Website       *string
+	TaxId         *string
+	Address       *Address
+	// Version is the expected version of the supplier, 0 skips the check
+	Version int64
+}
+
+// Apply copies the set profile fields into the supplier. Address is not
+// touched, it is stored separately.
+func (p *SupplierPatch) Apply(supplier *Supplier) {
+	if p.Name != nil {
+		supplier.Name = *p.Name
+	}
+
+	if p.PhoneNumber != nil {
+		supplier.PhoneNumber = *p.PhoneNumber
+	}
+
+	if p.ContactPerson != nil {
+		supplier.ContactPerson = *p.ContactPerson
+	}
+
+	if p.Email != nil {
+		supplier.Email = *p.Email
+	}
+
+	if p.Website != nil {
+		supplier.Website = *p.Website
+	}
+
+	if p.TaxId != nil {
+		supplier.TaxId = *p.TaxId
+	}
+}
+
+// HasProfile reports whether any field besides the address is set.
+func (p *SupplierPatch) HasProfile() bool {
+	return p.Name != nil || p.PhoneNumber != nil || p.ContactPerson != nil ||
+		p.Email != nil || p.Website != nil || p.TaxId != nil
+}
+
+// SupplierStats summarizes products of a supplier.
+type SupplierStats struct {
+	ProductCount    int        `json:"product_count" bson:"product_count"`
+	TotalStockUnits int64      `json:"total_stock_units" bson:"total_stock_units"`
+	TotalStockValue float64    `json:"total_stock_value" bson:"total_stock_value"`
+	OutOfStockCount int        `json:"out_of_stock_count" bson:"out_of_stock_count"`
+	LastDeliveryAt  *time.Time `json:"last_delivery_at,omitempty" bson:"last_delivery_at,omitempty"`
 }
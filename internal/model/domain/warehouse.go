@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Warehouse is a place products are stocked in.
+type Warehouse struct {
+	Id        uuid.UUID
+	Name      string
+	Address   *Address
+	Version   int64
+	CreatedAt time.Time
+}
+
+type WarehousePatch struct {
+	Name    *string
+	Address *Address
+	// Version is the expected version of the warehouse, 0 skips the check
+	Version int64
+}
+
+// Apply copies the set fields into the warehouse. Address is not touched, it
+// is stored separately.
+func (p *WarehousePatch) Apply(warehouse *Warehouse) {
+	if p.Name != nil {
+		warehouse.Name = *p.Name
+	}
+}
+
+// WarehouseStock is the stock of a product in a warehouse.
+type WarehouseStock struct {
+	WarehouseId   uuid.UUID
+	WarehouseName string
+	ProductId     uuid.UUID
+	ProductName   string
+	Sku           string
+	Quantity      int64
+}
+
+// Strategies picking the warehouse of a decrease which does not name one.
+const (
+	StockStrategyMostStock = "most_stock"
+	StockStrategyNearest   = "nearest"
+)
+
+// WarehousePick tells how to pick the warehouse of a decrease: the warehouse
+// holding the whole quantity with the most stock or the nearest one to the
+// destination.
+type WarehousePick struct {
+	// Strategy is empty for the configured default
+	Strategy string
+	// DestinationId is the address the goods go to, nearest requires it
+	DestinationId *uuid.UUID
+}
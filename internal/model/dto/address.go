@@ -1,7 +1,48 @@
 package dto
 
+import "github.com/google/uuid"
+
 type Address struct {
-	Country string `json:"country" xml:"country"`
-	City    string `json:"city" xml:"city"`
-	Street  string `json:"street" xml:"street"`
+	Country    string   `json:"country" xml:"country" binding:"required,country"`
+	City       string   `json:"city" xml:"city" binding:"required,max=100"`
+	Street     string   `json:"street" xml:"street" binding:"required,max=200"`
+	PostalCode string   `json:"postal_code,omitempty" xml:"postal_code,omitempty" binding:"max=16"`
+	Region     string   `json:"region,omitempty" xml:"region,omitempty" binding:"max=100"`
+	Building   string   `json:"building,omitempty" xml:"building,omitempty" binding:"max=20"`
+	Apartment  string   `json:"apartment,omitempty" xml:"apartment,omitempty" binding:"max=20"`
+	Line1      string   `json:"line1,omitempty" xml:"line1,omitempty" binding:"max=200"`
+	Line2      string   `json:"line2,omitempty" xml:"line2,omitempty" binding:"max=200"`
+	Latitude   *float64 `json:"latitude,omitempty" xml:"latitude,omitempty" binding:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64 `json:"longitude,omitempty" xml:"longitude,omitempty" binding:"omitempty,gte=-180,lte=180"`
+}
+
+type AddressBookEntryRequest struct {
+	Label     string `json:"label" xml:"label" binding:"required,max=50"`
+	IsDefault bool   `json:"is_default" xml:"is_default"`
+	Address
+}
+
+type AddressBookEntryUpdateRequest struct {
+	Label     *string `json:"label,omitempty" xml:"label,omitempty" binding:"omitempty,max=50"`
+	IsDefault *bool   `json:"is_default,omitempty" xml:"is_default,omitempty"`
+}
+
+type AddressBookEntryResponse struct {
+	Id        uuid.UUID `json:"id" xml:"id"`
+	Label     string    `json:"label" xml:"label"`
+	IsDefault bool      `json:"is_default" xml:"is_default"`
+	Version   int64     `json:"version" xml:"version"`
+	Address
+}
+
+type AddressResponse struct {
+	Id uuid.UUID `json:"id" xml:"id"`
+	Address
+}
+
+type AddressReferenceResponse struct {
+	OwnerType string    `json:"owner_type" xml:"owner_type"`
+	OwnerId   uuid.UUID `json:"owner_id" xml:"owner_id"`
+	Label     string    `json:"label" xml:"label"`
+	IsDefault bool      `json:"is_default" xml:"is_default"`
 }
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type AttributeRequest struct {
+	Key      string   `json:"key" xml:"key" binding:"required,attribute_key,max=50"`
+	Name     string   `json:"name" xml:"name" binding:"required,max=100"`
+	Type     string   `json:"type" xml:"type" binding:"required,oneof=string number integer boolean enum"`
+	Unit     string   `json:"unit,omitempty" xml:"unit,omitempty" binding:"max=20"`
+	Options  []string `json:"options,omitempty" xml:"options>option,omitempty" binding:"required_if=Type enum,dive,required,max=100"`
+	Min      *float64 `json:"min,omitempty" xml:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty" xml:"max,omitempty"`
+	Required bool     `json:"required" xml:"required"`
+	Position int      `json:"position" xml:"position" binding:"gte=0"`
+}
+
+type AttributeResponse struct {
+	Id         uuid.UUID `json:"id" xml:"id"`
+	CategoryId uuid.UUID `json:"category_id" xml:"category_id"`
+	Key        string    `json:"key" xml:"key"`
+	Name       string    `json:"name" xml:"name"`
+	Type       string    `json:"type" xml:"type"`
+	Unit       string    `json:"unit,omitempty" xml:"unit,omitempty"`
+	Options    []string  `json:"options,omitempty" xml:"options>option,omitempty"`
+	Min        *float64  `json:"min,omitempty" xml:"min,omitempty"`
+	Max        *float64  `json:"max,omitempty" xml:"max,omitempty"`
+	Required   bool      `json:"required" xml:"required"`
+	Position   int       `json:"position" xml:"position"`
+}
+
+// Attributes are attribute values of a product by key. In XML every value is
+// an <attribute key="..."> element, true and false are read as booleans,
+// numbers as numbers and an empty element removes the attribute of a patch.
+type Attributes map[string]any
+
+type xmlAttribute struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (a Attributes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		value := a[key]
+		if number, ok := value.(float64); ok {
+			value = strconv.FormatFloat(number, 'f', -1, 64)
+		}
+
+		item := xmlAttribute{Key: key, Value: fmt.Sprint(value)}
+		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: "attribute"}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func (a *Attributes) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var items struct {
+		Attributes []xmlAttribute `xml:"attribute"`
+	}
+
+	if err := d.DecodeElement(&items, &start); err != nil {
+		return err
+	}
+
+	attributes := make(Attributes, len(items.Attributes))
+	for _, item := range items.Attributes {
+		attributes[item.Key] = xmlAttributeValue(item.Value)
+	}
+
+	*a = attributes
+
+	return nil
+}
+
+func xmlAttributeValue(text string) any {
+	if text == "" {
+		return nil
+	}
+
+	if value, err := strconv.ParseBool(text); err == nil && (text == "true" || text == "false") {
+		return value
+	}
+
+	if value, err := strconv.ParseFloat(text, 64); err == nil {
+		return value
+	}
+
+	return text
+}
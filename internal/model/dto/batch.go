@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+type BatchRequest struct {
+	// Atomic rolls back the whole batch when any operation fails, it is set by default
+	Atomic     *bool            `json:"atomic,omitempty"`
+	Operations []BatchOperation `json:"operations" binding:"required"`
+}
+
+// BatchOperation changes one entity. Id and string values of Body equal to
+// "$<ref>" are replaced by the id returned by the earlier operation with the ref.
+// Version is the expected entity version of update and delete like If-Match.
+type BatchOperation struct {
+	Ref     string          `json:"ref,omitempty"`
+	Method  string          `json:"method" binding:"required"`
+	Entity  string          `json:"entity" binding:"required"`
+	Id      string          `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+type BatchOperationResult struct {
+	Index  int        `json:"index"`
+	Ref    string     `json:"ref,omitempty"`
+	Status int        `json:"status"`
+	Id     *uuid.UUID `json:"id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Atomic    bool                   `json:"atomic"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BatchOperationResult `json:"results"`
+}
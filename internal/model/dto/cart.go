@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CartRequest creates a cart of the client, an anonymous cart without it.
+type CartRequest struct {
+	ClientId *uuid.UUID `json:"client_id,omitempty" xml:"client_id,omitempty"`
+}
+
+type CartItemRequest struct {
+	ProductId uuid.UUID `json:"product_id" xml:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" xml:"quantity" binding:"gt=0"`
+}
+
+type CartItemUpdateRequest struct {
+	Quantity int64 `json:"quantity" xml:"quantity" binding:"gt=0"`
+}
+
+// CartMergeRequest names the identified client the anonymous cart is given to.
+type CartMergeRequest struct {
+	ClientId uuid.UUID `json:"client_id" xml:"client_id" binding:"required"`
+}
+
+// CheckoutRequest picks the warehouses the stock is taken from, the configured
+// strategy is used when it is empty. Coupons are the codes of promotions the
+// client redeems.
+type CheckoutRequest struct {
+	Strategy             string     `json:"strategy,omitempty" xml:"strategy,omitempty" binding:"omitempty,oneof=most_stock nearest"`
+	DestinationAddressId *uuid.UUID `json:"destination_address_id,omitempty" xml:"destination_address_id,omitempty"`
+	Coupons              []string   `json:"coupons,omitempty" xml:"coupons>coupon,omitempty" binding:"max=10,dive,required,max=32"`
+}
+
+// CartItemResponse is the item with the current product price and stock.
+type CartItemResponse struct {
+	ProductId      uuid.UUID `json:"product_id" xml:"product_id"`
+	Name           string    `json:"name" xml:"name"`
+	Sku            string    `json:"sku,omitempty" xml:"sku,omitempty"`
+	Price          float32   `json:"price" xml:"price"`
+	Quantity       int64     `json:"quantity" xml:"quantity"`
+	Subtotal       float64   `json:"subtotal" xml:"subtotal"`
+	AvailableStock int64     `json:"available_stock" xml:"available_stock"`
+}
+
+// CartAdjustmentResponse is an item lowered to the product stock by a merge,
+// quantity 0 means the item is removed.
+type CartAdjustmentResponse struct {
+	ProductId uuid.UUID `json:"product_id" xml:"product_id"`
+	Requested int64     `json:"requested" xml:"requested"`
+	Quantity  int64     `json:"quantity" xml:"quantity"`
+}
+
+type CartResponse struct {
+	Id          uuid.UUID                `json:"id" xml:"id"`
+	ClientId    *uuid.UUID               `json:"client_id,omitempty" xml:"client_id,omitempty"`
+	Items       []CartItemResponse       `json:"items" xml:"items>item"`
+	Total       float64                  `json:"total" xml:"total"`
+	Adjustments []CartAdjustmentResponse `json:"adjustments,omitempty" xml:"adjustments>adjustment,omitempty"`
+	CreatedAt   time.Time                `json:"created_at" xml:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at" xml:"updated_at"`
+	ExpiresAt   time.Time                `json:"expires_at" xml:"expires_at"`
+}
+
+type OrderItemResponse struct {
+	ProductId   *uuid.UUID `json:"product_id,omitempty" xml:"product_id,omitempty"`
+	Name        string     `json:"name" xml:"name"`
+	Sku         string     `json:"sku,omitempty" xml:"sku,omitempty"`
+	Price       float32    `json:"price" xml:"price"`
+	Quantity    int64      `json:"quantity" xml:"quantity"`
+	Subtotal    float64    `json:"subtotal" xml:"subtotal"`
+	Discount    float64    `json:"discount" xml:"discount"`
+	PromotionId *uuid.UUID `json:"promotion_id,omitempty" xml:"promotion_id,omitempty"`
+	WarehouseId *uuid.UUID `json:"warehouse_id,omitempty" xml:"warehouse_id,omitempty"`
+}
+
+type OrderResponse struct {
+	Id        uuid.UUID           `json:"id" xml:"id"`
+	ClientId  *uuid.UUID          `json:"client_id,omitempty" xml:"client_id,omitempty"`
+	Items     []OrderItemResponse `json:"items" xml:"items>item"`
+	Subtotal  float64             `json:"subtotal" xml:"subtotal"`
+	Discount  float64             `json:"discount" xml:"discount"`
+	Total     float64             `json:"total" xml:"total"`
+	CreatedAt time.Time           `json:"created_at" xml:"created_at"`
+}
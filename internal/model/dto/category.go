@@ -0,0 +1,40 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CategoryRequest struct {
+	Name     string     `json:"name" xml:"name" binding:"required,max=100"`
+	Slug     string     `json:"slug,omitempty" xml:"slug,omitempty" binding:"omitempty,slug,max=100"`
+	ParentId *uuid.UUID `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	Position int        `json:"position" xml:"position" binding:"gte=0"`
+}
+
+type CategoryUpdateRequest struct {
+	Name *string `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=100"`
+	Slug *string `json:"slug,omitempty" xml:"slug,omitempty" binding:"omitempty,slug,max=100"`
+	// ParentId is the id of the new parent, an empty string moves the category
+	// to the top level
+	ParentId *string `json:"parent_id,omitempty" xml:"parent_id,omitempty" binding:"omitempty,uuid"`
+	Position *int    `json:"position,omitempty" xml:"position,omitempty" binding:"omitempty,gte=0"`
+}
+
+type CategoryResponse struct {
+	Id       uuid.UUID  `json:"id" xml:"id"`
+	Name     string     `json:"name" xml:"name"`
+	Slug     string     `json:"slug" xml:"slug"`
+	ParentId *uuid.UUID `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	Position int        `json:"position" xml:"position"`
+	Depth    int        `json:"depth" xml:"depth"`
+	Path     string     `json:"path" xml:"path"`
+}
+
+type CategoryReferenceResponse struct {
+	Id   uuid.UUID `json:"id" xml:"id"`
+	Name string    `json:"name" xml:"name"`
+	Slug string    `json:"slug" xml:"slug"`
+}
+
+type CategoryProductsQuery struct {
+	ProductListQuery
+	IncludeSubcategories bool `form:"include_subcategories"`
+}
@@ -3,10 +3,22 @@ package dto
 import "github.com/google/uuid"
 
 type ClientRequest struct {
-	Name     string `json:"name" xml:"name" binding:"required"`
-	Surname  string `json:"surname" xml:"surname" binding:"required"`
-	Birthday string `json:"birthday" xml:"birthday" binding:"required"`
-	Gender   string `json:"gender" xml:"gender" binding:"required"`
+	Name     string `json:"name" xml:"name" binding:"required,max=100"`
+	Surname  string `json:"surname" xml:"surname" binding:"required,max=100"`
+	Birthday string `json:"birthday" xml:"birthday" binding:"required,date,notfuture"`
+	Gender   string `json:"gender" xml:"gender" binding:"required,oneof=male female"`
+	Email    string `json:"email,omitempty" xml:"email,omitempty" binding:"omitempty,email,max=254"`
+	Phone    string `json:"phone,omitempty" xml:"phone,omitempty" binding:"omitempty,phone"`
+	*Address
+}
+
+type ClientUpdateRequest struct {
+	Name     *string `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=100"`
+	Surname  *string `json:"surname,omitempty" xml:"surname,omitempty" binding:"omitempty,max=100"`
+	Birthday *string `json:"birthday,omitempty" xml:"birthday,omitempty" binding:"omitempty,date,notfuture"`
+	Gender   *string `json:"gender,omitempty" xml:"gender,omitempty" binding:"omitempty,oneof=male female"`
+	Email    *string `json:"email,omitempty" xml:"email,omitempty" binding:"omitempty,email,max=254"`
+	Phone    *string `json:"phone,omitempty" xml:"phone,omitempty" binding:"omitempty,phone"`
 	*Address
 }
 
@@ -16,5 +28,24 @@ type ClientResponse struct {
 	Surname  string    `json:"surname" xml:"surname"`
 	Birthday string    `json:"birthday" xml:"birthday"`
 	Gender   string    `json:"gender" xml:"gender"`
+	Email    string    `json:"email,omitempty" xml:"email,omitempty"`
+	Phone    string    `json:"phone,omitempty" xml:"phone,omitempty"`
 	*Address
 }
+
+type ClientSearchQuery struct {
+	Query          string `form:"q"`
+	Name           string `form:"name"`
+	Surname        string `form:"surname"`
+	Gender         string `form:"gender"`
+	BirthdayFrom   string `form:"birthday_from"`
+	BirthdayTo     string `form:"birthday_to"`
+	RegisteredFrom string `form:"registered_from"`
+	RegisteredTo   string `form:"registered_to"`
+	Country        string `form:"country"`
+	City           string `form:"city"`
+	Sort           string `form:"sort"`
+	Order          string `form:"order"`
+	Limit          int    `form:"limit,default=10"`
+	Offset         int    `form:"offset,default=0"`
+}
@@ -8,7 +8,16 @@ type ImageRequest struct {
 }
 
 type ImageResponse struct {
-	Id    uuid.UUID `json:"id" xml:"id"`
-	Title string    `json:"title" xml:"title"`
-	Image []byte    `json:"image" xml:"image"`
+	Id       uuid.UUID     `json:"id" xml:"id"`
+	Title    string        `json:"title" xml:"title"`
+	Image    []byte        `json:"image" xml:"image"`
+	Metadata ImageMetadata `json:"metadata" xml:"metadata"`
+}
+
+type ImageMetadata struct {
+	Width         int    `json:"width" xml:"width"`
+	Height        int    `json:"height" xml:"height"`
+	Format        string `json:"format" xml:"format"`
+	Size          int64  `json:"size" xml:"size"`
+	DominantColor string `json:"dominant_color" xml:"dominant_color"`
 }
@@ -0,0 +1,16 @@
+package dto
+
+type ImportRowError struct {
+	Row     int    `json:"row" xml:"row"`
+	Message string `json:"message" xml:"message"`
+}
+
+type ImportReportResponse struct {
+	Entity   string           `json:"entity" xml:"entity"`
+	DryRun   bool             `json:"dry_run" xml:"dry_run"`
+	Total    int              `json:"total" xml:"total"`
+	Valid    int              `json:"valid" xml:"valid"`
+	Imported int              `json:"imported" xml:"imported"`
+	Failed   int              `json:"failed" xml:"failed"`
+	Errors   []ImportRowError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
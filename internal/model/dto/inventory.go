@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReorderPolicyRequest sets when a product is low on stock and up to which
+// stock it is reordered, the target has to be above the threshold.
+type ReorderPolicyRequest struct {
+	Threshold int64 `json:"threshold" xml:"threshold" binding:"gte=0"`
+	Target    int64 `json:"target" xml:"target" binding:"gt=0"`
+}
+
+type ReorderPolicyResponse struct {
+	Threshold int64 `json:"threshold" xml:"threshold"`
+	Target    int64 `json:"target" xml:"target"`
+}
+
+type LowStockQuery struct {
+	SupplierId string `form:"supplier_id"`
+	Limit      int    `form:"limit,default=10"`
+	Offset     int    `form:"offset,default=0"`
+}
+
+type ReorderSuggestionQuery struct {
+	SupplierId string `form:"supplier_id"`
+}
+
+type LowStockItemResponse struct {
+	ProductId         uuid.UUID  `json:"product_id" xml:"product_id"`
+	ParentId          *uuid.UUID `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	Name              string     `json:"name" xml:"name"`
+	Sku               string     `json:"sku,omitempty" xml:"sku,omitempty"`
+	Barcode           string     `json:"barcode,omitempty" xml:"barcode,omitempty"`
+	SupplierId        uuid.UUID  `json:"supplier_id" xml:"supplier_id"`
+	SupplierName      string     `json:"supplier_name" xml:"supplier_name"`
+	AvailableStock    int64      `json:"available_stock" xml:"available_stock"`
+	ReorderThreshold  int64      `json:"reorder_threshold" xml:"reorder_threshold"`
+	ReorderTarget     int64      `json:"reorder_target" xml:"reorder_target"`
+	SuggestedQuantity int64      `json:"suggested_quantity" xml:"suggested_quantity"`
+	LowSince          *time.Time `json:"low_since,omitempty" xml:"low_since,omitempty"`
+}
+
+type ReorderLineResponse struct {
+	ProductId      uuid.UUID `json:"product_id" xml:"product_id"`
+	Name           string    `json:"name" xml:"name"`
+	Sku            string    `json:"sku,omitempty" xml:"sku,omitempty"`
+	Barcode        string    `json:"barcode,omitempty" xml:"barcode,omitempty"`
+	AvailableStock int64     `json:"available_stock" xml:"available_stock"`
+	ReorderTarget  int64     `json:"reorder_target" xml:"reorder_target"`
+	Quantity       int64     `json:"quantity" xml:"quantity"`
+}
+
+// ReorderSuggestionResponse is the draft of the purchase order of a supplier.
+type ReorderSuggestionResponse struct {
+	SupplierId    uuid.UUID             `json:"supplier_id" xml:"supplier_id"`
+	SupplierName  string                `json:"supplier_name" xml:"supplier_name"`
+	Items         []ReorderLineResponse `json:"items" xml:"items>item"`
+	TotalQuantity int64                 `json:"total_quantity" xml:"total_quantity"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobResponse struct {
+	Id              uuid.UUID         `json:"id" xml:"id"`
+	Kind            string            `json:"kind" xml:"kind"`
+	Status          string            `json:"status" xml:"status"`
+	Params          map[string]string `json:"params,omitempty" xml:"-"`
+	Progress        int64             `json:"progress" xml:"progress"`
+	Result          json.RawMessage   `json:"result,omitempty" xml:"result,omitempty"`
+	ResultUrl       string            `json:"result_url,omitempty" xml:"result_url,omitempty"`
+	Error           string            `json:"error,omitempty" xml:"error,omitempty"`
+	Attempts        int               `json:"attempts" xml:"attempts"`
+	CancelRequested bool              `json:"cancel_requested" xml:"cancel_requested"`
+	CreatedAt       time.Time         `json:"created_at" xml:"created_at"`
+	StartedAt       *time.Time        `json:"started_at,omitempty" xml:"started_at,omitempty"`
+	FinishedAt      *time.Time        `json:"finished_at,omitempty" xml:"finished_at,omitempty"`
+}
@@ -1,22 +1,144 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type ProductRequest struct {
-	Name           string    `json:"name" xml:"name" binding:"required"`
-	Category       string    `json:"category" xml:"category" binding:"required"`
-	Price          float32   `json:"price" xml:"price" binding:"required"`
-	AvailableStock int64     `json:"available_stock" xml:"available_stock" binding:"required"`
+	Name           string    `json:"name" xml:"name" binding:"required,max=200"`
+	CategoryId     uuid.UUID `json:"category_id" xml:"category_id" binding:"required"`
+	Price          float32   `json:"price" xml:"price" binding:"gt=0"`
+	AvailableStock int64     `json:"available_stock" xml:"available_stock" binding:"gte=0"`
 	SupplierId     uuid.UUID `json:"supplier_id" xml:"supplier_id" binding:"required"`
-	ImageId        uuid.UUID `json:"image_id" xml:"image_id" binding:"required"`
+	ImageId        uuid.UUID `json:"image_id,omitempty" xml:"image_id,omitempty"`
+	Sku            string    `json:"sku,omitempty" xml:"sku,omitempty" binding:"omitempty,sku"`
+	Barcode        string    `json:"barcode,omitempty" xml:"barcode,omitempty" binding:"omitempty,ean13"`
+	// Attributes are values of the attributes defined by the category
+	Attributes Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" swaggertype:"object"`
+}
+
+// ProductVariantRequest creates a variant, the name of the parent is used when
+// the name is not given.
+type ProductVariantRequest struct {
+	Name           string    `json:"name,omitempty" xml:"name,omitempty" binding:"max=200"`
+	Sku            string    `json:"sku" xml:"sku" binding:"required,sku"`
+	Barcode        string    `json:"barcode,omitempty" xml:"barcode,omitempty" binding:"omitempty,ean13"`
+	Price          float32   `json:"price" xml:"price" binding:"gt=0"`
+	AvailableStock int64     `json:"available_stock" xml:"available_stock" binding:"gte=0"`
+	ImageId        uuid.UUID `json:"image_id,omitempty" xml:"image_id,omitempty"`
+	// Attributes are merged into the attributes of the parent, null removes an
+	// attribute of the parent
+	Attributes Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" swaggertype:"object"`
 }
 
 type ProductResponse struct {
-	Id             uuid.UUID        `json:"id" xml:"id"`
-	Name           string           `json:"name" xml:"name"`
-	Category       string           `json:"category" xml:"category"`
-	Price          float32          `json:"price" xml:"price"`
-	AvailableStock int64            `json:"available_stock" xml:"available_stock"`
-	Supplier       SupplierResponse `json:"supplier" xml:"supplier"`
-	Image          ImageResponse    `json:"image" xml:"image"`
+	Id             uuid.UUID                 `json:"id" xml:"id"`
+	ParentId       *uuid.UUID                `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	Name           string                    `json:"name" xml:"name"`
+	Sku            string                    `json:"sku,omitempty" xml:"sku,omitempty"`
+	Barcode        string                    `json:"barcode,omitempty" xml:"barcode,omitempty"`
+	Category       CategoryReferenceResponse `json:"category" xml:"category"`
+	Price          float32                   `json:"price" xml:"price"`
+	AvailableStock int64                     `json:"available_stock" xml:"available_stock"`
+	Supplier       SupplierResponse          `json:"supplier" xml:"supplier"`
+	Image          ImageResponse             `json:"image" xml:"image"`
+	Gallery        []ProductImageResponse    `json:"gallery" xml:"gallery"`
+	Attributes     Attributes                `json:"attributes" xml:"attributes" swaggertype:"object"`
+	// Reorder is returned by product lookups, lists leave it out
+	Reorder  *ReorderPolicyResponse   `json:"reorder,omitempty" xml:"reorder,omitempty"`
+	Variants []ProductVariantResponse `json:"variants,omitempty" xml:"variants>variant,omitempty"`
+}
+
+type ProductVariantResponse struct {
+	Id             uuid.UUID  `json:"id" xml:"id"`
+	Name           string     `json:"name" xml:"name"`
+	Sku            string     `json:"sku" xml:"sku"`
+	Barcode        string     `json:"barcode,omitempty" xml:"barcode,omitempty"`
+	Price          float32    `json:"price" xml:"price"`
+	AvailableStock int64      `json:"available_stock" xml:"available_stock"`
+	ImageId        *uuid.UUID `json:"image_id,omitempty" xml:"image_id,omitempty"`
+	Attributes     Attributes `json:"attributes" xml:"attributes" swaggertype:"object"`
+}
+
+type ProductImageRequest struct {
+	ImageId   uuid.UUID `json:"image_id" xml:"image_id" binding:"required"`
+	Position  *int      `json:"position,omitempty" xml:"position,omitempty" binding:"omitempty,gte=0"`
+	IsPrimary bool      `json:"is_primary" xml:"is_primary"`
+}
+
+type ProductImageOrderRequest struct {
+	ImageIds []uuid.UUID `json:"image_ids" xml:"image_ids" binding:"required"`
+}
+
+type ProductImageResponse struct {
+	ImageResponse
+	Position  int  `json:"position" xml:"position"`
+	IsPrimary bool `json:"is_primary" xml:"is_primary"`
+}
+
+type ProductListQuery struct {
+	Query    string   `form:"q"`
+	Category string   `form:"category"`
+	PriceMin *float64 `form:"price_min"`
+	PriceMax *float64 `form:"price_max"`
+	InStock  *bool    `form:"in_stock"`
+	// Attributes are filters like energy_class=A++ or capacity>=300
+	Attributes []string `form:"attr"`
+	Sort       string   `form:"sort"`
+	Order      string   `form:"order"`
+	Limit      int      `form:"limit,default=10"`
+	Offset     int      `form:"offset,default=0"`
+}
+
+type ProductUpdateRequest struct {
+	Name       *string    `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=200"`
+	CategoryId *uuid.UUID `json:"category_id,omitempty" xml:"category_id,omitempty"`
+	Price      *float32   `json:"price,omitempty" xml:"price,omitempty" binding:"omitempty,gt=0"`
+	SupplierId *uuid.UUID `json:"supplier_id,omitempty" xml:"supplier_id,omitempty"`
+	ImageId    *uuid.UUID `json:"image_id,omitempty" xml:"image_id,omitempty"`
+	// Sku and Barcode are removed by empty strings
+	Sku     *string `json:"sku,omitempty" xml:"sku,omitempty" binding:"omitempty,sku"`
+	Barcode *string `json:"barcode,omitempty" xml:"barcode,omitempty" binding:"omitempty,ean13"`
+	// Attributes are merged into the product attributes, null removes an
+	// attribute
+	Attributes Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" swaggertype:"object"`
+}
+
+type ProductStockRequest struct {
+	Quantity int64  `json:"quantity" xml:"quantity" binding:"gte=0"`
+	Reason   string `json:"reason" xml:"reason" binding:"required,oneof=sale return restock damage loss correction"`
+	Note     string `json:"note,omitempty" xml:"note,omitempty" binding:"max=500"`
+	// WarehouseId is required to increase or set the stock of a product
+	// stocked in warehouses, a decrease without it picks the warehouse
+	WarehouseId *uuid.UUID `json:"warehouse_id,omitempty" xml:"warehouse_id,omitempty"`
+	// Strategy picks the warehouse of a decrease, the configured one is used
+	// when it is empty
+	Strategy string `json:"strategy,omitempty" xml:"strategy,omitempty" binding:"omitempty,oneof=most_stock nearest"`
+	// DestinationAddressId is the address the goods go to for the nearest
+	// strategy
+	DestinationAddressId *uuid.UUID `json:"destination_address_id,omitempty" xml:"destination_address_id,omitempty"`
+}
+
+type ProductStockTransferRequest struct {
+	FromWarehouseId uuid.UUID `json:"from_warehouse_id" xml:"from_warehouse_id" binding:"required"`
+	ToWarehouseId   uuid.UUID `json:"to_warehouse_id" xml:"to_warehouse_id" binding:"required"`
+	Quantity        int64     `json:"quantity" xml:"quantity" binding:"gt=0"`
+	Note            string    `json:"note,omitempty" xml:"note,omitempty" binding:"max=500"`
+}
+
+type StockMovementResponse struct {
+	Id          uuid.UUID `json:"id" xml:"id"`
+	ProductId   uuid.UUID `json:"product_id" xml:"product_id"`
+	Operation   string    `json:"operation" xml:"operation"`
+	Quantity    int64     `json:"quantity" xml:"quantity"`
+	Reason      string    `json:"reason" xml:"reason"`
+	Note        string    `json:"note,omitempty" xml:"note,omitempty"`
+	StockBefore int64     `json:"stock_before" xml:"stock_before"`
+	StockAfter  int64     `json:"stock_after" xml:"stock_after"`
+	// WarehouseId is the changed warehouse, the source of a transfer
+	WarehouseId   *uuid.UUID `json:"warehouse_id,omitempty" xml:"warehouse_id,omitempty"`
+	ToWarehouseId *uuid.UUID `json:"to_warehouse_id,omitempty" xml:"to_warehouse_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" xml:"created_at"`
 }
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromotionRequest creates a promotion. At most one of product_id,
+// category_id and supplier_id scopes it, none applies it to every product. A
+// promotion with coupon_code applies only when the coupon is given, active is
+// true by default.
+type PromotionRequest struct {
+	Name           string     `json:"name" xml:"name" binding:"required,max=200"`
+	Kind           string     `json:"kind" xml:"kind" binding:"required,oneof=percentage fixed"`
+	Value          float64    `json:"value" xml:"value" binding:"gt=0"`
+	ProductId      *uuid.UUID `json:"product_id,omitempty" xml:"product_id,omitempty"`
+	CategoryId     *uuid.UUID `json:"category_id,omitempty" xml:"category_id,omitempty"`
+	SupplierId     *uuid.UUID `json:"supplier_id,omitempty" xml:"supplier_id,omitempty"`
+	MinQuantity    int64      `json:"min_quantity,omitempty" xml:"min_quantity,omitempty" binding:"gte=0"`
+	StartsAt       *time.Time `json:"starts_at,omitempty" xml:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty" xml:"ends_at,omitempty"`
+	UsageLimit     *int64     `json:"usage_limit,omitempty" xml:"usage_limit,omitempty" binding:"omitempty,gt=0"`
+	PerClientLimit *int64     `json:"per_client_limit,omitempty" xml:"per_client_limit,omitempty" binding:"omitempty,gt=0"`
+	CouponCode     string     `json:"coupon_code,omitempty" xml:"coupon_code,omitempty" binding:"max=32"`
+	Active         *bool      `json:"active,omitempty" xml:"active,omitempty"`
+}
+
+type PromotionUpdateRequest struct {
+	Name           *string    `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=200"`
+	Active         *bool      `json:"active,omitempty" xml:"active,omitempty"`
+	StartsAt       *time.Time `json:"starts_at,omitempty" xml:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty" xml:"ends_at,omitempty"`
+	UsageLimit     *int64     `json:"usage_limit,omitempty" xml:"usage_limit,omitempty" binding:"omitempty,gt=0"`
+	PerClientLimit *int64     `json:"per_client_limit,omitempty" xml:"per_client_limit,omitempty" binding:"omitempty,gt=0"`
+}
+
+type PromotionResponse struct {
+	Id             uuid.UUID  `json:"id" xml:"id"`
+	Name           string     `json:"name" xml:"name"`
+	Kind           string     `json:"kind" xml:"kind"`
+	Value          float64    `json:"value" xml:"value"`
+	ProductId      *uuid.UUID `json:"product_id,omitempty" xml:"product_id,omitempty"`
+	CategoryId     *uuid.UUID `json:"category_id,omitempty" xml:"category_id,omitempty"`
+	SupplierId     *uuid.UUID `json:"supplier_id,omitempty" xml:"supplier_id,omitempty"`
+	MinQuantity    int64      `json:"min_quantity" xml:"min_quantity"`
+	StartsAt       *time.Time `json:"starts_at,omitempty" xml:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty" xml:"ends_at,omitempty"`
+	UsageLimit     *int64     `json:"usage_limit,omitempty" xml:"usage_limit,omitempty"`
+	PerClientLimit *int64     `json:"per_client_limit,omitempty" xml:"per_client_limit,omitempty"`
+	CouponCode     string     `json:"coupon_code,omitempty" xml:"coupon_code,omitempty"`
+	Active         bool       `json:"active" xml:"active"`
+	CreatedAt      time.Time  `json:"created_at" xml:"created_at"`
+}
+
+type PromotionItemRequest struct {
+	ProductId uuid.UUID `json:"product_id" xml:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" xml:"quantity" binding:"gt=0"`
+}
+
+// PromotionEvaluationRequest lists the items to discount, the client is
+// required by promotions limited per client.
+type PromotionEvaluationRequest struct {
+	ClientId *uuid.UUID             `json:"client_id,omitempty" xml:"client_id,omitempty"`
+	Items    []PromotionItemRequest `json:"items" xml:"items>item" binding:"required,min=1,max=100,dive"`
+	Coupons  []string               `json:"coupons,omitempty" xml:"coupons>coupon,omitempty" binding:"max=10,dive,required,max=32"`
+}
+
+type AppliedPromotionResponse struct {
+	Id          uuid.UUID `json:"id" xml:"id"`
+	Name        string    `json:"name" xml:"name"`
+	CouponCode  string    `json:"coupon_code,omitempty" xml:"coupon_code,omitempty"`
+	Explanation string    `json:"explanation" xml:"explanation"`
+}
+
+type PromotionLineResponse struct {
+	ProductId uuid.UUID                 `json:"product_id" xml:"product_id"`
+	Name      string                    `json:"name" xml:"name"`
+	Price     float32                   `json:"price" xml:"price"`
+	Quantity  int64                     `json:"quantity" xml:"quantity"`
+	Subtotal  float64                   `json:"subtotal" xml:"subtotal"`
+	Discount  float64                   `json:"discount" xml:"discount"`
+	Total     float64                   `json:"total" xml:"total"`
+	Promotion *AppliedPromotionResponse `json:"promotion,omitempty" xml:"promotion,omitempty"`
+}
+
+// PromotionNoteResponse explains a promotion or a coupon which is not applied.
+type PromotionNoteResponse struct {
+	PromotionId *uuid.UUID `json:"promotion_id,omitempty" xml:"promotion_id,omitempty"`
+	Name        string     `json:"name,omitempty" xml:"name,omitempty"`
+	CouponCode  string     `json:"coupon_code,omitempty" xml:"coupon_code,omitempty"`
+	ProductId   *uuid.UUID `json:"product_id,omitempty" xml:"product_id,omitempty"`
+	Reason      string     `json:"reason" xml:"reason"`
+	Message     string     `json:"message" xml:"message"`
+}
+
+type PromotionEvaluationResponse struct {
+	ClientId *uuid.UUID              `json:"client_id,omitempty" xml:"client_id,omitempty"`
+	Items    []PromotionLineResponse `json:"items" xml:"items>item"`
+	Subtotal float64                 `json:"subtotal" xml:"subtotal"`
+	Discount float64                 `json:"discount" xml:"discount"`
+	Total    float64                 `json:"total" xml:"total"`
+	Notes    []PromotionNoteResponse `json:"notes" xml:"notes>note"`
+}
@@ -1,16 +1,46 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SupplierRequest struct {
-	Name        string `json:"name" xml:"name" binding:"required"`
-	PhoneNumber string `json:"phone_number" xml:"phone_number" binding:"required"`
+	Name          string `json:"name" xml:"name" binding:"required,max=200"`
+	PhoneNumber   string `json:"phone_number" xml:"phone_number" binding:"required,intl_phone"`
+	ContactPerson string `json:"contact_person,omitempty" xml:"contact_person,omitempty" binding:"max=200"`
+	Email         string `json:"email,omitempty" xml:"email,omitempty" binding:"omitempty,email,max=254"`
+	Website       string `json:"website,omitempty" xml:"website,omitempty" binding:"max=2048"`
+	TaxId         string `json:"tax_id,omitempty" xml:"tax_id,omitempty" binding:"max=32"`
+	*Address
+}
+
+type SupplierUpdateRequest struct {
+	Name          *string `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=200"`
+	PhoneNumber   *string `json:"phone_number,omitempty" xml:"phone_number,omitempty" binding:"omitempty,intl_phone"`
+	ContactPerson *string `json:"contact_person,omitempty" xml:"contact_person,omitempty" binding:"omitempty,max=200"`
+	Email         *string `json:"email,omitempty" xml:"email,omitempty" binding:"omitempty,email,max=254"`
+	Website       *string `json:"website,omitempty" xml:"website,omitempty" binding:"omitempty,max=2048"`
+	TaxId         *string `json:"tax_id,omitempty" xml:"tax_id,omitempty" binding:"omitempty,max=32"`
 	*Address
 }
 
 type SupplierResponse struct {
-	Id          uuid.UUID `json:"id" xml:"id"`
-	Name        string    `json:"name" xml:"name"`
-	PhoneNumber string    `json:"phone_number" xml:"phone_number"`
+	Id            uuid.UUID `json:"id" xml:"id"`
+	Name          string    `json:"name" xml:"name"`
+	PhoneNumber   string    `json:"phone_number" xml:"phone_number"`
+	ContactPerson string    `json:"contact_person,omitempty" xml:"contact_person,omitempty"`
+	Email         string    `json:"email,omitempty" xml:"email,omitempty"`
+	Website       string    `json:"website,omitempty" xml:"website,omitempty"`
+	TaxId         string    `json:"tax_id,omitempty" xml:"tax_id,omitempty"`
 	*Address
 }
+
+type SupplierStatsResponse struct {
+	ProductCount    int        `json:"product_count" xml:"product_count"`
+	TotalStockUnits int64      `json:"total_stock_units" xml:"total_stock_units"`
+	TotalStockValue float64    `json:"total_stock_value" xml:"total_stock_value"`
+	OutOfStockCount int        `json:"out_of_stock_count" xml:"out_of_stock_count"`
+	LastDeliveryAt  *time.Time `json:"last_delivery_at,omitempty" xml:"last_delivery_at,omitempty"`
+}
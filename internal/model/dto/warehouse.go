@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WarehouseRequest struct {
+	Name string `json:"name" xml:"name" binding:"required,max=200"`
+	*Address
+}
+
+type WarehouseUpdateRequest struct {
+	Name *string `json:"name,omitempty" xml:"name,omitempty" binding:"omitempty,max=200"`
+	*Address
+}
+
+type WarehouseResponse struct {
+	Id        uuid.UUID `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	AddressId uuid.UUID `json:"address_id" xml:"address_id"`
+	*Address
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+type WarehouseStockResponse struct {
+	WarehouseId   uuid.UUID `json:"warehouse_id" xml:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name" xml:"warehouse_name"`
+	ProductId     uuid.UUID `json:"product_id" xml:"product_id"`
+	ProductName   string    `json:"product_name" xml:"product_name"`
+	Sku           string    `json:"sku,omitempty" xml:"sku,omitempty"`
+	Quantity      int64     `json:"quantity" xml:"quantity"`
+}
@@ -0,0 +1,456 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// addressBook names the link table between owners and addresses.
+type addressBook struct {
+	table       string
+	ownerColumn string
+}
+
+var (
+	clientAddressBook    = addressBook{table: "client_address", ownerColumn: "client_id"}
+	supplierLocationBook = addressBook{table: "supplier_location", ownerColumn: "supplier_id"}
+)
+
+// defaultAddressJoin joins the default entry address of the owner as "a".
+func (b addressBook) defaultAddressJoin(ownerId string) string {
+	return fmt.Sprintf(`LEFT JOIN %s ab ON ab.%s = %s AND ab.is_default
+		LEFT JOIN address a ON ab.address_id = a.id`, b.table, b.ownerColumn, ownerId)
+}
+
+// AddressBookRepo keeps addresses of clients or locations of suppliers.
+type AddressBookRepo struct {
+	*basePostgresRepository
+	book addressBook
+}
+
+func NewClientAddressRepository(db DB, logger *logger.Logger) *AddressBookRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres client address repository is created")
+	return &AddressBookRepo{
+		basePostgresRepository: repo,
+		book:                   clientAddressBook,
+	}
+}
+
+func NewSupplierLocationRepository(db DB, logger *logger.Logger) *AddressBookRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres supplier location repository is created")
+	return &AddressBookRepo{
+		basePostgresRepository: repo,
+		book:                   supplierLocationBook,
+	}
+}
+
+// Add links the address to the owner. The first entry always becomes default,
+// a new default entry takes the flag away from the previous one.
+func (r *AddressBookRepo) Add(ctx context.Context, ownerId uuid.UUID, entry *domain.AddressBookEntry) error {
+	op := "repository.postgres.addressBookRepository.Add"
+
+	if entry.IsDefault {
+		if err := r.resetDefault(ctx, ownerId, entry.Address.Id); err != nil {
+			r.logger.Error("failed to reset default entry", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %v", op, err)
+		}
+	}
+
+	sqlInsert := fmt.Sprintf(`INSERT INTO %[1]s(%[2]s, address_id, label, is_default)
+		SELECT
+			@owner_id,
+			@address_id,
+			@label,
+			@is_default::BOOLEAN OR NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[2]s = @owner_id)
+		RETURNING is_default, version;`, r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id":   ownerId,
+		"address_id": entry.Address.Id,
+		"label":      entry.Label,
+		"is_default": entry.IsDefault,
+	}
+
+	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&entry.IsDefault, &entry.Version)
+	if err != nil {
+		if mapped := mapAddressBookError(err); mapped != nil {
+			r.logger.Debug("address book constraint violated", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, mapped)
+		}
+
+		r.logger.Error("failed to add address", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
+
+// CopyFrom bulk links addresses to owners by owner id, owners must not have
+// entries yet.
+func (r *AddressBookRepo) CopyFrom(ctx context.Context, entries map[uuid.UUID]domain.AddressBookEntry) (int64, error) {
+	op := "repository.postgres.addressBookRepository.CopyFrom"
+	columns := []string{r.book.ownerColumn, "address_id", "label", "is_default"}
+
+	rows := make([][]any, 0, len(entries))
+	for ownerId, entry := range entries {
+		rows = append(rows, []any{ownerId, entry.Address.Id, entry.Label, entry.IsDefault})
+	}
+
+	count, err := r.db.CopyFrom(ctx, pgx.Identifier{r.book.table}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		if mapped := mapAddressBookError(err); mapped != nil {
+			r.logger.Debug("address book constraint violated", logger.Err(err), "op", op)
+			return 0, fmt.Errorf("%s: %w", op, mapped)
+		}
+
+		r.logger.Error("failed to copy address book entries", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: copy failed: %v", op, err)
+	}
+
+	return count, nil
+}
+
+// Update changes label or default flag of the entry and increments its version.
+// The default flag cannot be taken away directly, another entry has to become
+// default instead. A non-zero patch.Version is the expected version, the new
+// one is set back.
+func (r *AddressBookRepo) Update(ctx context.Context, ownerId, addressId uuid.UUID, patch *domain.AddressBookPatch) error {
+	op := "repository.postgres.addressBookRepository.Update"
+
+	if patch.IsDefault != nil && !*patch.IsDefault {
+		r.logger.Debug("default flag cannot be reset directly", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	if patch.IsDefault != nil {
+		if err := r.resetDefault(ctx, ownerId, addressId); err != nil {
+			r.logger.Error("failed to reset default entry", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %v", op, err)
+		}
+	}
+
+	sqlStatement := fmt.Sprintf(`UPDATE %s SET
+		label = COALESCE(@label, label),
+		is_default = is_default OR @is_default::BOOLEAN,
+		version = version + 1
+		WHERE %s = @owner_id AND address_id = @address_id AND (@version = 0 OR version = @version)
+		RETURNING version`, r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id":   ownerId,
+		"address_id": addressId,
+		"label":      patch.Label,
+		"is_default": patch.IsDefault != nil,
+		"version":    patch.Version,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&patch.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, r.missingEntry(ctx, ownerId, addressId, patch.Version, op))
+	}
+
+	if err != nil {
+		if mapped := mapAddressBookError(err); mapped != nil {
+			r.logger.Debug("address book constraint violated", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, mapped)
+		}
+
+		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+// Remove unlinks the address from the owner, a non-zero version must match the
+// entry version. If the removed entry was default, the remaining entry with the
+// lowest address id is promoted.
+func (r *AddressBookRepo) Remove(ctx context.Context, ownerId, addressId uuid.UUID, version int64) error {
+	op := "repository.postgres.addressBookRepository.Remove"
+	sqlDelete := fmt.Sprintf(`DELETE FROM %s
+		WHERE %s = @owner_id AND address_id = @address_id AND (@version = 0 OR version = @version)
+		RETURNING is_default;`, r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id":   ownerId,
+		"address_id": addressId,
+		"version":    version,
+	}
+
+	var wasDefault bool
+
+	err := r.db.QueryRow(ctx, sqlDelete, args).Scan(&wasDefault)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, r.missingEntry(ctx, ownerId, addressId, version, op))
+	}
+
+	if err != nil {
+		r.logger.Error("failed to remove address", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if !wasDefault {
+		return nil
+	}
+
+	sqlPromote := fmt.Sprintf(`UPDATE %[1]s SET is_default = TRUE, version = version + 1
+		WHERE %[2]s = @owner_id AND address_id = (
+			SELECT address_id FROM %[1]s
+			WHERE %[2]s = @owner_id
+			ORDER BY address_id
+			LIMIT 1
+		);`, r.book.table, r.book.ownerColumn)
+
+	if _, err := r.db.Exec(ctx, sqlPromote, args); err != nil {
+		r.logger.Error("failed to promote default address", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+// ReplaceDefault points the default entry to another address, the label of the
+// replaced entry is kept. Without a default entry the given label is used.
+func (r *AddressBookRepo) ReplaceDefault(ctx context.Context, ownerId, addressId uuid.UUID, label string) error {
+	op := "repository.postgres.addressBookRepository.ReplaceDefault"
+	sqlDelete := fmt.Sprintf(`DELETE FROM %s
+		WHERE %s = @owner_id AND (is_default OR address_id = @address_id)
+		RETURNING label, is_default;`, r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id":   ownerId,
+		"address_id": addressId,
+	}
+
+	rows, err := r.db.Query(ctx, sqlDelete, args)
+	if err != nil {
+		r.logger.Error("failed to remove default address", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	for rows.Next() {
+		var (
+			removedLabel string
+			wasDefault   bool
+		)
+
+		if err := rows.Scan(&removedLabel, &wasDefault); err != nil {
+			rows.Close()
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		if wasDefault {
+			label = removedLabel
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to remove default address", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return r.Add(ctx, ownerId, &domain.AddressBookEntry{
+		Address:   domain.Address{Id: addressId},
+		Label:     label,
+		IsDefault: true,
+	})
+}
+
+// Repoint moves entries of the address to another address. An owner having both
+// addresses keeps one entry, it stays default if any of the two was default.
+func (r *AddressBookRepo) Repoint(ctx context.Context, from, to uuid.UUID) error {
+	op := "repository.postgres.addressBookRepository.Repoint"
+	sqlDelete := fmt.Sprintf(`DELETE FROM %s
+		WHERE address_id = @from
+		RETURNING %s, label, is_default;`, r.book.table, r.book.ownerColumn)
+
+	rows, err := r.db.Query(ctx, sqlDelete, pgx.NamedArgs{"from": from})
+	if err != nil {
+		r.logger.Error("failed to remove entries", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	type movedEntry struct {
+		ownerId   uuid.UUID
+		label     string
+		isDefault bool
+	}
+
+	var moved []movedEntry
+
+	for rows.Next() {
+		var entry movedEntry
+
+		if err := rows.Scan(&entry.ownerId, &entry.label, &entry.isDefault); err != nil {
+			rows.Close()
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		moved = append(moved, entry)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to remove entries", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	sqlInsert := fmt.Sprintf(`INSERT INTO %[1]s AS ab (%[2]s, address_id, label, is_default)
+		VALUES (@owner_id, @to, @label, @is_default)
+		ON CONFLICT (%[2]s, address_id) DO UPDATE SET
+			is_default = ab.is_default OR EXCLUDED.is_default,
+			version = ab.version + 1;`, r.book.table, r.book.ownerColumn)
+
+	for _, entry := range moved {
+		args := pgx.NamedArgs{
+			"owner_id":   entry.ownerId,
+			"to":         to,
+			"label":      entry.label,
+			"is_default": entry.isDefault,
+		}
+
+		if _, err := r.db.Exec(ctx, sqlInsert, args); err != nil {
+			r.logger.Error("failed to move entry", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed exec query: %v", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *AddressBookRepo) GetByOwner(ctx context.Context, ownerId uuid.UUID) ([]domain.AddressBookEntry, error) {
+	op := "repository.postgres.addressBookRepository.GetByOwner"
+
+	entries, err := selectAddressBook(ctx, r.db, r.book, []uuid.UUID{ownerId})
+	if err != nil {
+		r.logger.Error("failed to get address book", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return entries[ownerId], nil
+}
+
+// resetDefault takes the default flag away from any entry of the owner except
+// the kept address, which keeps its flag and version.
+func (r *AddressBookRepo) resetDefault(ctx context.Context, ownerId, keepId uuid.UUID) error {
+	sqlReset := fmt.Sprintf(`UPDATE %s SET is_default = FALSE, version = version + 1
+		WHERE %s = @owner_id AND is_default AND address_id <> @keep_id`, r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id": ownerId,
+		"keep_id":  keepId,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlReset, args); err != nil {
+		return fmt.Errorf("failed exec query: %v", err)
+	}
+
+	return nil
+}
+
+// missingEntry tells why a versioned statement has touched no entry:
+// ErrVersionMismatch when the entry exists with another version, otherwise
+// ErrNotFound.
+func (r *AddressBookRepo) missingEntry(ctx context.Context, ownerId, addressId uuid.UUID, version int64, op string) error {
+	if version == 0 {
+		r.logger.Debug("address book entry not found", "op", op)
+		return crud_errors.ErrNotFound
+	}
+
+	sqlStatement := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE %s = @owner_id AND address_id = @address_id)`,
+		r.book.table, r.book.ownerColumn)
+	args := pgx.NamedArgs{
+		"owner_id":   ownerId,
+		"address_id": addressId,
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&exists); err != nil {
+		r.logger.Error("failed to check address book entry version", logger.Err(err), "op", op)
+		return fmt.Errorf("failed to check version: %v", err)
+	}
+
+	if exists {
+		r.logger.Debug("address book entry version mismatch", "op", op)
+		return crud_errors.ErrVersionMismatch
+	}
+
+	r.logger.Debug("address book entry not found", "op", op)
+	return crud_errors.ErrNotFound
+}
+
+// mapAddressBookError translates constraint violations, nil means the error is
+// not a known violation.
+func mapAddressBookError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+
+	switch pgErr.Code {
+	case "23503":
+		return crud_errors.ErrNotFound
+	case "23505":
+		return crud_errors.ErrDuplicateKeyValue
+	case "23514":
+		return crud_errors.ErrInvalidParam
+	}
+
+	return nil
+}
+
+// selectAddressBook loads address books of the given owners in one query, the
+// default entry goes first.
+func selectAddressBook(ctx context.Context, db DB, book addressBook, ownerIds []uuid.UUID) (map[uuid.UUID][]domain.AddressBookEntry, error) {
+	sqlStatement := fmt.Sprintf(`SELECT
+		ab.%[2]s,
+		ab.label,
+		ab.is_default,
+		ab.version,
+		%[3]s
+		FROM %[1]s ab
+		JOIN address a ON ab.address_id = a.id
+		WHERE ab.%[2]s = ANY(@owner_ids::UUID[])
+		ORDER BY ab.%[2]s, ab.is_default DESC, ab.label, a.id;`, book.table, book.ownerColumn, addressColumns)
+	args := pgx.NamedArgs{
+		"owner_ids": ownerIds,
+	}
+
+	rows, err := db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[uuid.UUID][]domain.AddressBookEntry, len(ownerIds))
+
+	for rows.Next() {
+		var (
+			ownerId uuid.UUID
+			entry   domain.AddressBookEntry
+			address nullableAddress
+		)
+
+		targets := append([]any{&ownerId, &entry.Label, &entry.IsDefault, &entry.Version}, address.targets()...)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("scan failed: %v", err)
+		}
+
+		entry.Address = *address.toDomain()
+		entries[ownerId] = append(entries[ownerId], entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return entries, nil
+}
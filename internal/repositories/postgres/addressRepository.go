@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type AddressRepo struct {
@@ -22,36 +23,223 @@ func NewAddressRepository(db DB, log *logger.Logger) *AddressRepo {
 	return &AddressRepo{baseRepo}
 }
 
+// Create inserts the address or takes the existing same address. Addresses are
+// the same when their country codes are equal and other fields are equal ignoring
+// case, the stored address is returned then and gains missing coordinates.
 func (r *AddressRepo) Create(ctx context.Context, address *domain.Address) error {
 	op := "repository.postgres.addressRepository.Create"
 	sqlInsert := `INSERT 
-		INTO address(country, city, street)
-		VALUES (@country, @city, @street) 
-		ON CONFLICT DO NOTHING
-		RETURNING id`
+		INTO address AS a (country, city, street, postal_code, region, building, apartment, line1, line2, latitude, longitude)
+		VALUES (@country, @city, @street, @postal_code, @region, @building, @apartment, @line1, @line2, @latitude, @longitude) 
+		ON CONFLICT (` + addressUniqueKey + `) DO UPDATE SET
+			latitude = COALESCE(a.latitude, EXCLUDED.latitude),
+			longitude = COALESCE(a.longitude, EXCLUDED.longitude)
+		RETURNING ` + addressColumns
 
 	args := pgx.NamedArgs{
-		"country": address.Country,
-		"city":    address.City,
-		"street":  address.Street,
+		"country":     address.Country,
+		"city":        address.City,
+		"street":      address.Street,
+		"postal_code": address.PostalCode,
+		"region":      address.Region,
+		"building":    address.Building,
+		"apartment":   address.Apartment,
+		"line1":       address.Line1,
+		"line2":       address.Line2,
+		"latitude":    address.Latitude,
+		"longitude":   address.Longitude,
 	}
 
-	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&address.Id)
-	if err == nil {
-		return nil
-	}
+	var stored nullableAddress
 
-	if !errors.Is(err, pgx.ErrNoRows) {
-		r.logger.Debug("failed to insert address", logger.Err(err), "op", op)
+	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(stored.targets()...)
+	if err != nil {
+		r.logger.Error("failed to insert address", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: %v", op, err)
 	}
 
-	sqlSelect := `SELECT id FROM address WHERE country = @country AND city = @city AND street = @street`
+	*address = *stored.toDomain()
+
+	return nil
+}
+
+// GetAll returns a page of addresses ordered by id.
+func (r *AddressRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Address, error) {
+	op := "repository.postgres.addressRepository.GetAll"
+	sqlStatement := `SELECT ` + addressColumns + ` FROM address a
+		ORDER BY a.id
+		LIMIT @limit OFFSET @offset;`
+	args := pgx.NamedArgs{
+		"limit":  limit,
+		"offset": offset,
+	}
 
-	err = r.db.QueryRow(ctx, sqlSelect, args).Scan(&address.Id)
+	rows, err := r.db.Query(ctx, sqlStatement, args)
 	if err != nil {
-		r.logger.Error("failed to get existing address id", logger.Err(err), "op", op)
-		return fmt.Errorf("%s: %v", op, err)
+		r.logger.Error("failed to get addresses", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var addresses []domain.Address
+
+	for rows.Next() {
+		var address nullableAddress
+		if err := rows.Scan(address.targets()...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		addresses = append(addresses, *address.toDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get addresses", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(addresses) == 0 {
+		r.logger.Debug("addresses not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return addresses, nil
+}
+
+func (r *AddressRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Address, error) {
+	op := "repository.postgres.addressRepository.GetById"
+	sqlStatement := `SELECT ` + addressColumns + ` FROM address a WHERE a.id = @id`
+
+	var address nullableAddress
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(address.targets()...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Debug("address not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to get address", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	return address.toDomain(), nil
+}
+
+// GetReferences returns clients and suppliers which have the address in their
+// address books and warehouses located at it.
+func (r *AddressRepo) GetReferences(ctx context.Context, id uuid.UUID) ([]domain.AddressReference, error) {
+	op := "repository.postgres.addressRepository.GetReferences"
+	sqlStatement := `SELECT 'client', client_id, label, is_default FROM client_address WHERE address_id = @id
+		UNION ALL
+		SELECT 'supplier', supplier_id, label, is_default FROM supplier_location WHERE address_id = @id
+		UNION ALL
+		SELECT 'warehouse', id, name, TRUE FROM warehouse WHERE address_id = @id
+		ORDER BY 1, 2;`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"id": id})
+	if err != nil {
+		r.logger.Error("failed to get address references", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	references := []domain.AddressReference{}
+
+	for rows.Next() {
+		var reference domain.AddressReference
+		if err := rows.Scan(&reference.OwnerType, &reference.OwnerId, &reference.Label, &reference.IsDefault); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		references = append(references, reference)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get address references", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return references, nil
+}
+
+// DeleteOrphans deletes up to limit addresses no client, supplier or warehouse
+// refers to and returns the number of deleted addresses. Rows locked by running
+// transactions are skipped, they may be getting a reference right now.
+func (r *AddressRepo) DeleteOrphans(ctx context.Context, limit int) (int, error) {
+	op := "repository.postgres.addressRepository.DeleteOrphans"
+	sqlDelete := `DELETE FROM address WHERE id IN (
+			SELECT a.id FROM address a
+			WHERE NOT EXISTS (SELECT 1 FROM client_address ca WHERE ca.address_id = a.id)
+			AND NOT EXISTS (SELECT 1 FROM supplier_location sl WHERE sl.address_id = a.id)
+			AND NOT EXISTS (SELECT 1 FROM warehouse w WHERE w.address_id = a.id)
+			ORDER BY a.id
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		);`
+
+	tag, err := r.db.Exec(ctx, sqlDelete, pgx.NamedArgs{"limit": limit})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			r.logger.Debug("address got a reference during deletion", "op", op)
+			return 0, fmt.Errorf("%s: %w", op, crud_errors.ErrForeignKeyViolation)
+		}
+
+		r.logger.Error("failed to delete orphan addresses", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// Update rewrites all fields of the address.
+func (r *AddressRepo) Update(ctx context.Context, address *domain.Address) error {
+	op := "repository.postgres.addressRepository.Update"
+	sqlStatement := `UPDATE address SET
+		country = @country,
+		city = @city,
+		street = @street,
+		postal_code = @postal_code,
+		region = @region,
+		building = @building,
+		apartment = @apartment,
+		line1 = @line1,
+		line2 = @line2,
+		latitude = @latitude,
+		longitude = @longitude
+		WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":          address.Id,
+		"country":     address.Country,
+		"city":        address.City,
+		"street":      address.Street,
+		"postal_code": address.PostalCode,
+		"region":      address.Region,
+		"building":    address.Building,
+		"apartment":   address.Apartment,
+		"line1":       address.Line1,
+		"line2":       address.Line2,
+		"latitude":    address.Latitude,
+		"longitude":   address.Longitude,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("same address already exists", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("address not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
 
 	return nil
@@ -73,3 +261,67 @@ func (r *AddressRepo) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// addressUniqueKey lists expressions of the address_unique_location index.
+const addressUniqueKey = `country, lower(region), lower(city), lower(postal_code), lower(street),
+		lower(building), lower(apartment), lower(line1), lower(line2)`
+
+// addressColumns selects address fields in the order of nullableAddress targets.
+const addressColumns = `a.id,
+		a.country,
+		a.city,
+		a.street,
+		a.postal_code,
+		a.region,
+		a.building,
+		a.apartment,
+		a.line1,
+		a.line2,
+		a.latitude,
+		a.longitude`
+
+// nullableAddress receives address columns of an outer join.
+type nullableAddress struct {
+	id                                                                           *uuid.UUID
+	country, city, street, postalCode, region, building, apartment, line1, line2 *string
+	latitude, longitude                                                          *float64
+}
+
+func (a *nullableAddress) targets() []any {
+	return []any{
+		&a.id,
+		&a.country,
+		&a.city,
+		&a.street,
+		&a.postalCode,
+		&a.region,
+		&a.building,
+		&a.apartment,
+		&a.line1,
+		&a.line2,
+		&a.latitude,
+		&a.longitude,
+	}
+}
+
+// toDomain returns nil when the join found no address.
+func (a *nullableAddress) toDomain() *domain.Address {
+	if a.id == nil {
+		return nil
+	}
+
+	return &domain.Address{
+		Id:         *a.id,
+		Country:    *a.country,
+		City:       *a.city,
+		Street:     *a.street,
+		PostalCode: *a.postalCode,
+		Region:     *a.region,
+		Building:   *a.building,
+		Apartment:  *a.apartment,
+		Line1:      *a.line1,
+		Line2:      *a.line2,
+		Latitude:   a.latitude,
+		Longitude:  a.longitude,
+	}
+}
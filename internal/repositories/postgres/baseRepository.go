@@ -3,7 +3,9 @@ package postgres
 import (
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
 	"context"
+	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -14,6 +16,7 @@ type DB interface {
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 }
 
 type basePostgresRepository struct {
@@ -28,3 +31,20 @@ func newBasePostgresRepository(db DB, logger *logger.Logger) *basePostgresReposi
 		logger: logger,
 	}
 }
+
+// versionChanged reports whether the row exists with a version other than the
+// expected one. It is called when a versioned statement has touched no row.
+func (r *basePostgresRepository) versionChanged(ctx context.Context, table string, id uuid.UUID, version int64) (bool, error) {
+	if version == 0 {
+		return false, nil
+	}
+
+	sqlStatement := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = @id)`, table)
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,357 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// cartColumns are scanned by cartTargets, expired carts are filtered by
+// cartAlive.
+const (
+	cartColumns = `c.id,
+		c.client_id,
+		c.version,
+		c.created_at,
+		c.updated_at,
+		c.expires_at`
+	cartAlive = `c.expires_at > NOW()`
+)
+
+func cartTargets(cart *domain.Cart) []any {
+	return []any{
+		&cart.Id,
+		&cart.ClientId,
+		&cart.Version,
+		&cart.CreatedAt,
+		&cart.UpdatedAt,
+		&cart.ExpiresAt,
+	}
+}
+
+type CartRepo struct {
+	*basePostgresRepository
+}
+
+func NewCartRepository(db DB, logger *logger.Logger) *CartRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres cart repository is created")
+	return &CartRepo{
+		repo,
+	}
+}
+
+// Create inserts the empty cart expiring after ttl. An expired cart of the
+// client is deleted first, ErrDuplicateKeyValue is returned when the client
+// has a cart and ErrNotFound when the client does not exist.
+func (r *CartRepo) Create(ctx context.Context, cart *domain.Cart, ttl time.Duration) error {
+	op := "repository.postgres.cartRepository.Create"
+
+	if err := r.deleteExpiredOf(ctx, op, cart.ClientId); err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO cart(client_id, expires_at)
+		VALUES (@client_id, NOW() + make_interval(secs => @ttl))
+		RETURNING id, version, created_at, updated_at, expires_at;`
+	args := pgx.NamedArgs{
+		"client_id": cart.ClientId,
+		"ttl":       ttl.Seconds(),
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&cart.Id, &cart.Version, &cart.CreatedAt, &cart.UpdatedAt, &cart.ExpiresAt)
+	if err != nil {
+		return r.clientError(op, err)
+	}
+
+	return nil
+}
+
+// GetById returns the cart with its items unless it is expired.
+func (r *CartRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Cart, error) {
+	op := "repository.postgres.cartRepository.GetById"
+	sqlStatement := `SELECT
+		` + cartColumns + `
+		FROM cart c
+		WHERE c.id = @id AND ` + cartAlive
+
+	return r.get(ctx, op, sqlStatement, pgx.NamedArgs{"id": id})
+}
+
+// GetByClient returns the cart of the client with its items unless it is
+// expired.
+func (r *CartRepo) GetByClient(ctx context.Context, clientId uuid.UUID) (*domain.Cart, error) {
+	op := "repository.postgres.cartRepository.GetByClient"
+	sqlStatement := `SELECT
+		` + cartColumns + `
+		FROM cart c
+		WHERE c.client_id = @client_id AND ` + cartAlive
+
+	return r.get(ctx, op, sqlStatement, pgx.NamedArgs{"client_id": clientId})
+}
+
+// Lock locks the cart till the end of the transaction and returns it with its
+// items. A non-zero version must match the cart version.
+func (r *CartRepo) Lock(ctx context.Context, id uuid.UUID, version int64) (*domain.Cart, error) {
+	op := "repository.postgres.cartRepository.Lock"
+	sqlStatement := `SELECT
+		` + cartColumns + `
+		FROM cart c
+		WHERE c.id = @id AND ` + cartAlive + `
+		FOR UPDATE`
+
+	cart, err := r.get(ctx, op, sqlStatement, pgx.NamedArgs{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && cart.Version != version {
+		r.logger.Debug("cart version mismatch", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+	}
+
+	return cart, nil
+}
+
+// SetItem sets the quantity of the product in the cart, the product is added
+// when the cart has no such item.
+func (r *CartRepo) SetItem(ctx context.Context, cartId, productId uuid.UUID, quantity int64) error {
+	op := "repository.postgres.cartRepository.SetItem"
+	sqlStatement := `INSERT INTO cart_item(cart_id, product_id, quantity)
+		VALUES (@cart_id, @product_id, @quantity)
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity`
+	args := pgx.NamedArgs{
+		"cart_id":    cartId,
+		"product_id": productId,
+		"quantity":   quantity,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlStatement, args); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			r.logger.Debug("product not found", "op", op)
+			return fmt.Errorf("%s: product: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to set cart item", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to set item: %v", op, err)
+	}
+
+	return nil
+}
+
+// RemoveItem removes the product from the cart, ErrNotFound is returned when
+// the cart has no such item.
+func (r *CartRepo) RemoveItem(ctx context.Context, cartId, productId uuid.UUID) error {
+	op := "repository.postgres.cartRepository.RemoveItem"
+	sqlStatement := `DELETE FROM cart_item WHERE cart_id = @cart_id AND product_id = @product_id`
+	args := pgx.NamedArgs{
+		"cart_id":    cartId,
+		"product_id": productId,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to remove cart item", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to remove item: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("cart item not found", "op", op)
+		return fmt.Errorf("%s: item: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Touch records a change of the cart: the version is incremented and the
+// cart expires after ttl from now. The new values are set back.
+func (r *CartRepo) Touch(ctx context.Context, cart *domain.Cart, ttl time.Duration) error {
+	op := "repository.postgres.cartRepository.Touch"
+	sqlStatement := `UPDATE cart SET
+		version = version + 1,
+		updated_at = NOW(),
+		expires_at = NOW() + make_interval(secs => @ttl)
+		WHERE id = @id
+		RETURNING version, updated_at, expires_at`
+	args := pgx.NamedArgs{
+		"id":  cart.Id,
+		"ttl": ttl.Seconds(),
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&cart.Version, &cart.UpdatedAt, &cart.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("cart not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("failed to touch cart", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to update cart: %v", op, err)
+	}
+
+	return nil
+}
+
+// Assign gives the anonymous cart to the client. An expired cart of the client
+// is deleted first, ErrDuplicateKeyValue is returned when the client has a
+// cart and ErrNotFound when the client does not exist.
+func (r *CartRepo) Assign(ctx context.Context, cartId, clientId uuid.UUID) error {
+	op := "repository.postgres.cartRepository.Assign"
+
+	if err := r.deleteExpiredOf(ctx, op, &clientId); err != nil {
+		return err
+	}
+
+	sqlStatement := `UPDATE cart SET client_id = @client_id WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":        cartId,
+		"client_id": clientId,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		return r.clientError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("cart not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete deletes the cart with its items. A missing cart is not an error
+// unless a non-zero version is expected.
+func (r *CartRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "repository.postgres.cartRepository.Delete"
+	sqlStatement := `DELETE FROM cart WHERE id = @id AND (@version = 0 OR version = @version)`
+	args := pgx.NamedArgs{
+		"id":      id,
+		"version": version,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to delete cart", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to delete cart: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "cart", id, version)
+		if err != nil {
+			r.logger.Error("failed to check cart version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("cart version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes expired carts and returns their number.
+func (r *CartRepo) DeleteExpired(ctx context.Context) (int, error) {
+	op := "repository.postgres.cartRepository.DeleteExpired"
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM cart WHERE expires_at <= NOW()`)
+	if err != nil {
+		r.logger.Error("failed to delete expired carts", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// get scans the cart selected by the statement and reads its items.
+func (r *CartRepo) get(ctx context.Context, op, sqlStatement string, args pgx.NamedArgs) (*domain.Cart, error) {
+	var cart domain.Cart
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(cartTargets(&cart)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("cart not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	sqlItems := `SELECT ci.product_id, p.name, COALESCE(p.sku, ''), p.price, ci.quantity, p.available_stock, ci.added_at
+		FROM cart_item ci
+		JOIN product p ON p.id = ci.product_id
+		WHERE ci.cart_id = @id
+		ORDER BY ci.added_at, p.name`
+
+	rows, err := r.db.Query(ctx, sqlItems, pgx.NamedArgs{"id": cart.Id})
+	if err != nil {
+		r.logger.Error("failed to get cart items", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.CartItem
+
+		if err := rows.Scan(&item.ProductId, &item.Name, &item.Sku, &item.Price, &item.Quantity, &item.AvailableStock, &item.AddedAt); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		cart.Items = append(cart.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get cart items", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return &cart, nil
+}
+
+// deleteExpiredOf deletes the expired cart of the client, it frees the client
+// for a new cart before the cleaner runs.
+func (r *CartRepo) deleteExpiredOf(ctx context.Context, op string, clientId *uuid.UUID) error {
+	if clientId == nil {
+		return nil
+	}
+
+	_, err := r.db.Exec(ctx, `DELETE FROM cart WHERE client_id = @client_id AND expires_at <= NOW()`, pgx.NamedArgs{"client_id": clientId})
+	if err != nil {
+		r.logger.Error("failed to delete expired cart of client", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to delete expired cart: %v", op, err)
+	}
+
+	return nil
+}
+
+// clientError maps errors of writing the cart client.
+func (r *CartRepo) clientError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			r.logger.Debug("client has a cart already", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		case "23503":
+			r.logger.Debug("client not found", "op", op)
+			return fmt.Errorf("%s: client: %w", op, crud_errors.ErrNotFound)
+		}
+	}
+
+	r.logger.Error("failed to write cart", logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unable to write cart: %v", op, err)
+}
@@ -0,0 +1,432 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// categorySubtree selects ids of the categories matched by the condition and
+// of all their descendants.
+func categorySubtree(condition string) string {
+	return `WITH RECURSIVE subtree AS (
+			SELECT id FROM category WHERE ` + condition + `
+			UNION
+			SELECT c.id FROM category c JOIN subtree ON c.parent_id = subtree.id
+		)
+		SELECT id FROM subtree`
+}
+
+// categoryTree walks categories from the top level down, siblings are ordered
+// by position and name. The sort key orders the tree depth-first.
+const categoryTree = `WITH RECURSIVE ranked AS (
+		SELECT id, name, slug, parent_id, position,
+			row_number() OVER (PARTITION BY parent_id ORDER BY position, name, id) AS rank
+		FROM category
+	), tree AS (
+		SELECT id, name, slug, parent_id, position, 0 AS depth, slug AS path, ARRAY[rank] AS sort_key
+		FROM ranked
+		WHERE parent_id IS NULL
+		UNION ALL
+		SELECT r.id, r.name, r.slug, r.parent_id, r.position, t.depth + 1, t.path || '/' || r.slug, t.sort_key || r.rank
+		FROM ranked r
+		JOIN tree t ON r.parent_id = t.id
+	)`
+
+type CategoryRepo struct {
+	*basePostgresRepository
+}
+
+func NewCategoryRepository(db DB, logger *logger.Logger) *CategoryRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("Postgres Category repository is created")
+	return &CategoryRepo{
+		repo,
+	}
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, category *domain.Category) error {
+	op := "repositories.postgres.categoryRepository.Create"
+	sqlStatement := `INSERT INTO category(name, slug, parent_id, position)
+		VALUES (@name, @slug, @parent_id, @position)
+		RETURNING id;`
+	args := pgx.NamedArgs{
+		"name":      category.Name,
+		"slug":      category.Slug,
+		"parent_id": category.ParentId,
+		"position":  category.Position,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&category.Id)
+	if err != nil {
+		return r.writeError(op, "create", err)
+	}
+
+	return nil
+}
+
+// GetAll returns the whole taxonomy flattened depth-first, every category is
+// followed by its subcategories.
+func (r *CategoryRepo) GetAll(ctx context.Context) ([]domain.Category, error) {
+	op := "repositories.postgres.categoryRepository.GetAll"
+	sqlStatement := categoryTree + `
+		SELECT id, name, slug, parent_id, position, depth, path
+		FROM tree
+		ORDER BY sort_key`
+
+	rows, err := r.db.Query(ctx, sqlStatement)
+	if err != nil {
+		r.logger.Error("query unvalable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var (
+		categories []domain.Category
+		category   domain.Category
+	)
+
+	targets := []any{
+		&category.Id,
+		&category.Name,
+		&category.Slug,
+		&category.ParentId,
+		&category.Position,
+		&category.Depth,
+		&category.Path,
+	}
+
+	_, err = pgx.ForEachRow(rows, targets, func() error {
+		categories = append(categories, category)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	if len(categories) == 0 {
+		r.logger.Debug("categories not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return categories, nil
+}
+
+func (r *CategoryRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	op := "repositories.postgres.categoryRepository.GetById"
+	sqlStatement := categoryTree + `
+		SELECT id, name, slug, parent_id, position, depth, path
+		FROM tree
+		WHERE id = @id`
+
+	var category domain.Category
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(
+		&category.Id,
+		&category.Name,
+		&category.Slug,
+		&category.ParentId,
+		&category.Position,
+		&category.Depth,
+		&category.Path,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("category not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return &category, nil
+}
+
+// IsDescendant reports whether the category is the ancestor itself or lies
+// in its subtree.
+func (r *CategoryRepo) IsDescendant(ctx context.Context, ancestorId, id uuid.UUID) (bool, error) {
+	op := "repositories.postgres.categoryRepository.IsDescendant"
+	sqlStatement := `SELECT @id IN (` + categorySubtree("id = @ancestor_id") + `)`
+	args := pgx.NamedArgs{
+		"ancestor_id": ancestorId,
+		"id":          id,
+	}
+
+	var descendant bool
+	if err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&descendant); err != nil {
+		r.logger.Error("failed to check category subtree", logger.Err(err), "op", op)
+		return false, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	return descendant, nil
+}
+
+// GetIdsBySlugs returns ids of the categories with given slugs by slug.
+func (r *CategoryRepo) GetIdsBySlugs(ctx context.Context, slugs []string) (map[string]uuid.UUID, error) {
+	op := "repositories.postgres.categoryRepository.GetIdsBySlugs"
+	rows, err := r.db.Query(ctx, `SELECT id, slug FROM category WHERE slug = ANY(@slugs)`, pgx.NamedArgs{"slugs": slugs})
+	if err != nil {
+		r.logger.Error("unable to query category", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	ids := make(map[string]uuid.UUID)
+
+	var (
+		id   uuid.UUID
+		slug string
+	)
+
+	_, err = pgx.ForEachRow(rows, []any{&id, &slug}, func() error {
+		ids[slug] = id
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return ids, nil
+}
+
+// Update rewrites the category fields.
+func (r *CategoryRepo) Update(ctx context.Context, category *domain.Category) error {
+	op := "repositories.postgres.categoryRepository.Update"
+	sqlStatement := `UPDATE category SET
+		name = @name,
+		slug = @slug,
+		parent_id = @parent_id,
+		position = @position
+		WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":        category.Id,
+		"name":      category.Name,
+		"slug":      category.Slug,
+		"parent_id": category.ParentId,
+		"position":  category.Position,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		return r.writeError(op, "update", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("category not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes the category, a category with subcategories or products
+// cannot be removed.
+func (r *CategoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	op := "repositories.postgres.categoryRepository.Delete"
+
+	_, err := r.db.Exec(ctx, `DELETE FROM category WHERE id = @id`, pgx.NamedArgs{"id": id})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			r.logger.Debug("category is in use", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrForeignKeyViolation)
+		}
+
+		r.logger.Error("execute sql statement for delete category is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	return nil
+}
+
+// writeError maps constraint violations of a written category: a taken slug
+// and a missing parent.
+func (r *CategoryRepo) writeError(op, action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			r.logger.Debug("category slug is taken", "op", op)
+			return fmt.Errorf("%s: slug: %w", op, crud_errors.ErrDuplicateKeyValue)
+		case "23503":
+			r.logger.Debug("parent category not found", "op", op)
+			return fmt.Errorf("%s: parent: %w", op, crud_errors.ErrNotFound)
+		case "23514":
+			r.logger.Debug("category is its own parent", "op", op)
+			return fmt.Errorf("%s: parent: %w", op, crud_errors.ErrInvalidParam)
+		}
+	}
+
+	r.logger.Error("failed to "+action+" category", logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unable to %s category: %v", op, action, err)
+}
+
+// GetAttributes returns the attribute definitions of the category and of its
+// ancestors, definitions of upper categories go first. A category without
+// definitions gives an empty list.
+func (r *CategoryRepo) GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error) {
+	op := "repositories.postgres.categoryRepository.GetAttributes"
+	sqlStatement := `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS distance FROM category WHERE id = @id
+			UNION ALL
+			SELECT c.id, c.parent_id, a.distance + 1 FROM category c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT ca.id, ca.category_id, ca.key, ca.name, ca.type, ca.unit, ca.options,
+			ca.min_value, ca.max_value, ca.required, ca.position
+		FROM category_attribute ca
+		JOIN ancestors a ON ca.category_id = a.id
+		ORDER BY a.distance DESC, ca.position, ca.key`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"id": categoryId})
+	if err != nil {
+		r.logger.Error("query unvalable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var (
+		definitions []domain.AttributeDefinition
+		definition  domain.AttributeDefinition
+	)
+
+	targets := []any{
+		&definition.Id,
+		&definition.CategoryId,
+		&definition.Key,
+		&definition.Name,
+		&definition.Type,
+		&definition.Unit,
+		&definition.Options,
+		&definition.Min,
+		&definition.Max,
+		&definition.Required,
+		&definition.Position,
+	}
+
+	_, err = pgx.ForEachRow(rows, targets, func() error {
+		definitions = append(definitions, definition)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return definitions, nil
+}
+
+// IsAttributeDefined reports whether the key is defined by the category, by its
+// ancestors or by its subcategories.
+func (r *CategoryRepo) IsAttributeDefined(ctx context.Context, categoryId uuid.UUID, key string) (bool, error) {
+	op := "repositories.postgres.categoryRepository.IsAttributeDefined"
+	sqlStatement := `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM category WHERE id = @id
+			UNION
+			SELECT c.id, c.parent_id FROM category c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT EXISTS (
+			SELECT 1 FROM category_attribute
+			WHERE key = @key AND (
+				category_id IN (SELECT id FROM ancestors)
+				OR category_id IN (` + categorySubtree("id = @id") + `)
+			)
+		)`
+	args := pgx.NamedArgs{
+		"id":  categoryId,
+		"key": key,
+	}
+
+	var defined bool
+	if err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&defined); err != nil {
+		r.logger.Error("failed to check attribute key", logger.Err(err), "op", op)
+		return false, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	return defined, nil
+}
+
+func (r *CategoryRepo) CreateAttribute(ctx context.Context, definition *domain.AttributeDefinition) error {
+	op := "repositories.postgres.categoryRepository.CreateAttribute"
+	sqlStatement := `INSERT INTO category_attribute(category_id, key, name, type, unit, options, min_value, max_value, required, position)
+		VALUES (@category_id, @key, @name, @type, @unit, @options, @min, @max, @required, @position)
+		RETURNING id`
+	options := definition.Options
+	if options == nil {
+		options = []string{}
+	}
+
+	args := pgx.NamedArgs{
+		"category_id": definition.CategoryId,
+		"key":         definition.Key,
+		"name":        definition.Name,
+		"type":        string(definition.Type),
+		"unit":        definition.Unit,
+		"options":     options,
+		"min":         definition.Min,
+		"max":         definition.Max,
+		"required":    definition.Required,
+		"position":    definition.Position,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&definition.Id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				r.logger.Debug("attribute key is taken", "op", op)
+				return fmt.Errorf("%s: key: %w", op, crud_errors.ErrDuplicateKeyValue)
+			case "23503":
+				r.logger.Debug("category not found", "op", op)
+				return fmt.Errorf("%s: category: %w", op, crud_errors.ErrNotFound)
+			}
+		}
+
+		r.logger.Error("failed to create attribute", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAttribute removes the definition of the category and the values of the
+// attribute from products of the category and its subcategories, the changed
+// products get a new version.
+func (r *CategoryRepo) DeleteAttribute(ctx context.Context, categoryId uuid.UUID, key string) error {
+	op := "repositories.postgres.categoryRepository.DeleteAttribute"
+	args := pgx.NamedArgs{
+		"category_id": categoryId,
+		"key":         key,
+	}
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM category_attribute WHERE category_id = @category_id AND key = @key`, args)
+	if err != nil {
+		r.logger.Error("execute sql statement for delete attribute is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("attribute not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	sqlStatement := `UPDATE product SET
+		attributes = attributes - @key::TEXT,
+		last_update_date = NOW(),
+		version = version + 1
+		WHERE attributes ? @key::TEXT AND category_id IN (` + categorySubtree("id = @category_id") + `)`
+
+	if _, err := r.db.Exec(ctx, sqlStatement, args); err != nil {
+		r.logger.Error("failed to remove attribute values", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed to remove values: %v", op, err)
+	}
+
+	return nil
+}
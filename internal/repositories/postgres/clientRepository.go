@@ -7,9 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type ClientRepo struct {
@@ -25,26 +27,27 @@ func NewClientRepository(db DB, log *logger.Logger) *ClientRepo {
 func (r *ClientRepo) Create(ctx context.Context, client *domain.Client) error {
 	op := "repositories.postgres.clientRepository.Create"
 	sqlStatement := `
-	INSERT INTO client(name, surname, birthday, gender, address_id)
-	VALUES (@clientName, @clientSurname, @clientBirthday, @clientGender, @clientAddressId) 
+	INSERT INTO client(name, surname, birthday, gender, email, phone)
+	VALUES (@clientName, @clientSurname, @clientBirthday, @clientGender, NULLIF(@clientEmail, ''), NULLIF(@clientPhone, '')) 
 	RETURNING id;
 	`
-	var addressId any = nil
-
-	if client.Address != nil {
-		addressId = client.Address.Id
-	}
-
 	args := pgx.NamedArgs{
-		"clientName":      client.Name,
-		"clientSurname":   client.Surname,
-		"clientBirthday":  client.Birthday,
-		"clientGender":    client.Gender,
-		"clientAddressId": addressId,
+		"clientName":     client.Name,
+		"clientSurname":  client.Surname,
+		"clientBirthday": client.Birthday,
+		"clientGender":   client.Gender,
+		"clientEmail":    client.Email,
+		"clientPhone":    client.Phone,
 	}
 
 	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&client.Id)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate client contacts", "op", op)
+			return fmt.Errorf("%s: unable to insert row: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
 		r.logger.Error("failed to create Client", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: unable to insert row: %v", op, err)
 	}
@@ -52,6 +55,123 @@ func (r *ClientRepo) Create(ctx context.Context, client *domain.Client) error {
 	return nil
 }
 
+// CopyFrom bulk inserts clients with assigned ids.
+func (r *ClientRepo) CopyFrom(ctx context.Context, clients []domain.Client) (int64, error) {
+	op := "repositories.postgres.clientRepository.CopyFrom"
+	columns := []string{"id", "name", "surname", "birthday", "gender", "email", "phone"}
+
+	nullable := func(value string) any {
+		if value == "" {
+			return nil
+		}
+
+		return value
+	}
+
+	count, err := r.db.CopyFrom(ctx, pgx.Identifier{"client"}, columns, pgx.CopyFromSlice(len(clients), func(i int) ([]any, error) {
+		c := clients[i]
+		return []any{c.Id, c.Name, c.Surname, c.Birthday, c.Gender, nullable(c.Email), nullable(c.Phone)}, nil
+	}))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate client contacts", "op", op)
+			return 0, fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		r.logger.Error("failed to copy clients", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: copy failed: %v", op, err)
+	}
+
+	return count, nil
+}
+
+// GetUsedContacts returns which of the emails and phones are already used by
+// clients.
+func (r *ClientRepo) GetUsedContacts(ctx context.Context, emails, phones []string) (map[string]bool, error) {
+	op := "repositories.postgres.clientRepository.GetUsedContacts"
+	sqlStatement := `SELECT email FROM client WHERE email = ANY(@emails)
+		UNION ALL
+		SELECT phone FROM client WHERE phone = ANY(@phones)`
+	args := pgx.NamedArgs{
+		"emails": emails,
+		"phones": phones,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("unable to query client", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	used := make(map[string]bool)
+
+	var contact string
+
+	_, err = pgx.ForEachRow(rows, []any{&contact}, func() error {
+		used[contact] = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return used, nil
+}
+
+// ForEach streams all clients with their default address ordered by
+// registration.
+func (r *ClientRepo) ForEach(ctx context.Context, fn func(client domain.Client) error) error {
+	op := "repositories.postgres.clientRepository.ForEach"
+	sqlStatement := `SELECT
+		c.id,
+		c.name,
+		c.surname,
+		c.birthday,
+		c.gender,
+		COALESCE(c.email, ''),
+		COALESCE(c.phone, ''),
+		c.registration_date,
+		` + addressColumns + `
+		FROM client c
+		` + clientAddressBook.defaultAddressJoin("c.id") + `
+		ORDER BY c.registration_date, c.id;`
+
+	rows, err := r.db.Query(ctx, sqlStatement)
+	if err != nil {
+		r.logger.Error("unable to query client", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var (
+		client  domain.Client
+		address nullableAddress
+	)
+
+	targets := append([]any{
+		&client.Id,
+		&client.Name,
+		&client.Surname,
+		&client.Birthday,
+		&client.Gender,
+		&client.Email,
+		&client.Phone,
+		&client.RegistrationDate,
+	}, address.targets()...)
+
+	_, err = pgx.ForEachRow(rows, targets, func() error {
+		client.Address = address.toDomain()
+		return fn(client)
+	})
+	if err != nil {
+		r.logger.Error("failed to stream clients", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (r *ClientRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Client, error) {
 	op := "repositories.postgres.clientRepository.GetAll"
 	sqlStatement := `SELECT 
@@ -60,13 +180,12 @@ func (r *ClientRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Cl
 		c.surname,
 		c.birthday,
 		c.gender,
+		COALESCE(c.email, ''),
+		COALESCE(c.phone, ''),
 		c.registration_date,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		` + addressColumns + `
 		FROM client c
-		LEFT JOIN address a ON c.address_id = a.id
+		` + clientAddressBook.defaultAddressJoin("c.id") + `
 		LIMIT @limit OFFSET @offset;`
 	args := pgx.NamedArgs{
 		"limit":  limit,
@@ -84,39 +203,28 @@ func (r *ClientRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Cl
 
 	for rows.Next() {
 		var (
-			client                                     domain.Client
-			addressId                                  *uuid.UUID
-			addressCountry, addressCity, addressStreet *string
+			client  domain.Client
+			address nullableAddress
 		)
 
-		err := rows.Scan(
+		targets := append([]any{
 			&client.Id,
 			&client.Name,
 			&client.Surname,
 			&client.Birthday,
 			&client.Gender,
+			&client.Email,
+			&client.Phone,
 			&client.RegistrationDate,
-			&addressId,
-			&addressCountry,
-			&addressCity,
-			&addressStreet,
-		)
+		}, address.targets()...)
+
+		err := rows.Scan(targets...)
 		if err != nil {
 			r.logger.Warn("failed binding data", logger.Err(err), "op", op)
 			continue
 		}
 
-		if addressId != nil {
-			client.Address = &domain.Address{
-				Id:      *addressId,
-				Country: *addressCountry,
-				City:    *addressCity,
-				Street:  *addressStreet,
-			}
-
-		} else {
-			client.Address = nil
-		}
+		client.Address = address.toDomain()
 
 		clients = append(clients, client)
 	}
@@ -137,13 +245,12 @@ func (r *ClientRepo) GetByNameAndSurname(ctx context.Context, name, surname stri
 		c.surname,
 		c.birthday,
 		c.gender,
+		COALESCE(c.email, ''),
+		COALESCE(c.phone, ''),
 		c.registration_date,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		` + addressColumns + `
 		FROM client c
-		LEFT JOIN address a ON c.address_id = a.id
+		` + clientAddressBook.defaultAddressJoin("c.id") + `
 		WHERE c.name = @clientName AND c.surname = @clientSurname;`
 	args := pgx.NamedArgs{
 		"clientName":    name,
@@ -161,39 +268,28 @@ func (r *ClientRepo) GetByNameAndSurname(ctx context.Context, name, surname stri
 
 	for rows.Next() {
 		var (
-			client                                     domain.Client
-			addressId                                  *uuid.UUID
-			addressCountry, addressCity, addressStreet *string
+			client  domain.Client
+			address nullableAddress
 		)
 
-		err := rows.Scan(
+		targets := append([]any{
 			&client.Id,
 			&client.Name,
 			&client.Surname,
 			&client.Birthday,
 			&client.Gender,
+			&client.Email,
+			&client.Phone,
 			&client.RegistrationDate,
-			&addressId,
-			&addressCountry,
-			&addressCity,
-			&addressStreet,
-		)
+		}, address.targets()...)
+
+		err := rows.Scan(targets...)
 		if err != nil {
 			r.logger.Warn("failed binding data", logger.Err(err), "op", op)
 			continue
 		}
 
-		if addressId != nil {
-			client.Address = &domain.Address{
-				Id:      *addressId,
-				Country: *addressCountry,
-				City:    *addressCity,
-				Street:  *addressStreet,
-			}
-
-		} else {
-			client.Address = nil
-		}
+		client.Address = address.toDomain()
 
 		clients = append(clients, client)
 	}
@@ -214,35 +310,35 @@ func (r *ClientRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Client,
 		c.surname,
 		c.birthday,
 		c.gender,
+		COALESCE(c.email, ''),
+		COALESCE(c.phone, ''),
 		c.registration_date,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		c.version,
+		` + addressColumns + `
 		FROM client c
-		LEFT JOIN address a ON c.address_id = a.id
+		` + clientAddressBook.defaultAddressJoin("c.id") + `
 		WHERE c.id = @id;`
 	arg := pgx.NamedArgs{"id": id}
 	row := r.db.QueryRow(ctx, sqlStatement, arg)
 
 	var (
-		client                                     domain.Client
-		addressId                                  *uuid.UUID
-		addressCountry, addressCity, addressStreet *string
+		client  domain.Client
+		address nullableAddress
 	)
 
-	err := row.Scan(
+	targets := append([]any{
 		&client.Id,
 		&client.Name,
 		&client.Surname,
 		&client.Birthday,
 		&client.Gender,
+		&client.Email,
+		&client.Phone,
 		&client.RegistrationDate,
-		&addressId,
-		&addressCountry,
-		&addressCity,
-		&addressStreet,
-	)
+		&client.Version,
+	}, address.targets()...)
+
+	err := row.Scan(targets...)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		r.logger.Debug("client not found", "op", op)
@@ -254,36 +350,68 @@ func (r *ClientRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Client,
 		return nil, fmt.Errorf("%s: %v", op, err)
 	}
 
-	if addressId != nil {
-		client.Address = &domain.Address{
-			Id:      *addressId,
-			Country: *addressCountry,
-			City:    *addressCity,
-			Street:  *addressStreet,
-		}
+	client.Address = address.toDomain()
 
-	} else {
-		client.Address = nil
+	book, err := selectAddressBook(ctx, r.db, clientAddressBook, []uuid.UUID{client.Id})
+	if err != nil {
+		r.logger.Error("failed to get address book", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
 	}
 
+	client.Addresses = book[client.Id]
+
 	return &client, nil
 }
 
-func (r *ClientRepo) UpdateAddress(ctx context.Context, id, address uuid.UUID) error {
+// Update rewrites the client profile and increments its version. A non-zero
+// client.Version is the expected version, the new one is set back.
+func (r *ClientRepo) Update(ctx context.Context, client *domain.Client) error {
 	op := "repositories.postgres.clientRepository.Update"
-	sqlStatement := "UPDATE client SET address_id=@addressId WHERE id=@id"
-	arg := pgx.NamedArgs{
-		"id":        id,
-		"addressId": address,
+	sqlStatement := `UPDATE client SET
+		name = @clientName,
+		surname = @clientSurname,
+		birthday = @clientBirthday,
+		gender = @clientGender,
+		email = NULLIF(@clientEmail, ''),
+		phone = NULLIF(@clientPhone, ''),
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
+	args := pgx.NamedArgs{
+		"id":             client.Id,
+		"clientName":     client.Name,
+		"clientSurname":  client.Surname,
+		"clientBirthday": client.Birthday,
+		"clientGender":   client.Gender,
+		"clientEmail":    client.Email,
+		"clientPhone":    client.Phone,
+		"version":        client.Version,
 	}
 
-	tag, err := r.db.Exec(ctx, sqlStatement, arg)
-	if tag.RowsAffected() == 0 {
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&client.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "client", client.Id, client.Version)
+		if err != nil {
+			r.logger.Error("failed to check client version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("client version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+
 		r.logger.Debug("client not found", "op", op)
 		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate client contacts", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
 		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: failed exec query: %v", op, err)
 	}
@@ -291,18 +419,189 @@ func (r *ClientRepo) UpdateAddress(ctx context.Context, id, address uuid.UUID) e
 	return nil
 }
 
-func (r *ClientRepo) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete removes the client, a non-zero version is the expected version.
+func (r *ClientRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "repositories.postgres.clientRepository.Delete"
-	sqlStatement := "DELETE FROM client WHERE id=@id"
+	sqlStatement := "DELETE FROM client WHERE id=@id AND (@version = 0 OR version = @version)"
 	arg := pgx.NamedArgs{
-		"id": id,
+		"id":      id,
+		"version": version,
 	}
 
-	_, err := r.db.Exec(ctx, sqlStatement, arg)
+	tag, err := r.db.Exec(ctx, sqlStatement, arg)
 	if err != nil {
 		r.logger.Error("error in exec delete request to data base", "op", op)
 		return fmt.Errorf("%s: %v", op, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "client", id, version)
+		if err != nil {
+			r.logger.Error("failed to check client version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("client version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
 	return nil
 }
+
+// clientSortColumns maps sort keys to expressions, the key is never put into
+// the query as is.
+var clientSortColumns = map[string]string{
+	domain.ClientSortName:             "c.name",
+	domain.ClientSortSurname:          "c.surname",
+	domain.ClientSortBirthday:         "c.birthday",
+	domain.ClientSortRegistrationDate: "c.registration_date",
+}
+
+// likeEscaper escapes the LIKE wildcards, the escaped query matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// clientSearchSimilarity is the pg_trgm similarity a fuzzy match must exceed.
+const clientSearchSimilarity = 0.3
+
+// Search returns a page of clients matched by the filter and the total number
+// of matched clients. The total is returned with ErrNotFound for an empty page.
+func (r *ClientRepo) Search(ctx context.Context, filter domain.ClientFilter) ([]domain.Client, int, error) {
+	op := "repositories.postgres.clientRepository.Search"
+
+	conditions := []string{"TRUE"}
+	args := pgx.NamedArgs{
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+		"similarity": clientSearchSimilarity,
+	}
+
+	relevance := "0"
+	if filter.Query != "" {
+		conditions = append(conditions, `((c.name || ' ' || c.surname) ILIKE '%' || @pattern || '%' ESCAPE '\'
+			OR similarity(c.name || ' ' || c.surname, @query) > @similarity)`)
+		relevance = "similarity(c.name || ' ' || c.surname, @query)"
+		args["query"] = filter.Query
+		args["pattern"] = likeEscaper.Replace(filter.Query)
+	}
+
+	optional := []struct {
+		set       bool
+		condition string
+		name      string
+		value     any
+	}{
+		{filter.Name != "", "lower(c.name) = lower(@name)", "name", filter.Name},
+		{filter.Surname != "", "lower(c.surname) = lower(@surname)", "surname", filter.Surname},
+		{filter.Gender != "", "c.gender = @gender", "gender", filter.Gender},
+		{filter.BirthdayFrom != nil, "c.birthday >= @birthdayFrom", "birthdayFrom", filter.BirthdayFrom},
+		{filter.BirthdayTo != nil, "c.birthday <= @birthdayTo", "birthdayTo", filter.BirthdayTo},
+		{filter.RegisteredFrom != nil, "c.registration_date >= @registeredFrom", "registeredFrom", filter.RegisteredFrom},
+		{filter.RegisteredTo != nil, "c.registration_date < @registeredTo::TIMESTAMP + INTERVAL '1 day'", "registeredTo", filter.RegisteredTo},
+		{filter.Country != "", "lower(a.country) = lower(@country)", "country", filter.Country},
+		{filter.City != "", "lower(a.city) = lower(@city)", "city", filter.City},
+	}
+
+	for _, item := range optional {
+		if item.set {
+			conditions = append(conditions, item.condition)
+			args[item.name] = item.value
+		}
+	}
+
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	orderBy := fmt.Sprintf("c.surname %s, c.name %s", direction, direction)
+	if column, ok := clientSortColumns[filter.SortBy]; ok {
+		orderBy = fmt.Sprintf("%s %s", column, direction)
+	} else if filter.Query != "" {
+		// relevance is the default sort of fuzzy search
+		orderBy = fmt.Sprintf("relevance %s", direction)
+	}
+
+	from := fmt.Sprintf(`FROM client c
+		%s
+		WHERE %s`, clientAddressBook.defaultAddressJoin("c.id"), strings.Join(conditions, " AND "))
+
+	sqlStatement := fmt.Sprintf(`SELECT
+		c.id,
+		c.name,
+		c.surname,
+		c.birthday,
+		c.gender,
+		COALESCE(c.email, ''),
+		COALESCE(c.phone, ''),
+		c.registration_date,
+		%s,
+		%s AS relevance,
+		COUNT(*) OVER() AS total
+		%s
+		ORDER BY %s, c.id
+		LIMIT @limit OFFSET @offset;`, addressColumns, relevance, from, orderBy)
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed search clients", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		clients []domain.Client
+		total   int
+	)
+
+	for rows.Next() {
+		var (
+			client    domain.Client
+			address   nullableAddress
+			relevance float64
+		)
+
+		targets := append([]any{
+			&client.Id,
+			&client.Name,
+			&client.Surname,
+			&client.Birthday,
+			&client.Gender,
+			&client.Email,
+			&client.Phone,
+			&client.RegistrationDate,
+		}, address.targets()...)
+		targets = append(targets, &relevance, &total)
+
+		err := rows.Scan(targets...)
+		if err != nil {
+			r.logger.Error("failed binding data", logger.Err(err), "op", op)
+			return nil, 0, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		client.Address = address.toDomain()
+
+		clients = append(clients, client)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(clients) == 0 {
+		// the window count is lost with the rows of a page past the end
+		if filter.Offset > 0 {
+			if err := r.db.QueryRow(ctx, "SELECT COUNT(*) "+from, args).Scan(&total); err != nil {
+				r.logger.Error("failed count clients", logger.Err(err), "op", op)
+				return nil, 0, fmt.Errorf("%s: count error: %v", op, err)
+			}
+		}
+
+		r.logger.Debug("clients not found", "total", total, "op", op)
+		return nil, total, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return clients, total, nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyRepo struct {
+	*basePostgresRepository
+}
+
+func NewIdempotencyRepository(db DB, log *logger.Logger) *IdempotencyRepo {
+	baseRepo := newBasePostgresRepository(db, log)
+	log.Debug("idempotency repo is created")
+	return &IdempotencyRepo{baseRepo}
+}
+
+// Acquire stores the key of a started request for the lease. An expired key is
+// taken over, false is returned when the key is alive.
+func (r *IdempotencyRepo) Acquire(ctx context.Context, key, fingerprint string, lease time.Duration) (bool, error) {
+	op := "repository.postgres.idempotencyRepository.Acquire"
+	sqlInsert := `INSERT INTO idempotency_key AS k (key, fingerprint, expires_at)
+		VALUES (@key, @fingerprint, NOW() + make_interval(secs => @lease))
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			completed = FALSE,
+			response_status = NULL,
+			response_type = '',
+			response_body = NULL,
+			response_etag = '',
+			response_location = '',
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE k.expires_at < NOW()
+		RETURNING key`
+
+	args := pgx.NamedArgs{
+		"key":         key,
+		"fingerprint": fingerprint,
+		"lease":       lease.Seconds(),
+	}
+
+	var stored string
+
+	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&stored)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		r.logger.Error("failed to acquire idempotency key", logger.Err(err), "op", op)
+		return false, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return true, nil
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	op := "repository.postgres.idempotencyRepository.Get"
+	sqlSelect := `SELECT key, fingerprint, completed, COALESCE(response_status, 0), response_type,
+		response_etag, response_location, response_body
+		FROM idempotency_key
+		WHERE key = @key AND expires_at >= NOW()`
+
+	var record domain.IdempotencyRecord
+
+	err := r.db.QueryRow(ctx, sqlSelect, pgx.NamedArgs{"key": key}).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&record.Completed,
+		&record.Response.Status,
+		&record.Response.ContentType,
+		&record.Response.ETag,
+		&record.Response.Location,
+		&record.Response.Body,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to get idempotency key", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return &record, nil
+}
+
+// Complete stores the response of the request, it is kept for the ttl.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key string, response domain.IdempotentResponse, ttl time.Duration) error {
+	op := "repository.postgres.idempotencyRepository.Complete"
+	sqlUpdate := `UPDATE idempotency_key SET
+			completed = TRUE,
+			response_status = @status,
+			response_type = @content_type,
+			response_body = @body,
+			response_etag = @etag,
+			response_location = @location,
+			expires_at = NOW() + make_interval(secs => @ttl)
+		WHERE key = @key AND NOT completed`
+
+	args := pgx.NamedArgs{
+		"key":          key,
+		"ttl":          ttl.Seconds(),
+		"status":       response.Status,
+		"content_type": response.ContentType,
+		"body":         response.Body,
+		"etag":         response.ETag,
+		"location":     response.Location,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlUpdate, args); err != nil {
+		r.logger.Error("failed to complete idempotency key", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) Delete(ctx context.Context, key string) error {
+	op := "repository.postgres.idempotencyRepository.Delete"
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM idempotency_key WHERE key = @key`, pgx.NamedArgs{"key": key}); err != nil {
+		r.logger.Error("failed to delete idempotency key", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int, error) {
+	op := "repository.postgres.idempotencyRepository.DeleteExpired"
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_key WHERE expires_at < NOW()`)
+	if err != nil {
+		r.logger.Error("failed to delete expired idempotency keys", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -26,12 +26,17 @@ func NewImageRepository(db DB, logger *logger.Logger) *ImageRepo {
 func (r *ImageRepo) Create(ctx context.Context, image *domain.Image) error {
 	op := "repository.postgres.imageRepository.Create"
 	sqlInsert := `INSERT
-		INTO image(title, data)
-		VALUES (@title, @image)
+		INTO image(title, data, width, height, format, size, dominant_color)
+		VALUES (@title, @image, @width, @height, @format, @size, @dominant_color)
 		RETURNING id;`
 	args := pgx.NamedArgs{
-		"title": image.Title,
-		"image": image.Data,
+		"title":          image.Title,
+		"image":          image.Data,
+		"width":          image.Metadata.Width,
+		"height":         image.Metadata.Height,
+		"format":         image.Metadata.Format,
+		"size":           image.Metadata.Size,
+		"dominant_color": image.Metadata.DominantColor,
 	}
 
 	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&image.Id)
@@ -45,7 +50,16 @@ func (r *ImageRepo) Create(ctx context.Context, image *domain.Image) error {
 
 func (r *ImageRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Image, error) {
 	op := "repostiory.postgres.imageRepository.GetAll"
-	sqlStatement := `SELECT * FROM image LIMIT @limit OFFSET @offset;`
+	sqlStatement := `SELECT
+		id,
+		title,
+		data,
+		width,
+		height,
+		format,
+		size,
+		dominant_color
+		FROM image LIMIT @limit OFFSET @offset;`
 	r.logger.Debug("check limit and offset", "limit", limit, "offset", offset)
 	args := pgx.NamedArgs{
 		"limit":  limit,
@@ -64,7 +78,17 @@ func (r *ImageRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Ima
 	for rows.Next() {
 		var image domain.Image
 
-		if err := rows.Scan(&image.Id, &image.Title, &image.Data); err != nil {
+		err := rows.Scan(
+			&image.Id,
+			&image.Title,
+			&image.Data,
+			&image.Metadata.Width,
+			&image.Metadata.Height,
+			&image.Metadata.Format,
+			&image.Metadata.Size,
+			&image.Metadata.DominantColor,
+		)
+		if err != nil {
 			r.logger.Warn("failed to bind data", logger.Err(err), "op", op)
 			continue
 		}
@@ -82,16 +106,97 @@ func (r *ImageRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Ima
 	return images, nil
 }
 
+// GetAfter returns up to limit images with ids greater than the given one in
+// the id order, uuid.Nil starts from the first image.
+func (r *ImageRepo) GetAfter(ctx context.Context, id uuid.UUID, limit int) ([]domain.Image, error) {
+	op := "repository.postgres.imageRepository.GetAfter"
+	sqlStatement := `SELECT
+		id,
+		title,
+		data,
+		width,
+		height,
+		format,
+		size,
+		dominant_color,
+		version
+		FROM image WHERE id > @id
+		ORDER BY id
+		LIMIT @limit`
+	args := pgx.NamedArgs{
+		"id":    id,
+		"limit": limit,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get images", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var images []domain.Image
+
+	for rows.Next() {
+		var image domain.Image
+
+		err := rows.Scan(
+			&image.Id,
+			&image.Title,
+			&image.Data,
+			&image.Metadata.Width,
+			&image.Metadata.Height,
+			&image.Metadata.Format,
+			&image.Metadata.Size,
+			&image.Metadata.DominantColor,
+			&image.Version,
+		)
+		if err != nil {
+			r.logger.Error("failed to bind data", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		images = append(images, image)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return images, nil
+}
+
 func (r *ImageRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Image, error) {
 	op := "repository.postgres.imageRepositoru.GetById"
-	sqlStatement := "SELECT * FROM image WHERE id = @id"
+	sqlStatement := `SELECT
+		id,
+		title,
+		data,
+		width,
+		height,
+		format,
+		size,
+		dominant_color,
+		version
+		FROM image WHERE id = @id`
 	arg := pgx.NamedArgs{
 		"id": id,
 	}
 
 	row := r.db.QueryRow(ctx, sqlStatement, arg)
 	image := domain.Image{}
-	err := row.Scan(&image.Id, &image.Title, &image.Data)
+	err := row.Scan(
+		&image.Id,
+		&image.Title,
+		&image.Data,
+		&image.Metadata.Width,
+		&image.Metadata.Height,
+		&image.Metadata.Format,
+		&image.Metadata.Size,
+		&image.Metadata.DominantColor,
+		&image.Version,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Debug("image not found", "op", op)
@@ -105,17 +210,46 @@ func (r *ImageRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Image, e
 	return &image, nil
 }
 
+// Update rewrites the image and increments its version. A non-zero
+// image.Version is the expected version, the new one is set back.
 func (r *ImageRepo) Update(ctx context.Context, image *domain.Image) error {
 	op := "repository.postgres.imageRepository.Update"
-	sqlStatement := `UPDATE image SET title = @title, data = @image WHERE id = @id`
+	sqlStatement := `UPDATE image SET
+		title = @title,
+		data = @image,
+		width = @width,
+		height = @height,
+		format = @format,
+		size = @size,
+		dominant_color = @dominant_color,
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
 	args := pgx.NamedArgs{
-		"id":    image.Id,
-		"title": image.Title,
-		"image": image.Data,
+		"id":             image.Id,
+		"title":          image.Title,
+		"image":          image.Data,
+		"width":          image.Metadata.Width,
+		"height":         image.Metadata.Height,
+		"format":         image.Metadata.Format,
+		"size":           image.Metadata.Size,
+		"dominant_color": image.Metadata.DominantColor,
+		"version":        image.Version,
 	}
 
-	tag, err := r.db.Exec(ctx, sqlStatement, args)
-	if tag.RowsAffected() == 0 {
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&image.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "image", image.Id, image.Version)
+		if err != nil {
+			r.logger.Error("failed to check image version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("image version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+
 		r.logger.Debug("image not found", "op", op)
 		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
@@ -128,18 +262,33 @@ func (r *ImageRepo) Update(ctx context.Context, image *domain.Image) error {
 	return nil
 }
 
-func (r *ImageRepo) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete removes the image, a non-zero version is the expected version.
+func (r *ImageRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "repository.postgres.productRepository.Delete"
-	sqlStatement := "DELETE FROM image WHERE id=@id"
+	sqlStatement := "DELETE FROM image WHERE id=@id AND (@version = 0 OR version = @version)"
 	arg := pgx.NamedArgs{
-		"id": id,
+		"id":      id,
+		"version": version,
 	}
 
-	_, err := r.db.Exec(ctx, sqlStatement, arg)
+	tag, err := r.db.Exec(ctx, sqlStatement, arg)
 	if err != nil {
 		r.logger.Error("failed delete image by id", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: %v", op, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "image", id, version)
+		if err != nil {
+			r.logger.Error("failed to check image version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("image version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
 	return nil
 }
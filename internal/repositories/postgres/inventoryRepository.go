@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// lowStockCondition matches products at or below the reorder threshold,
+// products with variants are left out as their stock is kept by the variants.
+const lowStockCondition = `p.available_stock <= p.reorder_threshold
+	AND NOT EXISTS (SELECT 1 FROM product v WHERE v.parent_id = p.id)`
+
+// lowStockColumns are scanned by lowStockTargets.
+const lowStockColumns = `p.id,
+	p.parent_id,
+	p.name,
+	COALESCE(p.sku, ''),
+	COALESCE(p.barcode, ''),
+	s.id,
+	s.name,
+	p.available_stock,
+	p.reorder_threshold,
+	p.reorder_target,
+	p.low_stock_since`
+
+func lowStockTargets(item *domain.LowStockItem) []any {
+	return []any{
+		&item.ProductId,
+		&item.ParentId,
+		&item.Name,
+		&item.Sku,
+		&item.Barcode,
+		&item.Supplier.Id,
+		&item.Supplier.Name,
+		&item.AvailableStock,
+		&item.Reorder.Threshold,
+		&item.Reorder.Target,
+		&item.LowSince,
+	}
+}
+
+type InventoryRepo struct {
+	*basePostgresRepository
+}
+
+func NewInventoryRepository(db DB, logger *logger.Logger) *InventoryRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("Postgres Inventory repository is created")
+	return &InventoryRepo{
+		repo,
+	}
+}
+
+// GetLowStock returns a page of products low on stock ordered by supplier and
+// name and the total number of such products. A non-nil supplier id limits
+// them to the supplier, ErrNotFound is returned for a missing supplier.
+func (r *InventoryRepo) GetLowStock(ctx context.Context, supplierId *uuid.UUID, limit, offset int) ([]domain.LowStockItem, int, error) {
+	op := "repository.postgres.inventoryRepository.GetLowStock"
+
+	if supplierId != nil {
+		if err := r.checkSupplier(ctx, op, *supplierId); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	sqlStatement := `SELECT ` + lowStockColumns + `, COUNT(*) OVER ()
+		FROM product p
+		JOIN supplier s ON p.supplier_id = s.id
+		WHERE ` + lowStockCondition + ` AND (@supplier_id::UUID IS NULL OR p.supplier_id = @supplier_id)
+		ORDER BY s.name, s.id, p.name, p.id
+		LIMIT @limit OFFSET @offset`
+	args := pgx.NamedArgs{
+		"supplier_id": supplierId,
+		"limit":       limit,
+		"offset":      offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("query unvalable", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		items []domain.LowStockItem
+		total int
+	)
+
+	for rows.Next() {
+		var item domain.LowStockItem
+
+		if err := rows.Scan(append(lowStockTargets(&item), &total)...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, 0, fmt.Errorf("%s: scan error: %v", op, err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return items, total, nil
+}
+
+// ForEachLowStock streams all products low on stock ordered by supplier and
+// name, a non-nil supplier id limits them to the supplier.
+func (r *InventoryRepo) ForEachLowStock(ctx context.Context, supplierId *uuid.UUID, fn func(item domain.LowStockItem) error) error {
+	op := "repository.postgres.inventoryRepository.ForEachLowStock"
+
+	if supplierId != nil {
+		if err := r.checkSupplier(ctx, op, *supplierId); err != nil {
+			return err
+		}
+	}
+
+	sqlStatement := `SELECT ` + lowStockColumns + `
+		FROM product p
+		JOIN supplier s ON p.supplier_id = s.id
+		WHERE ` + lowStockCondition + ` AND (@supplier_id::UUID IS NULL OR p.supplier_id = @supplier_id)
+		ORDER BY s.name, s.id, p.name, p.id`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"supplier_id": supplierId})
+	if err != nil {
+		r.logger.Error("unable to query low stock", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var item domain.LowStockItem
+
+	_, err = pgx.ForEachRow(rows, lowStockTargets(&item), func() error {
+		return fn(item)
+	})
+	if err != nil {
+		r.logger.Error("failed to stream low stock", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FlagLowStock flags products low on stock which are not flagged yet and
+// returns them. Flags of products above the threshold again are removed. The
+// version of products is not changed, the flag is derived from the stock.
+func (r *InventoryRepo) FlagLowStock(ctx context.Context) ([]domain.LowStockItem, error) {
+	op := "repository.postgres.inventoryRepository.FlagLowStock"
+
+	sqlClear := `UPDATE product p SET low_stock_since = NULL
+		WHERE p.low_stock_since IS NOT NULL AND NOT (COALESCE(` + lowStockCondition + `, FALSE))`
+
+	if _, err := r.db.Exec(ctx, sqlClear); err != nil {
+		r.logger.Error("failed to clear low stock flags", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	sqlFlag := `WITH flagged AS (
+			UPDATE product p SET low_stock_since = NOW()
+			WHERE p.low_stock_since IS NULL AND ` + lowStockCondition + `
+			RETURNING p.*
+		)
+		SELECT ` + lowStockColumns + `
+		FROM flagged p
+		JOIN supplier s ON p.supplier_id = s.id
+		ORDER BY s.name, s.id, p.name, p.id`
+
+	rows, err := r.db.Query(ctx, sqlFlag)
+	if err != nil {
+		r.logger.Error("failed to flag low stock", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var items []domain.LowStockItem
+
+	for rows.Next() {
+		var item domain.LowStockItem
+
+		if err := rows.Scan(lowStockTargets(&item)...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan error: %v", op, err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return items, nil
+}
+
+func (r *InventoryRepo) checkSupplier(ctx context.Context, op string, supplierId uuid.UUID) error {
+	var exists bool
+
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM supplier WHERE id = @id)`,
+		pgx.NamedArgs{"id": supplierId}).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check supplier", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if !exists {
+		r.logger.Debug("supplier not found", "op", op)
+		return fmt.Errorf("%s: supplier: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// jobColumns lists job columns without input and output data.
+const jobColumns = `id, kind, status, params, progress, result, output IS NOT NULL, output_type,
+	error, attempts, cancel_requested, created_at, started_at, finished_at`
+
+// nullableJob scans a job with optional dates.
+type nullableJob struct {
+	job        domain.Job
+	startedAt  *time.Time
+	finishedAt *time.Time
+}
+
+func (j *nullableJob) targets() []any {
+	return []any{
+		&j.job.Id, &j.job.Kind, &j.job.Status, &j.job.Params, &j.job.Progress, &j.job.Result,
+		&j.job.HasOutput, &j.job.OutputType, &j.job.Error, &j.job.Attempts, &j.job.CancelRequested,
+		&j.job.CreatedAt, &j.startedAt, &j.finishedAt,
+	}
+}
+
+func (j *nullableJob) toDomain() *domain.Job {
+	job := j.job
+	if j.startedAt != nil {
+		job.StartedAt = *j.startedAt
+	}
+
+	if j.finishedAt != nil {
+		job.FinishedAt = *j.finishedAt
+	}
+
+	return &job
+}
+
+type JobRepo struct {
+	*basePostgresRepository
+}
+
+func NewJobRepository(db DB, log *logger.Logger) *JobRepo {
+	baseRepo := newBasePostgresRepository(db, log)
+	log.Debug("job repo is created")
+	return &JobRepo{baseRepo}
+}
+
+// Create queues the job.
+func (r *JobRepo) Create(ctx context.Context, job *domain.Job) error {
+	op := "repository.postgres.jobRepository.Create"
+	sqlInsert := `INSERT INTO job (kind, params, input)
+		VALUES (@kind, @params, @input)
+		RETURNING ` + jobColumns
+
+	args := pgx.NamedArgs{
+		"kind":   job.Kind,
+		"params": job.Params,
+		"input":  job.Input,
+	}
+
+	var stored nullableJob
+
+	if err := r.db.QueryRow(ctx, sqlInsert, args).Scan(stored.targets()...); err != nil {
+		r.logger.Error("failed to insert job", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	input := job.Input
+	*job = *stored.toDomain()
+	job.Input = input
+
+	return nil
+}
+
+func (r *JobRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	op := "repository.postgres.jobRepository.GetById"
+	sqlSelect := `SELECT ` + jobColumns + ` FROM job WHERE id = @id`
+
+	var stored nullableJob
+
+	err := r.db.QueryRow(ctx, sqlSelect, pgx.NamedArgs{"id": id}).Scan(stored.targets()...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Debug("job not found", "id", id, "op", op)
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to get job", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return stored.toDomain(), nil
+}
+
+// GetOutput returns the output file of the job and its content type.
+func (r *JobRepo) GetOutput(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	op := "repository.postgres.jobRepository.GetOutput"
+	sqlSelect := `SELECT output, output_type FROM job WHERE id = @id AND output IS NOT NULL`
+
+	var (
+		output      []byte
+		contentType string
+	)
+
+	err := r.db.QueryRow(ctx, sqlSelect, pgx.NamedArgs{"id": id}).Scan(&output, &contentType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Debug("job output not found", "id", id, "op", op)
+			return nil, "", fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to get job output", logger.Err(err), "op", op)
+		return nil, "", fmt.Errorf("%s: %v", op, err)
+	}
+
+	return output, contentType, nil
+}
+
+// Claim takes the oldest queued job or a running job whose worker stopped
+// renewing the lease. Rows locked by other workers are skipped, ErrNotFound is
+// returned when nothing is left.
+func (r *JobRepo) Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*domain.Job, error) {
+	op := "repository.postgres.jobRepository.Claim"
+	sqlClaim := `UPDATE job SET
+			status = 'running',
+			attempts = attempts + 1,
+			locked_until = NOW() + make_interval(secs => @lease),
+			started_at = COALESCE(started_at, NOW())
+		WHERE id = (
+			SELECT id FROM job
+			WHERE status = 'queued'
+				OR (status = 'running' AND locked_until < NOW() AND attempts < @max_attempts)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING input, ` + jobColumns
+
+	args := pgx.NamedArgs{
+		"lease":        lease.Seconds(),
+		"max_attempts": maxAttempts,
+	}
+
+	var (
+		stored nullableJob
+		input  []byte
+	)
+
+	err := r.db.QueryRow(ctx, sqlClaim, args).Scan(append([]any{&input}, stored.targets()...)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to claim job", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	job := stored.toDomain()
+	job.Input = input
+
+	return job, nil
+}
+
+// Heartbeat saves the progress and renews the lease of a running job. It
+// reports whether cancellation of the job is requested. The attempt fences
+// the lease, ErrNotFound is returned when another worker claimed the job.
+func (r *JobRepo) Heartbeat(ctx context.Context, id uuid.UUID, attempt int, progress int64, lease time.Duration) (bool, error) {
+	op := "repository.postgres.jobRepository.Heartbeat"
+	sqlUpdate := `UPDATE job SET
+			progress = @progress,
+			locked_until = NOW() + make_interval(secs => @lease)
+		WHERE id = @id AND status = 'running' AND attempts = @attempt
+		RETURNING cancel_requested`
+
+	args := pgx.NamedArgs{
+		"id":       id,
+		"attempt":  attempt,
+		"progress": progress,
+		"lease":    lease.Seconds(),
+	}
+
+	var cancelRequested bool
+
+	err := r.db.QueryRow(ctx, sqlUpdate, args).Scan(&cancelRequested)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to renew job lease", logger.Err(err), "op", op)
+		return false, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return cancelRequested, nil
+}
+
+// Finish saves the outcome of the job, its input is dropped. The job has to be
+// running under job.Attempts, ErrNotFound is returned when the lease is lost.
+func (r *JobRepo) Finish(ctx context.Context, job *domain.Job) error {
+	op := "repository.postgres.jobRepository.Finish"
+	sqlUpdate := `UPDATE job SET
+			status = @status,
+			progress = @progress,
+			result = @result,
+			output = @output,
+			output_type = @output_type,
+			error = @error,
+			input = NULL,
+			locked_until = NULL,
+			finished_at = NOW()
+		WHERE id = @id AND status = 'running' AND attempts = @attempt`
+
+	args := pgx.NamedArgs{
+		"id":          job.Id,
+		"attempt":     job.Attempts,
+		"status":      job.Status,
+		"progress":    job.Progress,
+		"result":      job.Result,
+		"output":      job.Output,
+		"output_type": job.OutputType,
+		"error":       job.Error,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlUpdate, args)
+	if err != nil {
+		r.logger.Error("failed to finish job", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Release puts the running job back to the queue without counting the attempt.
+// ErrNotFound is returned when the lease of the attempt is lost.
+func (r *JobRepo) Release(ctx context.Context, id uuid.UUID, attempt int, progress int64) error {
+	op := "repository.postgres.jobRepository.Release"
+	sqlUpdate := `UPDATE job SET
+			status = 'queued',
+			progress = @progress,
+			attempts = GREATEST(attempts - 1, 0),
+			locked_until = NULL
+		WHERE id = @id AND status = 'running' AND attempts = @attempt`
+
+	args := pgx.NamedArgs{
+		"id":       id,
+		"attempt":  attempt,
+		"progress": progress,
+	}
+
+	tag, err := r.db.Exec(ctx, sqlUpdate, args)
+	if err != nil {
+		r.logger.Error("failed to release job", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Cancel cancels the queued job at once and asks the worker to stop the
+// running one. ErrJobFinished is returned for a finished job.
+func (r *JobRepo) Cancel(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	op := "repository.postgres.jobRepository.Cancel"
+	sqlUpdate := `UPDATE job SET
+			status = CASE WHEN status = 'queued' THEN 'canceled' ELSE status END,
+			finished_at = CASE WHEN status = 'queued' THEN NOW() ELSE finished_at END,
+			input = CASE WHEN status = 'queued' THEN NULL ELSE input END,
+			cancel_requested = TRUE
+		WHERE id = @id AND status IN ('queued', 'running')
+		RETURNING ` + jobColumns
+
+	var stored nullableJob
+
+	err := r.db.QueryRow(ctx, sqlUpdate, pgx.NamedArgs{"id": id}).Scan(stored.targets()...)
+	if err == nil {
+		return stored.toDomain(), nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Error("failed to cancel job", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	if _, err := r.GetById(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrJobFinished)
+}
+
+// FailAbandoned fails running jobs whose lease is expired and which used all
+// attempts, their workers stopped too many times.
+func (r *JobRepo) FailAbandoned(ctx context.Context, maxAttempts int) (int, error) {
+	op := "repository.postgres.jobRepository.FailAbandoned"
+	sqlUpdate := `UPDATE job SET
+			status = 'failed',
+			error = 'job is abandoned by workers too many times',
+			input = NULL,
+			locked_until = NULL,
+			finished_at = NOW()
+		WHERE status = 'running' AND locked_until < NOW() AND attempts >= @max_attempts`
+
+	tag, err := r.db.Exec(ctx, sqlUpdate, pgx.NamedArgs{"max_attempts": maxAttempts})
+	if err != nil {
+		r.logger.Error("failed to fail abandoned jobs", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteFinished deletes jobs finished longer than the retention ago.
+func (r *JobRepo) DeleteFinished(ctx context.Context, retention time.Duration) (int, error) {
+	op := "repository.postgres.jobRepository.DeleteFinished"
+	sqlDelete := `DELETE FROM job WHERE finished_at < NOW() - make_interval(secs => @retention)`
+
+	tag, err := r.db.Exec(ctx, sqlDelete, pgx.NamedArgs{"retention": retention.Seconds()})
+	if err != nil {
+		r.logger.Error("failed to delete finished jobs", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type OrderRepo struct {
+	*basePostgresRepository
+}
+
+func NewOrderRepository(db DB, logger *logger.Logger) *OrderRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres order repository is created")
+	return &OrderRepo{
+		repo,
+	}
+}
+
+// Create inserts the order with its items, the order id is given by the
+// caller.
+func (r *OrderRepo) Create(ctx context.Context, order *domain.Order) error {
+	op := "repository.postgres.orderRepository.Create"
+	sqlStatement := `INSERT INTO client_order(id, client_id, subtotal, discount, total)
+		VALUES (@id, @client_id, @subtotal, @discount, @total)
+		RETURNING created_at;`
+	args := pgx.NamedArgs{
+		"id":        order.Id,
+		"client_id": order.ClientId,
+		"subtotal":  order.Subtotal,
+		"discount":  order.Discount,
+		"total":     order.Total,
+	}
+
+	if err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&order.CreatedAt); err != nil {
+		r.logger.Error("failed to create order", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	sqlItem := `INSERT INTO order_item(order_id, product_id, name, sku, price, quantity, warehouse_id, discount, promotion_id)
+		VALUES (@order_id, @product_id, @name, NULLIF(@sku, ''), @price, @quantity, @warehouse_id, @discount, @promotion_id)`
+
+	for _, item := range order.Items {
+		args := pgx.NamedArgs{
+			"order_id":     order.Id,
+			"product_id":   item.ProductId,
+			"name":         item.Name,
+			"sku":          item.Sku,
+			"price":        item.Price,
+			"quantity":     item.Quantity,
+			"warehouse_id": item.WarehouseId,
+			"discount":     item.Discount,
+			"promotion_id": item.PromotionId,
+		}
+
+		if _, err := r.db.Exec(ctx, sqlItem, args); err != nil {
+			r.logger.Error("failed to create order item", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: unable to insert item: %v", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *OrderRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	op := "repository.postgres.orderRepository.GetById"
+	sqlStatement := `SELECT id, client_id, subtotal, discount, total, created_at FROM client_order WHERE id = @id`
+
+	var order domain.Order
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(&order.Id, &order.ClientId, &order.Subtotal, &order.Discount, &order.Total, &order.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("order not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	orders := []domain.Order{order}
+	if err := r.fillItems(ctx, op, orders); err != nil {
+		return nil, err
+	}
+
+	return &orders[0], nil
+}
+
+// GetByClient returns a page of the client orders with their items, the latest
+// first. ErrNotFound is returned when the client does not exist.
+func (r *OrderRepo) GetByClient(ctx context.Context, clientId uuid.UUID, limit, offset int) ([]domain.Order, error) {
+	op := "repository.postgres.orderRepository.GetByClient"
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM client WHERE id = @id)`, pgx.NamedArgs{"id": clientId}).Scan(&exists); err != nil {
+		r.logger.Error("failed to check client", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: failed to check client: %v", op, err)
+	}
+
+	if !exists {
+		r.logger.Debug("client not found", "op", op)
+		return nil, fmt.Errorf("%s: client: %w", op, crud_errors.ErrNotFound)
+	}
+
+	sqlStatement := `SELECT id, client_id, subtotal, discount, total, created_at
+		FROM client_order
+		WHERE client_id = @client_id
+		ORDER BY created_at DESC, id
+		LIMIT @limit OFFSET @offset`
+	args := pgx.NamedArgs{
+		"client_id": clientId,
+		"limit":     limit,
+		"offset":    offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get orders", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	orders := []domain.Order{}
+
+	for rows.Next() {
+		var order domain.Order
+
+		if err := rows.Scan(&order.Id, &order.ClientId, &order.Subtotal, &order.Discount, &order.Total, &order.CreatedAt); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get orders", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if err := r.fillItems(ctx, op, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// fillItems reads the items of the orders by one query.
+func (r *OrderRepo) fillItems(ctx context.Context, op string, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(orders))
+	index := make(map[uuid.UUID]int, len(orders))
+
+	for i, order := range orders {
+		ids[i] = order.Id
+		index[order.Id] = i
+	}
+
+	sqlStatement := `SELECT order_id, product_id, name, COALESCE(sku, ''), price, quantity, warehouse_id, discount, promotion_id
+		FROM order_item
+		WHERE order_id = ANY(@ids)
+		ORDER BY name, id`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		r.logger.Error("failed to get order items", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderId uuid.UUID
+			item    domain.OrderItem
+		)
+
+		if err := rows.Scan(&orderId, &item.ProductId, &item.Name, &item.Sku, &item.Price, &item.Quantity, &item.WarehouseId,
+			&item.Discount, &item.PromotionId); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		order := &orders[index[orderId]]
+		order.Items = append(order.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get order items", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ProductImageRepo struct {
+	*basePostgresRepository
+}
+
+func NewProductImageRepository(db DB, logger *logger.Logger) *ProductImageRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres product image repository is created")
+	return &ProductImageRepo{
+		repo,
+	}
+}
+
+// Attach links the image to the product gallery. The first attached image always
+// becomes primary, a new primary image takes the flag away from the previous one.
+// Images at and after an explicit position are shifted to keep positions unique.
+func (r *ProductImageRepo) Attach(ctx context.Context, productId uuid.UUID, productImage *domain.ProductImage) error {
+	op := "repository.postgres.productImageRepository.Attach"
+	args := pgx.NamedArgs{
+		"product_id": productId,
+		"image_id":   productImage.Image.Id,
+		"position":   productImage.Position,
+		"is_primary": productImage.IsPrimary,
+	}
+
+	// the product row serializes attaches, concurrent ones would take the same position
+	sqlLock := `SELECT id FROM product WHERE id = @product_id FOR UPDATE`
+	if err := r.db.QueryRow(ctx, sqlLock, args).Scan(new(uuid.UUID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Debug("product not found", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to lock product", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if productImage.Position >= 0 {
+		sqlShift := `UPDATE product_image SET position = position + 1
+			WHERE product_id = @product_id AND position >= @position`
+		if _, err := r.db.Exec(ctx, sqlShift, args); err != nil {
+			r.logger.Error("failed to shift gallery positions", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed exec query: %v", op, err)
+		}
+	}
+
+	if productImage.IsPrimary {
+		sqlReset := `UPDATE product_image SET is_primary = FALSE WHERE product_id = @product_id AND is_primary`
+		if _, err := r.db.Exec(ctx, sqlReset, args); err != nil {
+			r.logger.Error("failed to reset primary image", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed exec query: %v", op, err)
+		}
+	}
+
+	sqlInsert := `INSERT INTO product_image(product_id, image_id, position, is_primary)
+		SELECT
+			@product_id,
+			@image_id,
+			CASE WHEN @position::INT < 0
+				THEN COALESCE((SELECT MAX(position) + 1 FROM product_image WHERE product_id = @product_id), 0)
+				ELSE @position::INT
+			END,
+			@is_primary::BOOLEAN OR NOT EXISTS (SELECT 1 FROM product_image WHERE product_id = @product_id)
+		RETURNING position, is_primary;`
+
+	err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&productImage.Position, &productImage.IsPrimary)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503":
+				r.logger.Debug("product or image not found", "op", op)
+				return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+			case "23505":
+				r.logger.Debug("image is already attached", "op", op)
+				return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+			}
+		}
+
+		r.logger.Error("failed to attach image", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
+
+// SetPrimary makes the attached image primary, ErrNotFound is returned when the
+// image is not in the product gallery.
+func (r *ProductImageRepo) SetPrimary(ctx context.Context, productId, imageId uuid.UUID) error {
+	op := "repository.postgres.productImageRepository.SetPrimary"
+	// the primary is reset first, the unique index allows one primary at any moment
+	sqlReset := `UPDATE product_image SET is_primary = FALSE
+		WHERE product_id = @product_id AND is_primary AND image_id <> @image_id
+			AND EXISTS (SELECT 1 FROM product_image WHERE product_id = @product_id AND image_id = @image_id)`
+	sqlSet := `UPDATE product_image SET is_primary = TRUE WHERE product_id = @product_id AND image_id = @image_id`
+	args := pgx.NamedArgs{
+		"product_id": productId,
+		"image_id":   imageId,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlReset, args); err != nil {
+		r.logger.Error("failed to reset primary image", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	tag, err := r.db.Exec(ctx, sqlSet, args)
+	if err != nil {
+		r.logger.Error("failed to set primary image", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("image is not attached to product", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Detach removes the image from the product gallery. If the removed image was
+// primary, the image with the lowest position is promoted.
+func (r *ProductImageRepo) Detach(ctx context.Context, productId, imageId uuid.UUID) error {
+	op := "repository.postgres.productImageRepository.Detach"
+	sqlDelete := `DELETE FROM product_image
+		WHERE product_id = @product_id AND image_id = @image_id
+		RETURNING is_primary;`
+	args := pgx.NamedArgs{
+		"product_id": productId,
+		"image_id":   imageId,
+	}
+
+	var wasPrimary bool
+
+	err := r.db.QueryRow(ctx, sqlDelete, args).Scan(&wasPrimary)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("image is not attached to product", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("failed to detach image", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if !wasPrimary {
+		return nil
+	}
+
+	sqlPromote := `UPDATE product_image SET is_primary = TRUE
+		WHERE product_id = @product_id AND image_id = (
+			SELECT image_id FROM product_image
+			WHERE product_id = @product_id
+			ORDER BY position, image_id
+			LIMIT 1
+		);`
+
+	if _, err := r.db.Exec(ctx, sqlPromote, args); err != nil {
+		r.logger.Error("failed to promote primary image", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+// Reorder sets positions by the order of the given ids. The ids must contain
+// exactly the images attached to the product.
+func (r *ProductImageRepo) Reorder(ctx context.Context, productId uuid.UUID, imageIds []uuid.UUID) error {
+	op := "repository.postgres.productImageRepository.Reorder"
+
+	gallery, err := selectGallery(ctx, r.db, []uuid.UUID{productId})
+	if err != nil {
+		r.logger.Error("failed to get gallery", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	attached := gallery[productId]
+	if len(attached) == 0 {
+		r.logger.Debug("product gallery is empty", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if len(attached) != len(imageIds) {
+		r.logger.Debug("order does not match gallery", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	known := make(map[uuid.UUID]bool, len(attached))
+	for _, item := range attached {
+		known[item.Image.Id] = true
+	}
+
+	for _, id := range imageIds {
+		if !known[id] {
+			r.logger.Debug("order does not match gallery", "image id", id, "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+		}
+
+		delete(known, id)
+	}
+
+	sqlStatement := `UPDATE product_image p SET position = o.ord - 1
+		FROM unnest(@image_ids::UUID[]) WITH ORDINALITY AS o(image_id, ord)
+		WHERE p.product_id = @product_id AND p.image_id = o.image_id;`
+	args := pgx.NamedArgs{
+		"product_id": productId,
+		"image_ids":  imageIds,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlStatement, args); err != nil {
+		r.logger.Error("failed to reorder gallery", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+func (r *ProductImageRepo) GetByProductId(ctx context.Context, productId uuid.UUID) ([]domain.ProductImage, error) {
+	op := "repository.postgres.productImageRepository.GetByProductId"
+
+	gallery, err := selectGallery(ctx, r.db, []uuid.UUID{productId})
+	if err != nil {
+		r.logger.Error("failed to get gallery", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return gallery[productId], nil
+}
+
+// selectGallery loads ordered galleries for the given products in one query.
+func selectGallery(ctx context.Context, db DB, productIds []uuid.UUID) (map[uuid.UUID][]domain.ProductImage, error) {
+	sqlStatement := `SELECT
+		pi.product_id,
+		pi.position,
+		pi.is_primary,
+		i.id,
+		i.title,
+		i.data,
+		i.width,
+		i.height,
+		i.format,
+		i.size,
+		i.dominant_color
+		FROM product_image pi
+		JOIN image i ON pi.image_id = i.id
+		WHERE pi.product_id = ANY(@product_ids::UUID[])
+		ORDER BY pi.product_id, pi.position, i.id;`
+	args := pgx.NamedArgs{
+		"product_ids": productIds,
+	}
+
+	rows, err := db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %v", err)
+	}
+	defer rows.Close()
+
+	gallery := make(map[uuid.UUID][]domain.ProductImage, len(productIds))
+
+	for rows.Next() {
+		var (
+			productId    uuid.UUID
+			productImage domain.ProductImage
+		)
+
+		err := rows.Scan(
+			&productId,
+			&productImage.Position,
+			&productImage.IsPrimary,
+			&productImage.Image.Id,
+			&productImage.Image.Title,
+			&productImage.Image.Data,
+			&productImage.Image.Metadata.Width,
+			&productImage.Image.Metadata.Height,
+			&productImage.Image.Metadata.Format,
+			&productImage.Image.Metadata.Size,
+			&productImage.Image.Metadata.DominantColor,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %v", err)
+		}
+
+		gallery[productId] = append(gallery[productId], productImage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return gallery, nil
+}
@@ -5,11 +5,14 @@ import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type ProductRepo struct {
@@ -27,22 +30,66 @@ func NewProductRepository(db DB, logger *logger.Logger) *ProductRepo {
 func (r *ProductRepo) Create(ctx context.Context, product *domain.Product) error {
 	op := "repositories.postgres.productRepository.Create"
 	sqlStatement := `
-	INSERT INTO product(name, category, price, available_stock,  supplier_id, image_id) 
-	VALUES (@name, @category, @price, @available_stock, @supplier_id, @image_id)
+	INSERT INTO product(name, category_id, price, available_stock, supplier_id, attributes, parent_id, sku, barcode, last_delivery_date)
+	VALUES (@name, @category_id, @price, @available_stock, @supplier_id, @attributes, @parent_id, NULLIF(@sku, ''), NULLIF(@barcode, ''),
+		CASE WHEN @available_stock::INT > 0 THEN NOW() END)
 	RETURNING id;`
 	args := pgx.NamedArgs{
 		"name":            product.Name,
-		"category":        product.Category,
+		"category_id":     product.Category.Id,
 		"price":           product.Price,
 		"available_stock": product.AvailableStock,
 		"supplier_id":     product.Supplier.Id,
-		"image_id":        product.Image.Id,
+		"attributes":      attributesValue(product.Attributes),
+		"parent_id":       product.ParentId,
+		"sku":             product.Sku,
+		"barcode":         product.Barcode,
 	}
 
 	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&product.Id)
 	if err != nil {
-		r.logger.Error("failed to create product", logger.Err(err), "op", op)
-		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+		return r.writeError(op, "create", err)
+	}
+
+	if product.ParentId != nil {
+		if err := r.refreshStock(ctx, *product.ParentId); err != nil {
+			r.logger.Error("failed to refresh parent stock", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %v", op, err)
+		}
+	}
+
+	return nil
+}
+
+// writeError maps constraint violations of a written product: a missing
+// supplier or category and a taken SKU or barcode.
+func (r *ProductRepo) writeError(op, action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			r.logger.Debug("supplier or category not found", "op", op)
+			return fmt.Errorf("%s: supplier or category: %w", op, crud_errors.ErrNotFound)
+		case "23505":
+			r.logger.Debug("sku or barcode is taken", "op", op)
+			return fmt.Errorf("%s: sku or barcode: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+	}
+
+	r.logger.Error("failed to "+action+" product", logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unable to %s product: %v", op, action, err)
+}
+
+// refreshStock sets the stock of the parent to the sum of its variants.
+func (r *ProductRepo) refreshStock(ctx context.Context, parentId uuid.UUID) error {
+	sqlStatement := `UPDATE product p SET
+		available_stock = (SELECT COALESCE(SUM(v.available_stock), 0) FROM product v WHERE v.parent_id = p.id),
+		last_update_date = NOW(),
+		version = p.version + 1
+		WHERE p.id = @id`
+
+	if _, err := r.db.Exec(ctx, sqlStatement, pgx.NamedArgs{"id": parentId}); err != nil {
+		return fmt.Errorf("refresh stock of %s: %v", parentId, err)
 	}
 
 	return nil
@@ -53,22 +100,24 @@ func (r *ProductRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.P
 	sqlStatement := `SELECT
 		p.id,
 		p.name,
-		p.category,
+		p.parent_id,
+		COALESCE(p.sku, ''),
+		COALESCE(p.barcode, ''),
+		c.id,
+		c.name,
+		c.slug,
 		p.price,
 		p.available_stock,
+		p.attributes,
 		s.id,
 		s.name,
 		s.phone_number,
-		a.id,
-		a.country,
-		a.city,
-		a.street,
-		i.id,
-		i.data
+		` + addressColumns + `
 		FROM product p
+		JOIN category c ON p.category_id = c.id
 		LEFT JOIN supplier s ON p.supplier_id = s.id
-		LEFT JOIN address a ON s.address_id = a.id
-		LEFT JOIN image i ON p.image_id = i.id 
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
+		WHERE p.parent_id IS NULL
 		LIMIT @limit OFFSET @offset`
 	args := pgx.NamedArgs{
 		"limit":  limit,
@@ -80,54 +129,42 @@ func (r *ProductRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.P
 		r.logger.Error("query unvalable", logger.Err(err), "op", op)
 		return nil, fmt.Errorf("%s: query error: %v", op, err)
 	}
+	defer rows.Close()
 
 	var products []domain.Product
 
 	for rows.Next() {
 		var (
-			product                                                            domain.Product
-			imageData                                                          []byte
-			supplierAddressId                                                  *uuid.UUID
-			supplierAddressCountry, supplierAddressCity, supplierAddressStreet *string
+			product domain.Product
+			address nullableAddress
 		)
 
-		err := rows.Scan(
+		targets := append([]any{
 			&product.Id,
 			&product.Name,
-			&product.Category,
+			&product.ParentId,
+			&product.Sku,
+			&product.Barcode,
+			&product.Category.Id,
+			&product.Category.Name,
+			&product.Category.Slug,
 			&product.Price,
 			&product.AvailableStock,
+			&product.Attributes,
 			&product.Supplier.Id,
 			&product.Supplier.Name,
 			&product.Supplier.PhoneNumber,
-			&supplierAddressId,
-			&supplierAddressCountry,
-			&supplierAddressCity,
-			&supplierAddressStreet,
-			&product.Image.Id,
-			&imageData,
-		)
+		}, address.targets()...)
+
+		err := rows.Scan(targets...)
 
 		if err != nil {
 			r.logger.Warn("scan unable", logger.Err(err), "op", op)
 			continue
 		}
 
-		if supplierAddressId != nil && imageData != nil {
-			product.Supplier.Address = &domain.Address{
-				Id:      *supplierAddressId,
-				Country: *supplierAddressCountry,
-				City:    *supplierAddressCity,
-				Street:  *supplierAddressStreet,
-			}
-			product.Image.Data = imageData
-
-		} else {
-			if imageData == nil {
-				r.logger.Error("WRONG! Unthinkable, a image without data, this can't be", "op", op)
-				return nil, fmt.Errorf("%s: Image data is %w", op, crud_errors.ErrProductImageDataEmpty)
-			}
-
+		product.Supplier.Address = address.toDomain()
+		if product.Supplier.Address == nil {
 			r.logger.Error("WRONG! Unthinkable, a supplier without an address, this can't be", "op", op)
 			return nil, fmt.Errorf("%s: Supplier Address is %w", op, crud_errors.ErrProductSupplerAddressEmpty)
 		}
@@ -140,6 +177,28 @@ func (r *ProductRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.P
 		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
 
+	ids := make([]uuid.UUID, len(products))
+	for i := range products {
+		ids[i] = products[i].Id
+	}
+
+	gallery, err := selectGallery(ctx, r.db, ids)
+	if err != nil {
+		r.logger.Error("failed to get product galleries", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	variants, err := selectVariants(ctx, r.db, ids)
+	if err != nil {
+		r.logger.Error("failed to get product variants", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	for i := range products {
+		products[i].Images = gallery[products[i].Id]
+		products[i].Variants = variants[products[i].Id]
+	}
+
 	return products, nil
 }
 
@@ -148,22 +207,27 @@ func (r *ProductRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Produc
 	sqlStatement := `SELECT
 		p.id,
 		p.name,
-		p.category,
+		p.parent_id,
+		COALESCE(p.sku, ''),
+		COALESCE(p.barcode, ''),
+		c.id,
+		c.name,
+		c.slug,
 		p.price,
 		p.available_stock,
+		p.attributes,
+		p.last_update_date,
+		p.version,
+		p.reorder_threshold,
+		p.reorder_target,
 		s.id,
 		s.name,
 		s.phone_number,
-		a.id,
-		a.country,
-		a.city,
-		a.street,
-		i.id,
-		i.data
+		` + addressColumns + `
 		FROM product p
+		JOIN category c ON p.category_id = c.id
 		LEFT JOIN supplier s ON p.supplier_id = s.id
-		LEFT JOIN address a ON s.address_id = a.id
-		LEFT JOIN image i ON p.image_id = i.id 
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
 		WHERE p.id = @id`
 	arg := pgx.NamedArgs{
 		"id": id,
@@ -171,94 +235,1106 @@ func (r *ProductRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Produc
 
 	row := r.db.QueryRow(ctx, sqlStatement, arg)
 	var (
-		product                                                            domain.Product
-		imageData                                                          []byte
-		supplierAddressId                                                  *uuid.UUID
-		supplierAddressCountry, supplierAddressCity, supplierAddressStreet *string
+		product   domain.Product
+		address   nullableAddress
+		threshold *int64
+		target    *int64
 	)
-	err := row.Scan(
+	targets := append([]any{
 		&product.Id,
 		&product.Name,
-		&product.Category,
+		&product.ParentId,
+		&product.Sku,
+		&product.Barcode,
+		&product.Category.Id,
+		&product.Category.Name,
+		&product.Category.Slug,
 		&product.Price,
 		&product.AvailableStock,
+		&product.Attributes,
+		&product.LastUpdateDate,
+		&product.Version,
+		&threshold,
+		&target,
 		&product.Supplier.Id,
 		&product.Supplier.Name,
 		&product.Supplier.PhoneNumber,
-		&supplierAddressId,
-		&supplierAddressCountry,
-		&supplierAddressCity,
-		&supplierAddressStreet,
-		&product.Image.Id,
-		&imageData,
-	)
+	}, address.targets()...)
+
+	err := row.Scan(targets...)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		r.logger.Debug("product not found", "op", op)
 		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
 
-	if supplierAddressId != nil && imageData != nil {
-		product.Supplier.Address = &domain.Address{
-			Id:      *supplierAddressId,
-			Country: *supplierAddressCountry,
-			City:    *supplierAddressCity,
-			Street:  *supplierAddressStreet,
-		}
-		product.Image.Data = imageData
-
-	} else {
-		if imageData == nil {
-			r.logger.Error("WRONG! Unthinkable, a image without data, this can't be", "op", op)
-			return nil, fmt.Errorf("%s: Image data is %w", op, crud_errors.ErrProductImageDataEmpty)
-		}
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
 
+	product.Supplier.Address = address.toDomain()
+	if product.Supplier.Address == nil {
 		r.logger.Error("WRONG! Unthinkable, a supplier without an address, this can't be", "op", op)
 		return nil, fmt.Errorf("%s: Supplier Address is %w", op, crud_errors.ErrProductSupplerAddressEmpty)
 	}
 
+	if threshold != nil && target != nil {
+		product.Reorder = &domain.ReorderPolicy{Threshold: *threshold, Target: *target}
+	}
+
+	gallery, err := selectGallery(ctx, r.db, []uuid.UUID{product.Id})
+	if err != nil {
+		r.logger.Error("failed to get product gallery", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	product.Images = gallery[product.Id]
+
+	if product.ParentId != nil {
+		return &product, nil
+	}
+
+	variants, err := selectVariants(ctx, r.db, []uuid.UUID{product.Id})
+	if err != nil {
+		r.logger.Error("failed to get product variants", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	product.Variants = variants[product.Id]
+
+	return &product, nil
+}
+
+// GetBySku returns the product or the variant with the SKU.
+func (r *ProductRepo) GetBySku(ctx context.Context, sku string) (*domain.Product, error) {
+	return r.getByCode(ctx, "repository.postgres.productRepository.GetBySku", "sku", sku)
+}
+
+// GetByBarcode returns the product or the variant with the barcode.
+func (r *ProductRepo) GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	return r.getByCode(ctx, "repository.postgres.productRepository.GetByBarcode", "barcode", barcode)
+}
+
+// getByCode finds the product by a unique code column, the column is never
+// taken from a request.
+func (r *ProductRepo) getByCode(ctx context.Context, op, column, code string) (*domain.Product, error) {
+	var id uuid.UUID
+
+	err := r.db.QueryRow(ctx, `SELECT id FROM product WHERE `+column+` = @code`, pgx.NamedArgs{"code": code}).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("product not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
 	if err != nil {
 		r.logger.Error("scan unable", logger.Err(err), "op", op)
 		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
 	}
 
-	return &product, nil
+	product, err := r.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return product, nil
+}
+
+// selectVariants returns variants of the products by parent id ordered by
+// name and SKU, a variant has its primary image only.
+func selectVariants(ctx context.Context, db DB, parentIds []uuid.UUID) (map[uuid.UUID][]domain.Product, error) {
+	sqlStatement := `SELECT
+		v.parent_id,
+		v.id,
+		v.name,
+		COALESCE(v.sku, ''),
+		COALESCE(v.barcode, ''),
+		v.price,
+		v.available_stock,
+		v.attributes,
+		v.version,
+		pi.image_id
+		FROM product v
+		LEFT JOIN product_image pi ON pi.product_id = v.id AND pi.is_primary
+		WHERE v.parent_id = ANY(@parent_ids::UUID[])
+		ORDER BY v.parent_id, v.name, v.sku, v.id`
+
+	rows, err := db.Query(ctx, sqlStatement, pgx.NamedArgs{"parent_ids": parentIds})
+	if err != nil {
+		return nil, fmt.Errorf("query error: %v", err)
+	}
+	defer rows.Close()
+
+	variants := make(map[uuid.UUID][]domain.Product, len(parentIds))
+
+	for rows.Next() {
+		var (
+			variant domain.Product
+			parent  uuid.UUID
+			imageId *uuid.UUID
+		)
+
+		err := rows.Scan(
+			&parent,
+			&variant.Id,
+			&variant.Name,
+			&variant.Sku,
+			&variant.Barcode,
+			&variant.Price,
+			&variant.AvailableStock,
+			&variant.Attributes,
+			&variant.Version,
+			&imageId,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %v", err)
+		}
+
+		variant.ParentId = &parent
+		if imageId != nil {
+			variant.Images = []domain.ProductImage{{Image: domain.Image{Id: *imageId}, IsPrimary: true}}
+		}
+
+		variants[parent] = append(variants[parent], variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return variants, nil
+}
+
+// CopyFrom bulk inserts products with assigned ids and suppliers.
+func (r *ProductRepo) CopyFrom(ctx context.Context, products []domain.Product) (int64, error) {
+	op := "repositories.postgres.productRepository.CopyFrom"
+	columns := []string{"id", "name", "category_id", "price", "available_stock", "supplier_id", "attributes"}
+
+	count, err := r.db.CopyFrom(ctx, pgx.Identifier{"product"}, columns, pgx.CopyFromSlice(len(products), func(i int) ([]any, error) {
+		p := products[i]
+		return []any{p.Id, p.Name, p.Category.Id, p.Price, p.AvailableStock, p.Supplier.Id, attributesValue(p.Attributes)}, nil
+	}))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			r.logger.Debug("supplier or category not found", "op", op)
+			return 0, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		}
+
+		r.logger.Error("failed to copy products", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: copy failed: %v", op, err)
+	}
+
+	return count, nil
+}
+
+// ForEach streams all products with their supplier ordered by name.
+func (r *ProductRepo) ForEach(ctx context.Context, fn func(product domain.Product) error) error {
+	op := "repositories.postgres.productRepository.ForEach"
+	sqlStatement := `SELECT
+		p.id,
+		p.name,
+		c.id,
+		c.name,
+		c.slug,
+		p.price,
+		p.available_stock,
+		p.attributes,
+		p.last_update_date,
+		s.id,
+		s.name
+		FROM product p
+		JOIN category c ON p.category_id = c.id
+		JOIN supplier s ON p.supplier_id = s.id
+		ORDER BY p.name, p.id;`
+
+	rows, err := r.db.Query(ctx, sqlStatement)
+	if err != nil {
+		r.logger.Error("unable to query product", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var product domain.Product
+
+	targets := []any{
+		&product.Id,
+		&product.Name,
+		&product.Category.Id,
+		&product.Category.Name,
+		&product.Category.Slug,
+		&product.Price,
+		&product.AvailableStock,
+		&product.Attributes,
+		&product.LastUpdateDate,
+		&product.Supplier.Id,
+		&product.Supplier.Name,
+	}
+
+	_, err = pgx.ForEachRow(rows, targets, func() error {
+		err := fn(product)
+		// JSON is decoded into an existing map with the keys kept
+		product.Attributes = nil
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to stream products", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// productSortColumns maps sort keys to expressions, the key is never put into
+// the query as is.
+var productSortColumns = map[string]string{
+	domain.ProductSortName:           "p.name",
+	domain.ProductSortCategory:       "c.name",
+	domain.ProductSortPrice:          "p.price",
+	domain.ProductSortStock:          "p.available_stock",
+	domain.ProductSortLastUpdateDate: "p.last_update_date",
+}
+
+// attributeComparisons maps operators of attribute filters to SQL operators,
+// the operator is never put into the query as is.
+var attributeComparisons = map[string]string{
+	domain.AttributeGte: ">=",
+	domain.AttributeLte: "<=",
+	domain.AttributeGt:  ">",
+	domain.AttributeLt:  "<",
+}
+
+// attributeCondition builds the condition of the attribute filter with args
+// named by the prefix. Equality is matched by containment to use the index,
+// a number or a bool matches a string value of the same text too. Comparisons
+// match number values only.
+func attributeCondition(filter domain.AttributeFilter, prefix string, args pgx.NamedArgs) (string, error) {
+	key, value := prefix+"_key", prefix+"_value"
+
+	if filter.Operator == domain.AttributeEq {
+		document, err := json.Marshal(map[string]any{filter.Key: filter.Value})
+		if err != nil {
+			return "", fmt.Errorf("attribute %q: %w", filter.Key, crud_errors.ErrInvalidParam)
+		}
+
+		args[value] = string(document)
+		if _, ok := filter.Value.(string); ok {
+			return fmt.Sprintf("p.attributes @> @%s::JSONB", value), nil
+		}
+
+		args[key] = filter.Key
+		args[prefix+"_text"] = fmt.Sprint(filter.Value)
+		return fmt.Sprintf("(p.attributes @> @%s::JSONB OR p.attributes ->> @%s::TEXT = @%s_text::TEXT)", value, key, prefix), nil
+	}
+
+	comparison, ok := attributeComparisons[filter.Operator]
+	if !ok {
+		return "", fmt.Errorf("operator %q: %w", filter.Operator, crud_errors.ErrInvalidParam)
+	}
+
+	number, ok := filter.Value.(float64)
+	if !ok {
+		return "", fmt.Errorf("attribute %q is compared with a number only: %w", filter.Key, crud_errors.ErrInvalidParam)
+	}
+
+	args[key] = filter.Key
+	args[value] = number
+
+	// the cast is made for number values only
+	return fmt.Sprintf(`CASE WHEN jsonb_typeof(p.attributes -> @%[1]s::TEXT) = 'number'
+		THEN (p.attributes ->> @%[1]s::TEXT)::NUMERIC %[2]s @%[3]s::NUMERIC
+		ELSE FALSE END`, key, comparison, value), nil
 }
 
-func (r *ProductRepo) Update(ctx context.Context, id uuid.UUID, decrease int) error {
+// attributesValue gives the JSONB value of product attributes, no attributes
+// are stored as an empty object.
+func attributesValue(attributes map[string]any) map[string]any {
+	if attributes == nil {
+		return map[string]any{}
+	}
+
+	return attributes
+}
+
+// GetBySupplier returns a page of supplier products matched by the filter and
+// the total number of matched products. ErrNotFound means the supplier does not
+// exist, an existing supplier without matched products gives an empty page.
+func (r *ProductRepo) GetBySupplier(ctx context.Context, supplierId uuid.UUID, filter domain.ProductFilter) ([]domain.Product, int, error) {
+	op := "repositories.postgres.productRepository.GetBySupplier"
+
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM supplier WHERE id = @id)`, pgx.NamedArgs{"id": supplierId}).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check supplier", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if !exists {
+		r.logger.Debug("supplier not found", "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	conditions := []string{"p.supplier_id = @supplier_id"}
+	args := pgx.NamedArgs{"supplier_id": supplierId}
+
+	return r.selectPage(ctx, op, conditions, args, filter)
+}
+
+// GetByCategory returns a page of the category products matched by the filter
+// and the total number of matched products, products of subcategories are
+// included on request. ErrNotFound means the category does not exist.
+func (r *ProductRepo) GetByCategory(ctx context.Context, categoryId uuid.UUID, includeSubcategories bool, filter domain.ProductFilter) ([]domain.Product, int, error) {
+	op := "repositories.postgres.productRepository.GetByCategory"
+
+	var exists bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM category WHERE id = @id)`, pgx.NamedArgs{"id": categoryId}).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check category", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if !exists {
+		r.logger.Debug("category not found", "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	condition := "p.category_id = @category_id"
+	if includeSubcategories {
+		condition = "p.category_id IN (" + categorySubtree("id = @category_id") + ")"
+	}
+
+	args := pgx.NamedArgs{"category_id": categoryId}
+
+	return r.selectPage(ctx, op, []string{condition}, args, filter)
+}
+
+// selectPage returns a page of products matched by the conditions and the
+// filter, and the total number of matched products.
+func (r *ProductRepo) selectPage(ctx context.Context, op string, conditions []string, args pgx.NamedArgs, filter domain.ProductFilter) ([]domain.Product, int, error) {
+	// variants are listed with their parents
+	conditions = append(conditions, "p.parent_id IS NULL")
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	optional := []struct {
+		set       bool
+		condition string
+		name      string
+		value     any
+	}{
+		{filter.Query != "", "p.name ILIKE '%' || @query || '%'", "query", filter.Query},
+		{filter.Category != "", "p.category_id IN (" + categorySubtree("id::TEXT = @category OR slug = lower(@category)") + ")", "category", filter.Category},
+		{filter.PriceMin != nil, "p.price >= @price_min", "price_min", filter.PriceMin},
+		{filter.PriceMax != nil, "p.price <= @price_max", "price_max", filter.PriceMax},
+		{filter.InStock != nil, "(p.available_stock > 0) = @in_stock", "in_stock", filter.InStock},
+	}
+
+	for _, item := range optional {
+		if item.set {
+			conditions = append(conditions, item.condition)
+			args[item.name] = item.value
+		}
+	}
+
+	for i, attribute := range filter.Attributes {
+		condition, err := attributeCondition(attribute, fmt.Sprintf("attribute_%d", i), args)
+		if err != nil {
+			r.logger.Debug("attribute filter is invalid", logger.Err(err), "op", op)
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	orderBy := fmt.Sprintf("p.name %s", direction)
+	if column, ok := productSortColumns[filter.SortBy]; ok {
+		orderBy = fmt.Sprintf("%s %s", column, direction)
+	}
+
+	sqlStatement := fmt.Sprintf(`SELECT
+		p.id,
+		p.name,
+		p.parent_id,
+		COALESCE(p.sku, ''),
+		COALESCE(p.barcode, ''),
+		c.id,
+		c.name,
+		c.slug,
+		p.price,
+		p.available_stock,
+		p.attributes,
+		p.last_update_date,
+		s.id,
+		s.name,
+		s.phone_number,
+		%s,
+		COUNT(*) OVER() AS total
+		FROM product p
+		JOIN category c ON p.category_id = c.id
+		JOIN supplier s ON p.supplier_id = s.id
+		%s
+		WHERE %s
+		ORDER BY %s, p.id
+		LIMIT @limit OFFSET @offset;`, addressColumns, supplierLocationBook.defaultAddressJoin("s.id"), strings.Join(conditions, " AND "), orderBy)
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get supplier products", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		products []domain.Product
+		total    int
+	)
+
+	for rows.Next() {
+		var (
+			product domain.Product
+			address nullableAddress
+		)
+
+		targets := append([]any{
+			&product.Id,
+			&product.Name,
+			&product.ParentId,
+			&product.Sku,
+			&product.Barcode,
+			&product.Category.Id,
+			&product.Category.Name,
+			&product.Category.Slug,
+			&product.Price,
+			&product.AvailableStock,
+			&product.Attributes,
+			&product.LastUpdateDate,
+			&product.Supplier.Id,
+			&product.Supplier.Name,
+			&product.Supplier.PhoneNumber,
+		}, address.targets()...)
+		targets = append(targets, &total)
+
+		if err := rows.Scan(targets...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, 0, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		product.Supplier.Address = address.toDomain()
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i := range products {
+		ids[i] = products[i].Id
+	}
+
+	gallery, err := selectGallery(ctx, r.db, ids)
+	if err != nil {
+		r.logger.Error("failed to get product galleries", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	variants, err := selectVariants(ctx, r.db, ids)
+	if err != nil {
+		r.logger.Error("failed to get product variants", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	for i := range products {
+		products[i].Images = gallery[products[i].Id]
+		products[i].Variants = variants[products[i].Id]
+	}
+
+	return products, total, nil
+}
+
+// Update rewrites the product fields, increments the version and sets the
+// update date. A non-zero version of the product is the expected version.
+func (r *ProductRepo) Update(ctx context.Context, product *domain.Product) error {
 	op := "repository.postgres.productRepository.Update"
-	sqlStatement := "UPDATE product SET available_stock = available_stock - @decrease WHERE id = @id"
+	sqlStatement := `UPDATE product SET
+		name = @name,
+		category_id = @category_id,
+		price = @price,
+		supplier_id = @supplier_id,
+		attributes = @attributes,
+		sku = NULLIF(@sku, ''),
+		barcode = NULLIF(@barcode, ''),
+		last_update_date = NOW(),
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
 	args := pgx.NamedArgs{
-		"decrease": decrease,
-		"id":       id,
+		"id":          product.Id,
+		"name":        product.Name,
+		"category_id": product.Category.Id,
+		"price":       product.Price,
+		"supplier_id": product.Supplier.Id,
+		"attributes":  attributesValue(product.Attributes),
+		"sku":         product.Sku,
+		"barcode":     product.Barcode,
+		"version":     product.Version,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&product.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missingProduct(ctx, op, product.Id, product.Version)
+	}
+
+	if err != nil {
+		return r.writeError(op, "update", err)
+	}
+
+	// variants follow the category and the supplier of the parent
+	sqlStatement = `UPDATE product SET
+		category_id = @category_id,
+		supplier_id = @supplier_id,
+		last_update_date = NOW(),
+		version = version + 1
+		WHERE parent_id = @id AND (category_id <> @category_id OR supplier_id <> @supplier_id)`
+
+	if _, err := r.db.Exec(ctx, sqlStatement, args); err != nil {
+		r.logger.Error("failed to update product variants", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed to update variants: %v", op, err)
+	}
+
+	return nil
+}
+
+// productStock is the locked stock state of a product.
+type productStock struct {
+	parentId    *uuid.UUID
+	hasVariants bool
+	// stocked is set when the product is stocked in warehouses, its stock is
+	// the sum of the warehouse stocks then
+	stocked bool
+	stock   int64
+}
+
+// lockStock locks the product row for a stock change, the warehouse stocks
+// and the product stock change together under the lock.
+func (r *ProductRepo) lockStock(ctx context.Context, op string, id uuid.UUID, version int64) (*productStock, error) {
+	sqlStatement := `SELECT p.parent_id,
+			EXISTS (SELECT 1 FROM product v WHERE v.parent_id = p.id),
+			EXISTS (SELECT 1 FROM warehouse_stock ws WHERE ws.product_id = p.id),
+			p.available_stock
+		FROM product p WHERE p.id = @id
+		FOR UPDATE`
+
+	var state productStock
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(&state.parentId, &state.hasVariants, &state.stocked, &state.stock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missingProduct(ctx, op, id, version)
+	}
+
+	if err != nil {
+		r.logger.Error("failed to lock product stock", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if state.hasVariants {
+		r.logger.Debug("stock of a product with variants", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrVariantStock)
+	}
+
+	return &state, nil
+}
+
+// stockError maps violations of warehouse stock constraints.
+func (r *ProductRepo) stockError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23514":
+			r.logger.Debug("stock is not enough", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrInsufficientStock)
+		case "23503":
+			r.logger.Debug("warehouse not found", "op", op)
+			return fmt.Errorf("%s: warehouse: %w", op, crud_errors.ErrNotFound)
+		}
+	}
+
+	r.logger.Error("execute sql statement for change warehouse stock is unable", logger.Err(err), "op", op)
+	return fmt.Errorf("%s: failed exec query: %v", op, err)
+}
+
+// ChangeStock applies the movement to the stock of the product and records it,
+// the stock before and after the change are filled in. The stock cannot become
+// negative, a restock also sets the delivery date. A non-zero version is the
+// expected version. The product is flagged as low on stock when the stock is at
+// or below its reorder threshold and unflagged otherwise, the item is returned
+// when the change flags it.
+//
+// A movement with a warehouse changes the stock in the warehouse and the
+// product stock becomes the sum of its warehouse stocks. The stock of a product
+// not stocked in warehouses yet is taken as the stock of the first warehouse.
+// A product stocked in warehouses requires the warehouse.
+func (r *ProductRepo) ChangeStock(ctx context.Context, movement *domain.StockMovement, version int64) (*domain.LowStockItem, error) {
+	op := "repository.postgres.productRepository.ChangeStock"
+
+	state, err := r.lockStock(ctx, op, movement.ProductId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	parentId := state.parentId
+
+	if state.stocked && movement.WarehouseId == nil {
+		r.logger.Debug("stock change without a warehouse", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrWarehouseRequired)
+	}
+
+	if movement.WarehouseId != nil {
+		var unplaced int64
+		if !state.stocked {
+			unplaced = state.stock
+		}
+
+		sqlWarehouse := `INSERT INTO warehouse_stock AS ws (warehouse_id, product_id, quantity)
+			VALUES (@warehouse_id, @id, CASE @operation::TEXT
+				WHEN 'set' THEN @quantity::BIGINT
+				WHEN 'increase' THEN @unplaced::BIGINT + @quantity::BIGINT
+				ELSE @unplaced::BIGINT - @quantity::BIGINT
+			END)
+			ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = CASE @operation::TEXT
+				WHEN 'set' THEN @quantity::BIGINT
+				WHEN 'increase' THEN ws.quantity + @quantity::BIGINT
+				ELSE ws.quantity - @quantity::BIGINT
+			END`
+		args := pgx.NamedArgs{
+			"warehouse_id": movement.WarehouseId,
+			"id":           movement.ProductId,
+			"operation":    string(movement.Operation),
+			"quantity":     movement.Quantity,
+			"unplaced":     unplaced,
+		}
+
+		if _, err := r.db.Exec(ctx, sqlWarehouse, args); err != nil {
+			return nil, r.stockError(op, err)
+		}
+	}
+
+	sqlStatement := `WITH current AS (
+			SELECT id, available_stock, low_stock_since,
+				CASE
+					WHEN @warehouse_id::UUID IS NOT NULL THEN (SELECT COALESCE(SUM(quantity), 0) FROM warehouse_stock WHERE product_id = @id)
+					WHEN @operation::TEXT = 'set' THEN @quantity::BIGINT
+					WHEN @operation::TEXT = 'increase' THEN available_stock + @quantity::BIGINT
+					ELSE available_stock - @quantity::BIGINT
+				END AS new_stock
+			FROM product WHERE id = @id
+		), changed AS (
+			UPDATE product p SET
+				available_stock = current.new_stock,
+				low_stock_since = CASE WHEN current.new_stock <= p.reorder_threshold THEN COALESCE(p.low_stock_since, NOW()) END,
+				last_delivery_date = CASE WHEN @reason::TEXT = 'restock' THEN NOW() ELSE p.last_delivery_date END,
+				last_update_date = NOW(),
+				version = p.version + 1
+			FROM current
+			WHERE p.id = current.id AND (@version = 0 OR p.version = @version)
+			RETURNING p.id, current.available_stock AS stock_before, p.available_stock AS stock_after,
+				current.low_stock_since IS NULL AND p.low_stock_since IS NOT NULL AS flagged,
+				p.name, COALESCE(p.sku, '') AS sku, COALESCE(p.barcode, '') AS barcode, p.supplier_id,
+				p.reorder_threshold, p.reorder_target, p.low_stock_since
+		), recorded AS (
+			INSERT INTO stock_movement(product_id, operation, quantity, reason, note, stock_before, stock_after, warehouse_id)
+			SELECT id, @operation, @quantity, @reason, @note, stock_before, stock_after, @warehouse_id FROM changed
+			RETURNING id, created_at
+		)
+		SELECT recorded.id, changed.stock_before, changed.stock_after, recorded.created_at, changed.flagged,
+			changed.name, changed.sku, changed.barcode, changed.supplier_id,
+			COALESCE(changed.reorder_threshold, 0), COALESCE(changed.reorder_target, 0), changed.low_stock_since
+		FROM recorded, changed`
+	args := pgx.NamedArgs{
+		"id":           movement.ProductId,
+		"operation":    string(movement.Operation),
+		"quantity":     movement.Quantity,
+		"reason":       movement.Reason,
+		"note":         movement.Note,
+		"warehouse_id": movement.WarehouseId,
+		"version":      version,
+	}
+
+	var (
+		item    domain.LowStockItem
+		flagged bool
+	)
+
+	err = r.db.QueryRow(ctx, sqlStatement, args).Scan(
+		&movement.Id,
+		&movement.StockBefore,
+		&movement.StockAfter,
+		&movement.CreatedAt,
+		&flagged,
+		&item.Name,
+		&item.Sku,
+		&item.Barcode,
+		&item.Supplier.Id,
+		&item.Reorder.Threshold,
+		&item.Reorder.Target,
+		&item.LowSince,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missingProduct(ctx, op, movement.ProductId, version)
+	}
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			r.logger.Debug("stock is not enough", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInsufficientStock)
+		}
+
+		r.logger.Error("execute sql statement for change stock is unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	if parentId != nil {
+		if err := r.refreshStock(ctx, *parentId); err != nil {
+			r.logger.Error("failed to refresh parent stock", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: %v", op, err)
+		}
+	}
+
+	if !flagged {
+		return nil, nil
+	}
+
+	item.ProductId = movement.ProductId
+	item.ParentId = parentId
+	item.AvailableStock = movement.StockAfter
+
+	return &item, nil
+}
+
+// SetReorder sets the reorder policy of the product, nil removes it. The low
+// stock flag is kept only while the stock is still at or below the threshold.
+// A non-zero version is the expected version.
+func (r *ProductRepo) SetReorder(ctx context.Context, id uuid.UUID, policy *domain.ReorderPolicy, version int64) error {
+	op := "repository.postgres.productRepository.SetReorder"
+
+	var hasVariants bool
+
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM product v WHERE v.parent_id = @id)`,
+		pgx.NamedArgs{"id": id}).Scan(&hasVariants)
+	if err != nil {
+		r.logger.Error("failed to check product variants", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if hasVariants {
+		r.logger.Debug("reorder policy of a product with variants", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrVariantStock)
+	}
+
+	sqlStatement := `UPDATE product SET
+		reorder_threshold = @threshold,
+		reorder_target = @target,
+		low_stock_since = CASE WHEN available_stock <= @threshold::INT THEN low_stock_since END,
+		last_update_date = NOW(),
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)`
+	args := pgx.NamedArgs{
+		"id":        id,
+		"threshold": nil,
+		"target":    nil,
+		"version":   version,
+	}
+
+	if policy != nil {
+		args["threshold"] = policy.Threshold
+		args["target"] = policy.Target
 	}
 
 	tag, err := r.db.Exec(ctx, sqlStatement, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			r.logger.Debug("reorder policy is rejected", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+		}
+
+		r.logger.Error("execute sql statement for set reorder policy is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
 	if tag.RowsAffected() == 0 {
-		r.logger.Debug("product not found", "op", op)
-		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+		return r.missingProduct(ctx, op, id, version)
+	}
+
+	return nil
+}
+
+// TransferStock moves the quantity of the movement from its warehouse to its
+// destination warehouse and records it. The product stock is kept, the
+// version of the product is incremented. A non-zero version is the expected
+// version.
+func (r *ProductRepo) TransferStock(ctx context.Context, movement *domain.StockMovement, version int64) error {
+	op := "repository.postgres.productRepository.TransferStock"
+
+	if _, err := r.lockStock(ctx, op, movement.ProductId, version); err != nil {
+		return err
+	}
+
+	sqlStatement := `UPDATE product SET
+		last_update_date = NOW(),
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING available_stock`
+	args := pgx.NamedArgs{
+		"id":        movement.ProductId,
+		"from":      movement.WarehouseId,
+		"to":        movement.ToWarehouseId,
+		"operation": string(movement.Operation),
+		"quantity":  movement.Quantity,
+		"reason":    movement.Reason,
+		"note":      movement.Note,
+		"version":   version,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&movement.StockBefore)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missingProduct(ctx, op, movement.ProductId, version)
 	}
 
 	if err != nil {
-		r.logger.Error("execute sql statement for update stock is unable", logger.Err(err), "op", op)
+		r.logger.Error("execute sql statement for transfer stock is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	movement.StockAfter = movement.StockBefore
+
+	var warehouses int
+
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM warehouse WHERE id IN (@from, @to)`, args).Scan(&warehouses)
+	if err != nil {
+		r.logger.Error("failed to check warehouses", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if warehouses != 2 {
+		r.logger.Debug("warehouse not found", "op", op)
+		return fmt.Errorf("%s: warehouse: %w", op, crud_errors.ErrNotFound)
+	}
+
+	tag, err := r.db.Exec(ctx, `UPDATE warehouse_stock SET quantity = quantity - @quantity
+		WHERE warehouse_id = @from AND product_id = @id`, args)
+	if err != nil {
+		return r.stockError(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		r.logger.Debug("product is not stocked in the source warehouse", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrInsufficientStock)
+	}
+
+	_, err = r.db.Exec(ctx, `INSERT INTO warehouse_stock AS ws (warehouse_id, product_id, quantity)
+		VALUES (@to, @id, @quantity)
+		ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = ws.quantity + EXCLUDED.quantity`, args)
+	if err != nil {
+		return r.stockError(op, err)
+	}
+
+	sqlInsert := `INSERT INTO stock_movement(product_id, operation, quantity, reason, note, stock_before, stock_after, warehouse_id, to_warehouse_id)
+		VALUES (@id, @operation, @quantity, @reason, @note, @stock, @stock, @from, @to)
+		RETURNING id, created_at`
+	args["stock"] = movement.StockBefore
+
+	if err := r.db.QueryRow(ctx, sqlInsert, args).Scan(&movement.Id, &movement.CreatedAt); err != nil {
+		r.logger.Error("failed to record stock transfer", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: failed exec query: %v", op, err)
 	}
 
 	return nil
 }
 
-func (r *ProductRepo) Delete(ctx context.Context, id uuid.UUID) error {
+// GetWarehouseStock returns the stock of the product in every warehouse it is
+// stocked in ordered by warehouse name, the list is empty for a product not
+// stocked in warehouses.
+func (r *ProductRepo) GetWarehouseStock(ctx context.Context, productId uuid.UUID) ([]domain.WarehouseStock, error) {
+	op := "repository.postgres.productRepository.GetWarehouseStock"
+	sqlStatement := `SELECT w.id, w.name, p.id, p.name, COALESCE(p.sku, ''), ws.quantity
+		FROM product p
+		LEFT JOIN warehouse_stock ws ON ws.product_id = p.id
+		LEFT JOIN warehouse w ON w.id = ws.warehouse_id
+		WHERE p.id = @id
+		ORDER BY w.name`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"id": productId})
+	if err != nil {
+		r.logger.Error("query unvalable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		found bool
+		stock = []domain.WarehouseStock{}
+	)
+
+	for rows.Next() {
+		var (
+			item        domain.WarehouseStock
+			warehouseId *uuid.UUID
+			name        *string
+			quantity    *int64
+		)
+
+		if err := rows.Scan(&warehouseId, &name, &item.ProductId, &item.ProductName, &item.Sku, &quantity); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan error: %v", op, err)
+		}
+
+		found = true
+
+		// the outer join gives one row without a warehouse to a product not
+		// stocked in warehouses
+		if warehouseId == nil {
+			continue
+		}
+
+		item.WarehouseId, item.WarehouseName, item.Quantity = *warehouseId, *name, *quantity
+		stock = append(stock, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if !found {
+		r.logger.Debug("product not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return stock, nil
+}
+
+// GetStockMovements returns changes of the product stock, the latest first.
+func (r *ProductRepo) GetStockMovements(ctx context.Context, productId uuid.UUID, limit, offset int) ([]domain.StockMovement, error) {
+	op := "repository.postgres.productRepository.GetStockMovements"
+	sqlStatement := `SELECT id, product_id, operation, quantity, reason, note, stock_before, stock_after,
+			warehouse_id, to_warehouse_id, created_at
+		FROM stock_movement
+		WHERE product_id = @product_id
+		ORDER BY created_at DESC, id
+		LIMIT @limit OFFSET @offset`
+	args := pgx.NamedArgs{
+		"product_id": productId,
+		"limit":      limit,
+		"offset":     offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("query unvalable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var movements []domain.StockMovement
+
+	for rows.Next() {
+		var movement domain.StockMovement
+
+		err := rows.Scan(
+			&movement.Id,
+			&movement.ProductId,
+			&movement.Operation,
+			&movement.Quantity,
+			&movement.Reason,
+			&movement.Note,
+			&movement.StockBefore,
+			&movement.StockAfter,
+			&movement.WarehouseId,
+			&movement.ToWarehouseId,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan error: %v", op, err)
+		}
+
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("rows iteration failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(movements) == 0 {
+		r.logger.Debug("stock movements not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return movements, nil
+}
+
+// missingProduct explains why no product row is changed: the version is
+// changed or the product does not exist.
+func (r *ProductRepo) missingProduct(ctx context.Context, op string, id uuid.UUID, version int64) error {
+	changed, err := r.versionChanged(ctx, "product", id, version)
+	if err != nil {
+		r.logger.Error("failed to check product version", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed to check version: %v", op, err)
+	}
+
+	if changed {
+		r.logger.Debug("product version mismatch", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+	}
+
+	r.logger.Debug("product not found", "op", op)
+	return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+}
+
+// Delete removes the product, a non-zero version is the expected version.
+func (r *ProductRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "repository.postgres.productRepository.Delete"
-	sqlStatement := "DELETE FROM product WHERE id=@id"
+	sqlStatement := "DELETE FROM product WHERE id=@id AND (@version = 0 OR version = @version) RETURNING parent_id"
 	arg := pgx.NamedArgs{
-		"id": id,
+		"id":      id,
+		"version": version,
 	}
 
-	_, err := r.db.Exec(ctx, sqlStatement, arg)
-	if err != nil {
+	var parentId *uuid.UUID
+
+	err := r.db.QueryRow(ctx, sqlStatement, arg).Scan(&parentId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		r.logger.Error("execute sql statement for delete product is unable", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: %v", op, err)
 	}
 
+	if parentId != nil {
+		if err := r.refreshStock(ctx, *parentId); err != nil {
+			r.logger.Error("failed to refresh parent stock", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %v", op, err)
+		}
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "product", id, version)
+		if err != nil {
+			r.logger.Error("failed to check product version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("product version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
 	return nil
 }
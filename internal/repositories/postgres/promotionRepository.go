@@ -0,0 +1,463 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// promotionColumns are scanned by promotionTargets.
+const promotionColumns = `p.id,
+		p.name,
+		p.kind,
+		p.value,
+		p.product_id,
+		p.category_id,
+		p.supplier_id,
+		p.min_quantity,
+		p.starts_at,
+		p.ends_at,
+		p.usage_limit,
+		p.per_client_limit,
+		COALESCE(p.coupon_code, ''),
+		p.active,
+		p.version,
+		p.created_at`
+
+func promotionTargets(promotion *domain.Promotion) []any {
+	return []any{
+		&promotion.Id,
+		&promotion.Name,
+		&promotion.Kind,
+		&promotion.Value,
+		&promotion.ProductId,
+		&promotion.CategoryId,
+		&promotion.SupplierId,
+		&promotion.MinQuantity,
+		&promotion.StartsAt,
+		&promotion.EndsAt,
+		&promotion.UsageLimit,
+		&promotion.PerClientLimit,
+		&promotion.CouponCode,
+		&promotion.Active,
+		&promotion.Version,
+		&promotion.CreatedAt,
+	}
+}
+
+type PromotionRepo struct {
+	*basePostgresRepository
+}
+
+func NewPromotionRepository(db DB, logger *logger.Logger) *PromotionRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres promotion repository is created")
+	return &PromotionRepo{
+		repo,
+	}
+}
+
+// Create inserts the promotion. ErrDuplicateKeyValue is returned for a taken
+// coupon code and ErrNotFound when the scope refers to a missing entity.
+func (r *PromotionRepo) Create(ctx context.Context, promotion *domain.Promotion) error {
+	op := "repository.postgres.promotionRepository.Create"
+	sqlStatement := `INSERT INTO promotion(name, kind, value, product_id, category_id, supplier_id, min_quantity,
+			starts_at, ends_at, usage_limit, per_client_limit, coupon_code, active)
+		VALUES (@name, @kind, @value, @product_id, @category_id, @supplier_id, @min_quantity,
+			@starts_at, @ends_at, @usage_limit, @per_client_limit, NULLIF(@coupon_code, ''), @active)
+		RETURNING id, version, created_at;`
+	args := pgx.NamedArgs{
+		"name":             promotion.Name,
+		"kind":             promotion.Kind,
+		"value":            promotion.Value,
+		"product_id":       promotion.ProductId,
+		"category_id":      promotion.CategoryId,
+		"supplier_id":      promotion.SupplierId,
+		"min_quantity":     promotion.MinQuantity,
+		"starts_at":        promotion.StartsAt,
+		"ends_at":          promotion.EndsAt,
+		"usage_limit":      promotion.UsageLimit,
+		"per_client_limit": promotion.PerClientLimit,
+		"coupon_code":      promotion.CouponCode,
+		"active":           promotion.Active,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&promotion.Id, &promotion.Version, &promotion.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				r.logger.Debug("Duplicate coupon code", "op", op)
+				return fmt.Errorf("%s: unable to insert row: %w", op, crud_errors.ErrDuplicateKeyValue)
+			case "23503":
+				r.logger.Debug("promotion scope not found", "op", op)
+				return fmt.Errorf("%s: scope: %w", op, crud_errors.ErrNotFound)
+			}
+		}
+
+		r.logger.Error("failed to create promotion", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
+
+// GetAll returns a page of promotions, the latest first.
+func (r *PromotionRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Promotion, error) {
+	op := "repository.postgres.promotionRepository.GetAll"
+	sqlStatement := `SELECT
+		` + promotionColumns + `
+		FROM promotion p
+		ORDER BY p.created_at DESC, p.id
+		LIMIT @limit OFFSET @offset;`
+	args := pgx.NamedArgs{
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get promotions", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var promotions []domain.Promotion
+
+	for rows.Next() {
+		var promotion domain.Promotion
+
+		if err := rows.Scan(promotionTargets(&promotion)...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get promotions", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(promotions) == 0 {
+		r.logger.Debug("promotions not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	op := "repository.postgres.promotionRepository.GetById"
+	sqlStatement := `SELECT
+		` + promotionColumns + `
+		FROM promotion p
+		WHERE p.id = @id;`
+
+	var promotion domain.Promotion
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(promotionTargets(&promotion)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("promotion not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return &promotion, nil
+}
+
+// Update rewrites the changeable fields of the promotion and increments its
+// version. A non-zero promotion.Version is the expected version, the new one
+// is set back.
+func (r *PromotionRepo) Update(ctx context.Context, promotion *domain.Promotion) error {
+	op := "repository.postgres.promotionRepository.Update"
+	sqlStatement := `UPDATE promotion SET
+		name = @name,
+		active = @active,
+		starts_at = @starts_at,
+		ends_at = @ends_at,
+		usage_limit = @usage_limit,
+		per_client_limit = @per_client_limit,
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
+	args := pgx.NamedArgs{
+		"id":               promotion.Id,
+		"name":             promotion.Name,
+		"active":           promotion.Active,
+		"starts_at":        promotion.StartsAt,
+		"ends_at":          promotion.EndsAt,
+		"usage_limit":      promotion.UsageLimit,
+		"per_client_limit": promotion.PerClientLimit,
+		"version":          promotion.Version,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&promotion.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "promotion", promotion.Id, promotion.Version)
+		if err != nil {
+			r.logger.Error("failed to check promotion version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("promotion version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+
+		r.logger.Debug("promotion not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+// Delete removes the promotion with its redemptions, orders keep their
+// discounts. A non-zero version must match the promotion version.
+func (r *PromotionRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "repository.postgres.promotionRepository.Delete"
+	arg := pgx.NamedArgs{
+		"id":      id,
+		"version": version,
+	}
+
+	tag, err := r.db.Exec(ctx, "DELETE FROM promotion WHERE id = @id AND (@version = 0 OR version = @version)", arg)
+	if err != nil {
+		r.logger.Error("execute sql statement is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "promotion", id, version)
+		if err != nil {
+			r.logger.Error("failed to check promotion version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("promotion version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
+	return nil
+}
+
+// GetProducts returns the products by id with the ancestors of their
+// categories. ErrNotFound is returned when a product does not exist.
+func (r *PromotionRepo) GetProducts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]domain.PromotionProduct, error) {
+	op := "repository.postgres.promotionRepository.GetProducts"
+	sqlStatement := `WITH RECURSIVE ancestors AS (
+			SELECT p.id AS product_id, c.id, c.parent_id
+			FROM product p
+			JOIN category c ON c.id = p.category_id
+			WHERE p.id = ANY(@ids)
+			UNION
+			SELECT a.product_id, c.id, c.parent_id FROM category c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT p.id, p.parent_id, p.name, p.price, p.supplier_id, a.id
+		FROM product p
+		JOIN ancestors a ON a.product_id = p.id`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		r.logger.Error("failed to get promotion products", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	products := make(map[uuid.UUID]domain.PromotionProduct, len(ids))
+
+	for rows.Next() {
+		var (
+			product    domain.PromotionProduct
+			categoryId uuid.UUID
+		)
+
+		if err := rows.Scan(&product.Id, &product.ParentId, &product.Name, &product.Price, &product.SupplierId, &categoryId); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		if known, ok := products[product.Id]; ok {
+			product.CategoryIds = known.CategoryIds
+		}
+
+		product.CategoryIds = append(product.CategoryIds, categoryId)
+		products[product.Id] = product
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get promotion products", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	for _, id := range ids {
+		if _, ok := products[id]; !ok {
+			r.logger.Debug("product not found", "product_id", id, "op", op)
+			return nil, fmt.Errorf("%s: product %s: %w", op, id, crud_errors.ErrNotFound)
+		}
+	}
+
+	return products, nil
+}
+
+// GetCandidates returns the running automatic promotions and the promotions of
+// the coupon codes in any state with their usage, the usage by the client when
+// it is set. ErrNotFound is returned when the client does not exist. With lock
+// the limited promotions are locked until the end of the transaction, so the
+// usage cannot change before the redemption is recorded.
+func (r *PromotionRepo) GetCandidates(ctx context.Context, codes []string, clientId *uuid.UUID, lock bool) ([]domain.PromotionCandidate, error) {
+	op := "repository.postgres.promotionRepository.GetCandidates"
+
+	if clientId != nil {
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM client WHERE id = @id)`, pgx.NamedArgs{"id": clientId}).Scan(&exists); err != nil {
+			r.logger.Error("failed to check client", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: failed to check client: %v", op, err)
+		}
+
+		if !exists {
+			r.logger.Debug("client not found", "op", op)
+			return nil, fmt.Errorf("%s: client: %w", op, crud_errors.ErrNotFound)
+		}
+	}
+
+	sqlStatement := `SELECT
+		` + promotionColumns + `,
+		(p.starts_at IS NULL OR p.starts_at <= now()),
+		(p.ends_at IS NOT NULL AND p.ends_at <= now())
+		FROM promotion p
+		WHERE p.coupon_code = ANY(@codes)
+			OR (p.coupon_code IS NULL AND p.active
+				AND (p.starts_at IS NULL OR p.starts_at <= now())
+				AND (p.ends_at IS NULL OR p.ends_at > now()))
+		ORDER BY p.created_at, p.id`
+
+	rows, err := r.db.Query(ctx, sqlStatement, pgx.NamedArgs{"codes": codes})
+	if err != nil {
+		r.logger.Error("failed to get promotion candidates", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		candidates []domain.PromotionCandidate
+		ids        []uuid.UUID
+		limited    []uuid.UUID
+	)
+
+	for rows.Next() {
+		var candidate domain.PromotionCandidate
+
+		if err := rows.Scan(append(promotionTargets(&candidate.Promotion), &candidate.Started, &candidate.Ended)...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		candidates = append(candidates, candidate)
+		ids = append(ids, candidate.Id)
+		if candidate.UsageLimit != nil || candidate.PerClientLimit != nil {
+			limited = append(limited, candidate.Id)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get promotion candidates", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(limited) == 0 {
+		return candidates, nil
+	}
+
+	if lock {
+		// locked in one order to avoid deadlocks of concurrent checkouts
+		_, err := r.db.Exec(ctx, `SELECT id FROM promotion WHERE id = ANY(@ids) ORDER BY id FOR UPDATE`, pgx.NamedArgs{"ids": limited})
+		if err != nil {
+			r.logger.Error("failed to lock promotions", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: lock failed: %v", op, err)
+		}
+	}
+
+	usageStatement := `SELECT promotion_id, COUNT(*), COUNT(*) FILTER (WHERE client_id = @client_id)
+		FROM promotion_redemption
+		WHERE promotion_id = ANY(@ids)
+		GROUP BY promotion_id`
+	args := pgx.NamedArgs{
+		"ids":       ids,
+		"client_id": clientId,
+	}
+
+	usageRows, err := r.db.Query(ctx, usageStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get promotion usage", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer usageRows.Close()
+
+	index := make(map[uuid.UUID]int, len(candidates))
+	for i, candidate := range candidates {
+		index[candidate.Id] = i
+	}
+
+	for usageRows.Next() {
+		var (
+			id                 uuid.UUID
+			used, usedByClient int64
+		)
+
+		if err := usageRows.Scan(&id, &used, &usedByClient); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		candidate := &candidates[index[id]]
+		candidate.Used, candidate.UsedByClient = used, usedByClient
+	}
+
+	if err := usageRows.Err(); err != nil {
+		r.logger.Error("failed to get promotion usage", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return candidates, nil
+}
+
+// Redeem records the promotion applied to the order.
+func (r *PromotionRepo) Redeem(ctx context.Context, redemption domain.PromotionRedemption) error {
+	op := "repository.postgres.promotionRepository.Redeem"
+	sqlStatement := `INSERT INTO promotion_redemption(promotion_id, client_id, order_id, discount)
+		VALUES (@promotion_id, @client_id, @order_id, @discount)`
+	args := pgx.NamedArgs{
+		"promotion_id": redemption.PromotionId,
+		"client_id":    redemption.ClientId,
+		"order_id":     redemption.OrderId,
+		"discount":     redemption.Discount,
+	}
+
+	if _, err := r.db.Exec(ctx, sqlStatement, args); err != nil {
+		r.logger.Error("failed to record redemption", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
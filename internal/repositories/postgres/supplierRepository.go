@@ -13,6 +13,29 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// supplierColumns are the supplier profile columns, scanned by supplierTargets.
+const supplierColumns = `s.id,
+		s.name,
+		s.phone_number,
+		s.contact_person,
+		s.email,
+		s.website,
+		s.tax_id,
+		s.version`
+
+func supplierTargets(supplier *domain.Supplier) []any {
+	return []any{
+		&supplier.Id,
+		&supplier.Name,
+		&supplier.PhoneNumber,
+		&supplier.ContactPerson,
+		&supplier.Email,
+		&supplier.Website,
+		&supplier.TaxId,
+		&supplier.Version,
+	}
+}
+
 type SupplierRepo struct {
 	*basePostgresRepository
 }
@@ -27,13 +50,16 @@ func NewSupplierRepository(db DB, logger *logger.Logger) *SupplierRepo {
 
 func (r *SupplierRepo) Create(ctx context.Context, supplier *domain.Supplier) error {
 	op := "repository.postgres.supplierRepository.Create"
-	sqlStatement := `INSERT INTO supplier(name, address_id, phone_number) 
-					 VALUES (@name, @address_id, @phone_number)
+	sqlStatement := `INSERT INTO supplier(name, phone_number, contact_person, email, website, tax_id)
+					 VALUES (@name, @phone_number, @contact_person, @email, @website, @tax_id)
 					 RETURNING id;`
 	args := pgx.NamedArgs{
-		"name":         supplier.Name,
-		"address_id":   supplier.Address.Id,
-		"phone_number": supplier.PhoneNumber,
+		"name":           supplier.Name,
+		"phone_number":   supplier.PhoneNumber,
+		"contact_person": supplier.ContactPerson,
+		"email":          supplier.Email,
+		"website":        supplier.Website,
+		"tax_id":         supplier.TaxId,
 	}
 
 	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&supplier.Id)
@@ -54,15 +80,10 @@ func (r *SupplierRepo) Create(ctx context.Context, supplier *domain.Supplier) er
 func (r *SupplierRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Supplier, error) {
 	op := "repository.postgres.supplierRepository.GetAll"
 	sqlStatement := `SELECT
-		s.id,
-		s.name,
-		s.phone_number,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		` + supplierColumns + `,
+		` + addressColumns + `
 		FROM supplier s
-		LEFT JOIN address a ON s.address_id = a.id
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
 		LIMIT @limit OFFSET @offset;`
 	args := pgx.NamedArgs{
 		"limit":  limit,
@@ -79,24 +100,21 @@ func (r *SupplierRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.
 	var suppliers []domain.Supplier
 
 	for rows.Next() {
-		supplier := domain.Supplier{
-			Address: &domain.Address{},
-		}
-
-		err := rows.Scan(
-			&supplier.Id,
-			&supplier.Name,
-			&supplier.PhoneNumber,
-			&supplier.Address.Id,
-			&supplier.Address.Country,
-			&supplier.Address.City,
-			&supplier.Address.Street,
+		var (
+			supplier domain.Supplier
+			address  nullableAddress
 		)
+
+		targets := append(supplierTargets(&supplier), address.targets()...)
+
+		err := rows.Scan(targets...)
 		if err != nil {
 			r.logger.Warn("failed binding data", logger.Err(err), "op", op)
 			continue
 		}
 
+		supplier.Address = address.toDomain()
+
 		suppliers = append(suppliers, supplier)
 	}
 
@@ -109,36 +127,28 @@ func (r *SupplierRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.
 }
 
 func (r *SupplierRepo) GetByName(ctx context.Context, name string) (*domain.Supplier, error) {
-	op := "repository.postgres.supplierRepository.GetById"
+	op := "repository.postgres.supplierRepository.GetByName"
 	sqlStatement := `SELECT
-		s.id,
-		s.name,
-		s.phone_number,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		` + supplierColumns + `,
+		` + addressColumns + `
 		FROM supplier s
-		LEFT JOIN address a ON s.address_id = a.id
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
 		WHERE s.name = @name;`
 	arg := pgx.NamedArgs{
 		"name": name,
 	}
 
 	row := r.db.QueryRow(ctx, sqlStatement, arg)
-	supplier := domain.Supplier{
-		Address: &domain.Address{},
-	}
-	err := row.Scan(
-		&supplier.Id,
-		&supplier.Name,
-		&supplier.PhoneNumber,
-		&supplier.Address.Id,
-		&supplier.Address.Country,
-		&supplier.Address.City,
-		&supplier.Address.Street,
+
+	var (
+		supplier domain.Supplier
+		address  nullableAddress
 	)
 
+	targets := append(supplierTargets(&supplier), address.targets()...)
+
+	err := row.Scan(targets...)
+
 	if errors.Is(err, pgx.ErrNoRows) {
 		r.logger.Debug("supplier not found", "op", op)
 		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
@@ -149,40 +159,42 @@ func (r *SupplierRepo) GetByName(ctx context.Context, name string) (*domain.Supp
 		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
 	}
 
+	supplier.Address = address.toDomain()
+
+	book, err := selectAddressBook(ctx, r.db, supplierLocationBook, []uuid.UUID{supplier.Id})
+	if err != nil {
+		r.logger.Error("failed to get locations", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	supplier.Locations = book[supplier.Id]
+
 	return &supplier, nil
 }
 
 func (r *SupplierRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Supplier, error) {
 	op := "repository.postgres.supplierRepository.GetById"
 	sqlStatement := `SELECT
-		s.id,
-		s.name,
-		s.phone_number,
-		a.id,
-		a.country,
-		a.city,
-		a.street
+		` + supplierColumns + `,
+		` + addressColumns + `
 		FROM supplier s
-		LEFT JOIN address a ON s.address_id = a.id
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
 		WHERE s.id = @id;`
 	arg := pgx.NamedArgs{
 		"id": id,
 	}
 
 	row := r.db.QueryRow(ctx, sqlStatement, arg)
-	supplier := domain.Supplier{
-		Address: &domain.Address{},
-	}
-	err := row.Scan(
-		&supplier.Id,
-		&supplier.Name,
-		&supplier.PhoneNumber,
-		&supplier.Address.Id,
-		&supplier.Address.Country,
-		&supplier.Address.City,
-		&supplier.Address.Street,
+
+	var (
+		supplier domain.Supplier
+		address  nullableAddress
 	)
 
+	targets := append(supplierTargets(&supplier), address.targets()...)
+
+	err := row.Scan(targets...)
+
 	if errors.Is(err, pgx.ErrNoRows) {
 		r.logger.Debug("supplier not found", "op", op)
 		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
@@ -193,26 +205,69 @@ func (r *SupplierRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Suppl
 		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
 	}
 
+	supplier.Address = address.toDomain()
+
+	book, err := selectAddressBook(ctx, r.db, supplierLocationBook, []uuid.UUID{supplier.Id})
+	if err != nil {
+		r.logger.Error("failed to get locations", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	supplier.Locations = book[supplier.Id]
+
 	return &supplier, nil
 }
 
-func (r *SupplierRepo) Update(ctx context.Context, id, address uuid.UUID) error {
-	op := "repositories.postgres.supplierRepository.Update"
-	sqlStatement := "UPDATE supplier SET address_id=@address_id WHERE id=@id"
-	arg := pgx.NamedArgs{
-		"id":         id,
-		"address_id": address,
+// Update rewrites the supplier profile and increments its version, the
+// address is stored separately. A non-zero supplier.Version is the expected
+// version, the new one is set back.
+func (r *SupplierRepo) Update(ctx context.Context, supplier *domain.Supplier) error {
+	op := "repository.postgres.supplierRepository.Update"
+	sqlStatement := `UPDATE supplier SET
+		name = @name,
+		phone_number = @phone_number,
+		contact_person = @contact_person,
+		email = @email,
+		website = @website,
+		tax_id = @tax_id,
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
+	args := pgx.NamedArgs{
+		"id":             supplier.Id,
+		"name":           supplier.Name,
+		"phone_number":   supplier.PhoneNumber,
+		"contact_person": supplier.ContactPerson,
+		"email":          supplier.Email,
+		"website":        supplier.Website,
+		"tax_id":         supplier.TaxId,
+		"version":        supplier.Version,
 	}
 
-	r.logger.Debug("parameter", "id", id, "address_id", address)
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&supplier.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "supplier", supplier.Id, supplier.Version)
+		if err != nil {
+			r.logger.Error("failed to check supplier version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("supplier version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
 
-	tag, err := r.db.Exec(ctx, sqlStatement, arg)
-	if tag.RowsAffected() == 0 {
 		r.logger.Debug("supplier not found", "op", op)
 		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
 	}
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate supplier name", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
 		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: failed exec query: %v", op, err)
 	}
@@ -220,18 +275,179 @@ func (r *SupplierRepo) Update(ctx context.Context, id, address uuid.UUID) error
 	return nil
 }
 
-func (r *SupplierRepo) Delete(ctx context.Context, id uuid.UUID) error {
+// CopyFrom bulk inserts suppliers with assigned ids.
+func (r *SupplierRepo) CopyFrom(ctx context.Context, suppliers []domain.Supplier) (int64, error) {
+	op := "repository.postgres.supplierRepository.CopyFrom"
+	columns := []string{"id", "name", "phone_number", "contact_person", "email", "website", "tax_id"}
+
+	count, err := r.db.CopyFrom(ctx, pgx.Identifier{"supplier"}, columns, pgx.CopyFromSlice(len(suppliers), func(i int) ([]any, error) {
+		s := suppliers[i]
+		return []any{s.Id, s.Name, s.PhoneNumber, s.ContactPerson, s.Email, s.Website, s.TaxId}, nil
+	}))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate supplier name", "op", op)
+			return 0, fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		r.logger.Error("failed to copy suppliers", logger.Err(err), "op", op)
+		return 0, fmt.Errorf("%s: copy failed: %v", op, err)
+	}
+
+	return count, nil
+}
+
+// GetIdsByNames returns ids of the suppliers with given names by name.
+func (r *SupplierRepo) GetIdsByNames(ctx context.Context, names []string) (map[string]uuid.UUID, error) {
+	op := "repository.postgres.supplierRepository.GetIdsByNames"
+	rows, err := r.db.Query(ctx, `SELECT id, name FROM supplier WHERE name = ANY(@names)`, pgx.NamedArgs{"names": names})
+	if err != nil {
+		r.logger.Error("unable to query supplier", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	ids := make(map[string]uuid.UUID)
+
+	var (
+		id   uuid.UUID
+		name string
+	)
+
+	_, err = pgx.ForEachRow(rows, []any{&id, &name}, func() error {
+		ids[name] = id
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return ids, nil
+}
+
+// GetExistingIds returns which of the ids belong to suppliers.
+func (r *SupplierRepo) GetExistingIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	op := "repository.postgres.supplierRepository.GetExistingIds"
+	rows, err := r.db.Query(ctx, `SELECT id FROM supplier WHERE id = ANY(@ids)`, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		r.logger.Error("unable to query supplier", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	existing := make(map[uuid.UUID]bool)
+
+	var id uuid.UUID
+
+	_, err = pgx.ForEachRow(rows, []any{&id}, func() error {
+		existing[id] = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return existing, nil
+}
+
+// ForEach streams all suppliers with their default address ordered by name.
+func (r *SupplierRepo) ForEach(ctx context.Context, fn func(supplier domain.Supplier) error) error {
+	op := "repository.postgres.supplierRepository.ForEach"
+	sqlStatement := `SELECT
+		` + supplierColumns + `,
+		` + addressColumns + `
+		FROM supplier s
+		` + supplierLocationBook.defaultAddressJoin("s.id") + `
+		ORDER BY s.name;`
+
+	rows, err := r.db.Query(ctx, sqlStatement)
+	if err != nil {
+		r.logger.Error("unable to query supplier", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var (
+		supplier domain.Supplier
+		address  nullableAddress
+	)
+
+	_, err = pgx.ForEachRow(rows, append(supplierTargets(&supplier), address.targets()...), func() error {
+		supplier.Address = address.toDomain()
+		return fn(supplier)
+	})
+	if err != nil {
+		r.logger.Error("failed to stream suppliers", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetStats summarizes stock of the supplier products.
+func (r *SupplierRepo) GetStats(ctx context.Context, id uuid.UUID) (*domain.SupplierStats, error) {
+	op := "repository.postgres.supplierRepository.GetStats"
+	sqlStatement := `SELECT
+		COUNT(p.id),
+		COALESCE(SUM(p.available_stock), 0),
+		COALESCE(SUM(p.available_stock * p.price), 0),
+		COUNT(p.id) FILTER (WHERE p.available_stock = 0),
+		MAX(p.last_delivery_date)
+		FROM supplier s
+		LEFT JOIN product p ON p.supplier_id = s.id
+		WHERE s.id = @id
+		GROUP BY s.id;`
+
+	var stats domain.SupplierStats
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(
+		&stats.ProductCount,
+		&stats.TotalStockUnits,
+		&stats.TotalStockValue,
+		&stats.OutOfStockCount,
+		&stats.LastDeliveryAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("supplier not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	return &stats, nil
+}
+
+// Delete removes the supplier, a non-zero version is the expected version.
+func (r *SupplierRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "repository.postgres.supplierRepository.Delete"
-	sqlStatement := "DELETE FROM supplier WHERE id=@id"
+	sqlStatement := "DELETE FROM supplier WHERE id=@id AND (@version = 0 OR version = @version)"
 	arg := pgx.NamedArgs{
-		"id": id,
+		"id":      id,
+		"version": version,
 	}
 
-	_, err := r.db.Exec(ctx, sqlStatement, arg)
+	tag, err := r.db.Exec(ctx, sqlStatement, arg)
 	if err != nil {
 		r.logger.Error("execute sql statement is unable", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: %v", op, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "supplier", id, version)
+		if err != nil {
+			r.logger.Error("failed to check supplier version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("supplier version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
 	return nil
 }
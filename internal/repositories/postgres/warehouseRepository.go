@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// warehouseColumns are scanned by warehouseTargets followed by addressColumns.
+const warehouseColumns = `w.id,
+		w.name,
+		w.version,
+		w.created_at`
+
+func warehouseTargets(warehouse *domain.Warehouse) []any {
+	return []any{
+		&warehouse.Id,
+		&warehouse.Name,
+		&warehouse.Version,
+		&warehouse.CreatedAt,
+	}
+}
+
+// distanceKm is the great-circle distance in kilometres between the addresses
+// a and d by the haversine formula, NULL when either has no coordinates.
+const distanceKm = `2 * 6371 * asin(sqrt(
+		power(sin(radians(d.latitude - a.latitude) / 2), 2) +
+		cos(radians(a.latitude)) * cos(radians(d.latitude)) * power(sin(radians(d.longitude - a.longitude) / 2), 2)
+	))`
+
+type WarehouseRepo struct {
+	*basePostgresRepository
+}
+
+func NewWarehouseRepository(db DB, logger *logger.Logger) *WarehouseRepo {
+	repo := newBasePostgresRepository(db, logger)
+	logger.Debug("postgres warehouse repository is created")
+	return &WarehouseRepo{
+		repo,
+	}
+}
+
+// Create inserts the warehouse, its address has to be stored already.
+func (r *WarehouseRepo) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	op := "repository.postgres.warehouseRepository.Create"
+	sqlStatement := `INSERT INTO warehouse(name, address_id)
+		VALUES (@name, @address_id)
+		RETURNING id, version, created_at;`
+	args := pgx.NamedArgs{
+		"name":       warehouse.Name,
+		"address_id": warehouse.Address.Id,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&warehouse.Id, &warehouse.Version, &warehouse.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate creation", "op", op)
+			return fmt.Errorf("%s: unable to insert row: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		r.logger.Error("failed to create warehouse", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to insert row: %v", op, err)
+	}
+
+	return nil
+}
+
+// GetAll returns a page of warehouses ordered by name.
+func (r *WarehouseRepo) GetAll(ctx context.Context, limit, offset int) ([]domain.Warehouse, error) {
+	op := "repository.postgres.warehouseRepository.GetAll"
+	sqlStatement := `SELECT
+		` + warehouseColumns + `,
+		` + addressColumns + `
+		FROM warehouse w
+		JOIN address a ON a.id = w.address_id
+		ORDER BY w.name
+		LIMIT @limit OFFSET @offset;`
+	args := pgx.NamedArgs{
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get warehouses", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	var warehouses []domain.Warehouse
+
+	for rows.Next() {
+		var (
+			warehouse domain.Warehouse
+			address   nullableAddress
+		)
+
+		if err := rows.Scan(append(warehouseTargets(&warehouse), address.targets()...)...); err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		warehouse.Address = address.toDomain()
+		warehouses = append(warehouses, warehouse)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get warehouses", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	if len(warehouses) == 0 {
+		r.logger.Debug("warehouses not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return warehouses, nil
+}
+
+func (r *WarehouseRepo) GetById(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	op := "repository.postgres.warehouseRepository.GetById"
+	sqlStatement := `SELECT
+		` + warehouseColumns + `,
+		` + addressColumns + `
+		FROM warehouse w
+		JOIN address a ON a.id = w.address_id
+		WHERE w.id = @id;`
+
+	var (
+		warehouse domain.Warehouse
+		address   nullableAddress
+	)
+
+	err := r.db.QueryRow(ctx, sqlStatement, pgx.NamedArgs{"id": id}).Scan(append(warehouseTargets(&warehouse), address.targets()...)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Debug("warehouse not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		r.logger.Error("scan unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+	}
+
+	warehouse.Address = address.toDomain()
+
+	return &warehouse, nil
+}
+
+// Update rewrites the name and the address of the warehouse and increments its
+// version. A non-zero warehouse.Version is the expected version, the new one is
+// set back.
+func (r *WarehouseRepo) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	op := "repository.postgres.warehouseRepository.Update"
+	sqlStatement := `UPDATE warehouse SET
+		name = @name,
+		address_id = @address_id,
+		version = version + 1
+		WHERE id = @id AND (@version = 0 OR version = @version)
+		RETURNING version`
+	args := pgx.NamedArgs{
+		"id":         warehouse.Id,
+		"name":       warehouse.Name,
+		"address_id": warehouse.Address.Id,
+		"version":    warehouse.Version,
+	}
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&warehouse.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		changed, err := r.versionChanged(ctx, "warehouse", warehouse.Id, warehouse.Version)
+		if err != nil {
+			r.logger.Error("failed to check warehouse version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("warehouse version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+
+		r.logger.Debug("warehouse not found", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			r.logger.Debug("Duplicate warehouse name", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		r.logger.Error("failed execution update query", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
+
+// Delete removes the warehouse, a non-zero version is the expected version.
+// Empty stock rows are removed with it, a warehouse still holding stock is not
+// removed.
+func (r *WarehouseRepo) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "repository.postgres.warehouseRepository.Delete"
+	arg := pgx.NamedArgs{
+		"id":      id,
+		"version": version,
+	}
+
+	_, err := r.db.Exec(ctx, "DELETE FROM warehouse_stock WHERE warehouse_id = @id AND quantity = 0", arg)
+	if err != nil {
+		r.logger.Error("failed to delete empty warehouse stock", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	tag, err := r.db.Exec(ctx, "DELETE FROM warehouse WHERE id = @id AND (@version = 0 OR version = @version)", arg)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			r.logger.Debug("warehouse holds stock", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrForeignKeyViolation)
+		}
+
+		r.logger.Error("execute sql statement is unable", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		changed, err := r.versionChanged(ctx, "warehouse", id, version)
+		if err != nil {
+			r.logger.Error("failed to check warehouse version", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: failed to check version: %v", op, err)
+		}
+
+		if changed {
+			r.logger.Debug("warehouse version mismatch", "op", op)
+			return fmt.Errorf("%s: %w", op, crud_errors.ErrVersionMismatch)
+		}
+	}
+
+	return nil
+}
+
+// GetStock returns a page of products stocked in the warehouse ordered by
+// product name.
+func (r *WarehouseRepo) GetStock(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.WarehouseStock, error) {
+	op := "repository.postgres.warehouseRepository.GetStock"
+
+	var exists bool
+
+	err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM warehouse WHERE id = @id)`, pgx.NamedArgs{"id": id}).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check warehouse", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if !exists {
+		r.logger.Debug("warehouse not found", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	sqlStatement := `SELECT w.id, w.name, p.id, p.name, COALESCE(p.sku, ''), ws.quantity
+		FROM warehouse_stock ws
+		JOIN warehouse w ON w.id = ws.warehouse_id
+		JOIN product p ON p.id = ws.product_id
+		WHERE ws.warehouse_id = @id
+		ORDER BY p.name, p.id
+		LIMIT @limit OFFSET @offset`
+	args := pgx.NamedArgs{
+		"id":     id,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := r.db.Query(ctx, sqlStatement, args)
+	if err != nil {
+		r.logger.Error("failed to get warehouse stock", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+	defer rows.Close()
+
+	stock := []domain.WarehouseStock{}
+
+	for rows.Next() {
+		var item domain.WarehouseStock
+
+		err := rows.Scan(&item.WarehouseId, &item.WarehouseName, &item.ProductId, &item.ProductName, &item.Sku, &item.Quantity)
+		if err != nil {
+			r.logger.Error("scan unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: scan failed: %v", op, err)
+		}
+
+		stock = append(stock, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to get warehouse stock", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: rows error: %v", op, err)
+	}
+
+	return stock, nil
+}
+
+// Pick returns the warehouse a decrease of the product by the quantity is
+// taken from: a warehouse holding the whole quantity with the most stock, or
+// the nearest one to the destination address for the nearest strategy.
+// Warehouses without coordinates come after the located ones. Nil is returned
+// for a product not stocked in warehouses, ErrInsufficientStock when no
+// warehouse holds the quantity.
+func (r *WarehouseRepo) Pick(ctx context.Context, productId uuid.UUID, quantity int64, pick domain.WarehousePick) (*uuid.UUID, error) {
+	op := "repository.postgres.warehouseRepository.Pick"
+
+	if pick.DestinationId != nil {
+		var exists bool
+
+		err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM address WHERE id = @id)`,
+			pgx.NamedArgs{"id": pick.DestinationId}).Scan(&exists)
+		if err != nil {
+			r.logger.Error("failed to check destination address", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: query error: %v", op, err)
+		}
+
+		if !exists {
+			r.logger.Debug("destination address not found", "op", op)
+			return nil, fmt.Errorf("%s: destination address: %w", op, crud_errors.ErrNotFound)
+		}
+	}
+
+	sqlStatement := `SELECT ws.warehouse_id
+		FROM warehouse_stock ws
+		JOIN warehouse w ON w.id = ws.warehouse_id
+		JOIN address a ON a.id = w.address_id
+		LEFT JOIN address d ON d.id = @destination_id
+		WHERE ws.product_id = @product_id AND ws.quantity >= @quantity
+		ORDER BY CASE WHEN @strategy::TEXT = 'nearest' THEN ` + distanceKm + ` END NULLS LAST,
+			ws.quantity DESC, w.name
+		LIMIT 1`
+	args := pgx.NamedArgs{
+		"product_id":     productId,
+		"quantity":       quantity,
+		"strategy":       pick.Strategy,
+		"destination_id": pick.DestinationId,
+	}
+
+	var warehouseId uuid.UUID
+
+	err := r.db.QueryRow(ctx, sqlStatement, args).Scan(&warehouseId)
+	if err == nil {
+		return &warehouseId, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		r.logger.Error("failed to pick warehouse", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	var stocked bool
+
+	err = r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM warehouse_stock WHERE product_id = @product_id)`, args).Scan(&stocked)
+	if err != nil {
+		r.logger.Error("failed to check product warehouses", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: query error: %v", op, err)
+	}
+
+	if stocked {
+		r.logger.Debug("no warehouse holds the quantity", "quantity", quantity, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInsufficientStock)
+	}
+
+	return nil, nil
+}
+
+// Repoint moves warehouses from the address to another one.
+func (r *WarehouseRepo) Repoint(ctx context.Context, from, to uuid.UUID) error {
+	op := "repository.postgres.warehouseRepository.Repoint"
+	sqlStatement := `UPDATE warehouse SET address_id = @to WHERE address_id = @from`
+
+	if _, err := r.db.Exec(ctx, sqlStatement, pgx.NamedArgs{"from": from, "to": to}); err != nil {
+		r.logger.Error("failed to move warehouses", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: failed exec query: %v", op, err)
+	}
+
+	return nil
+}
@@ -70,12 +70,27 @@ func (unit *unitOfWork) Clear() {
 	unit.repositories = make(map[uow.RepositoryName]uow.RepositoryGenerator)
 }
 
+// txKey keeps the transaction of the unit in the context of Do.
+type txKey struct {
+	unit *unitOfWork
+}
+
+// Do runs fn in a transaction. A call inside fn of another Do of the same unit
+// joins the outer transaction with a savepoint, so its rollback undoes only its
+// own changes and the outer rollback undoes everything.
 func (unit *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, tx uow.Transaction) error) error {
-	tx, err := unit.db.Begin(ctx)
+	begin := unit.db.Begin
+	if outer, ok := ctx.Value(txKey{unit}).(pgx.Tx); ok {
+		begin = outer.Begin
+	}
+
+	tx, err := begin(ctx)
 	if err != nil {
 		return err
 	}
 
+	ctx = context.WithValue(ctx, txKey{unit}, tx)
+
 	if err := fn(ctx, NewTransaction(tx, unit.repositories)); err != nil {
 		if err := tx.Rollback(ctx); err != nil {
 			return err
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/controllers"
+	"expvar"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -13,10 +15,22 @@ type routes struct {
 }
 
 type RouterConfig struct {
-	ClientController   *controllers.ClientController
-	ProductController  *controllers.ProductController
-	SupplierController *controllers.SupplierController
-	ImageController    *controllers.ImageController
+	ClientController    *controllers.ClientController
+	ProductController   *controllers.ProductController
+	SupplierController  *controllers.SupplierController
+	CategoryController  *controllers.CategoryController
+	ImageController     *controllers.ImageController
+	AddressController   *controllers.AddressController
+	TransferController  *controllers.TransferController
+	JobController       *controllers.JobController
+	BatchController     *controllers.BatchController
+	InventoryController *controllers.InventoryController
+	WarehouseController *controllers.WarehouseController
+	CartController      *controllers.CartController
+	OrderController     *controllers.OrderController
+	PromotionController *controllers.PromotionController
+
+	IdempotencyMiddleware *controllers.IdempotencyMiddleware
 }
 
 func NewRouter(cfg RouterConfig) routes {
@@ -24,46 +38,162 @@ func NewRouter(cfg RouterConfig) routes {
 		router: gin.Default(),
 	}
 
+	controllers.SetupValidation()
+
+	r.router.Use(controllers.RequestId)
+	r.router.Use(cfg.IdempotencyMiddleware.Handle)
+	r.router.NoRoute(controllers.NoRoute)
+
 	r.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.router.GET("/api/check", controllers.Check)
+	r.router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 	r.router.GET("/api/v1/amogus", func(c *gin.Context) {
 		c.File("./misc/images/amogus.gif")
 	})
 
 	clientGroup := r.router.Group("/api/v1/clients")
 	{
-		clientGroup.GET("", cfg.ClientController.GetAll)
-		clientGroup.POST("", cfg.ClientController.Create)
-		clientGroup.GET("/search", cfg.ClientController.GetByNameAndSurname)
-		clientGroup.PATCH("/:id", cfg.ClientController.UpdateAddress)
-		clientGroup.DELETE("/:id", cfg.ClientController.Delete)
+		clientGroup.GET("", controllers.NegotiateList, cfg.ClientController.GetAll)
+		clientGroup.POST("", controllers.Negotiate, cfg.ClientController.Create)
+		clientGroup.GET("/search", controllers.NegotiateList, cfg.ClientController.Search)
+		clientGroup.GET("/:id", controllers.Negotiate, cfg.ClientController.GetById)
+		clientGroup.PATCH("/:id", controllers.Negotiate, cfg.ClientController.Update)
+		clientGroup.PUT("/:id", controllers.Negotiate, cfg.ClientController.Replace)
+		clientGroup.DELETE("/:id", controllers.Negotiate, cfg.ClientController.Delete)
+		clientGroup.GET("/:id/addresses", controllers.NegotiateList, cfg.ClientController.GetAddresses)
+		clientGroup.POST("/:id/addresses", controllers.Negotiate, cfg.ClientController.AddAddress)
+		clientGroup.PATCH("/:id/addresses/:address_id", controllers.Negotiate, cfg.ClientController.UpdateAddress)
+		clientGroup.DELETE("/:id/addresses/:address_id", controllers.Negotiate, cfg.ClientController.RemoveAddress)
+		clientGroup.GET("/:id/cart", controllers.Negotiate, cfg.CartController.GetByClient)
+		clientGroup.GET("/:id/orders", controllers.NegotiateList, cfg.OrderController.GetByClient)
 	}
 
 	productGroup := r.router.Group("/api/v1/products")
 	{
-		productGroup.GET("", cfg.ProductController.GetAll)
-		productGroup.POST("", cfg.ProductController.Create)
-		productGroup.GET("/:id", cfg.ProductController.GetById)
-		productGroup.PATCH("/:id", cfg.ProductController.Update)
-		productGroup.DELETE("/:id", cfg.ProductController.Delete)
+		productGroup.GET("", controllers.NegotiateList, cfg.ProductController.GetAll)
+		productGroup.POST("", controllers.Negotiate, cfg.ProductController.Create)
+		productGroup.GET("/by-sku/:sku", controllers.Negotiate, cfg.ProductController.GetBySku)
+		productGroup.GET("/by-barcode/:code", controllers.Negotiate, cfg.ProductController.GetByBarcode)
+		productGroup.GET("/:id", controllers.Negotiate, cfg.ProductController.GetById)
+		productGroup.PATCH("/:id", controllers.Negotiate, cfg.ProductController.Update)
+		productGroup.DELETE("/:id", controllers.Negotiate, cfg.ProductController.Delete)
+		productGroup.POST("/:id/variants", controllers.Negotiate, cfg.ProductController.CreateVariant)
+		productGroup.POST("/:id/stock/decrease", controllers.Negotiate, cfg.ProductController.DecreaseStock)
+		productGroup.POST("/:id/stock/increase", controllers.Negotiate, cfg.ProductController.IncreaseStock)
+		productGroup.POST("/:id/stock/set", controllers.Negotiate, cfg.ProductController.SetStock)
+		productGroup.POST("/:id/stock/transfer", controllers.Negotiate, cfg.ProductController.TransferStock)
+		productGroup.GET("/:id/stock/movements", controllers.NegotiateList, cfg.ProductController.GetStockMovements)
+		productGroup.GET("/:id/stock/warehouses", controllers.NegotiateList, cfg.ProductController.GetWarehouseStock)
+		productGroup.PUT("/:id/reorder", controllers.Negotiate, cfg.ProductController.SetReorder)
+		productGroup.DELETE("/:id/reorder", controllers.Negotiate, cfg.ProductController.RemoveReorder)
+		productGroup.GET("/:id/images", controllers.NegotiateList, cfg.ProductController.GetImages)
+		productGroup.POST("/:id/images", controllers.Negotiate, cfg.ProductController.AttachImage)
+		productGroup.PUT("/:id/images", controllers.Negotiate, cfg.ProductController.ReorderImages)
+		productGroup.DELETE("/:id/images/:image_id", controllers.Negotiate, cfg.ProductController.DetachImage)
 	}
 
 	supplierGroup := r.router.Group("/api/v1/suppliers")
 	{
-		supplierGroup.GET("", cfg.SupplierController.GetAll)
-		supplierGroup.POST("", cfg.SupplierController.Create)
-		supplierGroup.GET("/:id", cfg.SupplierController.GetById)
-		supplierGroup.PATCH("/:id", cfg.SupplierController.UpdateAddress)
-		supplierGroup.DELETE("/:id", cfg.SupplierController.Delete)
+		supplierGroup.GET("", controllers.NegotiateList, cfg.SupplierController.GetAll)
+		supplierGroup.POST("", controllers.Negotiate, cfg.SupplierController.Create)
+		supplierGroup.GET("/search", controllers.Negotiate, cfg.SupplierController.Search)
+		supplierGroup.GET("/:id", controllers.Negotiate, cfg.SupplierController.GetById)
+		supplierGroup.GET("/:id/products", controllers.NegotiateList, cfg.ProductController.GetBySupplier)
+		supplierGroup.GET("/:id/stats", controllers.Negotiate, cfg.SupplierController.GetStats)
+		supplierGroup.PATCH("/:id", controllers.Negotiate, cfg.SupplierController.Update)
+		supplierGroup.DELETE("/:id", controllers.Negotiate, cfg.SupplierController.Delete)
+		supplierGroup.GET("/:id/locations", controllers.NegotiateList, cfg.SupplierController.GetLocations)
+		supplierGroup.POST("/:id/locations", controllers.Negotiate, cfg.SupplierController.AddLocation)
+		supplierGroup.PATCH("/:id/locations/:address_id", controllers.Negotiate, cfg.SupplierController.UpdateLocation)
+		supplierGroup.DELETE("/:id/locations/:address_id", controllers.Negotiate, cfg.SupplierController.RemoveLocation)
+	}
+
+	categoryGroup := r.router.Group("/api/v1/categories")
+	{
+		categoryGroup.GET("", controllers.NegotiateList, cfg.CategoryController.GetAll)
+		categoryGroup.POST("", controllers.Negotiate, cfg.CategoryController.Create)
+		categoryGroup.GET("/:id", controllers.Negotiate, cfg.CategoryController.GetById)
+		categoryGroup.PATCH("/:id", controllers.Negotiate, cfg.CategoryController.Update)
+		categoryGroup.DELETE("/:id", controllers.Negotiate, cfg.CategoryController.Delete)
+		categoryGroup.GET("/:id/products", controllers.NegotiateList, cfg.ProductController.GetByCategory)
+		categoryGroup.GET("/:id/attributes", controllers.NegotiateList, cfg.CategoryController.GetAttributes)
+		categoryGroup.POST("/:id/attributes", controllers.Negotiate, cfg.CategoryController.CreateAttribute)
+		categoryGroup.DELETE("/:id/attributes/:key", controllers.Negotiate, cfg.CategoryController.DeleteAttribute)
 	}
 
 	imageGroup := r.router.Group("/api/v1/images")
 	{
-		imageGroup.GET("", cfg.ImageController.GetAll)
-		imageGroup.POST("", cfg.ImageController.Create)
-		imageGroup.GET("/:id", cfg.ImageController.GetById)
-		imageGroup.PATCH("/:id", cfg.ImageController.Update)
-		imageGroup.DELETE("/:id", cfg.ImageController.Delete)
+		imageGroup.GET("", controllers.NegotiateList, cfg.ImageController.GetAll)
+		imageGroup.POST("", controllers.Negotiate, cfg.ImageController.Create)
+		imageGroup.GET("/:id", controllers.Negotiate, cfg.ImageController.GetById)
+		imageGroup.PATCH("/:id", controllers.Negotiate, cfg.ImageController.Update)
+		imageGroup.DELETE("/:id", controllers.Negotiate, cfg.ImageController.Delete)
+	}
+
+	addressGroup := r.router.Group("/api/v1/addresses")
+	{
+		addressGroup.GET("", controllers.NegotiateList, cfg.AddressController.GetAll)
+		addressGroup.GET("/:id", controllers.Negotiate, cfg.AddressController.GetById)
+		addressGroup.GET("/:id/references", controllers.NegotiateList, cfg.AddressController.GetReferences)
+	}
+
+	inventoryGroup := r.router.Group("/api/v1/inventory")
+	{
+		inventoryGroup.GET("/low-stock", controllers.NegotiateList, cfg.InventoryController.GetLowStock)
+		inventoryGroup.GET("/reorder-suggestions", controllers.NegotiateList, cfg.InventoryController.GetReorderSuggestions)
+	}
+
+	warehouseGroup := r.router.Group("/api/v1/warehouses")
+	{
+		warehouseGroup.GET("", controllers.NegotiateList, cfg.WarehouseController.GetAll)
+		warehouseGroup.POST("", controllers.Negotiate, cfg.WarehouseController.Create)
+		warehouseGroup.GET("/:id", controllers.Negotiate, cfg.WarehouseController.GetById)
+		warehouseGroup.GET("/:id/stock", controllers.NegotiateList, cfg.WarehouseController.GetStock)
+		warehouseGroup.PATCH("/:id", controllers.Negotiate, cfg.WarehouseController.Update)
+		warehouseGroup.DELETE("/:id", controllers.Negotiate, cfg.WarehouseController.Delete)
+	}
+
+	cartGroup := r.router.Group("/api/v1/carts")
+	{
+		cartGroup.POST("", controllers.Negotiate, cfg.CartController.Create)
+		cartGroup.GET("/:id", controllers.Negotiate, cfg.CartController.GetById)
+		cartGroup.DELETE("/:id", controllers.Negotiate, cfg.CartController.Delete)
+		cartGroup.POST("/:id/items", controllers.Negotiate, cfg.CartController.AddItem)
+		cartGroup.PUT("/:id/items/:product_id", controllers.Negotiate, cfg.CartController.SetItem)
+		cartGroup.DELETE("/:id/items/:product_id", controllers.Negotiate, cfg.CartController.RemoveItem)
+		cartGroup.POST("/:id/merge", controllers.Negotiate, cfg.CartController.Merge)
+		cartGroup.POST("/:id/checkout", controllers.Negotiate, cfg.CartController.Checkout)
+	}
+
+	orderGroup := r.router.Group("/api/v1/orders")
+	{
+		orderGroup.GET("/:id", controllers.Negotiate, cfg.OrderController.GetById)
+	}
+
+	promotionGroup := r.router.Group("/api/v1/promotions")
+	{
+		promotionGroup.GET("", controllers.NegotiateList, cfg.PromotionController.GetAll)
+		promotionGroup.POST("", controllers.Negotiate, cfg.PromotionController.Create)
+		promotionGroup.POST("/evaluate", controllers.Negotiate, cfg.PromotionController.Evaluate)
+		promotionGroup.GET("/:id", controllers.Negotiate, cfg.PromotionController.GetById)
+		promotionGroup.PATCH("/:id", controllers.Negotiate, cfg.PromotionController.Update)
+		promotionGroup.DELETE("/:id", controllers.Negotiate, cfg.PromotionController.Delete)
+	}
+
+	r.router.POST("/api/v1/import/:entity", controllers.Negotiate, cfg.TransferController.Import)
+	r.router.GET("/api/v1/export/:entity", cfg.TransferController.Export)
+
+	r.router.POST("/api/v1/batch", controllers.Negotiate, cfg.BatchController.Run)
+
+	jobGroup := r.router.Group("/api/v1/jobs")
+	{
+		jobGroup.POST("/import/:entity", controllers.Negotiate, cfg.JobController.SubmitImport)
+		jobGroup.POST("/export/:entity", controllers.Negotiate, cfg.JobController.SubmitExport)
+		jobGroup.POST("/address-purge", controllers.Negotiate, cfg.JobController.SubmitAddressPurge)
+		jobGroup.POST("/image-reprocess", controllers.Negotiate, cfg.JobController.SubmitImageReprocess)
+		jobGroup.GET("/:id", controllers.Negotiate, cfg.JobController.GetById)
+		jobGroup.GET("/:id/output", cfg.JobController.GetOutput)
+		jobGroup.POST("/:id/cancel", controllers.Negotiate, cfg.JobController.Cancel)
 	}
 
 	return r
@@ -72,3 +202,7 @@ func NewRouter(cfg RouterConfig) routes {
 func (r routes) Run(addr ...string) error {
 	return r.router.Run(addr...)
 }
+
+func (r routes) Handler() http.Handler {
+	return r.router.Handler()
+}
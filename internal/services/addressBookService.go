@@ -0,0 +1,220 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	clientAddressLabels = map[string]bool{
+		domain.AddressLabelBilling:  true,
+		domain.AddressLabelShipping: true,
+		domain.AddressLabelHome:     true,
+	}
+	supplierLocationLabels = map[string]bool{
+		domain.AddressLabelWarehouse: true,
+		domain.AddressLabelOffice:    true,
+	}
+)
+
+// addressBook manages addresses linked to clients or suppliers, the owner
+// kind is defined by the repository name and allowed labels.
+type addressBook struct {
+	uow      uow.UOW
+	repoName uow.RepositoryName
+	labels   map[string]bool
+	// keepLast forbids removing the only entry
+	keepLast bool
+	// entries returns the address book of the owner or ErrNotFound
+	entries    func(ctx context.Context, ownerId uuid.UUID) ([]domain.AddressBookEntry, error)
+	normalizer *AddressNormalizer
+	logger     *logger.Logger
+}
+
+func isAddressEmpty(address *domain.Address) bool {
+	return address.City == "" || address.Country == "" || address.Street == ""
+}
+
+func (b *addressBook) Add(ctx context.Context, ownerId uuid.UUID, entry *domain.AddressBookEntry) error {
+	op := "services.addressBook.Add"
+
+	if !b.labels[entry.Label] {
+		b.logger.Debug("unknown address label", "label", entry.Label, "op", op)
+		return fmt.Errorf("%s: label %q: %w", op, entry.Label, crud_errors.ErrInvalidParam)
+	}
+
+	if err := b.normalizer.Normalize(ctx, &entry.Address); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := b.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, b.logger)
+		if err != nil {
+			b.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+		}
+
+		addressRepo, ok := addressRepoGen.(addressWriter)
+		if !ok {
+			b.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		bookRepo, err := b.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := addressRepo.Create(ctx, &entry.Address); err != nil {
+			b.logger.Error("address creation is unavailable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+		}
+
+		if err := bookRepo.Add(ctx, ownerId, entry); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return b.wrapError(op, err)
+	}
+
+	return nil
+}
+
+func (b *addressBook) Update(ctx context.Context, ownerId, addressId uuid.UUID, patch *domain.AddressBookPatch) error {
+	op := "services.addressBook.Update"
+
+	if patch.Label == nil && patch.IsDefault == nil {
+		b.logger.Debug("nothing to update", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	if patch.Label != nil && !b.labels[*patch.Label] {
+		b.logger.Debug("unknown address label", "label", *patch.Label, "op", op)
+		return fmt.Errorf("%s: label %q: %w", op, *patch.Label, crud_errors.ErrInvalidParam)
+	}
+
+	err := b.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		bookRepo, err := b.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := bookRepo.Update(ctx, ownerId, addressId, patch); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return b.wrapError(op, err)
+	}
+
+	return nil
+}
+
+// Remove unlinks the address and deletes it unless someone else still uses it.
+// A non-zero version must match the entry version.
+func (b *addressBook) Remove(ctx context.Context, ownerId, addressId uuid.UUID, version int64) error {
+	op := "services.addressBook.Remove"
+
+	err := b.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+
+		if b.keepLast {
+			entries, err := b.entries(ctx, ownerId)
+			if err != nil {
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			if len(entries) == 1 && entries[0].Address.Id == addressId {
+				b.logger.Debug("the only address cannot be removed", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrLastAddress)
+			}
+		}
+
+		bookRepo, err := b.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := bookRepo.Remove(ctx, ownerId, addressId, version); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, b.logger)
+		if err != nil {
+			b.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+		}
+
+		addressRepo, ok := addressRepoGen.(addressWriter)
+		if !ok {
+			b.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		savepoint := `sp_delete_address`
+		err = safeDelete(ctx, tx.GetTX(), addressId, addressRepo.Delete, b.logger, uowOp, savepoint)
+		if err != nil {
+			b.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return b.wrapError(op, err)
+	}
+
+	return nil
+}
+
+func (b *addressBook) repository(tx uow.Transaction, op string) (addressBookWriter, error) {
+	bookRepoGen, err := getReposiotry(tx, b.repoName, b.logger)
+	if err != nil {
+		b.logger.Error("get address book repository generator is unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get address book repository generator is unable: %v", op, err)
+	}
+
+	bookRepo, ok := bookRepoGen.(addressBookWriter)
+	if !ok {
+		b.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrConversionProblem)
+	}
+
+	return bookRepo, nil
+}
+
+// wrapError keeps sentinel errors the controllers map to statuses.
+func (b *addressBook) wrapError(op string, err error) error {
+	for _, known := range []error{
+		crud_errors.ErrNotFound,
+		crud_errors.ErrInvalidParam,
+		crud_errors.ErrDuplicateKeyValue,
+		crud_errors.ErrLastAddress,
+		crud_errors.ErrVersionMismatch,
+	} {
+		if errors.Is(err, known) {
+			b.logger.Debug("address book change is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	b.logger.Error("something wrong with UOW", logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unit of work problem: %v", op, err)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// addressGCMetrics is published as "address_gc" on /debug/vars.
+var addressGCMetrics = expvar.NewMap("address_gc")
+
+type orphanAddressDeleter interface {
+	DeleteOrphans(ctx context.Context, limit int) (int, error)
+}
+
+// AddressCollector periodically deletes addresses no client or supplier refers
+// to. Deletion goes in batches so a large backlog does not hold locks for long.
+type AddressCollector struct {
+	repo      orphanAddressDeleter
+	interval  time.Duration
+	batchSize int
+	logger    *logger.Logger
+}
+
+func NewAddressCollector(repo orphanAddressDeleter, interval time.Duration, batchSize int, logger *logger.Logger) *AddressCollector {
+	logger.Debug("Address collector is created", "interval", interval, "batch size", batchSize)
+	return &AddressCollector{
+		repo:      repo,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run collects orphan addresses every interval until the context is done.
+func (c *AddressCollector) Run(ctx context.Context) {
+	op := "services.addressCollector.Run"
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Address collector is stopped", "op", op)
+			return
+		case <-ticker.C:
+			if _, err := c.Collect(ctx); err != nil {
+				c.logger.Error("orphan address collection failed", logger.Err(err), "op", op)
+			}
+		}
+	}
+}
+
+// Collect deletes orphan addresses batch by batch until a batch is not full and
+// returns the number of deleted addresses.
+func (c *AddressCollector) Collect(ctx context.Context) (int, error) {
+	op := "services.addressCollector.Collect"
+	started := time.Now()
+	deleted := 0
+
+	addressGCMetrics.Add("runs", 1)
+	defer func() {
+		addressGCMetrics.Add("deleted", int64(deleted))
+		addressGCMetrics.Set("last_deleted", intVar(deleted))
+		addressGCMetrics.Set("last_run_unix", intVar(int(started.Unix())))
+		addressGCMetrics.Set("last_duration_ms", intVar(int(time.Since(started).Milliseconds())))
+	}()
+
+	for {
+		count, err := c.repo.DeleteOrphans(ctx, c.batchSize)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrForeignKeyViolation) {
+				// an orphan got a reference meanwhile, the next run retries the rest
+				c.logger.Debug("batch is skipped", logger.Err(err), "op", op)
+				addressGCMetrics.Add("skipped_batches", 1)
+				return deleted, nil
+			}
+
+			addressGCMetrics.Add("failures", 1)
+			return deleted, fmt.Errorf("%s: %v", op, err)
+		}
+
+		deleted += count
+		addressGCMetrics.Add("batches", 1)
+
+		if count < c.batchSize {
+			break
+		}
+	}
+
+	c.logger.Debug("Orphan addresses are collected", "deleted", deleted, "op", op)
+	return deleted, nil
+}
+
+func intVar(value int) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(int64(value))
+	return v
+}
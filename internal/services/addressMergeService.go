@@ -0,0 +1,198 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// addressMergePageSize limits addresses read by one query.
+const addressMergePageSize = 1000
+
+// errDryRun rolls back the merge transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+type addressMerger interface {
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Address, error)
+	Update(ctx context.Context, address *domain.Address) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type addressRepointer interface {
+	Repoint(ctx context.Context, from, to uuid.UUID) error
+}
+
+// AddressMergeReport describes the result of a merge.
+type AddressMergeReport struct {
+	Scanned int
+	// Updated counts kept addresses rewritten to the normalized form
+	Updated int
+	// Merged counts removed duplicates
+	Merged int
+	// Invalid holds addresses which cannot be normalized, they are left as is
+	Invalid []uuid.UUID
+}
+
+type addressMergeService struct {
+	uow        uow.UOW
+	normalizer *AddressNormalizer
+	logger     *logger.Logger
+}
+
+func NewAddressMergeService(unit uow.UOW, normalizer *AddressNormalizer, logger *logger.Logger) *addressMergeService {
+	logger.Debug("Address merge service is created")
+	return &addressMergeService{
+		uow:        unit,
+		normalizer: normalizer,
+		logger:     logger,
+	}
+}
+
+// MergeDuplicates normalizes stored addresses and merges addresses which are the
+// same after normalization. Client and supplier entries of a duplicate are moved
+// to the address with the lowest id. A dry run reports changes and rolls back.
+func (s *addressMergeService) MergeDuplicates(ctx context.Context, dryRun bool) (*AddressMergeReport, error) {
+	op := "services.addressMergeService.MergeDuplicates"
+	report := &AddressMergeReport{}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+		}
+
+		addressRepo, ok := addressRepoGen.(addressMerger)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		var books []addressRepointer
+		for _, name := range []uow.RepositoryName{uow.ClientAddressRepoName, uow.SupplierLocationRepoName, uow.WarehouseRepoName} {
+			bookRepoGen, err := getReposiotry(tx, name, s.logger)
+			if err != nil {
+				s.logger.Error("get address book repository generator is unable", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: get address book repository generator is unable: %v", uowOp, err)
+			}
+
+			bookRepo, ok := bookRepoGen.(addressRepointer)
+			if !ok {
+				s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+				return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+			}
+
+			books = append(books, bookRepo)
+		}
+
+		var addresses []domain.Address
+		for {
+			page, err := addressRepo.GetAll(ctx, addressMergePageSize, len(addresses))
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				break
+			}
+
+			if err != nil {
+				s.logger.Error("unable to get addresses", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to get addresses: %v", uowOp, err)
+			}
+
+			addresses = append(addresses, page...)
+		}
+
+		report.Scanned = len(addresses)
+
+		var keys []string
+		groups := make(map[string][]domain.Address)
+		stored := make(map[uuid.UUID]domain.Address, len(addresses))
+
+		for _, address := range addresses {
+			stored[address.Id] = address
+
+			normalized := address
+			if err := s.normalizer.Normalize(ctx, &normalized); err != nil {
+				s.logger.Warn("address cannot be normalized", "id", address.Id, logger.Err(err), "op", uowOp)
+				report.Invalid = append(report.Invalid, address.Id)
+				continue
+			}
+
+			key := addressKey(normalized)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			groups[key] = append(groups[key], normalized)
+		}
+
+		for _, key := range keys {
+			group := groups[key]
+			kept := group[0]
+
+			for _, duplicate := range group[1:] {
+				for _, book := range books {
+					if err := book.Repoint(ctx, duplicate.Id, kept.Id); err != nil {
+						s.logger.Error("unable to move address entries", logger.Err(err), "op", uowOp)
+						return fmt.Errorf("%s: unable to move address entries: %v", uowOp, err)
+					}
+				}
+
+				if err := addressRepo.Delete(ctx, duplicate.Id); err != nil {
+					s.logger.Error("unable to delete duplicate address", "id", duplicate.Id, logger.Err(err), "op", uowOp)
+					return fmt.Errorf("%s: unable to delete duplicate address: %v", uowOp, err)
+				}
+
+				if kept.Latitude == nil && duplicate.Latitude != nil {
+					kept.Latitude, kept.Longitude = duplicate.Latitude, duplicate.Longitude
+				}
+
+				report.Merged++
+			}
+
+			if len(group) == 1 && sameAddress(kept, stored[kept.Id]) {
+				continue
+			}
+
+			if err := addressRepo.Update(ctx, &kept); err != nil {
+				s.logger.Error("unable to update address", "id", kept.Id, logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to update address: %v", uowOp, err)
+			}
+
+			report.Updated++
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		s.logger.Error("something wrong with UOW merging", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: unit of work merge problem: %v", op, err)
+	}
+
+	s.logger.Info("Addresses are merged", "scanned", report.Scanned, "updated", report.Updated,
+		"merged", report.Merged, "invalid", len(report.Invalid), "dry run", dryRun, "op", op)
+
+	return report, nil
+}
+
+// sameAddress compares all fields of addresses including coordinates.
+func sameAddress(a, b domain.Address) bool {
+	sameCoordinate := func(x, y *float64) bool {
+		return x == nil && y == nil || x != nil && y != nil && *x == *y
+	}
+
+	return a.Country == b.Country && a.Region == b.Region && a.City == b.City &&
+		a.PostalCode == b.PostalCode && a.Street == b.Street && a.Building == b.Building &&
+		a.Apartment == b.Apartment && a.Line1 == b.Line1 && a.Line2 == b.Line2 &&
+		sameCoordinate(a.Latitude, b.Latitude) && sameCoordinate(a.Longitude, b.Longitude)
+}
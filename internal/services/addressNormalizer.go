@@ -0,0 +1,221 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxAddressFieldLength limits every text field of an address.
+const maxAddressFieldLength = 200
+
+// postalCodeFormats holds postal code formats of countries with a well known
+// format, postal codes of other countries are only checked by length.
+var postalCodeFormats = map[string]*regexp.Regexp{
+	"RU": regexp.MustCompile(`^\d{6}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"KR": regexp.MustCompile(`^\d{5}$`),
+	"CN": regexp.MustCompile(`^\d{6}$`),
+	"IN": regexp.MustCompile(`^\d{6}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+}
+
+// AddressValidator checks an address after normalization, a failed check is
+// reported with ErrInvalidParam.
+type AddressValidator interface {
+	Validate(address *domain.Address) error
+}
+
+// AddressValidatorFunc adapts a function to AddressValidator.
+type AddressValidatorFunc func(address *domain.Address) error
+
+func (f AddressValidatorFunc) Validate(address *domain.Address) error {
+	return f(address)
+}
+
+// Geocoder resolves coordinates of a normalized address, ErrNotFound means the
+// address is unknown to the geocoder.
+type Geocoder interface {
+	Geocode(ctx context.Context, address domain.Address) (latitude, longitude float64, err error)
+}
+
+// AddressNormalizer brings addresses to the form they are stored and
+// deduplicated in: trimmed fields, ISO 3166-1 alpha-2 country code and upper
+// case postal code. Case of other fields is kept, the storage compares them
+// case-insensitively.
+type AddressNormalizer struct {
+	validators []AddressValidator
+	// geocoder is optional, addresses are stored without coordinates without it
+	geocoder Geocoder
+	logger   *logger.Logger
+}
+
+func NewAddressNormalizer(geocoder Geocoder, logger *logger.Logger, validators ...AddressValidator) *AddressNormalizer {
+	logger.Debug("Address normalizer is created", "validators", len(validators), "geocoder", geocoder != nil)
+	return &AddressNormalizer{
+		validators: validators,
+		geocoder:   geocoder,
+		logger:     logger,
+	}
+}
+
+// DefaultAddressValidators returns validators used by the service.
+func DefaultAddressValidators() []AddressValidator {
+	return []AddressValidator{
+		AddressValidatorFunc(validateAddressLength),
+		AddressValidatorFunc(validatePostalCode),
+		AddressValidatorFunc(validateCoordinates),
+	}
+}
+
+// Normalize rewrites the address in place, runs validators and fills missing
+// coordinates. Geocoding failures do not fail normalization.
+func (n *AddressNormalizer) Normalize(ctx context.Context, address *domain.Address) error {
+	op := "services.addressNormalizer.Normalize"
+
+	normalizeAddress(address)
+
+	if isAddressEmpty(address) {
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrAddressIsEmpty)
+	}
+
+	code, ok := countryCode(address.Country)
+	if !ok {
+		n.logger.Debug("unknown country", "country", address.Country, "op", op)
+		return fmt.Errorf("%s: country %q: %w", op, address.Country, crud_errors.ErrInvalidParam)
+	}
+
+	address.Country = code
+
+	for _, validator := range n.validators {
+		if err := validator.Validate(address); err != nil {
+			n.logger.Debug("address is invalid", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if n.geocoder == nil || address.Latitude != nil || address.Longitude != nil {
+		return nil
+	}
+
+	latitude, longitude, err := n.geocoder.Geocode(ctx, *address)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			n.logger.Debug("address is unknown to geocoder", "op", op)
+			return nil
+		}
+
+		n.logger.Warn("geocoding is unavailable", logger.Err(err), "op", op)
+		return nil
+	}
+
+	address.Latitude = &latitude
+	address.Longitude = &longitude
+
+	return nil
+}
+
+// normalizeAddress trims fields and collapses inner whitespace.
+func normalizeAddress(address *domain.Address) {
+	for _, field := range []*string{
+		&address.Country,
+		&address.Region,
+		&address.City,
+		&address.PostalCode,
+		&address.Street,
+		&address.Building,
+		&address.Apartment,
+		&address.Line1,
+		&address.Line2,
+	} {
+		*field = strings.Join(strings.Fields(*field), " ")
+	}
+
+	address.PostalCode = strings.ToUpper(address.PostalCode)
+}
+
+// addressKey identifies an address the same way the storage does.
+func addressKey(address domain.Address) string {
+	return strings.Join([]string{
+		address.Country,
+		foldText(address.Region),
+		foldText(address.City),
+		foldText(address.PostalCode),
+		foldText(address.Street),
+		foldText(address.Building),
+		foldText(address.Apartment),
+		foldText(address.Line1),
+		foldText(address.Line2),
+	}, "\x00")
+}
+
+func validateAddressLength(address *domain.Address) error {
+	fields := map[string]string{
+		"region":      address.Region,
+		"city":        address.City,
+		"postal_code": address.PostalCode,
+		"street":      address.Street,
+		"building":    address.Building,
+		"apartment":   address.Apartment,
+		"line1":       address.Line1,
+		"line2":       address.Line2,
+	}
+
+	for name, value := range fields {
+		if utf8.RuneCountInString(value) > maxAddressFieldLength {
+			return fmt.Errorf("%s is longer than %d characters: %w", name, maxAddressFieldLength, crud_errors.ErrInvalidParam)
+		}
+	}
+
+	return nil
+}
+
+func validatePostalCode(address *domain.Address) error {
+	if address.PostalCode == "" {
+		return nil
+	}
+
+	format, ok := postalCodeFormats[address.Country]
+	if !ok {
+		if len(address.PostalCode) > 10 {
+			return fmt.Errorf("postal code %q is too long: %w", address.PostalCode, crud_errors.ErrInvalidParam)
+		}
+
+		return nil
+	}
+
+	if !format.MatchString(address.PostalCode) {
+		return fmt.Errorf("postal code %q does not match format of %s: %w", address.PostalCode, address.Country, crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+func validateCoordinates(address *domain.Address) error {
+	if address.Latitude == nil && address.Longitude == nil {
+		return nil
+	}
+
+	if address.Latitude == nil || address.Longitude == nil {
+		return fmt.Errorf("latitude and longitude go together: %w", crud_errors.ErrInvalidParam)
+	}
+
+	if *address.Latitude < -90 || *address.Latitude > 90 || *address.Longitude < -180 || *address.Longitude > 180 {
+		return fmt.Errorf("coordinates are out of range: %w", crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
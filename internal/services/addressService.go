@@ -0,0 +1,88 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type addressReader interface {
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Address, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Address, error)
+	GetReferences(ctx context.Context, id uuid.UUID) ([]domain.AddressReference, error)
+}
+
+type addressService struct {
+	reader addressReader
+	logger *logger.Logger
+}
+
+func NewAddressService(reader addressReader, logger *logger.Logger) *addressService {
+	logger.Debug("Address service is created")
+	return &addressService{
+		reader: reader,
+		logger: logger,
+	}
+}
+
+func (s *addressService) GetAll(ctx context.Context, limit, offset int) ([]domain.Address, error) {
+	op := "services.addressService.GetAll"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid parameter limit and offset", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	addresses, err := s.reader.GetAll(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("No content", "op", op)
+			return nil, fmt.Errorf("%s: No content (%w)", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return addresses, nil
+}
+
+func (s *addressService) GetById(ctx context.Context, id uuid.UUID) (*domain.Address, error) {
+	op := "services.addressService.GetById"
+
+	address, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("address not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return address, nil
+}
+
+// GetReferences returns clients, suppliers and warehouses using the address, an
+// empty list means the address is an orphan waiting for the garbage collector.
+func (s *addressService) GetReferences(ctx context.Context, id uuid.UUID) ([]domain.AddressReference, error) {
+	op := "services.addressService.GetReferences"
+
+	if _, err := s.GetById(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	references, err := s.reader.GetReferences(ctx, id)
+	if err != nil {
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return references, nil
+}
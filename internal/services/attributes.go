@@ -0,0 +1,212 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type attributeSchemaReader interface {
+	GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error)
+}
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// IsAttributeKey reports whether the value is a lowercase snake_case key.
+func IsAttributeKey(value string) bool {
+	return attributeKeyPattern.MatchString(value)
+}
+
+// validateAttributeDefinition checks the key, the type and the type specific
+// options and bounds of the definition.
+func validateAttributeDefinition(definition *domain.AttributeDefinition) error {
+	definition.Name = strings.TrimSpace(definition.Name)
+
+	if !IsAttributeKey(definition.Key) || definition.Name == "" {
+		return fmt.Errorf("key %q and name are required: %w", definition.Key, crud_errors.ErrInvalidParam)
+	}
+
+	switch definition.Type {
+	case domain.AttributeEnum:
+		if len(definition.Options) == 0 {
+			return fmt.Errorf("enum %q without options: %w", definition.Key, crud_errors.ErrInvalidParam)
+		}
+	case domain.AttributeString, domain.AttributeNumber, domain.AttributeInteger, domain.AttributeBoolean:
+		if len(definition.Options) > 0 {
+			return fmt.Errorf("options of %s %q: %w", definition.Type, definition.Key, crud_errors.ErrInvalidParam)
+		}
+	default:
+		return fmt.Errorf("type %q: %w", definition.Type, crud_errors.ErrInvalidParam)
+	}
+
+	numeric := definition.Type == domain.AttributeNumber || definition.Type == domain.AttributeInteger
+	if !numeric && (definition.Min != nil || definition.Max != nil) {
+		return fmt.Errorf("bounds of %s %q: %w", definition.Type, definition.Key, crud_errors.ErrInvalidParam)
+	}
+
+	if definition.Min != nil && definition.Max != nil && *definition.Min > *definition.Max {
+		return fmt.Errorf("min of %q is greater than max: %w", definition.Key, crud_errors.ErrInvalidParam)
+	}
+
+	if definition.Position < 0 {
+		return fmt.Errorf("position %d: %w", definition.Position, crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+// validateAttributes checks the values against the definitions: every value has
+// to be defined and of the defined type, required attributes have to be set.
+// Integers decoded as float64 are stored as int64. All invalid attributes are
+// reported in *domain.ValidationError.
+func validateAttributes(definitions []domain.AttributeDefinition, values map[string]any) (map[string]any, error) {
+	checked := make(map[string]any, len(values))
+	byKey := make(map[string]domain.AttributeDefinition, len(definitions))
+
+	var fields []domain.FieldError
+	invalid := func(key, code, message string) {
+		fields = append(fields, domain.FieldError{Field: "attributes." + key, Code: code, Message: key + " " + message})
+	}
+
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+
+		if _, ok := values[definition.Key]; !ok && definition.Required {
+			invalid(definition.Key, "required", "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition, ok := byKey[key]
+		if !ok {
+			invalid(key, "unknown", "is not an attribute of the category")
+			continue
+		}
+
+		value, code, message := attributeValue(definition, values[key])
+		if code != "" {
+			invalid(key, code, message)
+			continue
+		}
+
+		checked[key] = value
+	}
+
+	if len(fields) > 0 {
+		return nil, &domain.ValidationError{Fields: fields}
+	}
+
+	return checked, nil
+}
+
+// attributeValue converts the value to the type of the definition, the code and
+// the message describe a failed check.
+func attributeValue(definition domain.AttributeDefinition, value any) (any, string, string) {
+	switch definition.Type {
+	case domain.AttributeString, domain.AttributeEnum:
+		text, ok := value.(string)
+		if !ok {
+			return nil, "type", "must be a string"
+		}
+
+		if definition.Type == domain.AttributeEnum && !slices.Contains(definition.Options, text) {
+			return nil, "oneof", "must be one of: " + strings.Join(definition.Options, ", ")
+		}
+
+		return text, "", ""
+	case domain.AttributeBoolean:
+		if _, ok := value.(bool); !ok {
+			return nil, "type", "must be true or false"
+		}
+
+		return value, "", ""
+	}
+
+	number, ok := attributeNumber(value)
+	if !ok {
+		return nil, "type", "must be a number"
+	}
+
+	if definition.Type == domain.AttributeInteger && number != math.Trunc(number) {
+		return nil, "type", "must be an integer"
+	}
+
+	if definition.Min != nil && number < *definition.Min {
+		return nil, "gte", "must be greater than or equal to " + strconv.FormatFloat(*definition.Min, 'f', -1, 64)
+	}
+
+	if definition.Max != nil && number > *definition.Max {
+		return nil, "lte", "must be less than or equal to " + strconv.FormatFloat(*definition.Max, 'f', -1, 64)
+	}
+
+	if definition.Type == domain.AttributeInteger {
+		return int64(number), "", ""
+	}
+
+	return number, "", ""
+}
+
+func attributeNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+// checkProductAttributes validates the product attributes against the schema of
+// the product category read in the transaction.
+func checkProductAttributes(ctx context.Context, tx uow.Transaction, product *domain.Product, log *logger.Logger, uowOp string) error {
+	categoryRepoGen, err := getReposiotry(tx, uow.CategoryRepoName, log)
+	if err != nil {
+		log.Error("get category repository generator is unable", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: get category repository generator is unable: %v", uowOp, err)
+	}
+
+	categoryRepo, ok := categoryRepoGen.(attributeSchemaReader)
+	if !ok {
+		log.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	definitions, err := categoryRepo.GetAttributes(ctx, product.Category.Id)
+	if err != nil {
+		log.Error("failed to get category attributes", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: failed to get category attributes: %v", uowOp, err)
+	}
+
+	attributes, err := validateAttributes(definitions, product.Attributes)
+	if err != nil {
+		log.Debug("product attributes are invalid", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, err)
+	}
+
+	product.Attributes = attributes
+
+	return nil
+}
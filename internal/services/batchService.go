@@ -0,0 +1,103 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BatchStep is an operation of a batch. Run gets ids returned by earlier steps
+// by their refs and returns the id of the changed entity.
+type BatchStep struct {
+	Ref string
+	Run func(ctx context.Context, refs map[string]uuid.UUID) (uuid.UUID, error)
+}
+
+// BatchResult is the outcome of a step. Skipped steps are not run because an
+// earlier step of an atomic batch failed, changes of rolled back steps are
+// undone with the batch.
+type BatchResult struct {
+	Id         uuid.UUID
+	Err        error
+	Skipped    bool
+	RolledBack bool
+}
+
+type batchService struct {
+	uow    uow.UOW
+	logger *logger.Logger
+}
+
+func NewBatchService(unit uow.UOW, logger *logger.Logger) *batchService {
+	logger.Debug("Batch service is created")
+	return &batchService{
+		uow:    unit,
+		logger: logger,
+	}
+}
+
+// Run executes the steps in order in one transaction, a step sees changes of
+// the earlier ones. Every step runs in a savepoint, so a failed step leaves no
+// changes. When atomic is set the first failed step rolls back the whole batch
+// and ErrBatchFailed is returned, otherwise the rest of the steps is run.
+func (s *batchService) Run(ctx context.Context, steps []BatchStep, atomic bool) ([]BatchResult, error) {
+	op := "services.batchService.Run"
+	results := make([]BatchResult, len(steps))
+	refs := make(map[string]uuid.UUID)
+
+	err := s.uow.Do(ctx, func(ctx context.Context, _ uow.Transaction) error {
+		for i, step := range steps {
+			var id uuid.UUID
+
+			err := s.uow.Do(ctx, func(ctx context.Context, _ uow.Transaction) error {
+				var err error
+				id, err = step.Run(ctx, refs)
+				return err
+			})
+
+			results[i].Err = err
+
+			if err == nil {
+				results[i].Id = id
+				if step.Ref != "" {
+					refs[step.Ref] = id
+				}
+
+				continue
+			}
+
+			s.logger.Debug("batch step failed", "index", i, logger.Err(err), "op", op)
+
+			if atomic {
+				for j := range results[:i] {
+					results[j].RolledBack = true
+				}
+
+				for j := range results[i+1:] {
+					results[i+1+j].Skipped = true
+				}
+
+				return crud_errors.ErrBatchFailed
+			}
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, crud_errors.ErrBatchFailed) {
+		return results, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err != nil {
+		s.logger.Error("something wrong with UOW batch", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: unit of work batch problem: %w", op, err)
+	}
+
+	s.logger.Debug("Batch is executed", "steps", len(steps), "atomic", atomic, "op", op)
+	return results, nil
+}
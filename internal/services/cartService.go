@@ -0,0 +1,690 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cartReader interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Cart, error)
+	GetByClient(ctx context.Context, clientId uuid.UUID) (*domain.Cart, error)
+}
+
+type cartWriter interface {
+	cartReader
+	Create(ctx context.Context, cart *domain.Cart, ttl time.Duration) error
+	Lock(ctx context.Context, id uuid.UUID, version int64) (*domain.Cart, error)
+	SetItem(ctx context.Context, cartId, productId uuid.UUID, quantity int64) error
+	RemoveItem(ctx context.Context, cartId, productId uuid.UUID) error
+	Touch(ctx context.Context, cart *domain.Cart, ttl time.Duration) error
+	Assign(ctx context.Context, cartId, clientId uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+type orderWriter interface {
+	Create(ctx context.Context, order *domain.Order) error
+}
+
+type cartPromotionWriter interface {
+	promotionEvaluator
+	Redeem(ctx context.Context, redemption domain.PromotionRedemption) error
+}
+
+type cartProductReader interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+}
+
+// cartRejections are errors of cart requests passed to the caller as is.
+var cartRejections = []error{
+	crud_errors.ErrNotFound,
+	crud_errors.ErrVersionMismatch,
+	crud_errors.ErrDuplicateKeyValue,
+	crud_errors.ErrInsufficientStock,
+	crud_errors.ErrVariantStock,
+	crud_errors.ErrCartEmpty,
+	crud_errors.ErrCartClientRequired,
+	crud_errors.ErrCartOwned,
+	crud_errors.ErrCouponNotApplicable,
+}
+
+func isCartRejection(err error) bool {
+	for _, rejection := range cartRejections {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type cartService struct {
+	uow    uow.UOW
+	reader cartReader
+	alerts lowStockPublisher
+	// ttl is the inactivity after which a cart expires
+	ttl time.Duration
+	// stockStrategy picks the warehouses of the checkout without a strategy
+	stockStrategy string
+	logger        *logger.Logger
+}
+
+func NewCartService(reader cartReader, unit uow.UOW, alerts lowStockPublisher, ttl time.Duration, stockStrategy string, logger *logger.Logger) *cartService {
+	logger.Debug("cart service is created", "ttl", ttl)
+	return &cartService{
+		uow:           unit,
+		reader:        reader,
+		alerts:        alerts,
+		ttl:           ttl,
+		stockStrategy: stockStrategy,
+		logger:        logger,
+	}
+}
+
+// Create creates an empty cart, an anonymous one when it has no client. A
+// client has one cart, ErrDuplicateKeyValue is returned for the second one.
+func (s *cartService) Create(ctx context.Context, cart *domain.Cart) error {
+	op := "services.cartService.Create"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := cartRepo.Create(ctx, cart, s.ttl); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return s.fail(op, "creating", err)
+	}
+
+	s.logger.Info("cart created", "id", cart.Id, "client_id", cart.ClientId, "op", op)
+
+	return nil
+}
+
+// GetById returns the cart with the current prices and stock of its products.
+// Expired carts are not found.
+func (s *cartService) GetById(ctx context.Context, id uuid.UUID) (*domain.Cart, error) {
+	op := "services.cartService.GetById"
+	cart, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("cart not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return cart, nil
+}
+
+// GetByClient returns the cart of the client unless it is expired.
+func (s *cartService) GetByClient(ctx context.Context, clientId uuid.UUID) (*domain.Cart, error) {
+	op := "services.cartService.GetByClient"
+	cart, err := s.reader.GetByClient(ctx, clientId)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("client cart not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return cart, nil
+}
+
+// AddItem adds the quantity of the product to the cart and returns the
+// changed cart. The quantity in the cart cannot exceed the product stock. A
+// non-zero version must match the cart version.
+func (s *cartService) AddItem(ctx context.Context, cartId, productId uuid.UUID, quantity, version int64) (*domain.Cart, error) {
+	return s.putItem(ctx, "services.cartService.AddItem", cartId, productId, quantity, true, version)
+}
+
+// SetItem sets the quantity of the product in the cart and returns the
+// changed cart, the product is added when the cart has no such item.
+func (s *cartService) SetItem(ctx context.Context, cartId, productId uuid.UUID, quantity, version int64) (*domain.Cart, error) {
+	return s.putItem(ctx, "services.cartService.SetItem", cartId, productId, quantity, false, version)
+}
+
+func (s *cartService) putItem(ctx context.Context, op string, cartId, productId uuid.UUID, quantity int64, add bool, version int64) (*domain.Cart, error) {
+	if err := validateCartQuantity(quantity); err != nil {
+		s.logger.Debug("cart item is invalid", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var changed *domain.Cart
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		cart, err := cartRepo.Lock(ctx, cartId, version)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product repository generator is unable: %v", uowOp, err)
+		}
+
+		productRepo, ok := productRepoGen.(cartProductReader)
+		if !ok {
+			s.logger.Error("conversion problem, not contained expected convesion", "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		product, err := productRepo.GetById(ctx, productId)
+		if err != nil {
+			return fmt.Errorf("%s: product: %w", uowOp, err)
+		}
+
+		// a product with variants is bought by its variants
+		if len(product.Variants) > 0 {
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrVariantStock)
+		}
+
+		if item := cart.Item(productId); add && item != nil {
+			quantity += item.Quantity
+		}
+
+		if quantity > product.AvailableStock {
+			s.logger.Debug("cart quantity exceeds stock", "product_id", productId, "quantity", quantity,
+				"stock", product.AvailableStock, "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrInsufficientStock)
+		}
+
+		if err := cartRepo.SetItem(ctx, cart.Id, productId, quantity); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		changed, err = s.touch(ctx, cartRepo, cart, uowOp)
+		return err
+	})
+
+	if err != nil {
+		return nil, s.fail(op, "item change", err)
+	}
+
+	s.logger.Debug("cart item set", "id", cartId, "product_id", productId, "quantity", quantity, "op", op)
+
+	return changed, nil
+}
+
+// RemoveItem removes the product from the cart and returns the changed cart.
+func (s *cartService) RemoveItem(ctx context.Context, cartId, productId uuid.UUID, version int64) (*domain.Cart, error) {
+	op := "services.cartService.RemoveItem"
+
+	var changed *domain.Cart
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		cart, err := cartRepo.Lock(ctx, cartId, version)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		if err := cartRepo.RemoveItem(ctx, cart.Id, productId); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		changed, err = s.touch(ctx, cartRepo, cart, uowOp)
+		return err
+	})
+
+	if err != nil {
+		return nil, s.fail(op, "item removal", err)
+	}
+
+	return changed, nil
+}
+
+// Merge gives the anonymous cart to the identified client. The cart becomes
+// the client cart when the client has none, otherwise its items are added to
+// the client cart limited by the product stock and the anonymous cart is
+// deleted. The client cart is returned with the items lowered to the stock or
+// removed as adjustments.
+func (s *cartService) Merge(ctx context.Context, id, clientId uuid.UUID) (*domain.Cart, error) {
+	op := "services.cartService.Merge"
+
+	var merged *domain.Cart
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		cart, err := cartRepo.Lock(ctx, id, 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		if cart.ClientId != nil {
+			if *cart.ClientId != clientId {
+				return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrCartOwned)
+			}
+
+			merged = cart
+			return nil
+		}
+
+		target, err := cartRepo.GetByClient(ctx, clientId)
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			if err := cartRepo.Assign(ctx, cart.Id, clientId); err != nil {
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			merged, err = s.touch(ctx, cartRepo, cart, uowOp)
+			return err
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		target, err = cartRepo.Lock(ctx, target.Id, 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		var adjustments []domain.CartAdjustment
+
+		for _, item := range cart.Items {
+			existing := target.Item(item.ProductId)
+
+			requested := item.Quantity
+			if existing != nil {
+				requested += existing.Quantity
+			}
+
+			// the stock may be lower than the quantity put in the carts before
+			quantity := min(requested, max(item.AvailableStock, 0))
+			if quantity < requested {
+				s.logger.Debug("merged quantity is lowered to the stock", "product_id", item.ProductId,
+					"quantity", requested, "stock", item.AvailableStock, "op", uowOp)
+				adjustments = append(adjustments, domain.CartAdjustment{
+					ProductId: item.ProductId,
+					Requested: requested,
+					Quantity:  quantity,
+				})
+			}
+
+			if quantity > 0 {
+				err = cartRepo.SetItem(ctx, target.Id, item.ProductId, quantity)
+			} else if existing != nil {
+				err = cartRepo.RemoveItem(ctx, target.Id, item.ProductId)
+			}
+
+			if err != nil {
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+		}
+
+		if err := cartRepo.Delete(ctx, cart.Id, 0); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		merged, err = s.touch(ctx, cartRepo, target, uowOp)
+		if err != nil {
+			return err
+		}
+
+		merged.Adjustments = adjustments
+		return nil
+	})
+
+	if err != nil {
+		return nil, s.fail(op, "merging", err)
+	}
+
+	s.logger.Info("cart merged", "id", id, "client_id", clientId, "cart_id", merged.Id, "op", op)
+
+	return merged, nil
+}
+
+// Checkout turns the client cart into an order in one transaction: the stock
+// of every item is decreased in the warehouse picked by pick, the configured
+// strategy by default, the order keeps the current prices discounted by the
+// promotions, the redemptions are recorded and the cart is deleted. Every
+// coupon has to discount an item. A non-zero version must match the cart
+// version. Low stock events are fired after the commit.
+func (s *cartService) Checkout(ctx context.Context, id uuid.UUID, pick domain.WarehousePick, coupons []string, version int64) (*domain.Order, error) {
+	op := "services.cartService.Checkout"
+
+	if pick.Strategy == "" {
+		pick.Strategy = s.stockStrategy
+	}
+
+	if err := validateWarehousePick(pick); err != nil {
+		s.logger.Debug("warehouse pick is invalid", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	codes, err := normalizeCoupons(coupons)
+	if err != nil {
+		s.logger.Debug("coupons are invalid", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		order    *domain.Order
+		lowStock []domain.LowStockItem
+	)
+
+	err = s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		lowStock = nil
+
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		cart, err := cartRepo.Lock(ctx, id, version)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		if cart.ClientId == nil {
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrCartClientRequired)
+		}
+
+		if len(cart.Items) == 0 {
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrCartEmpty)
+		}
+
+		promotionRepo, err := s.promotionRepository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		items := make([]domain.PromotionItem, len(cart.Items))
+		for i, item := range cart.Items {
+			items[i] = domain.PromotionItem{ProductId: item.ProductId, Quantity: item.Quantity}
+		}
+
+		// the limited promotions stay locked until the redemptions are recorded
+		evaluation, err := evaluatePromotions(ctx, promotionRepo, items, cart.ClientId, codes, true)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		if code := couponNotApplied(evaluation, codes); code != "" {
+			s.logger.Debug("coupon is not applicable", "code", code, "op", uowOp)
+			return fmt.Errorf("%s: coupon %s: %w", uowOp, code, crud_errors.ErrCouponNotApplicable)
+		}
+
+		order = &domain.Order{
+			Id:       uuid.New(),
+			ClientId: cart.ClientId,
+			Subtotal: evaluation.Subtotal(),
+			Discount: evaluation.Discount(),
+			Total:    evaluation.Total(),
+		}
+
+		for _, item := range cart.Items {
+			movement := domain.StockMovement{
+				ProductId: item.ProductId,
+				Operation: domain.StockDecrease,
+				Quantity:  item.Quantity,
+				Reason:    domain.StockReasonSale,
+				Note:      "order " + order.Id.String(),
+			}
+
+			low, err := changeStock(ctx, tx, &movement, pick, 0, s.logger, uowOp)
+			if err != nil {
+				return err
+			}
+
+			if low != nil {
+				lowStock = append(lowStock, *low)
+			}
+
+			productId := item.ProductId
+			orderItem := domain.OrderItem{
+				ProductId:   &productId,
+				Name:        item.Name,
+				Sku:         item.Sku,
+				Price:       item.Price,
+				Quantity:    item.Quantity,
+				WarehouseId: movement.WarehouseId,
+			}
+
+			if line := evaluation.Line(item.ProductId); line != nil && line.Promotion != nil {
+				promotionId := line.Promotion.Id
+				orderItem.Discount, orderItem.PromotionId = line.Discount, &promotionId
+			}
+
+			order.Items = append(order.Items, orderItem)
+		}
+
+		orderRepoGen, err := getReposiotry(tx, uow.OrderRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get order repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get order repository generator is unable: %v", uowOp, err)
+		}
+
+		orderRepo, ok := orderRepoGen.(orderWriter)
+		if !ok {
+			s.logger.Error("conversion problem, not contained expected convesion", "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := orderRepo.Create(ctx, order); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		for _, redemption := range orderRedemptions(order) {
+			if err := promotionRepo.Redeem(ctx, redemption); err != nil {
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+		}
+
+		if err := cartRepo.Delete(ctx, cart.Id, 0); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, s.fail(op, "checkout", err)
+	}
+
+	s.logger.Info("cart checked out", "id", id, "order_id", order.Id, "client_id", order.ClientId,
+		"total", order.Total, "discount", order.Discount, "op", op)
+
+	// the events are fired after the commit, a rolled back checkout fires nothing
+	for _, item := range lowStock {
+		s.alerts.Fire(ctx, domain.LowStockEvent{Item: item, Source: domain.LowStockSourceStockChange})
+	}
+
+	return order, nil
+}
+
+// Delete deletes the cart with its items, a non-zero version must match the
+// cart version.
+func (s *cartService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "services.cartService.Delete"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		cartRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := cartRepo.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return s.fail(op, "deleting", err)
+	}
+
+	return nil
+}
+
+// touch records the change of the cart and reads it back with its items.
+func (s *cartService) touch(ctx context.Context, cartRepo cartWriter, cart *domain.Cart, uowOp string) (*domain.Cart, error) {
+	if err := cartRepo.Touch(ctx, cart, s.ttl); err != nil {
+		return nil, fmt.Errorf("%s: %w", uowOp, err)
+	}
+
+	changed, err := cartRepo.GetById(ctx, cart.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", uowOp, err)
+	}
+
+	return changed, nil
+}
+
+// fail wraps the error of the unit of work, rejections keep their sentinel.
+func (s *cartService) fail(op, action string, err error) error {
+	if isCartRejection(err) {
+		s.logger.Debug("cart request is rejected", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Error("something wrong with UOW "+action, logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unit of work %s problem: %v", op, action, err)
+}
+
+func (s *cartService) promotionRepository(tx uow.Transaction, uowOp string) (cartPromotionWriter, error) {
+	promotionRepoGen, err := getReposiotry(tx, uow.PromotionRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get promotion repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get promotion repository generator is unable: %v", uowOp, err)
+	}
+
+	promotionRepo, ok := promotionRepoGen.(cartPromotionWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return promotionRepo, nil
+}
+
+// orderRedemptions sums the discounts of the order items by promotion.
+func orderRedemptions(order *domain.Order) []domain.PromotionRedemption {
+	var redemptions []domain.PromotionRedemption
+	index := make(map[uuid.UUID]int)
+
+	for _, item := range order.Items {
+		if item.PromotionId == nil {
+			continue
+		}
+
+		i, ok := index[*item.PromotionId]
+		if !ok {
+			i = len(redemptions)
+			index[*item.PromotionId] = i
+			redemptions = append(redemptions, domain.PromotionRedemption{
+				PromotionId: *item.PromotionId,
+				ClientId:    order.ClientId,
+				OrderId:     order.Id,
+			})
+		}
+
+		redemptions[i].Discount += item.Discount
+	}
+
+	for i := range redemptions {
+		redemptions[i].Discount = math.Round(redemptions[i].Discount*100) / 100
+	}
+
+	return redemptions
+}
+
+func (s *cartService) repository(tx uow.Transaction, uowOp string) (cartWriter, error) {
+	cartRepoGen, err := getReposiotry(tx, uow.CartRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get cart repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get cart repository generator is unable: %v", uowOp, err)
+	}
+
+	cartRepo, ok := cartRepoGen.(cartWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return cartRepo, nil
+}
+
+type expiredCartDeleter interface {
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+// CartCleaner periodically deletes expired carts.
+type CartCleaner struct {
+	repo     expiredCartDeleter
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+func NewCartCleaner(repo expiredCartDeleter, interval time.Duration, logger *logger.Logger) *CartCleaner {
+	logger.Debug("Cart cleaner is created", "interval", interval)
+	return &CartCleaner{
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run deletes expired carts every interval until the context is done.
+func (c *CartCleaner) Run(ctx context.Context) {
+	op := "services.cartCleaner.Run"
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Cart cleaner is stopped", "op", op)
+			return
+		case <-ticker.C:
+			deleted, err := c.repo.DeleteExpired(ctx)
+			if err != nil {
+				c.logger.Error("unable to delete expired carts", logger.Err(err), "op", op)
+				continue
+			}
+
+			c.logger.Debug("Expired carts are deleted", "deleted", deleted, "op", op)
+		}
+	}
+}
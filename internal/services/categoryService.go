@@ -0,0 +1,444 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type categoryReader interface {
+	GetAll(ctx context.Context) ([]domain.Category, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error)
+}
+
+type categoryWriter interface {
+	Create(ctx context.Context, category *domain.Category) error
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	IsDescendant(ctx context.Context, ancestorId, id uuid.UUID) (bool, error)
+	IsAttributeDefined(ctx context.Context, categoryId uuid.UUID, key string) (bool, error)
+	CreateAttribute(ctx context.Context, definition *domain.AttributeDefinition) error
+	DeleteAttribute(ctx context.Context, categoryId uuid.UUID, key string) error
+}
+
+type categoryService struct {
+	uow    uow.UOW
+	reader categoryReader
+	logger *logger.Logger
+}
+
+func NewCategoryService(reader categoryReader, unit uow.UOW, logger *logger.Logger) *categoryService {
+	logger.Debug("category service is created")
+	return &categoryService{
+		uow:    unit,
+		reader: reader,
+		logger: logger,
+	}
+}
+
+// Slugify makes a slug of the name: letters are lowercased, digits are kept
+// and every other run of characters becomes a single hyphen.
+func Slugify(name string) string {
+	var (
+		slug   strings.Builder
+		hyphen bool
+	)
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+
+			slug.WriteRune(r)
+			hyphen = false
+			continue
+		}
+
+		hyphen = true
+	}
+
+	return slug.String()
+}
+
+// IsSlug reports whether the value is a slug made by Slugify.
+func IsSlug(value string) bool {
+	return value != "" && Slugify(value) == value
+}
+
+// validateCategory trims the name and makes the slug from the name when it is
+// not given.
+func validateCategory(category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("name is required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	if category.Slug == "" {
+		category.Slug = Slugify(category.Name)
+	}
+
+	if !IsSlug(category.Slug) {
+		return fmt.Errorf("slug %q: %w", category.Slug, crud_errors.ErrInvalidParam)
+	}
+
+	if category.Position < 0 {
+		return fmt.Errorf("position %d: %w", category.Position, crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+func (s *categoryService) Create(ctx context.Context, category *domain.Category) error {
+	op := "services.categoryService.Create"
+
+	if err := validateCategory(category); err != nil {
+		s.logger.Debug("category data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		categoryRepo, err := s.writer(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := categoryRepo.Create(ctx, category); err != nil {
+			if errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("category is rejected", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to create category", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to create category: %v", uowOp, err)
+		}
+
+		categoryRepoRead, ok := categoryRepo.(categoryReader)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		// depth and path are known from the tree only
+		created, err := categoryRepoRead.GetById(ctx, category.Id)
+		if err != nil {
+			s.logger.Error("failed get category by id", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed get category by id: %v", uowOp, err)
+		}
+
+		*category = *created
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work problem %v", op, err)
+	}
+
+	return nil
+}
+
+// GetAll returns the taxonomy flattened depth-first, an empty taxonomy gives
+// ErrNotFound.
+func (s *categoryService) GetAll(ctx context.Context) ([]domain.Category, error) {
+	op := "services.categoryService.GetAll"
+
+	categories, err := s.reader.GetAll(ctx)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("no content", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("extract data failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return categories, nil
+}
+
+func (s *categoryService) GetById(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	op := "services.categoryService.GetById"
+
+	category, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("category not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("extract data failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+// Update changes the set fields of the category. A category cannot be moved
+// under itself or under one of its subcategories.
+func (s *categoryService) Update(ctx context.Context, id uuid.UUID, patch *domain.CategoryPatch) (*domain.Category, error) {
+	op := "services.categoryService.Update"
+
+	if patch.IsEmpty() {
+		s.logger.Debug("nothing to update", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	var category *domain.Category
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		categoryRepo, err := s.writer(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		categoryRepoRead, ok := categoryRepo.(categoryReader)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		category, err = categoryRepoRead.GetById(ctx, id)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("category not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed get category by id", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed get category by id: %v", uowOp, err)
+		}
+
+		if patch.ParentId != nil && *patch.ParentId != uuid.Nil {
+			cycle, err := categoryRepo.IsDescendant(ctx, id, *patch.ParentId)
+			if err != nil {
+				s.logger.Error("failed to check category subtree", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: failed to check subtree: %v", uowOp, err)
+			}
+
+			if cycle {
+				s.logger.Debug("category cannot be moved into its subtree", "op", uowOp)
+				return fmt.Errorf("%s: parent is the category or its subcategory: %w", uowOp, crud_errors.ErrInvalidParam)
+			}
+		}
+
+		patch.Apply(category)
+
+		if err := validateCategory(category); err != nil {
+			s.logger.Debug("category data is invalid", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		if err := categoryRepo.Update(ctx, category); err != nil {
+			if errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrNotFound) ||
+				errors.Is(err, crud_errors.ErrInvalidParam) {
+				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to update category", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to update category: %v", uowOp, err)
+		}
+
+		// depth and path follow the new parent
+		category, err = categoryRepoRead.GetById(ctx, id)
+		if err != nil {
+			s.logger.Error("failed get category by id", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed get category by id: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrInvalidParam) ||
+			errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("update initialize is unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW updating", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: unit of work problem %v", op, err)
+	}
+
+	return category, nil
+}
+
+// Delete removes the category, ErrForeignKeyViolation means it still has
+// subcategories or products.
+func (s *categoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	op := "services.categoryService.Delete"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		categoryRepo, err := s.writer(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := categoryRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, crud_errors.ErrForeignKeyViolation) {
+				s.logger.Debug("category is in use", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to delete category", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to delete category: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrForeignKeyViolation) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW deleting", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work problem %v", op, err)
+	}
+
+	return nil
+}
+
+// GetAttributes returns the attribute schema of the category products: the
+// definitions of the category and of its ancestors.
+func (s *categoryService) GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error) {
+	op := "services.categoryService.GetAttributes"
+
+	if _, err := s.GetById(ctx, categoryId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	definitions, err := s.reader.GetAttributes(ctx, categoryId)
+	if err != nil {
+		s.logger.Error("extract data failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return definitions, nil
+}
+
+// CreateAttribute defines an attribute of the category products. The key cannot
+// be defined by an ancestor or a subcategory of the category too. Existing
+// products are checked against the new definition when they are changed.
+func (s *categoryService) CreateAttribute(ctx context.Context, definition *domain.AttributeDefinition) error {
+	op := "services.categoryService.CreateAttribute"
+
+	if err := validateAttributeDefinition(definition); err != nil {
+		s.logger.Debug("attribute data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		categoryRepo, err := s.writer(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		defined, err := categoryRepo.IsAttributeDefined(ctx, definition.CategoryId, definition.Key)
+		if err != nil {
+			s.logger.Error("failed to check attribute key", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to check attribute key: %v", uowOp, err)
+		}
+
+		if defined {
+			s.logger.Debug("attribute key is defined in the category tree", "op", uowOp)
+			return fmt.Errorf("%s: key %q: %w", uowOp, definition.Key, crud_errors.ErrDuplicateKeyValue)
+		}
+
+		if err := categoryRepo.CreateAttribute(ctx, definition); err != nil {
+			if errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("attribute is rejected", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to create attribute", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to create attribute: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work problem %v", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAttribute removes the attribute of the category and its values from
+// the products of the category and its subcategories.
+func (s *categoryService) DeleteAttribute(ctx context.Context, categoryId uuid.UUID, key string) error {
+	op := "services.categoryService.DeleteAttribute"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		categoryRepo, err := s.writer(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := categoryRepo.DeleteAttribute(ctx, categoryId, key); err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("attribute not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to delete attribute", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to delete attribute: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW deleting", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work problem %v", op, err)
+	}
+
+	return nil
+}
+
+// writer gets the category repository from the transaction.
+func (s *categoryService) writer(tx uow.Transaction, uowOp string) (categoryWriter, error) {
+	categoryRepoGen, err := getReposiotry(tx, uow.CategoryRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get category repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get category repository generator is unable: %v", uowOp, err)
+	}
+
+	categoryRepo, ok := categoryRepoGen.(categoryWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return categoryRepo, nil
+}
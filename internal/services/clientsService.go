@@ -14,56 +14,61 @@ import (
 
 type clientReader interface {
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Client, error)
-	GetByNameAndSurname(ctx context.Context, name, surname string) ([]domain.Client, error)
+	Search(ctx context.Context, filter domain.ClientFilter) ([]domain.Client, int, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Client, error)
 }
 
 type clientWriter interface {
 	Create(ctx context.Context, client *domain.Client) error
-	UpdateAddress(ctx context.Context, id, address uuid.UUID) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, client *domain.Client) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
 }
 
 type clientsService struct {
-	uow    uow.UOW
-	reader clientReader
-	logger *logger.Logger
+	uow        uow.UOW
+	reader     clientReader
+	addresses  *addressBook
+	normalizer *AddressNormalizer
+	logger     *logger.Logger
 }
 
-func NewClientService(reader clientReader, unit uow.UOW, logger *logger.Logger) *clientsService {
+func NewClientService(reader clientReader, unit uow.UOW, normalizer *AddressNormalizer, logger *logger.Logger) *clientsService {
 	logger.Debug("Client service is created")
-	return &clientsService{
-		uow:    unit,
-		reader: reader,
-		logger: logger,
+	service := &clientsService{
+		uow:        unit,
+		reader:     reader,
+		normalizer: normalizer,
+		logger:     logger,
 	}
+
+	service.addresses = &addressBook{
+		uow:        unit,
+		repoName:   uow.ClientAddressRepoName,
+		labels:     clientAddressLabels,
+		entries:    service.GetAddresses,
+		normalizer: normalizer,
+		logger:     logger,
+	}
+
+	return service
 }
 
 func (s *clientsService) Create(ctx context.Context, client *domain.Client) error {
 	op := "services.clientService.Create"
 
-	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
-		uowOp := op + ".uow"
-
-		if client.Address != nil {
-			addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
-			if err != nil {
-				s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
-				return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
-			}
-
-			addressRepo, ok := addressRepoGen.(addressWriter)
-			if !ok {
-				s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
-				return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
-			}
+	if err := validateClient(client); err != nil {
+		s.logger.Debug("client data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-			err = addressRepo.Create(ctx, client.Address)
-			if err != nil {
-				s.logger.Error("address creation is unavailable", logger.Err(err), "op", uowOp)
-				return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
-			}
+	if client.Address != nil {
+		if err := s.normalizer.Normalize(ctx, client.Address); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
 		}
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
 
 		clientRepoGen, err := getReposiotry(tx, uow.ClientRepoName, s.logger)
 		if err != nil {
@@ -78,14 +83,64 @@ func (s *clientsService) Create(ctx context.Context, client *domain.Client) erro
 		}
 
 		if err := clientRepo.Create(ctx, client); err != nil {
+			if errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+				s.logger.Debug("client contacts are already used", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
 			s.logger.Error("failed to create client", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: failed to create client: %v", uowOp, err)
 		}
 
+		if client.Address == nil {
+			return nil
+		}
+
+		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+		}
+
+		addressRepo, ok := addressRepoGen.(addressWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		err = addressRepo.Create(ctx, client.Address)
+		if err != nil {
+			s.logger.Error("address creation is unavailable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+		}
+
+		bookRepo, err := s.addresses.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		entry := domain.AddressBookEntry{
+			Address:   *client.Address,
+			Label:     domain.AddressLabelHome,
+			IsDefault: true,
+		}
+
+		if err := bookRepo.Add(ctx, client.Id, &entry); err != nil {
+			s.logger.Error("failed to add client address", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to add client address: %v", uowOp, err)
+		}
+
+		client.Addresses = []domain.AddressBookEntry{entry}
+
 		return nil
 	})
 
 	if err != nil {
+		if errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("client contacts are already used", "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: unit of work creating problem: %v", op, err)
 	}
@@ -110,31 +165,143 @@ func (s *clientsService) GetAll(ctx context.Context, limit, offset int) ([]domai
 	return clients, nil
 }
 
-func (s *clientsService) GetByNameAndSurname(ctx context.Context, name, surname string) ([]domain.Client, error) {
-	op := "services.clientsService.GetByNameAndSurname"
+// clientSearchMaxLimit caps a page of the client search.
+const clientSearchMaxLimit = 100
 
-	if name == "" || surname == "" {
-		s.logger.Error("name or surname is empty", "op", op)
-		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+// Search returns a page of clients matched by the filter and the total number of
+// matched clients.
+func (s *clientsService) Search(ctx context.Context, filter domain.ClientFilter) ([]domain.Client, int, error) {
+	op := "services.clientsService.Search"
+
+	if filter.Limit <= 0 || filter.Limit > clientSearchMaxLimit || filter.Offset < 0 {
+		s.logger.Error("invalid parameter limit and offset", "limit", filter.Limit, "offset", filter.Offset, "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
 	}
 
-	clients, err := s.reader.GetByNameAndSurname(ctx, name, surname)
+	if filter.Gender != "" && !allowedGenders[filter.Gender] {
+		s.logger.Debug("unknown gender", "gender", filter.Gender, "op", op)
+		return nil, 0, fmt.Errorf("%s: gender %q: %w", op, filter.Gender, crud_errors.ErrInvalidParam)
+	}
+
+	// addresses keep the alpha-2 code of the country
+	if filter.Country != "" {
+		code, ok := countryCode(filter.Country)
+		if !ok {
+			s.logger.Debug("unknown country", "country", filter.Country, "op", op)
+			return nil, 0, fmt.Errorf("%s: country %q: %w", op, filter.Country, crud_errors.ErrInvalidParam)
+		}
+
+		filter.Country = code
+	}
+
+	switch filter.SortBy {
+	case "", domain.ClientSortName, domain.ClientSortSurname, domain.ClientSortBirthday, domain.ClientSortRegistrationDate:
+	case domain.ClientSortRelevance:
+		if filter.Query == "" {
+			s.logger.Debug("relevance sort without query", "op", op)
+			return nil, 0, fmt.Errorf("%s: relevance sort requires query: %w", op, crud_errors.ErrInvalidParam)
+		}
+	default:
+		s.logger.Debug("unknown sort key", "sort", filter.SortBy, "op", op)
+		return nil, 0, fmt.Errorf("%s: sort %q: %w", op, filter.SortBy, crud_errors.ErrInvalidParam)
+	}
+
+	clients, total, err := s.reader.Search(ctx, filter)
 	if err != nil {
 		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
-		return nil, fmt.Errorf("%s: error when taking a client by first and last name: %w", op, err)
+		return nil, total, fmt.Errorf("%s: error when searching clients: %w", op, err)
 	}
 
-	return clients, nil
+	return clients, total, nil
 }
 
-func (s *clientsService) UpdateAddress(ctx context.Context, id uuid.UUID, address *domain.Address) error {
-	op := "services.clientsService.UpdateAddress"
-	if address.City == "" || address.Country == "" || address.Street == "" {
-		return fmt.Errorf("%s: %w", op, crud_errors.ErrAddressIsEmpty)
+func (s *clientsService) GetById(ctx context.Context, id uuid.UUID) (*domain.Client, error) {
+	op := "services.clientsService.GetById"
+
+	client, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("client not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("extract data failed", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return client, nil
+}
+
+// Update changes the set profile fields and re-points the client to a new
+// address when the patch contains one. Every update increments the client
+// version, a non-zero patch.Version must match it.
+func (s *clientsService) Update(ctx context.Context, id uuid.UUID, patch *domain.ClientPatch) error {
+	op := "services.clientsService.Update"
+
+	if !patch.HasProfile() && patch.Address == nil {
+		s.logger.Debug("nothing to update", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	if patch.Address != nil {
+		if err := s.normalizer.Normalize(ctx, patch.Address); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
+		clientRepoGen, err := getReposiotry(tx, uow.ClientRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get client repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: error when try to get repository generator: %v", uowOp, err)
+		}
+
+		clientRepo, ok := clientRepoGen.(clientWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		client, err := s.reader.GetById(ctx, id)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("client not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("unable to get client data", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to get client data: %v", uowOp, err)
+		}
+
+		patch.Apply(client)
+
+		if err := validateClient(client); err != nil {
+			s.logger.Debug("client data is invalid", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		// the profile is rewritten on address changes too, it increments the
+		// version. Without an expected version the read one is, so a write in
+		// between is not lost.
+		if patch.Version != 0 {
+			client.Version = patch.Version
+		}
+		if err := clientRepo.Update(ctx, client); err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) ||
+				errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to update client", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to update client: %v", uowOp, err)
+		}
+
+		if patch.Address == nil {
+			return nil
+		}
+
 		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
 		if err != nil {
 			s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
@@ -147,25 +314,18 @@ func (s *clientsService) UpdateAddress(ctx context.Context, id uuid.UUID, addres
 			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
 		}
 
-		err = addressRepo.Create(ctx, address)
+		err = addressRepo.Create(ctx, patch.Address)
 		if err != nil {
 			s.logger.Error("address creation is unavailable", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
 		}
 
-		clientRepoGen, err := getReposiotry(tx, uow.ClientRepoName, s.logger)
+		bookRepo, err := s.addresses.repository(tx, uowOp)
 		if err != nil {
-			s.logger.Error("get client repository generator is unable", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: error when try to get repository generator: %v", uowOp, err)
+			return err
 		}
 
-		clientRepo, ok := clientRepoGen.(clientWriter)
-		if !ok {
-			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
-			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
-		}
-
-		if err := clientRepo.UpdateAddress(ctx, id, address.Id); err != nil {
+		if err := bookRepo.ReplaceDefault(ctx, id, patch.Address.Id, domain.AddressLabelHome); err != nil {
 			if errors.Is(err, crud_errors.ErrNotFound) {
 				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
 				return fmt.Errorf("%s: %w", uowOp, err)
@@ -179,8 +339,9 @@ func (s *clientsService) UpdateAddress(ctx context.Context, id uuid.UUID, addres
 	})
 
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			s.logger.Warn("update initialize is unable: client not found", logger.Err(err), "op", op)
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrInvalidParam) ||
+			errors.Is(err, crud_errors.ErrDuplicateKeyValue) || errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Warn("update initialize is unable", logger.Err(err), "op", op)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -191,7 +352,9 @@ func (s *clientsService) UpdateAddress(ctx context.Context, id uuid.UUID, addres
 	return nil
 }
 
-func (s *clientsService) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete removes the client and its addresses unless other entities use them,
+// a non-zero version must match the client version.
+func (s *clientsService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "services.clientService.Delete"
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
@@ -218,12 +381,17 @@ func (s *clientsService) Delete(ctx context.Context, id uuid.UUID) error {
 			return fmt.Errorf("%s: unable to get client data: %v", uowOp, err)
 		}
 
-		if err := clientRepo.Delete(ctx, id); err != nil {
+		if err := clientRepo.Delete(ctx, id, version); err != nil {
+			if errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("client is changed", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
 			s.logger.Error("unable to delete client", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: unable to delete client: %v", uowOp, err)
 		}
 
-		if client.Address != nil {
+		if len(client.Addresses) > 0 {
 			addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
 			if err != nil {
 				s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
@@ -237,15 +405,17 @@ func (s *clientsService) Delete(ctx context.Context, id uuid.UUID) error {
 			}
 
 			savepoint := `sp_delete_address`
-			err = safeDelete(ctx, tx.GetTX(), client.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
-			if err != nil {
-				if errors.Is(err, crud_errors.ErrNotFound) {
-					s.logger.Debug("client not found", "op", uowOp)
-					return nil
+			for _, entry := range client.Addresses {
+				err = safeDelete(ctx, tx.GetTX(), entry.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
+				if err != nil {
+					if errors.Is(err, crud_errors.ErrNotFound) {
+						s.logger.Debug("client not found", "op", uowOp)
+						return nil
+					}
+
+					s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+					return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
 				}
-
-				s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
-				return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
 			}
 		}
 
@@ -253,8 +423,8 @@ func (s *clientsService) Delete(ctx context.Context, id uuid.UUID) error {
 	})
 
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			s.logger.Debug("client not found", "op", op)
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Debug("delete initialize is unable", logger.Err(err), "op", op)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -264,3 +434,44 @@ func (s *clientsService) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+func (s *clientsService) GetAddresses(ctx context.Context, id uuid.UUID) ([]domain.AddressBookEntry, error) {
+	op := "services.clientsService.GetAddresses"
+
+	client, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return client.Addresses, nil
+}
+
+func (s *clientsService) AddAddress(ctx context.Context, id uuid.UUID, entry *domain.AddressBookEntry) error {
+	op := "services.clientsService.AddAddress"
+
+	if err := s.addresses.Add(ctx, id, entry); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *clientsService) UpdateAddress(ctx context.Context, id, addressId uuid.UUID, patch *domain.AddressBookPatch) error {
+	op := "services.clientsService.UpdateAddress"
+
+	if err := s.addresses.Update(ctx, id, addressId, patch); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *clientsService) RemoveAddress(ctx context.Context, id, addressId uuid.UUID, version int64) error {
+	op := "services.clientsService.RemoveAddress"
+
+	if err := s.addresses.Remove(ctx, id, addressId, version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -11,3 +11,10 @@ type addressWriter interface {
 	Create(ctx context.Context, address *domain.Address) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+type addressBookWriter interface {
+	Add(ctx context.Context, ownerId uuid.UUID, entry *domain.AddressBookEntry) error
+	Update(ctx context.Context, ownerId, addressId uuid.UUID, patch *domain.AddressBookPatch) error
+	Remove(ctx context.Context, ownerId, addressId uuid.UUID, version int64) error
+	ReplaceDefault(ctx context.Context, ownerId, addressId uuid.UUID, label string) error
+}
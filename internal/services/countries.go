@@ -0,0 +1,296 @@
+package services
+
+import "strings"
+
+type isoCountry struct {
+	alpha2 string
+	alpha3 string
+	// names holds the short, common and official English names and popular aliases
+	names []string
+}
+
+// isoCountries lists ISO 3166-1 countries, addresses keep the alpha-2 code.
+var isoCountries = []isoCountry{
+	{alpha2: "AD", alpha3: "AND", names: []string{"Andorra", "Principality of Andorra"}},
+	{alpha2: "AE", alpha3: "ARE", names: []string{"United Arab Emirates", "UAE"}},
+	{alpha2: "AF", alpha3: "AFG", names: []string{"Afghanistan", "Islamic Republic of Afghanistan"}},
+	{alpha2: "AG", alpha3: "ATG", names: []string{"Antigua and Barbuda"}},
+	{alpha2: "AI", alpha3: "AIA", names: []string{"Anguilla"}},
+	{alpha2: "AL", alpha3: "ALB", names: []string{"Albania", "Republic of Albania"}},
+	{alpha2: "AM", alpha3: "ARM", names: []string{"Armenia", "Republic of Armenia"}},
+	{alpha2: "AO", alpha3: "AGO", names: []string{"Angola", "Republic of Angola"}},
+	{alpha2: "AQ", alpha3: "ATA", names: []string{"Antarctica"}},
+	{alpha2: "AR", alpha3: "ARG", names: []string{"Argentina", "Argentine Republic"}},
+	{alpha2: "AS", alpha3: "ASM", names: []string{"American Samoa"}},
+	{alpha2: "AT", alpha3: "AUT", names: []string{"Austria", "Republic of Austria"}},
+	{alpha2: "AU", alpha3: "AUS", names: []string{"Australia"}},
+	{alpha2: "AW", alpha3: "ABW", names: []string{"Aruba"}},
+	{alpha2: "AX", alpha3: "ALA", names: []string{"Åland Islands"}},
+	{alpha2: "AZ", alpha3: "AZE", names: []string{"Azerbaijan", "Republic of Azerbaijan"}},
+	{alpha2: "BA", alpha3: "BIH", names: []string{"Bosnia and Herzegovina", "Republic of Bosnia and Herzegovina"}},
+	{alpha2: "BB", alpha3: "BRB", names: []string{"Barbados"}},
+	{alpha2: "BD", alpha3: "BGD", names: []string{"Bangladesh", "People's Republic of Bangladesh"}},
+	{alpha2: "BE", alpha3: "BEL", names: []string{"Belgium", "Kingdom of Belgium"}},
+	{alpha2: "BF", alpha3: "BFA", names: []string{"Burkina Faso"}},
+	{alpha2: "BG", alpha3: "BGR", names: []string{"Bulgaria", "Republic of Bulgaria"}},
+	{alpha2: "BH", alpha3: "BHR", names: []string{"Bahrain", "Kingdom of Bahrain"}},
+	{alpha2: "BI", alpha3: "BDI", names: []string{"Burundi", "Republic of Burundi"}},
+	{alpha2: "BJ", alpha3: "BEN", names: []string{"Benin", "Republic of Benin"}},
+	{alpha2: "BL", alpha3: "BLM", names: []string{"Saint Barthélemy"}},
+	{alpha2: "BM", alpha3: "BMU", names: []string{"Bermuda"}},
+	{alpha2: "BN", alpha3: "BRN", names: []string{"Brunei Darussalam"}},
+	{alpha2: "BO", alpha3: "BOL", names: []string{"Bolivia, Plurinational State of", "Bolivia", "Plurinational State of Bolivia"}},
+	{alpha2: "BQ", alpha3: "BES", names: []string{"Bonaire, Sint Eustatius and Saba"}},
+	{alpha2: "BR", alpha3: "BRA", names: []string{"Brazil", "Federative Republic of Brazil"}},
+	{alpha2: "BS", alpha3: "BHS", names: []string{"Bahamas", "Commonwealth of the Bahamas"}},
+	{alpha2: "BT", alpha3: "BTN", names: []string{"Bhutan", "Kingdom of Bhutan"}},
+	{alpha2: "BV", alpha3: "BVT", names: []string{"Bouvet Island"}},
+	{alpha2: "BW", alpha3: "BWA", names: []string{"Botswana", "Republic of Botswana"}},
+	{alpha2: "BY", alpha3: "BLR", names: []string{"Belarus", "Republic of Belarus"}},
+	{alpha2: "BZ", alpha3: "BLZ", names: []string{"Belize"}},
+	{alpha2: "CA", alpha3: "CAN", names: []string{"Canada"}},
+	{alpha2: "CC", alpha3: "CCK", names: []string{"Cocos (Keeling) Islands"}},
+	{alpha2: "CD", alpha3: "COD", names: []string{"Congo, The Democratic Republic of the"}},
+	{alpha2: "CF", alpha3: "CAF", names: []string{"Central African Republic"}},
+	{alpha2: "CG", alpha3: "COG", names: []string{"Congo", "Republic of the Congo"}},
+	{alpha2: "CH", alpha3: "CHE", names: []string{"Switzerland", "Swiss Confederation"}},
+	{alpha2: "CI", alpha3: "CIV", names: []string{"Côte d'Ivoire", "Republic of Côte d'Ivoire", "Ivory Coast"}},
+	{alpha2: "CK", alpha3: "COK", names: []string{"Cook Islands"}},
+	{alpha2: "CL", alpha3: "CHL", names: []string{"Chile", "Republic of Chile"}},
+	{alpha2: "CM", alpha3: "CMR", names: []string{"Cameroon", "Republic of Cameroon"}},
+	{alpha2: "CN", alpha3: "CHN", names: []string{"China", "People's Republic of China"}},
+	{alpha2: "CO", alpha3: "COL", names: []string{"Colombia", "Republic of Colombia"}},
+	{alpha2: "CR", alpha3: "CRI", names: []string{"Costa Rica", "Republic of Costa Rica"}},
+	{alpha2: "CU", alpha3: "CUB", names: []string{"Cuba", "Republic of Cuba"}},
+	{alpha2: "CV", alpha3: "CPV", names: []string{"Cabo Verde", "Republic of Cabo Verde", "Cape Verde"}},
+	{alpha2: "CW", alpha3: "CUW", names: []string{"Curaçao"}},
+	{alpha2: "CX", alpha3: "CXR", names: []string{"Christmas Island"}},
+	{alpha2: "CY", alpha3: "CYP", names: []string{"Cyprus", "Republic of Cyprus"}},
+	{alpha2: "CZ", alpha3: "CZE", names: []string{"Czechia", "Czech Republic"}},
+	{alpha2: "DE", alpha3: "DEU", names: []string{"Germany", "Federal Republic of Germany"}},
+	{alpha2: "DJ", alpha3: "DJI", names: []string{"Djibouti", "Republic of Djibouti"}},
+	{alpha2: "DK", alpha3: "DNK", names: []string{"Denmark", "Kingdom of Denmark"}},
+	{alpha2: "DM", alpha3: "DMA", names: []string{"Dominica", "Commonwealth of Dominica"}},
+	{alpha2: "DO", alpha3: "DOM", names: []string{"Dominican Republic"}},
+	{alpha2: "DZ", alpha3: "DZA", names: []string{"Algeria", "People's Democratic Republic of Algeria"}},
+	{alpha2: "EC", alpha3: "ECU", names: []string{"Ecuador", "Republic of Ecuador"}},
+	{alpha2: "EE", alpha3: "EST", names: []string{"Estonia", "Republic of Estonia"}},
+	{alpha2: "EG", alpha3: "EGY", names: []string{"Egypt", "Arab Republic of Egypt"}},
+	{alpha2: "EH", alpha3: "ESH", names: []string{"Western Sahara"}},
+	{alpha2: "ER", alpha3: "ERI", names: []string{"Eritrea", "the State of Eritrea"}},
+	{alpha2: "ES", alpha3: "ESP", names: []string{"Spain", "Kingdom of Spain"}},
+	{alpha2: "ET", alpha3: "ETH", names: []string{"Ethiopia", "Federal Democratic Republic of Ethiopia"}},
+	{alpha2: "FI", alpha3: "FIN", names: []string{"Finland", "Republic of Finland"}},
+	{alpha2: "FJ", alpha3: "FJI", names: []string{"Fiji", "Republic of Fiji"}},
+	{alpha2: "FK", alpha3: "FLK", names: []string{"Falkland Islands (Malvinas)"}},
+	{alpha2: "FM", alpha3: "FSM", names: []string{"Micronesia, Federated States of", "Federated States of Micronesia"}},
+	{alpha2: "FO", alpha3: "FRO", names: []string{"Faroe Islands"}},
+	{alpha2: "FR", alpha3: "FRA", names: []string{"France", "French Republic"}},
+	{alpha2: "GA", alpha3: "GAB", names: []string{"Gabon", "Gabonese Republic"}},
+	{alpha2: "GB", alpha3: "GBR", names: []string{"United Kingdom", "United Kingdom of Great Britain and Northern Ireland", "UK", "Great Britain", "England"}},
+	{alpha2: "GD", alpha3: "GRD", names: []string{"Grenada"}},
+	{alpha2: "GE", alpha3: "GEO", names: []string{"Georgia"}},
+	{alpha2: "GF", alpha3: "GUF", names: []string{"French Guiana"}},
+	{alpha2: "GG", alpha3: "GGY", names: []string{"Guernsey"}},
+	{alpha2: "GH", alpha3: "GHA", names: []string{"Ghana", "Republic of Ghana"}},
+	{alpha2: "GI", alpha3: "GIB", names: []string{"Gibraltar"}},
+	{alpha2: "GL", alpha3: "GRL", names: []string{"Greenland"}},
+	{alpha2: "GM", alpha3: "GMB", names: []string{"Gambia", "Republic of the Gambia"}},
+	{alpha2: "GN", alpha3: "GIN", names: []string{"Guinea", "Republic of Guinea"}},
+	{alpha2: "GP", alpha3: "GLP", names: []string{"Guadeloupe"}},
+	{alpha2: "GQ", alpha3: "GNQ", names: []string{"Equatorial Guinea", "Republic of Equatorial Guinea"}},
+	{alpha2: "GR", alpha3: "GRC", names: []string{"Greece", "Hellenic Republic"}},
+	{alpha2: "GS", alpha3: "SGS", names: []string{"South Georgia and the South Sandwich Islands"}},
+	{alpha2: "GT", alpha3: "GTM", names: []string{"Guatemala", "Republic of Guatemala"}},
+	{alpha2: "GU", alpha3: "GUM", names: []string{"Guam"}},
+	{alpha2: "GW", alpha3: "GNB", names: []string{"Guinea-Bissau", "Republic of Guinea-Bissau"}},
+	{alpha2: "GY", alpha3: "GUY", names: []string{"Guyana", "Republic of Guyana"}},
+	{alpha2: "HK", alpha3: "HKG", names: []string{"Hong Kong", "Hong Kong Special Administrative Region of China"}},
+	{alpha2: "HM", alpha3: "HMD", names: []string{"Heard Island and McDonald Islands"}},
+	{alpha2: "HN", alpha3: "HND", names: []string{"Honduras", "Republic of Honduras"}},
+	{alpha2: "HR", alpha3: "HRV", names: []string{"Croatia", "Republic of Croatia"}},
+	{alpha2: "HT", alpha3: "HTI", names: []string{"Haiti", "Republic of Haiti"}},
+	{alpha2: "HU", alpha3: "HUN", names: []string{"Hungary"}},
+	{alpha2: "ID", alpha3: "IDN", names: []string{"Indonesia", "Republic of Indonesia"}},
+	{alpha2: "IE", alpha3: "IRL", names: []string{"Ireland"}},
+	{alpha2: "IL", alpha3: "ISR", names: []string{"Israel", "State of Israel"}},
+	{alpha2: "IM", alpha3: "IMN", names: []string{"Isle of Man"}},
+	{alpha2: "IN", alpha3: "IND", names: []string{"India", "Republic of India"}},
+	{alpha2: "IO", alpha3: "IOT", names: []string{"British Indian Ocean Territory"}},
+	{alpha2: "IQ", alpha3: "IRQ", names: []string{"Iraq", "Republic of Iraq"}},
+	{alpha2: "IR", alpha3: "IRN", names: []string{"Iran, Islamic Republic of", "Iran", "Islamic Republic of Iran"}},
+	{alpha2: "IS", alpha3: "ISL", names: []string{"Iceland", "Republic of Iceland"}},
+	{alpha2: "IT", alpha3: "ITA", names: []string{"Italy", "Italian Republic"}},
+	{alpha2: "JE", alpha3: "JEY", names: []string{"Jersey"}},
+	{alpha2: "JM", alpha3: "JAM", names: []string{"Jamaica"}},
+	{alpha2: "JO", alpha3: "JOR", names: []string{"Jordan", "Hashemite Kingdom of Jordan"}},
+	{alpha2: "JP", alpha3: "JPN", names: []string{"Japan"}},
+	{alpha2: "KE", alpha3: "KEN", names: []string{"Kenya", "Republic of Kenya"}},
+	{alpha2: "KG", alpha3: "KGZ", names: []string{"Kyrgyzstan", "Kyrgyz Republic"}},
+	{alpha2: "KH", alpha3: "KHM", names: []string{"Cambodia", "Kingdom of Cambodia"}},
+	{alpha2: "KI", alpha3: "KIR", names: []string{"Kiribati", "Republic of Kiribati"}},
+	{alpha2: "KM", alpha3: "COM", names: []string{"Comoros", "Union of the Comoros"}},
+	{alpha2: "KN", alpha3: "KNA", names: []string{"Saint Kitts and Nevis"}},
+	{alpha2: "KP", alpha3: "PRK", names: []string{"Korea, Democratic People's Republic of", "North Korea", "Democratic People's Republic of Korea"}},
+	{alpha2: "KR", alpha3: "KOR", names: []string{"Korea, Republic of", "South Korea", "Korea"}},
+	{alpha2: "KW", alpha3: "KWT", names: []string{"Kuwait", "State of Kuwait"}},
+	{alpha2: "KY", alpha3: "CYM", names: []string{"Cayman Islands"}},
+	{alpha2: "KZ", alpha3: "KAZ", names: []string{"Kazakhstan", "Republic of Kazakhstan"}},
+	{alpha2: "LA", alpha3: "LAO", names: []string{"Lao People's Democratic Republic", "Laos"}},
+	{alpha2: "LB", alpha3: "LBN", names: []string{"Lebanon", "Lebanese Republic"}},
+	{alpha2: "LC", alpha3: "LCA", names: []string{"Saint Lucia"}},
+	{alpha2: "LI", alpha3: "LIE", names: []string{"Liechtenstein", "Principality of Liechtenstein"}},
+	{alpha2: "LK", alpha3: "LKA", names: []string{"Sri Lanka", "Democratic Socialist Republic of Sri Lanka"}},
+	{alpha2: "LR", alpha3: "LBR", names: []string{"Liberia", "Republic of Liberia"}},
+	{alpha2: "LS", alpha3: "LSO", names: []string{"Lesotho", "Kingdom of Lesotho"}},
+	{alpha2: "LT", alpha3: "LTU", names: []string{"Lithuania", "Republic of Lithuania"}},
+	{alpha2: "LU", alpha3: "LUX", names: []string{"Luxembourg", "Grand Duchy of Luxembourg"}},
+	{alpha2: "LV", alpha3: "LVA", names: []string{"Latvia", "Republic of Latvia"}},
+	{alpha2: "LY", alpha3: "LBY", names: []string{"Libya"}},
+	{alpha2: "MA", alpha3: "MAR", names: []string{"Morocco", "Kingdom of Morocco"}},
+	{alpha2: "MC", alpha3: "MCO", names: []string{"Monaco", "Principality of Monaco"}},
+	{alpha2: "MD", alpha3: "MDA", names: []string{"Moldova, Republic of", "Moldova", "Republic of Moldova"}},
+	{alpha2: "ME", alpha3: "MNE", names: []string{"Montenegro"}},
+	{alpha2: "MF", alpha3: "MAF", names: []string{"Saint Martin (French part)"}},
+	{alpha2: "MG", alpha3: "MDG", names: []string{"Madagascar", "Republic of Madagascar"}},
+	{alpha2: "MH", alpha3: "MHL", names: []string{"Marshall Islands", "Republic of the Marshall Islands"}},
+	{alpha2: "MK", alpha3: "MKD", names: []string{"North Macedonia", "Republic of North Macedonia", "Macedonia"}},
+	{alpha2: "ML", alpha3: "MLI", names: []string{"Mali", "Republic of Mali"}},
+	{alpha2: "MM", alpha3: "MMR", names: []string{"Myanmar", "Republic of Myanmar"}},
+	{alpha2: "MN", alpha3: "MNG", names: []string{"Mongolia"}},
+	{alpha2: "MO", alpha3: "MAC", names: []string{"Macao", "Macao Special Administrative Region of China"}},
+	{alpha2: "MP", alpha3: "MNP", names: []string{"Northern Mariana Islands", "Commonwealth of the Northern Mariana Islands"}},
+	{alpha2: "MQ", alpha3: "MTQ", names: []string{"Martinique"}},
+	{alpha2: "MR", alpha3: "MRT", names: []string{"Mauritania", "Islamic Republic of Mauritania"}},
+	{alpha2: "MS", alpha3: "MSR", names: []string{"Montserrat"}},
+	{alpha2: "MT", alpha3: "MLT", names: []string{"Malta", "Republic of Malta"}},
+	{alpha2: "MU", alpha3: "MUS", names: []string{"Mauritius", "Republic of Mauritius"}},
+	{alpha2: "MV", alpha3: "MDV", names: []string{"Maldives", "Republic of Maldives"}},
+	{alpha2: "MW", alpha3: "MWI", names: []string{"Malawi", "Republic of Malawi"}},
+	{alpha2: "MX", alpha3: "MEX", names: []string{"Mexico", "United Mexican States"}},
+	{alpha2: "MY", alpha3: "MYS", names: []string{"Malaysia"}},
+	{alpha2: "MZ", alpha3: "MOZ", names: []string{"Mozambique", "Republic of Mozambique"}},
+	{alpha2: "NA", alpha3: "NAM", names: []string{"Namibia", "Republic of Namibia"}},
+	{alpha2: "NC", alpha3: "NCL", names: []string{"New Caledonia"}},
+	{alpha2: "NE", alpha3: "NER", names: []string{"Niger", "Republic of the Niger"}},
+	{alpha2: "NF", alpha3: "NFK", names: []string{"Norfolk Island"}},
+	{alpha2: "NG", alpha3: "NGA", names: []string{"Nigeria", "Federal Republic of Nigeria"}},
+	{alpha2: "NI", alpha3: "NIC", names: []string{"Nicaragua", "Republic of Nicaragua"}},
+	{alpha2: "NL", alpha3: "NLD", names: []string{"Netherlands", "Kingdom of the Netherlands", "Holland"}},
+	{alpha2: "NO", alpha3: "NOR", names: []string{"Norway", "Kingdom of Norway"}},
+	{alpha2: "NP", alpha3: "NPL", names: []string{"Nepal", "Federal Democratic Republic of Nepal"}},
+	{alpha2: "NR", alpha3: "NRU", names: []string{"Nauru", "Republic of Nauru"}},
+	{alpha2: "NU", alpha3: "NIU", names: []string{"Niue"}},
+	{alpha2: "NZ", alpha3: "NZL", names: []string{"New Zealand"}},
+	{alpha2: "OM", alpha3: "OMN", names: []string{"Oman", "Sultanate of Oman"}},
+	{alpha2: "PA", alpha3: "PAN", names: []string{"Panama", "Republic of Panama"}},
+	{alpha2: "PE", alpha3: "PER", names: []string{"Peru", "Republic of Peru"}},
+	{alpha2: "PF", alpha3: "PYF", names: []string{"French Polynesia"}},
+	{alpha2: "PG", alpha3: "PNG", names: []string{"Papua New Guinea", "Independent State of Papua New Guinea"}},
+	{alpha2: "PH", alpha3: "PHL", names: []string{"Philippines", "Republic of the Philippines"}},
+	{alpha2: "PK", alpha3: "PAK", names: []string{"Pakistan", "Islamic Republic of Pakistan"}},
+	{alpha2: "PL", alpha3: "POL", names: []string{"Poland", "Republic of Poland"}},
+	{alpha2: "PM", alpha3: "SPM", names: []string{"Saint Pierre and Miquelon"}},
+	{alpha2: "PN", alpha3: "PCN", names: []string{"Pitcairn"}},
+	{alpha2: "PR", alpha3: "PRI", names: []string{"Puerto Rico"}},
+	{alpha2: "PS", alpha3: "PSE", names: []string{"Palestine, State of", "the State of Palestine"}},
+	{alpha2: "PT", alpha3: "PRT", names: []string{"Portugal", "Portuguese Republic"}},
+	{alpha2: "PW", alpha3: "PLW", names: []string{"Palau", "Republic of Palau"}},
+	{alpha2: "PY", alpha3: "PRY", names: []string{"Paraguay", "Republic of Paraguay"}},
+	{alpha2: "QA", alpha3: "QAT", names: []string{"Qatar", "State of Qatar"}},
+	{alpha2: "RE", alpha3: "REU", names: []string{"Réunion"}},
+	{alpha2: "RO", alpha3: "ROU", names: []string{"Romania"}},
+	{alpha2: "RS", alpha3: "SRB", names: []string{"Serbia", "Republic of Serbia"}},
+	{alpha2: "RU", alpha3: "RUS", names: []string{"Russian Federation", "Russia"}},
+	{alpha2: "RW", alpha3: "RWA", names: []string{"Rwanda", "Rwandese Republic"}},
+	{alpha2: "SA", alpha3: "SAU", names: []string{"Saudi Arabia", "Kingdom of Saudi Arabia"}},
+	{alpha2: "SB", alpha3: "SLB", names: []string{"Solomon Islands"}},
+	{alpha2: "SC", alpha3: "SYC", names: []string{"Seychelles", "Republic of Seychelles"}},
+	{alpha2: "SD", alpha3: "SDN", names: []string{"Sudan", "Republic of the Sudan"}},
+	{alpha2: "SE", alpha3: "SWE", names: []string{"Sweden", "Kingdom of Sweden"}},
+	{alpha2: "SG", alpha3: "SGP", names: []string{"Singapore", "Republic of Singapore"}},
+	{alpha2: "SH", alpha3: "SHN", names: []string{"Saint Helena, Ascension and Tristan da Cunha"}},
+	{alpha2: "SI", alpha3: "SVN", names: []string{"Slovenia", "Republic of Slovenia"}},
+	{alpha2: "SJ", alpha3: "SJM", names: []string{"Svalbard and Jan Mayen"}},
+	{alpha2: "SK", alpha3: "SVK", names: []string{"Slovakia", "Slovak Republic"}},
+	{alpha2: "SL", alpha3: "SLE", names: []string{"Sierra Leone", "Republic of Sierra Leone"}},
+	{alpha2: "SM", alpha3: "SMR", names: []string{"San Marino", "Republic of San Marino"}},
+	{alpha2: "SN", alpha3: "SEN", names: []string{"Senegal", "Republic of Senegal"}},
+	{alpha2: "SO", alpha3: "SOM", names: []string{"Somalia", "Federal Republic of Somalia"}},
+	{alpha2: "SR", alpha3: "SUR", names: []string{"Suriname", "Republic of Suriname"}},
+	{alpha2: "SS", alpha3: "SSD", names: []string{"South Sudan", "Republic of South Sudan"}},
+	{alpha2: "ST", alpha3: "STP", names: []string{"Sao Tome and Principe", "Democratic Republic of Sao Tome and Principe"}},
+	{alpha2: "SV", alpha3: "SLV", names: []string{"El Salvador", "Republic of El Salvador"}},
+	{alpha2: "SX", alpha3: "SXM", names: []string{"Sint Maarten (Dutch part)"}},
+	{alpha2: "SY", alpha3: "SYR", names: []string{"Syrian Arab Republic", "Syria"}},
+	{alpha2: "SZ", alpha3: "SWZ", names: []string{"Eswatini", "Kingdom of Eswatini", "Swaziland"}},
+	{alpha2: "TC", alpha3: "TCA", names: []string{"Turks and Caicos Islands"}},
+	{alpha2: "TD", alpha3: "TCD", names: []string{"Chad", "Republic of Chad"}},
+	{alpha2: "TF", alpha3: "ATF", names: []string{"French Southern Territories"}},
+	{alpha2: "TG", alpha3: "TGO", names: []string{"Togo", "Togolese Republic"}},
+	{alpha2: "TH", alpha3: "THA", names: []string{"Thailand", "Kingdom of Thailand"}},
+	{alpha2: "TJ", alpha3: "TJK", names: []string{"Tajikistan", "Republic of Tajikistan"}},
+	{alpha2: "TK", alpha3: "TKL", names: []string{"Tokelau"}},
+	{alpha2: "TL", alpha3: "TLS", names: []string{"Timor-Leste", "Democratic Republic of Timor-Leste"}},
+	{alpha2: "TM", alpha3: "TKM", names: []string{"Turkmenistan"}},
+	{alpha2: "TN", alpha3: "TUN", names: []string{"Tunisia", "Republic of Tunisia"}},
+	{alpha2: "TO", alpha3: "TON", names: []string{"Tonga", "Kingdom of Tonga"}},
+	{alpha2: "TR", alpha3: "TUR", names: []string{"Türkiye", "Republic of Türkiye", "Turkey"}},
+	{alpha2: "TT", alpha3: "TTO", names: []string{"Trinidad and Tobago", "Republic of Trinidad and Tobago"}},
+	{alpha2: "TV", alpha3: "TUV", names: []string{"Tuvalu"}},
+	{alpha2: "TW", alpha3: "TWN", names: []string{"Taiwan, Province of China", "Taiwan"}},
+	{alpha2: "TZ", alpha3: "TZA", names: []string{"Tanzania, United Republic of", "Tanzania", "United Republic of Tanzania"}},
+	{alpha2: "UA", alpha3: "UKR", names: []string{"Ukraine"}},
+	{alpha2: "UG", alpha3: "UGA", names: []string{"Uganda", "Republic of Uganda"}},
+	{alpha2: "UM", alpha3: "UMI", names: []string{"United States Minor Outlying Islands"}},
+	{alpha2: "US", alpha3: "USA", names: []string{"United States", "United States of America", "USA", "America"}},
+	{alpha2: "UY", alpha3: "URY", names: []string{"Uruguay", "Eastern Republic of Uruguay"}},
+	{alpha2: "UZ", alpha3: "UZB", names: []string{"Uzbekistan", "Republic of Uzbekistan"}},
+	{alpha2: "VA", alpha3: "VAT", names: []string{"Holy See (Vatican City State)"}},
+	{alpha2: "VC", alpha3: "VCT", names: []string{"Saint Vincent and the Grenadines"}},
+	{alpha2: "VE", alpha3: "VEN", names: []string{"Venezuela, Bolivarian Republic of", "Venezuela", "Bolivarian Republic of Venezuela"}},
+	{alpha2: "VG", alpha3: "VGB", names: []string{"Virgin Islands, British", "British Virgin Islands"}},
+	{alpha2: "VI", alpha3: "VIR", names: []string{"Virgin Islands, U.S.", "Virgin Islands of the United States"}},
+	{alpha2: "VN", alpha3: "VNM", names: []string{"Viet Nam", "Vietnam", "Socialist Republic of Viet Nam"}},
+	{alpha2: "VU", alpha3: "VUT", names: []string{"Vanuatu", "Republic of Vanuatu"}},
+	{alpha2: "WF", alpha3: "WLF", names: []string{"Wallis and Futuna"}},
+	{alpha2: "WS", alpha3: "WSM", names: []string{"Samoa", "Independent State of Samoa"}},
+	{alpha2: "YE", alpha3: "YEM", names: []string{"Yemen", "Republic of Yemen"}},
+	{alpha2: "YT", alpha3: "MYT", names: []string{"Mayotte"}},
+	{alpha2: "ZA", alpha3: "ZAF", names: []string{"South Africa", "Republic of South Africa"}},
+	{alpha2: "ZM", alpha3: "ZMB", names: []string{"Zambia", "Republic of Zambia"}},
+	{alpha2: "ZW", alpha3: "ZWE", names: []string{"Zimbabwe", "Republic of Zimbabwe"}},
+}
+
+// countryIndex maps folded codes and names to alpha-2 codes.
+var countryIndex = func() map[string]string {
+	index := make(map[string]string, len(isoCountries)*4)
+	for _, country := range isoCountries {
+		index[foldText(country.alpha2)] = country.alpha2
+		index[foldText(country.alpha3)] = country.alpha2
+		for _, name := range country.names {
+			index[foldText(name)] = country.alpha2
+		}
+	}
+
+	return index
+}()
+
+// countryCode resolves an ISO 3166-1 alpha-2, alpha-3 code or an English
+// country name to the alpha-2 code.
+func countryCode(value string) (string, bool) {
+	code, ok := countryIndex[foldText(value)]
+	return code, ok
+}
+
+// IsCountry reports whether the value is a known country code or name.
+func IsCountry(value string) bool {
+	_, ok := countryCode(value)
+	return ok
+}
+
+// foldText collapses whitespace and folds case, the result is only used for
+// comparison.
+func foldText(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
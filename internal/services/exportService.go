@@ -0,0 +1,115 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type productIterator interface {
+	ForEach(ctx context.Context, fn func(product domain.Product) error) error
+}
+
+type supplierIterator interface {
+	ForEach(ctx context.Context, fn func(supplier domain.Supplier) error) error
+}
+
+type clientIterator interface {
+	ForEach(ctx context.Context, fn func(client domain.Client) error) error
+}
+
+type lowStockIterator interface {
+	ForEachLowStock(ctx context.Context, supplierId *uuid.UUID, fn func(item domain.LowStockItem) error) error
+}
+
+// exportEntities are entities accepted by export, purchase orders are not
+// imported.
+var exportEntities = map[string]struct{}{
+	domain.ImportProducts:       {},
+	domain.ImportSuppliers:      {},
+	domain.ImportClients:        {},
+	domain.ExportPurchaseOrders: {},
+}
+
+type exportService struct {
+	products  productIterator
+	suppliers supplierIterator
+	clients   clientIterator
+	inventory lowStockIterator
+	logger    *logger.Logger
+}
+
+func NewExportService(products productIterator, suppliers supplierIterator, clients clientIterator, inventory lowStockIterator, logger *logger.Logger) *exportService {
+	logger.Debug("Export service is created")
+	return &exportService{
+		products:  products,
+		suppliers: suppliers,
+		clients:   clients,
+		inventory: inventory,
+		logger:    logger,
+	}
+}
+
+// Columns returns the columns of exported entity, ErrNotFound for an unknown one.
+func (s *exportService) Columns(entity string) ([]string, error) {
+	op := "services.exportService.Columns"
+
+	switch entity {
+	case domain.ImportProducts:
+		return mapper.ProductRecordColumns, nil
+	case domain.ImportSuppliers:
+		return mapper.SupplierRecordColumns, nil
+	case domain.ImportClients:
+		return mapper.ClientRecordColumns, nil
+	case domain.ExportPurchaseOrders:
+		return mapper.PurchaseOrderRecordColumns, nil
+	}
+
+	return nil, fmt.Errorf("%s: entity %q: %w", op, entity, crud_errors.ErrNotFound)
+}
+
+// Export streams all rows of the entity to the writer without loading them at once.
+func (s *exportService) Export(ctx context.Context, entity string, writer tabular.Writer) error {
+	op := "services.exportService.Export"
+
+	var err error
+	switch entity {
+	case domain.ImportProducts:
+		err = s.products.ForEach(ctx, func(product domain.Product) error {
+			return writer.Write(mapper.ProductToRecord(product))
+		})
+	case domain.ImportSuppliers:
+		err = s.suppliers.ForEach(ctx, func(supplier domain.Supplier) error {
+			return writer.Write(mapper.SupplierToRecord(supplier))
+		})
+	case domain.ImportClients:
+		err = s.clients.ForEach(ctx, func(client domain.Client) error {
+			return writer.Write(mapper.ClientToRecord(client))
+		})
+	case domain.ExportPurchaseOrders:
+		err = s.inventory.ForEachLowStock(ctx, nil, func(item domain.LowStockItem) error {
+			return writer.Write(mapper.LowStockItemToRecord(item))
+		})
+	default:
+		return fmt.Errorf("%s: entity %q: %w", op, entity, crud_errors.ErrNotFound)
+	}
+
+	if err != nil {
+		s.logger.Error("unable to export rows", "entity", entity, logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to export rows: %w", op, err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		s.logger.Error("unable to flush rows", "entity", entity, logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unable to flush rows: %w", op, err)
+	}
+
+	s.logger.Debug("Export finished", "entity", entity, "op", op)
+	return nil
+}
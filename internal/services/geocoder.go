@@ -0,0 +1,60 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"context"
+	"fmt"
+)
+
+type coordinates struct {
+	latitude, longitude float64
+}
+
+// localCities holds centers of cities known to LocalGeocoder, the key is the
+// country code and the folded city name.
+var localCities = map[string]coordinates{
+	"RU|moscow":           {55.7558, 37.6173},
+	"RU|saint petersburg": {59.9343, 30.3351},
+	"RU|novosibirsk":      {55.0084, 82.9357},
+	"US|new york":         {40.7128, -74.0060},
+	"US|los angeles":      {34.0522, -118.2437},
+	"US|chicago":          {41.8781, -87.6298},
+	"GB|london":           {51.5074, -0.1278},
+	"DE|berlin":           {52.5200, 13.4050},
+	"DE|munich":           {48.1351, 11.5820},
+	"FR|paris":            {48.8566, 2.3522},
+	"IT|rome":             {41.9028, 12.4964},
+	"ES|madrid":           {40.4168, -3.7038},
+	"NL|amsterdam":        {52.3676, 4.9041},
+	"JP|tokyo":            {35.6762, 139.6503},
+	"JP|osaka":            {34.6937, 135.5023},
+	"KR|seoul":            {37.5665, 126.9780},
+	"CN|beijing":          {39.9042, 116.4074},
+	"CN|shanghai":         {31.2304, 121.4737},
+	"IN|new delhi":        {28.6139, 77.2090},
+	"CA|toronto":          {43.6532, -79.3832},
+	"BR|sao paulo":        {-23.5505, -46.6333},
+	"AU|sydney":           {-33.8688, 151.2093},
+}
+
+// LocalGeocoder is an offline Geocoder resolving addresses to centers of a few
+// large cities. It stands in until a real geocoding service is plugged in.
+type LocalGeocoder struct {
+	cities map[string]coordinates
+}
+
+func NewLocalGeocoder() *LocalGeocoder {
+	return &LocalGeocoder{cities: localCities}
+}
+
+func (g *LocalGeocoder) Geocode(ctx context.Context, address domain.Address) (float64, float64, error) {
+	op := "services.localGeocoder.Geocode"
+
+	point, ok := g.cities[address.Country+"|"+foldText(address.City)]
+	if !ok {
+		return 0, 0, fmt.Errorf("%s: %w", op, crud_errors.ErrNotFound)
+	}
+
+	return point.latitude, point.longitude, nil
+}
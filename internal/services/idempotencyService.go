@@ -0,0 +1,152 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type idempotencyRepository interface {
+	Acquire(ctx context.Context, key, fingerprint string, lease time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, response domain.IdempotentResponse, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// idempotencyService holds a key for the lease while its request runs and
+// keeps the completed response for the ttl.
+type idempotencyService struct {
+	repo   idempotencyRepository
+	ttl    time.Duration
+	lease  time.Duration
+	logger *logger.Logger
+}
+
+func NewIdempotencyService(repo idempotencyRepository, ttl, lease time.Duration, logger *logger.Logger) *idempotencyService {
+	logger.Debug("Idempotency service is created", "ttl", ttl, "lease", lease)
+	return &idempotencyService{
+		repo:   repo,
+		ttl:    ttl,
+		lease:  lease,
+		logger: logger,
+	}
+}
+
+// Fingerprint identifies the request by method, path with query and body.
+func (s *idempotencyService) Fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin registers the request with the key. Nil is returned when the request
+// has to be handled, the stored response when the same request is completed.
+// ErrIdempotencyKeyReused is returned for another request with the key and
+// ErrIdempotencyKeyInProgress while the first request is not completed.
+func (s *idempotencyService) Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotentResponse, error) {
+	op := "services.idempotencyService.Begin"
+
+	acquired, err := s.repo.Acquire(ctx, key, fingerprint, s.lease)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if acquired {
+		return nil, nil
+	}
+
+	record, err := s.repo.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			// the key is expired right now, the retry takes it over
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrIdempotencyKeyInProgress)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if record.Fingerprint != fingerprint {
+		s.logger.Debug("idempotency key is reused", "key", key, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrIdempotencyKeyReused)
+	}
+
+	if !record.Completed {
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrIdempotencyKeyInProgress)
+	}
+
+	s.logger.Debug("Response is replayed", "key", key, "status", record.Response.Status, "op", op)
+	return &record.Response, nil
+}
+
+// Complete stores the response replayed for the key until it expires.
+func (s *idempotencyService) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	op := "services.idempotencyService.Complete"
+
+	if err := s.repo.Complete(ctx, key, response, s.ttl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Abort forgets the key, so the request can be retried with it.
+func (s *idempotencyService) Abort(ctx context.Context, key string) error {
+	op := "services.idempotencyService.Abort"
+
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type expiredKeyDeleter interface {
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+// IdempotencyCleaner periodically deletes expired idempotency keys.
+type IdempotencyCleaner struct {
+	repo     expiredKeyDeleter
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+func NewIdempotencyCleaner(repo expiredKeyDeleter, interval time.Duration, logger *logger.Logger) *IdempotencyCleaner {
+	logger.Debug("Idempotency cleaner is created", "interval", interval)
+	return &IdempotencyCleaner{
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run deletes expired keys every interval until the context is done.
+func (c *IdempotencyCleaner) Run(ctx context.Context) {
+	op := "services.idempotencyCleaner.Run"
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Idempotency cleaner is stopped", "op", op)
+			return
+		case <-ticker.C:
+			deleted, err := c.repo.DeleteExpired(ctx)
+			if err != nil {
+				c.logger.Error("unable to delete expired idempotency keys", logger.Err(err), "op", op)
+				continue
+			}
+
+			c.logger.Debug("Expired idempotency keys are deleted", "deleted", deleted, "op", op)
+		}
+	}
+}
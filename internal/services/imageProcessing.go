@@ -0,0 +1,171 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	jpegMarkerPrefix = 0xFF
+	jpegSOI          = 0xD8
+	jpegSOS          = 0xDA
+	jpegAPP1         = 0xE1 // Exif and XMP, contains GPS tags
+	jpegAPP13        = 0xED // Photoshop IRB, contains IPTC
+
+	// dominantColorSamples bounds the number of pixels inspected per axis.
+	dominantColorSamples = 64
+)
+
+// ImageLimits guards against decompression bombs: dimensions are read from the
+// header before the image is decoded.
+type ImageLimits struct {
+	MaxWidth  int
+	MaxHeight int
+}
+
+// processImage checks the image header against limits, strips metadata from
+// jpeg images and extracts the metadata stored along with the image.
+func processImage(data []byte, limits ImageLimits) ([]byte, domain.ImageMetadata, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ImageMetadata{}, fmt.Errorf("%v :image is corruption %w", err, crud_errors.ErrImageCorruption)
+	}
+
+	if (limits.MaxWidth > 0 && config.Width > limits.MaxWidth) || (limits.MaxHeight > 0 && config.Height > limits.MaxHeight) {
+		return nil, domain.ImageMetadata{}, fmt.Errorf("%dx%d: %w", config.Width, config.Height, crud_errors.ErrImageTooLarge)
+	}
+
+	if format == "jpeg" {
+		data, err = stripJpegMetadata(data)
+		if err != nil {
+			return nil, domain.ImageMetadata{}, fmt.Errorf("%v :image is corruption %w", err, crud_errors.ErrImageCorruption)
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ImageMetadata{}, fmt.Errorf("%v :image is corruption %w", err, crud_errors.ErrImageCorruption)
+	}
+
+	metadata := domain.ImageMetadata{
+		Width:         config.Width,
+		Height:        config.Height,
+		Format:        format,
+		Size:          int64(len(data)),
+		DominantColor: dominantColor(img),
+	}
+
+	return data, metadata, nil
+}
+
+// stripJpegMetadata drops APP1 and APP13 segments which carry EXIF, GPS, XMP
+// and IPTC data. Scan data after SOS is copied untouched.
+func stripJpegMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != jpegMarkerPrefix || data[1] != jpegSOI {
+		return nil, fmt.Errorf("missing jpeg SOI marker")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+
+	for pos+4 <= len(data) {
+		if data[pos] != jpegMarkerPrefix {
+			return nil, fmt.Errorf("invalid jpeg marker at %d", pos)
+		}
+
+		marker := data[pos+1]
+		if marker == jpegMarkerPrefix {
+			// fill byte before marker
+			pos++
+			continue
+		}
+
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			// standalone markers without length
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("invalid jpeg segment length at %d", pos)
+		}
+
+		if marker == jpegSOS {
+			out = append(out, data[pos:]...)
+			return out, nil
+		}
+
+		if marker != jpegAPP1 && marker != jpegAPP13 {
+			out = append(out, data[pos:end]...)
+		}
+
+		pos = end
+	}
+
+	return nil, fmt.Errorf("jpeg scan data not found")
+}
+
+// dominantColor returns the most frequent color of a sampled grid with channels
+// quantized to 4 bits, formatted as hex.
+func dominantColor(img image.Image) string {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return ""
+	}
+
+	stepX := max(bounds.Dx()/dominantColorSamples, 1)
+	stepY := max(bounds.Dy()/dominantColorSamples, 1)
+
+	type bucket struct {
+		count   int
+		r, g, b uint64
+	}
+
+	buckets := make(map[uint16]*bucket)
+	var best *bucket
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+
+			r8, g8, b8 := r>>8, g>>8, b>>8
+			key := uint16(r8>>4)<<8 | uint16(g8>>4)<<4 | uint16(b8>>4)
+
+			item, ok := buckets[key]
+			if !ok {
+				item = &bucket{}
+				buckets[key] = item
+			}
+
+			item.count++
+			item.r += uint64(r8)
+			item.g += uint64(g8)
+			item.b += uint64(b8)
+
+			if best == nil || item.count > best.count {
+				best = item
+			}
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+
+	n := uint64(best.count)
+	return fmt.Sprintf("#%02x%02x%02x", best.r/n, best.g/n, best.b/n)
+}
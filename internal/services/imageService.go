@@ -5,14 +5,9 @@ import (
 	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
 
 	"github.com/google/uuid"
 )
@@ -25,16 +20,7 @@ type imageReader interface {
 type imageWriter interface {
 	Create(ctx context.Context, image *domain.Image) error
 	Update(ctx context.Context, image *domain.Image) error
-	Delete(ctx context.Context, id uuid.UUID) error
-}
-
-func validateImage(data []byte) error {
-	_, _, err := image.Decode(bytes.NewReader(data))
-	if err != nil {
-		return fmt.Errorf("%v :image is corruption %w", err, crud_errors.ErrImageCorruption)
-	}
-
-	return nil
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
 }
 
 // func generateImageHash(data []byte) string {
@@ -45,14 +31,16 @@ func validateImage(data []byte) error {
 type imageService struct {
 	uow    uow.UOW
 	reader imageReader
+	limits ImageLimits
 	logger *logger.Logger
 }
 
-func NewImageService(reader imageReader, unit uow.UOW, logger *logger.Logger) *imageService {
+func NewImageService(reader imageReader, unit uow.UOW, limits ImageLimits, logger *logger.Logger) *imageService {
 	logger.Debug("image service is created")
 	return &imageService{
 		uow:    unit,
 		reader: reader,
+		limits: limits,
 		logger: logger,
 	}
 }
@@ -60,12 +48,16 @@ func NewImageService(reader imageReader, unit uow.UOW, logger *logger.Logger) *i
 func (s *imageService) Create(ctx context.Context, image *domain.Image) error {
 	op := "services.imageService.Create"
 
-	if err := validateImage(image.Data); err != nil {
-		s.logger.Debug("image is corruption or taked data is not image", logger.Err(err), "op", op)
+	data, metadata, err := processImage(image.Data, s.limits)
+	if err != nil {
+		s.logger.Debug("image is corruption, too large or taked data is not image", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: validation error: %w", op, err)
 	}
 
-	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+	image.Data = data
+	image.Metadata = metadata
+
+	err = s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
 		imageRepoGen, err := getReposiotry(tx, uow.ImageRepoName, s.logger)
 		if err != nil {
@@ -135,15 +127,21 @@ func (s *imageService) GetById(ctx context.Context, id uuid.UUID) (*domain.Image
 	return image, nil
 }
 
+// Update replaces the image, a non-zero image.Version must match the stored
+// version.
 func (s *imageService) Update(ctx context.Context, image *domain.Image) error {
 	op := "services.imageService.Update"
 
-	if err := validateImage(image.Data); err != nil {
-		s.logger.Error("image is corruption or taked data is not image", logger.Err(err), "op", op)
+	data, metadata, err := processImage(image.Data, s.limits)
+	if err != nil {
+		s.logger.Error("image is corruption, too large or taked data is not image", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: validation error: %w", op, err)
 	}
 
-	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+	image.Data = data
+	image.Metadata = metadata
+
+	err = s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
 		imageRepoGen, err := getReposiotry(tx, uow.ImageRepoName, s.logger)
 		if err != nil {
@@ -173,7 +171,7 @@ func (s *imageService) Update(ctx context.Context, image *domain.Image) error {
 		}
 
 		if err := imageRepo.Update(ctx, image); err != nil {
-			if errors.Is(err, crud_errors.ErrNotFound) {
+			if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) {
 				s.logger.Warn("update initialize is unable", "op", uowOp)
 				return fmt.Errorf("%s: %w", uowOp, err)
 			}
@@ -186,8 +184,8 @@ func (s *imageService) Update(ctx context.Context, image *domain.Image) error {
 	})
 
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			s.logger.Warn("update initialize is unable: image not found", "op", op)
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Warn("update initialize is unable", logger.Err(err), "op", op)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -198,7 +196,9 @@ func (s *imageService) Update(ctx context.Context, image *domain.Image) error {
 	return nil
 }
 
-func (s *imageService) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete removes the image unless products use it, a non-zero version must
+// match the image version.
+func (s *imageService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "services.imageService.Delete"
 
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
@@ -216,13 +216,22 @@ func (s *imageService) Delete(ctx context.Context, id uuid.UUID) error {
 		}
 
 		savepoint := `sp_delete_image`
-		err = safeDelete(ctx, tx.GetTX(), id, imageRepo.Delete, s.logger, uowOp, savepoint)
+		remove := func(ctx context.Context, id uuid.UUID) error {
+			return imageRepo.Delete(ctx, id, version)
+		}
+
+		err = safeDelete(ctx, tx.GetTX(), id, remove, s.logger, uowOp, savepoint)
 		if err != nil {
 			if errors.Is(err, crud_errors.ErrNotFound) {
 				s.logger.Debug("image not found", "op", uowOp)
 				return nil
 			}
 
+			if errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("image is changed", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
 			s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: unable to safe delete image: %v", uowOp, err)
 		}
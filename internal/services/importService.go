@@ -0,0 +1,667 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/mapper"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// importErrorsLimit limits row errors kept in the report.
+const importErrorsLimit = 100
+
+// importEntities are entities accepted by import.
+var importEntities = map[string]struct{}{
+	domain.ImportProducts:  {},
+	domain.ImportSuppliers: {},
+	domain.ImportClients:   {},
+}
+
+type productCopier interface {
+	CopyFrom(ctx context.Context, products []domain.Product) (int64, error)
+}
+
+type categoryResolver interface {
+	GetIdsBySlugs(ctx context.Context, slugs []string) (map[string]uuid.UUID, error)
+	GetAttributes(ctx context.Context, categoryId uuid.UUID) ([]domain.AttributeDefinition, error)
+}
+
+type supplierCopier interface {
+	CopyFrom(ctx context.Context, suppliers []domain.Supplier) (int64, error)
+	GetIdsByNames(ctx context.Context, names []string) (map[string]uuid.UUID, error)
+	GetExistingIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type clientCopier interface {
+	CopyFrom(ctx context.Context, clients []domain.Client) (int64, error)
+	GetUsedContacts(ctx context.Context, emails, phones []string) (map[string]bool, error)
+}
+
+type addressBookCopier interface {
+	CopyFrom(ctx context.Context, entries map[uuid.UUID]domain.AddressBookEntry) (int64, error)
+}
+
+// rowImporter validates rows of one entity and writes them in batches.
+type rowImporter interface {
+	// add validates the record and queues it
+	add(ctx context.Context, row int, record tabular.Record) error
+	pending() int
+	// flush checks queued rows against stored data and writes the rows which
+	// pass when write is set, rows failed the check are returned
+	flush(ctx context.Context, write bool) ([]domain.ImportRowError, int, error)
+}
+
+type importService struct {
+	uow        uow.UOW
+	normalizer *AddressNormalizer
+	batchSize  int
+	logger     *logger.Logger
+}
+
+func NewImportService(unit uow.UOW, normalizer *AddressNormalizer, batchSize int, logger *logger.Logger) *importService {
+	logger.Debug("Import service is created")
+	return &importService{
+		uow:        unit,
+		normalizer: normalizer,
+		batchSize:  batchSize,
+		logger:     logger,
+	}
+}
+
+// Import reads all records and writes them in one transaction. When any row is
+// rejected nothing is written and ErrImportRejected is returned along with the
+// report, a dry run validates and writes rows but rolls back.
+func (s *importService) Import(ctx context.Context, entity string, reader tabular.Reader, dryRun bool) (*domain.ImportReport, error) {
+	op := "services.importService.Import"
+	report := &domain.ImportReport{Entity: entity, DryRun: dryRun}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		importer, err := s.newImporter(tx, entity, uowOp)
+		if err != nil {
+			return err
+		}
+
+		flush := func() error {
+			rowErrors, written, err := importer.flush(ctx, report.Failed == 0)
+			if err != nil {
+				s.logger.Error("unable to write batch", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to write batch: %w", uowOp, err)
+			}
+
+			for _, rowErr := range rowErrors {
+				report.AddError(rowErr.Row, rowErr.Message, importErrorsLimit)
+			}
+
+			report.Imported += written
+
+			return nil
+		}
+
+		for row := 1; ; row++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			var rowErr *tabular.RowError
+			if errors.As(err, &rowErr) {
+				report.Total++
+				report.AddError(row, rowErr.Error(), importErrorsLimit)
+				continue
+			}
+
+			if err != nil {
+				s.logger.Warn("unable to read import data", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to read data: %w", uowOp, err)
+			}
+
+			report.Total++
+
+			if err := importer.add(ctx, row, record); err != nil {
+				report.AddError(row, rowMessage(err), importErrorsLimit)
+				continue
+			}
+
+			if importer.pending() >= s.batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+
+		report.Valid = report.Total - report.Failed
+
+		if report.Failed > 0 {
+			return crud_errors.ErrImportRejected
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errDryRun) {
+		report.Imported = 0
+		s.logger.Info("Import dry run", "entity", entity, "total", report.Total, "op", op)
+		return report, nil
+	}
+
+	if errors.Is(err, crud_errors.ErrImportRejected) {
+		report.Imported = 0
+		s.logger.Debug("Import rejected", "entity", entity, "failed", report.Failed, "op", op)
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err != nil {
+		s.logger.Error("something wrong with UOW importing", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: unit of work import problem: %w", op, err)
+	}
+
+	s.logger.Info("Import finished", "entity", entity, "imported", report.Imported, "op", op)
+	return report, nil
+}
+
+func (s *importService) newImporter(tx uow.Transaction, entity, uowOp string) (rowImporter, error) {
+	switch entity {
+	case domain.ImportProducts:
+		products, err := importRepository[productCopier](tx, uow.ProductRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		suppliers, err := importRepository[supplierCopier](tx, uow.SupplierRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		categories, err := importRepository[categoryResolver](tx, uow.CategoryRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		return &productImporter{products: products, suppliers: suppliers, categories: categories}, nil
+	case domain.ImportSuppliers, domain.ImportClients:
+		addresses, err := importRepository[addressWriter](tx, uow.AddressRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		if entity == domain.ImportSuppliers {
+			suppliers, err := importRepository[supplierCopier](tx, uow.SupplierRepoName, s.logger, uowOp)
+			if err != nil {
+				return nil, err
+			}
+
+			book, err := importRepository[addressBookCopier](tx, uow.SupplierLocationRepoName, s.logger, uowOp)
+			if err != nil {
+				return nil, err
+			}
+
+			return &supplierImporter{
+				suppliers:  suppliers,
+				addresses:  addresses,
+				book:       book,
+				normalizer: s.normalizer,
+				seen:       make(map[string]int),
+			}, nil
+		}
+
+		clients, err := importRepository[clientCopier](tx, uow.ClientRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		book, err := importRepository[addressBookCopier](tx, uow.ClientAddressRepoName, s.logger, uowOp)
+		if err != nil {
+			return nil, err
+		}
+
+		return &clientImporter{
+			clients:    clients,
+			addresses:  addresses,
+			book:       book,
+			normalizer: s.normalizer,
+			seen:       make(map[string]int),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s: entity %q: %w", uowOp, entity, crud_errors.ErrNotFound)
+}
+
+// importRepository gets the repository from the transaction as T.
+func importRepository[T any](tx uow.Transaction, name uow.RepositoryName, log *logger.Logger, op string) (T, error) {
+	var empty T
+
+	repoGen, err := getReposiotry(tx, name, log)
+	if err != nil {
+		log.Error("get repository generator is unable", "name", name, logger.Err(err), "op", op)
+		return empty, fmt.Errorf("%s: get repository generator is unable: %v", op, err)
+	}
+
+	repo, ok := repoGen.(T)
+	if !ok {
+		log.Error("Conversion problem, not contained expected convesion", "name", name, "op", op)
+		return empty, fmt.Errorf("%s: %w", op, crud_errors.ErrConversionProblem)
+	}
+
+	return repo, nil
+}
+
+// rowMessage drops operation prefixes from the error text.
+func rowMessage(err error) string {
+	message := err.Error()
+	for strings.HasPrefix(message, "services.") {
+		i := strings.Index(message, ": ")
+		if i < 0 {
+			break
+		}
+
+		message = message[i+2:]
+	}
+
+	return message
+}
+
+type queuedProduct struct {
+	row     int
+	product domain.Product
+}
+
+type productImporter struct {
+	products   productCopier
+	suppliers  supplierCopier
+	categories categoryResolver
+	// schemas keeps attribute definitions by category id
+	schemas map[uuid.UUID][]domain.AttributeDefinition
+	queue   []queuedProduct
+}
+
+func (i *productImporter) add(_ context.Context, row int, record tabular.Record) error {
+	product, err := mapper.RecordToProduct(record)
+	if err != nil {
+		return err
+	}
+
+	product.Name = strings.TrimSpace(product.Name)
+	// the category is referenced by slug, its name is accepted as well
+	product.Category.Slug = Slugify(product.Category.Slug)
+
+	switch {
+	case product.Name == "" || product.Category.Slug == "":
+		return fmt.Errorf("name and category are required")
+	case product.Price <= 0:
+		return fmt.Errorf("price must be positive")
+	case product.AvailableStock < 0:
+		return fmt.Errorf("available_stock cannot be negative")
+	case product.Supplier.Id == uuid.Nil && product.Supplier.Name == "":
+		return fmt.Errorf("supplier_id or supplier_name is required")
+	}
+
+	i.queue = append(i.queue, queuedProduct{row: row, product: product})
+
+	return nil
+}
+
+func (i *productImporter) pending() int {
+	return len(i.queue)
+}
+
+func (i *productImporter) flush(ctx context.Context, write bool) ([]domain.ImportRowError, int, error) {
+	if len(i.queue) == 0 {
+		return nil, 0, nil
+	}
+
+	defer func() { i.queue = i.queue[:0] }()
+
+	var (
+		ids   []uuid.UUID
+		names []string
+		slugs []string
+	)
+
+	for _, item := range i.queue {
+		slugs = append(slugs, item.product.Category.Slug)
+
+		if item.product.Supplier.Id != uuid.Nil {
+			ids = append(ids, item.product.Supplier.Id)
+		} else {
+			names = append(names, item.product.Supplier.Name)
+		}
+	}
+
+	existing, err := i.suppliers.GetExistingIds(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byName, err := i.suppliers.GetIdsByNames(ctx, names)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bySlug, err := i.categories.GetIdsBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		rowErrors []domain.ImportRowError
+		products  []domain.Product
+	)
+
+	for _, item := range i.queue {
+		product := item.product
+
+		if product.Supplier.Id == uuid.Nil {
+			id, ok := byName[product.Supplier.Name]
+			if !ok {
+				rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: fmt.Sprintf("supplier %q not found", product.Supplier.Name)})
+				continue
+			}
+
+			product.Supplier.Id = id
+		} else if !existing[product.Supplier.Id] {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: fmt.Sprintf("supplier %s not found", product.Supplier.Id)})
+			continue
+		}
+
+		categoryId, ok := bySlug[product.Category.Slug]
+		if !ok {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: fmt.Sprintf("category %q not found", product.Category.Slug)})
+			continue
+		}
+
+		product.Category.Id = categoryId
+
+		definitions, ok := i.schemas[categoryId]
+		if !ok {
+			if definitions, err = i.categories.GetAttributes(ctx, categoryId); err != nil {
+				return nil, 0, err
+			}
+
+			if i.schemas == nil {
+				i.schemas = make(map[uuid.UUID][]domain.AttributeDefinition)
+			}
+
+			i.schemas[categoryId] = definitions
+		}
+
+		if product.Attributes, err = validateAttributes(definitions, product.Attributes); err != nil {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: err.Error()})
+			continue
+		}
+
+		product.Id = uuid.New()
+		products = append(products, product)
+	}
+
+	if !write || len(rowErrors) > 0 || len(products) == 0 {
+		return rowErrors, 0, nil
+	}
+
+	count, err := i.products.CopyFrom(ctx, products)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return nil, int(count), nil
+}
+
+type queuedSupplier struct {
+	row      int
+	supplier domain.Supplier
+}
+
+type supplierImporter struct {
+	suppliers  supplierCopier
+	addresses  addressWriter
+	book       addressBookCopier
+	normalizer *AddressNormalizer
+	// seen keeps rows by supplier name to find duplicates in the file
+	seen  map[string]int
+	queue []queuedSupplier
+}
+
+func (i *supplierImporter) add(ctx context.Context, row int, record tabular.Record) error {
+	supplier, err := mapper.RecordToSupplier(record)
+	if err != nil {
+		return err
+	}
+
+	if supplier.Address == nil {
+		return fmt.Errorf("address is required")
+	}
+
+	if err := validateSupplier(&supplier); err != nil {
+		return err
+	}
+
+	if err := i.normalizer.Normalize(ctx, supplier.Address); err != nil {
+		if errors.Is(err, crud_errors.ErrAddressIsEmpty) {
+			return fmt.Errorf("country, city and street are required")
+		}
+
+		return err
+	}
+
+	if first, ok := i.seen[supplier.Name]; ok {
+		return fmt.Errorf("name duplicates row %d", first)
+	}
+
+	i.seen[supplier.Name] = row
+	i.queue = append(i.queue, queuedSupplier{row: row, supplier: supplier})
+
+	return nil
+}
+
+func (i *supplierImporter) pending() int {
+	return len(i.queue)
+}
+
+func (i *supplierImporter) flush(ctx context.Context, write bool) ([]domain.ImportRowError, int, error) {
+	if len(i.queue) == 0 {
+		return nil, 0, nil
+	}
+
+	defer func() { i.queue = i.queue[:0] }()
+
+	names := make([]string, len(i.queue))
+	for j, item := range i.queue {
+		names[j] = item.supplier.Name
+	}
+
+	used, err := i.suppliers.GetIdsByNames(ctx, names)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		rowErrors []domain.ImportRowError
+		suppliers []domain.Supplier
+	)
+
+	for _, item := range i.queue {
+		if _, ok := used[item.supplier.Name]; ok {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: "name is already used"})
+			continue
+		}
+
+		supplier := item.supplier
+		supplier.Id = uuid.New()
+		suppliers = append(suppliers, supplier)
+	}
+
+	if !write || len(rowErrors) > 0 {
+		return rowErrors, 0, nil
+	}
+
+	count, err := i.suppliers.CopyFrom(ctx, suppliers)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make(map[uuid.UUID]domain.AddressBookEntry, len(suppliers))
+	for _, supplier := range suppliers {
+		if err := i.addresses.Create(ctx, supplier.Address); err != nil {
+			return nil, 0, err
+		}
+
+		entries[supplier.Id] = domain.AddressBookEntry{
+			Address:   *supplier.Address,
+			Label:     domain.AddressLabelOffice,
+			IsDefault: true,
+		}
+	}
+
+	if _, err := i.book.CopyFrom(ctx, entries); err != nil {
+		return nil, 0, err
+	}
+
+	return nil, int(count), nil
+}
+
+type queuedClient struct {
+	row    int
+	client domain.Client
+}
+
+type clientImporter struct {
+	clients    clientCopier
+	addresses  addressWriter
+	book       addressBookCopier
+	normalizer *AddressNormalizer
+	// seen keeps rows by email and phone to find duplicates in the file
+	seen  map[string]int
+	queue []queuedClient
+}
+
+func (i *clientImporter) add(ctx context.Context, row int, record tabular.Record) error {
+	client, err := mapper.RecordToClient(record)
+	if err != nil {
+		return err
+	}
+
+	if err := validateClient(&client); err != nil {
+		return err
+	}
+
+	if client.Address != nil {
+		if err := i.normalizer.Normalize(ctx, client.Address); err != nil {
+			if errors.Is(err, crud_errors.ErrAddressIsEmpty) {
+				return fmt.Errorf("country, city and street are required")
+			}
+
+			return err
+		}
+	}
+
+	for _, contact := range []string{client.Email, client.Phone} {
+		if first, ok := i.seen[contact]; ok && contact != "" {
+			return fmt.Errorf("%s duplicates row %d", contact, first)
+		}
+	}
+
+	for _, contact := range []string{client.Email, client.Phone} {
+		if contact != "" {
+			i.seen[contact] = row
+		}
+	}
+
+	i.queue = append(i.queue, queuedClient{row: row, client: client})
+
+	return nil
+}
+
+func (i *clientImporter) pending() int {
+	return len(i.queue)
+}
+
+func (i *clientImporter) flush(ctx context.Context, write bool) ([]domain.ImportRowError, int, error) {
+	if len(i.queue) == 0 {
+		return nil, 0, nil
+	}
+
+	defer func() { i.queue = i.queue[:0] }()
+
+	var emails, phones []string
+	for _, item := range i.queue {
+		if item.client.Email != "" {
+			emails = append(emails, item.client.Email)
+		}
+
+		if item.client.Phone != "" {
+			phones = append(phones, item.client.Phone)
+		}
+	}
+
+	used, err := i.clients.GetUsedContacts(ctx, emails, phones)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		rowErrors []domain.ImportRowError
+		clients   []domain.Client
+	)
+
+	for _, item := range i.queue {
+		if used[item.client.Email] || used[item.client.Phone] {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: item.row, Message: "email or phone is already used"})
+			continue
+		}
+
+		client := item.client
+		client.Id = uuid.New()
+		clients = append(clients, client)
+	}
+
+	if !write || len(rowErrors) > 0 {
+		return rowErrors, 0, nil
+	}
+
+	count, err := i.clients.CopyFrom(ctx, clients)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make(map[uuid.UUID]domain.AddressBookEntry)
+	for _, client := range clients {
+		if client.Address == nil {
+			continue
+		}
+
+		if err := i.addresses.Create(ctx, client.Address); err != nil {
+			return nil, 0, err
+		}
+
+		entries[client.Id] = domain.AddressBookEntry{
+			Address:   *client.Address,
+			Label:     domain.AddressLabelHome,
+			IsDefault: true,
+		}
+	}
+
+	if len(entries) > 0 {
+		if _, err := i.book.CopyFrom(ctx, entries); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return nil, int(count), nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type lowStockReader interface {
+	GetLowStock(ctx context.Context, supplierId *uuid.UUID, limit, offset int) ([]domain.LowStockItem, int, error)
+	ForEachLowStock(ctx context.Context, supplierId *uuid.UUID, fn func(item domain.LowStockItem) error) error
+}
+
+type inventoryService struct {
+	reader lowStockReader
+	logger *logger.Logger
+}
+
+func NewInventoryService(reader lowStockReader, logger *logger.Logger) *inventoryService {
+	logger.Debug("inventory service is created")
+	return &inventoryService{
+		reader: reader,
+		logger: logger,
+	}
+}
+
+// GetLowStock returns a page of products at or below their reorder threshold
+// and the total number of them, a non-nil supplier id limits them to the
+// supplier.
+func (s *inventoryService) GetLowStock(ctx context.Context, supplierId *uuid.UUID, limit, offset int) ([]domain.LowStockItem, int, error) {
+	op := "services.inventoryService.GetLowStock"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	items, total, err := s.reader.GetLowStock(ctx, supplierId, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("supplier not found", "op", op)
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, total, nil
+}
+
+// GetReorderSuggestions returns drafts of purchase orders: products low on
+// stock grouped by supplier with the quantity bringing them to the reorder
+// target. A non-nil supplier id gives the draft of the supplier only.
+func (s *inventoryService) GetReorderSuggestions(ctx context.Context, supplierId *uuid.UUID) ([]domain.ReorderSuggestion, error) {
+	op := "services.inventoryService.GetReorderSuggestions"
+
+	var suggestions []domain.ReorderSuggestion
+
+	// items come ordered by supplier
+	err := s.reader.ForEachLowStock(ctx, supplierId, func(item domain.LowStockItem) error {
+		last := len(suggestions) - 1
+		if last < 0 || suggestions[last].Supplier.Id != item.Supplier.Id {
+			suggestions = append(suggestions, domain.ReorderSuggestion{Supplier: item.Supplier})
+			last++
+		}
+
+		suggestions[last].Items = append(suggestions[last].Items, item)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("supplier not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type tabularImporter interface {
+	Import(ctx context.Context, entity string, reader tabular.Reader, dryRun bool) (*domain.ImportReport, error)
+}
+
+type tabularExporter interface {
+	Columns(entity string) ([]string, error)
+	Export(ctx context.Context, entity string, writer tabular.Writer) error
+}
+
+type orphanAddressCollector interface {
+	Collect(ctx context.Context) (int, error)
+}
+
+type imageStore interface {
+	GetAfter(ctx context.Context, id uuid.UUID, limit int) ([]domain.Image, error)
+	Update(ctx context.Context, image *domain.Image) error
+}
+
+// countingReader adds every read record to the job progress.
+type countingReader struct {
+	tabular.Reader
+	progress *JobProgress
+}
+
+func (r *countingReader) Read() (tabular.Record, error) {
+	record, err := r.Reader.Read()
+	if err == nil || errors.As(err, new(*tabular.RowError)) {
+		r.progress.Add(1)
+	}
+
+	return record, err
+}
+
+// countingWriter adds every written row to the job progress.
+type countingWriter struct {
+	tabular.Writer
+	progress *JobProgress
+}
+
+func (w *countingWriter) Write(values []any) error {
+	if err := w.Writer.Write(values); err != nil {
+		return err
+	}
+
+	w.progress.Add(1)
+	return nil
+}
+
+type importJobHandler struct {
+	importer tabularImporter
+}
+
+// NewImportJobHandler runs imports of the uploaded file, the import report is
+// the job result.
+func NewImportJobHandler(importer tabularImporter) JobHandler {
+	return &importJobHandler{importer: importer}
+}
+
+func (h *importJobHandler) Handle(ctx context.Context, job *domain.Job, progress *JobProgress) error {
+	format, _ := tabular.ParseFormat(job.Params["format"])
+	dryRun, _ := strconv.ParseBool(job.Params["dry_run"])
+
+	reader, err := tabular.NewReader(bytes.NewReader(job.Input), format)
+	if err != nil {
+		return err
+	}
+
+	report, err := h.importer.Import(ctx, job.Params["entity"], &countingReader{Reader: reader, progress: progress}, dryRun)
+	if report != nil {
+		result, marshalErr := json.Marshal(report)
+		if marshalErr != nil {
+			return fmt.Errorf("unable to encode import report: %v", marshalErr)
+		}
+
+		job.Result = result
+	}
+
+	if errors.Is(err, crud_errors.ErrImportRejected) {
+		return crud_errors.ErrImportRejected
+	}
+
+	return err
+}
+
+type exportJobHandler struct {
+	exporter tabularExporter
+}
+
+// NewExportJobHandler runs exports, the exported file is the job output.
+func NewExportJobHandler(exporter tabularExporter) JobHandler {
+	return &exportJobHandler{exporter: exporter}
+}
+
+func (h *exportJobHandler) Handle(ctx context.Context, job *domain.Job, progress *JobProgress) error {
+	entity := job.Params["entity"]
+	format, _ := tabular.ParseFormat(job.Params["format"])
+
+	columns, err := h.exporter.Columns(entity)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+
+	writer, err := tabular.NewWriter(&output, format, columns)
+	if err != nil {
+		return err
+	}
+
+	if err := h.exporter.Export(ctx, entity, &countingWriter{Writer: writer, progress: progress}); err != nil {
+		return err
+	}
+
+	result, err := json.Marshal(map[string]any{"entity": entity, "rows": progress.Load()})
+	if err != nil {
+		return fmt.Errorf("unable to encode export result: %v", err)
+	}
+
+	job.Result = result
+	job.Output = output.Bytes()
+	job.OutputType = format.ContentType()
+
+	return nil
+}
+
+type addressPurgeJobHandler struct {
+	collector orphanAddressCollector
+}
+
+// NewAddressPurgeJobHandler deletes orphan addresses at once instead of waiting
+// for the collector run.
+func NewAddressPurgeJobHandler(collector orphanAddressCollector) JobHandler {
+	return &addressPurgeJobHandler{collector: collector}
+}
+
+func (h *addressPurgeJobHandler) Handle(ctx context.Context, job *domain.Job, progress *JobProgress) error {
+	deleted, err := h.collector.Collect(ctx)
+	progress.Add(int64(deleted))
+	if err != nil {
+		return err
+	}
+
+	result, err := json.Marshal(map[string]int{"deleted": deleted})
+	if err != nil {
+		return fmt.Errorf("unable to encode purge result: %v", err)
+	}
+
+	job.Result = result
+
+	return nil
+}
+
+// imageReprocessBatch is the number of images loaded at once by the reprocess job.
+const imageReprocessBatch = 20
+
+type imageReprocessJobHandler struct {
+	images imageStore
+	limits ImageLimits
+}
+
+// NewImageReprocessJobHandler processes every stored image the way uploads are
+// processed, an image is saved only when its data or metadata change.
+func NewImageReprocessJobHandler(images imageStore, limits ImageLimits) JobHandler {
+	return &imageReprocessJobHandler{images: images, limits: limits}
+}
+
+func (h *imageReprocessJobHandler) Handle(ctx context.Context, job *domain.Job, progress *JobProgress) error {
+	var (
+		after                    uuid.UUID
+		updated, skipped, failed int
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		images, err := h.images.GetAfter(ctx, after, imageReprocessBatch)
+		if err != nil {
+			return err
+		}
+
+		if len(images) == 0 {
+			break
+		}
+
+		for _, image := range images {
+			after = image.Id
+			progress.Add(1)
+
+			data, metadata, err := processImage(image.Data, h.limits)
+			if err != nil {
+				failed++
+				continue
+			}
+
+			if bytes.Equal(data, image.Data) && metadata == image.Metadata {
+				skipped++
+				continue
+			}
+
+			image.Data, image.Metadata = data, metadata
+
+			// the read version guards against overwriting an image replaced meanwhile
+			err = h.images.Update(ctx, &image)
+			if errors.Is(err, crud_errors.ErrVersionMismatch) || errors.Is(err, crud_errors.ErrNotFound) {
+				skipped++
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			updated++
+		}
+	}
+
+	result, err := json.Marshal(map[string]int{"updated": updated, "skipped": skipped, "failed": failed})
+	if err != nil {
+		return fmt.Errorf("unable to encode reprocess result: %v", err)
+	}
+
+	job.Result = result
+
+	return nil
+}
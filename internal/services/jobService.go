@@ -0,0 +1,120 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/tabular"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type jobRepository interface {
+	Create(ctx context.Context, job *domain.Job) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+	GetOutput(ctx context.Context, id uuid.UUID) ([]byte, string, error)
+	Cancel(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+}
+
+type jobService struct {
+	repo   jobRepository
+	logger *logger.Logger
+}
+
+func NewJobService(repo jobRepository, logger *logger.Logger) *jobService {
+	logger.Debug("Job service is created")
+	return &jobService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Submit queues the job after checking its parameters.
+func (s *jobService) Submit(ctx context.Context, job *domain.Job) error {
+	op := "services.jobService.Submit"
+
+	if err := validateJobParams(job); err != nil {
+		s.logger.Debug("job parameters are invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("Job is queued", "id", job.Id, "kind", job.Kind, "op", op)
+	return nil
+}
+
+func (s *jobService) GetById(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	op := "services.jobService.GetById"
+
+	job, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// GetOutput returns the file produced by the job and its content type.
+func (s *jobService) GetOutput(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	op := "services.jobService.GetOutput"
+
+	output, contentType, err := s.repo.GetOutput(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return output, contentType, nil
+}
+
+// Cancel cancels a queued job, a running job is stopped by its worker soon.
+func (s *jobService) Cancel(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	op := "services.jobService.Cancel"
+
+	job, err := s.repo.Cancel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Info("Job cancellation is requested", "id", id, "status", job.Status, "op", op)
+	return job, nil
+}
+
+func validateJobParams(job *domain.Job) error {
+	switch job.Kind {
+	case domain.JobImport:
+		if _, ok := importEntities[job.Params["entity"]]; !ok {
+			return fmt.Errorf("entity %q is not importable: %w", job.Params["entity"], crud_errors.ErrInvalidParam)
+		}
+
+		if _, ok := tabular.ParseFormat(job.Params["format"]); !ok {
+			return fmt.Errorf("format %q is unknown: %w", job.Params["format"], crud_errors.ErrInvalidParam)
+		}
+
+		if _, err := strconv.ParseBool(job.Params["dry_run"]); err != nil {
+			return fmt.Errorf("dry_run is not a boolean: %w", crud_errors.ErrInvalidParam)
+		}
+
+		if len(job.Input) == 0 {
+			return crud_errors.ErrNoContent
+		}
+	case domain.JobExport:
+		if _, ok := exportEntities[job.Params["entity"]]; !ok {
+			return fmt.Errorf("entity %q is not exportable: %w", job.Params["entity"], crud_errors.ErrInvalidParam)
+		}
+
+		if _, ok := tabular.ParseFormat(job.Params["format"]); !ok {
+			return fmt.Errorf("format %q is unknown: %w", job.Params["format"], crud_errors.ErrInvalidParam)
+		}
+	case domain.JobAddressPurge, domain.JobImageReprocess:
+	default:
+		return fmt.Errorf("job kind %q is unknown: %w", job.Kind, crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
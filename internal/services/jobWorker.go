@@ -0,0 +1,321 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jobMetrics is published as "jobs" on /debug/vars.
+var jobMetrics = expvar.NewMap("jobs")
+
+// finishTimeout limits saving of the job outcome, it is done even when the
+// worker is stopping.
+const finishTimeout = 10 * time.Second
+
+var (
+	errJobCanceled = errors.New("job is canceled")
+	errJobLost     = errors.New("job lease is lost")
+)
+
+// JobProgress counts processed items of a running job, it is saved with every
+// lease renewal.
+type JobProgress struct {
+	value atomic.Int64
+}
+
+func (p *JobProgress) Add(n int64) {
+	p.value.Add(n)
+}
+
+func (p *JobProgress) Load() int64 {
+	return p.value.Load()
+}
+
+// JobHandler runs jobs of one kind. It fills the result and the output of the
+// job and stops when the context is done.
+type JobHandler interface {
+	Handle(ctx context.Context, job *domain.Job, progress *JobProgress) error
+}
+
+// JobHandlers maps job kinds to their handlers.
+type JobHandlers map[string]JobHandler
+
+type jobQueue interface {
+	Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*domain.Job, error)
+	Heartbeat(ctx context.Context, id uuid.UUID, attempt int, progress int64, lease time.Duration) (bool, error)
+	Finish(ctx context.Context, job *domain.Job) error
+	Release(ctx context.Context, id uuid.UUID, attempt int, progress int64) error
+}
+
+type JobWorkerConfig struct {
+	// Lease is how long a claimed job stays with the worker without renewal
+	Lease        time.Duration
+	PollInterval time.Duration
+	// MaxAttempts limits claims of a job whose worker stopped renewing the lease
+	MaxAttempts int
+}
+
+// JobWorker claims queued jobs one by one and runs them. The queue and the
+// handlers must use different connections, the lease is renewed while the
+// handler works.
+type JobWorker struct {
+	queue    jobQueue
+	handlers JobHandlers
+	cfg      JobWorkerConfig
+	logger   *logger.Logger
+}
+
+func NewJobWorker(queue jobQueue, handlers JobHandlers, cfg JobWorkerConfig, logger *logger.Logger) *JobWorker {
+	logger.Debug("Job worker is created", "lease", cfg.Lease, "poll interval", cfg.PollInterval)
+	return &JobWorker{
+		queue:    queue,
+		handlers: handlers,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run processes jobs until the context is done. A job running at that moment is
+// interrupted and put back to the queue.
+func (w *JobWorker) Run(ctx context.Context) {
+	op := "services.jobWorker.Run"
+
+	for {
+		job, err := w.queue.Claim(ctx, w.cfg.Lease, w.cfg.MaxAttempts)
+		if err == nil {
+			w.execute(ctx, job)
+			continue
+		}
+
+		if !errors.Is(err, crud_errors.ErrNotFound) && ctx.Err() == nil {
+			w.logger.Error("unable to claim job", logger.Err(err), "op", op)
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Job worker is stopped", "op", op)
+			return
+		case <-time.After(w.cfg.PollInterval):
+		}
+	}
+}
+
+func (w *JobWorker) execute(ctx context.Context, job *domain.Job) {
+	op := "services.jobWorker.execute"
+	jobMetrics.Add("claimed", 1)
+	w.logger.Info("Job is started", "id", job.Id, "kind", job.Kind, "attempt", job.Attempts, "op", op)
+
+	handler, ok := w.handlers[job.Kind]
+	if !ok {
+		job.Status = domain.JobFailed
+		job.Error = fmt.Sprintf("job kind %q is not supported", job.Kind)
+		w.finish(ctx, job)
+		return
+	}
+
+	if job.CancelRequested {
+		job.Status = domain.JobCanceled
+		job.Error = errJobCanceled.Error()
+		w.finish(ctx, job)
+		return
+	}
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	progress := &JobProgress{}
+	stopped := make(chan struct{})
+	heartbeatDone := make(chan struct{})
+
+	go func() {
+		defer close(heartbeatDone)
+		w.heartbeat(ctx, job, progress, cancel, stopped)
+	}()
+
+	err := w.handle(jobCtx, handler, job, progress)
+	close(stopped)
+	<-heartbeatDone
+
+	cause := context.Cause(jobCtx)
+	job.Progress = progress.Load()
+
+	switch {
+	case err == nil:
+		job.Status = domain.JobSucceeded
+	case errors.Is(cause, errJobCanceled):
+		job.Status = domain.JobCanceled
+		job.Error = errJobCanceled.Error()
+	case errors.Is(cause, errJobLost):
+		// another worker took the job over, the outcome is its business
+		w.lost(job)
+		return
+	case ctx.Err() != nil:
+		w.release(ctx, job)
+		return
+	default:
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+	}
+
+	w.finish(ctx, job)
+}
+
+// handle runs the handler, a panic fails the job instead of the worker.
+func (w *JobWorker) handle(ctx context.Context, handler JobHandler, job *domain.Job, progress *JobProgress) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Error("job handler panicked", "id", job.Id, "panic", r, "op", "services.jobWorker.handle")
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+
+	return handler.Handle(ctx, job, progress)
+}
+
+// heartbeat renews the lease until stopped is closed and cancels the job when
+// cancellation is requested or the lease is lost.
+func (w *JobWorker) heartbeat(ctx context.Context, job *domain.Job, progress *JobProgress, cancel context.CancelCauseFunc, stopped <-chan struct{}) {
+	op := "services.jobWorker.heartbeat"
+	ticker := time.NewTicker(w.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopped:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelRequested, err := w.queue.Heartbeat(ctx, job.Id, job.Attempts, progress.Load(), w.cfg.Lease)
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				cancel(errJobLost)
+				return
+			}
+
+			if err != nil {
+				// the lease lasts for a few more renewals, the next one may pass
+				w.logger.Warn("unable to renew job lease", "id", job.Id, logger.Err(err), "op", op)
+				continue
+			}
+
+			if cancelRequested {
+				w.logger.Info("Job cancellation is requested", "id", job.Id, "op", op)
+				cancel(errJobCanceled)
+				return
+			}
+		}
+	}
+}
+
+func (w *JobWorker) finish(ctx context.Context, job *domain.Job) {
+	op := "services.jobWorker.finish"
+
+	finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	err := w.queue.Finish(finishCtx, job)
+	if errors.Is(err, crud_errors.ErrNotFound) {
+		// the lease expired before the outcome was saved, the job is run again
+		w.lost(job)
+		return
+	}
+
+	if err != nil {
+		w.logger.Error("unable to save job outcome", "id", job.Id, logger.Err(err), "op", op)
+		return
+	}
+
+	jobMetrics.Add(job.Status, 1)
+	w.logger.Info("Job is finished", "id", job.Id, "status", job.Status, "error", job.Error, "op", op)
+}
+
+func (w *JobWorker) release(ctx context.Context, job *domain.Job) {
+	op := "services.jobWorker.release"
+
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	// an unreleased job is claimed again when its lease is expired
+	err := w.queue.Release(releaseCtx, job.Id, job.Attempts, job.Progress)
+	if errors.Is(err, crud_errors.ErrNotFound) {
+		w.lost(job)
+		return
+	}
+
+	if err != nil {
+		w.logger.Error("unable to release job", "id", job.Id, logger.Err(err), "op", op)
+		return
+	}
+
+	jobMetrics.Add("released", 1)
+	w.logger.Info("Job is put back to the queue", "id", job.Id, "op", op)
+}
+
+// lost reports the job claimed by another worker after the lease expired.
+func (w *JobWorker) lost(job *domain.Job) {
+	jobMetrics.Add("lost", 1)
+	w.logger.Warn("Job lease is lost", "id", job.Id, "attempt", job.Attempts, "op", "services.jobWorker.lost")
+}
+
+type jobJanitor interface {
+	FailAbandoned(ctx context.Context, maxAttempts int) (int, error)
+	DeleteFinished(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// JobCleaner periodically fails jobs abandoned by workers too many times and
+// deletes jobs finished longer than the retention ago.
+type JobCleaner struct {
+	repo        jobJanitor
+	interval    time.Duration
+	retention   time.Duration
+	maxAttempts int
+	logger      *logger.Logger
+}
+
+func NewJobCleaner(repo jobJanitor, interval, retention time.Duration, maxAttempts int, logger *logger.Logger) *JobCleaner {
+	logger.Debug("Job cleaner is created", "interval", interval, "retention", retention)
+	return &JobCleaner{
+		repo:        repo,
+		interval:    interval,
+		retention:   retention,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// Run cleans jobs every interval until the context is done.
+func (c *JobCleaner) Run(ctx context.Context) {
+	op := "services.jobCleaner.Run"
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("Job cleaner is stopped", "op", op)
+			return
+		case <-ticker.C:
+			abandoned, err := c.repo.FailAbandoned(ctx, c.maxAttempts)
+			if err != nil {
+				c.logger.Error("unable to fail abandoned jobs", logger.Err(err), "op", op)
+			}
+
+			deleted, err := c.repo.DeleteFinished(ctx, c.retention)
+			if err != nil {
+				c.logger.Error("unable to delete finished jobs", logger.Err(err), "op", op)
+			}
+
+			jobMetrics.Add(domain.JobFailed, int64(abandoned))
+			c.logger.Debug("Jobs are cleaned", "abandoned", abandoned, "deleted", deleted, "op", op)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// lowStockMetrics is published as "low_stock" on /debug/vars.
+var lowStockMetrics = expvar.NewMap("low_stock")
+
+type lowStockPublisher interface {
+	Fire(ctx context.Context, event domain.LowStockEvent)
+}
+
+// LowStockAlerts fires low stock events after the change is committed, every
+// event is logged and counted by its source.
+type LowStockAlerts struct {
+	logger *logger.Logger
+}
+
+func NewLowStockAlerts(logger *logger.Logger) *LowStockAlerts {
+	logger.Debug("Low stock alerts are created")
+	return &LowStockAlerts{
+		logger: logger,
+	}
+}
+
+// Fire logs and counts the event.
+func (a *LowStockAlerts) Fire(ctx context.Context, event domain.LowStockEvent) {
+	op := "services.lowStockAlerts.Fire"
+
+	lowStockMetrics.Add("events_"+event.Source, 1)
+	a.logger.Warn("Product is low on stock", "product_id", event.Item.ProductId, "sku", event.Item.Sku,
+		"supplier_id", event.Item.Supplier.Id, "stock", event.Item.AvailableStock,
+		"threshold", event.Item.Reorder.Threshold, "source", event.Source, "op", op)
+}
+
+type lowStockFlagger interface {
+	FlagLowStock(ctx context.Context) ([]domain.LowStockItem, error)
+}
+
+// LowStockEvaluator periodically flags products at or below their reorder
+// threshold which are not flagged by a stock change, e.g. after an import or a
+// lowered threshold, and fires an event for each of them.
+type LowStockEvaluator struct {
+	repo     lowStockFlagger
+	alerts   lowStockPublisher
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+func NewLowStockEvaluator(repo lowStockFlagger, alerts lowStockPublisher, interval time.Duration, logger *logger.Logger) *LowStockEvaluator {
+	logger.Debug("Low stock evaluator is created", "interval", interval)
+	return &LowStockEvaluator{
+		repo:     repo,
+		alerts:   alerts,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run evaluates stock every interval until the context is done.
+func (e *LowStockEvaluator) Run(ctx context.Context) {
+	op := "services.lowStockEvaluator.Run"
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Low stock evaluator is stopped", "op", op)
+			return
+		case <-ticker.C:
+			if _, err := e.Evaluate(ctx); err != nil {
+				e.logger.Error("low stock evaluation failed", logger.Err(err), "op", op)
+			}
+		}
+	}
+}
+
+// Evaluate flags products low on stock and returns the number of newly
+// flagged products.
+func (e *LowStockEvaluator) Evaluate(ctx context.Context) (int, error) {
+	op := "services.lowStockEvaluator.Evaluate"
+
+	lowStockMetrics.Add("evaluations", 1)
+
+	items, err := e.repo.FlagLowStock(ctx)
+	if err != nil {
+		lowStockMetrics.Add("failures", 1)
+		return 0, fmt.Errorf("%s: %v", op, err)
+	}
+
+	for _, item := range items {
+		e.alerts.Fire(ctx, domain.LowStockEvent{Item: item, Source: domain.LowStockSourceEvaluator})
+	}
+
+	lowStockMetrics.Set("last_flagged", intVar(len(items)))
+	e.logger.Debug("Low stock is evaluated", "flagged", len(items), "op", op)
+	return len(items), nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type orderReader interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	GetByClient(ctx context.Context, clientId uuid.UUID, limit, offset int) ([]domain.Order, error)
+}
+
+type orderService struct {
+	reader orderReader
+	logger *logger.Logger
+}
+
+func NewOrderService(reader orderReader, logger *logger.Logger) *orderService {
+	logger.Debug("order service is created")
+	return &orderService{
+		reader: reader,
+		logger: logger,
+	}
+}
+
+func (s *orderService) GetById(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	op := "services.orderService.GetById"
+	order, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("order not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return order, nil
+}
+
+// GetByClient returns a page of the client orders, the latest first.
+func (s *orderService) GetByClient(ctx context.Context, clientId uuid.UUID, limit, offset int) ([]domain.Order, error) {
+	op := "services.orderService.GetByClient"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	orders, err := s.reader.GetByClient(ctx, clientId, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("client not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return orders, nil
+}
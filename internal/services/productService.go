@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -15,26 +16,51 @@ import (
 type productReader interface {
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Product, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	GetBySku(ctx context.Context, sku string) (*domain.Product, error)
+	GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	GetBySupplier(ctx context.Context, supplierId uuid.UUID, filter domain.ProductFilter) ([]domain.Product, int, error)
+	GetByCategory(ctx context.Context, categoryId uuid.UUID, includeSubcategories bool, filter domain.ProductFilter) ([]domain.Product, int, error)
+	GetStockMovements(ctx context.Context, productId uuid.UUID, limit, offset int) ([]domain.StockMovement, error)
+	GetWarehouseStock(ctx context.Context, productId uuid.UUID) ([]domain.WarehouseStock, error)
 }
 
 type productWriter interface {
 	Create(ctx context.Context, product *domain.Product) error
-	Update(ctx context.Context, id uuid.UUID, decrease int) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, product *domain.Product) error
+	ChangeStock(ctx context.Context, movement *domain.StockMovement, version int64) (*domain.LowStockItem, error)
+	TransferStock(ctx context.Context, movement *domain.StockMovement, version int64) error
+	SetReorder(ctx context.Context, id uuid.UUID, policy *domain.ReorderPolicy, version int64) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+type productImageWriter interface {
+	Attach(ctx context.Context, productId uuid.UUID, productImage *domain.ProductImage) error
+	Detach(ctx context.Context, productId, imageId uuid.UUID) error
+	Reorder(ctx context.Context, productId uuid.UUID, imageIds []uuid.UUID) error
+	SetPrimary(ctx context.Context, productId, imageId uuid.UUID) error
+}
+
+type warehousePicker interface {
+	Pick(ctx context.Context, productId uuid.UUID, quantity int64, pick domain.WarehousePick) (*uuid.UUID, error)
 }
 
 type productService struct {
 	uow    uow.UOW
 	reader productReader
-	logger *logger.Logger
+	alerts lowStockPublisher
+	// stockStrategy picks the warehouse of a decrease without a strategy
+	stockStrategy string
+	logger        *logger.Logger
 }
 
-func NewProductService(reader productReader, uow uow.UOW, logger *logger.Logger) *productService {
-	logger.Debug("product service is created")
+func NewProductService(reader productReader, uow uow.UOW, alerts lowStockPublisher, stockStrategy string, logger *logger.Logger) *productService {
+	logger.Debug("product service is created", "stock_strategy", stockStrategy)
 	return &productService{
-		uow:    uow,
-		reader: reader,
-		logger: logger,
+		uow:           uow,
+		reader:        reader,
+		alerts:        alerts,
+		stockStrategy: stockStrategy,
+		logger:        logger,
 	}
 }
 
@@ -89,36 +115,198 @@ func (s *productService) Create(ctx context.Context, product *domain.Product) er
 		// 	return fmt.Errorf("%s: failed get image by id %w", uowOp, err)
 		// }
 
-		// Creating a product repository, type assertion is necessary, since the function returns any
+		return s.create(ctx, tx, product, uowOp)
+	})
+
+	if err != nil {
+		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work creating problem: %w", op, err)
+	}
+
+	return nil
+}
+
+// create validates the product and writes it with its images in the
+// transaction.
+func (s *productService) create(ctx context.Context, tx uow.Transaction, product *domain.Product, uowOp string) error {
+	if err := validateProduct(product); err != nil {
+		s.logger.Debug("product data is invalid", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, err)
+	}
+
+	// Creating a product repository, type assertion is necessary, since the function returns any
+	productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get product repository generator is unable", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: get product repository generator is unable: %v", uowOp, err)
+	}
+
+	// Type assertion for extract product reposiotry
+	productRepo, ok := productRepoGen.(productWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	if err := checkProductAttributes(ctx, tx, product, s.logger, uowOp); err != nil {
+		return err
+	}
+
+	if err := productRepo.Create(ctx, product); err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("product is rejected", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		s.logger.Error("failed to create product", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: failed to create product: %v", uowOp, err)
+	}
+
+	if len(product.Images) == 0 {
+		return nil
+	}
+
+	productImageRepoGen, err := getReposiotry(tx, uow.ProductImageRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get product image repository generator is unable", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: get product image repository generator is unable: %v", uowOp, err)
+	}
+
+	productImageRepo, ok := productImageRepoGen.(productImageWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	for i := range product.Images {
+		if err := productImageRepo.Attach(ctx, product.Id, &product.Images[i]); err != nil {
+			s.logger.Warn("failed to attach image to created product", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to attach image: %w", uowOp, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateVariant creates the variant of the parent product. The variant gets
+// the category and the supplier of the parent and the parent attributes not
+// set or removed by null by the variant, its name is the parent name when it is not given.
+func (s *productService) CreateVariant(ctx context.Context, parentId uuid.UUID, variant *domain.Product) error {
+	op := "services.productService.CreateVariant"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
 		productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, s.logger)
 		if err != nil {
 			s.logger.Error("get product repository generator is unable", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: get product repository generator is unable: %v", uowOp, err)
 		}
 
-		// Type assertion for extract product reposiotry
-		productRepo, ok := productRepoGen.(productWriter)
+		productRepo, ok := productRepoGen.(productReader)
 		if !ok {
-			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			s.logger.Error("conversion problem, not contained expected convesion", "op", uowOp)
 			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
 		}
 
-		if err := productRepo.Create(ctx, product); err != nil {
-			s.logger.Error("failed to create product", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: failed to create product: %v", uowOp, err)
+		parent, err := productRepo.GetById(ctx, parentId)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("parent product not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed get product by id", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed get product by id: %v", uowOp, err)
 		}
 
-		return nil
+		if parent.ParentId != nil {
+			s.logger.Debug("variant of a variant", "op", uowOp)
+			return fmt.Errorf("%s: parent is a variant: %w", uowOp, crud_errors.ErrInvalidParam)
+		}
+
+		variant.ParentId = &parent.Id
+		variant.Category = parent.Category
+		variant.Supplier = domain.Supplier{Id: parent.Supplier.Id}
+
+		if strings.TrimSpace(variant.Name) == "" {
+			variant.Name = parent.Name
+		}
+
+		attributes := make(map[string]any, len(parent.Attributes)+len(variant.Attributes))
+		for key, value := range parent.Attributes {
+			attributes[key] = value
+		}
+
+		for key, value := range variant.Attributes {
+			if value == nil {
+				delete(attributes, key)
+				continue
+			}
+
+			attributes[key] = value
+		}
+
+		variant.Attributes = attributes
+
+		return s.create(ctx, tx, variant, uowOp)
 	})
 
 	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrInvalidParam) ||
+			errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("variant is rejected", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
-		return fmt.Errorf("%s: unit of work creating problem: %w", op, err)
+		return fmt.Errorf("%s: unit of work creating problem: %v", op, err)
 	}
 
 	return nil
 }
 
+// GetBySku returns the product or the variant with the SKU, the SKU is
+// compared in upper case.
+func (s *productService) GetBySku(ctx context.Context, sku string) (*domain.Product, error) {
+	op := "services.productService.GetBySku"
+
+	product, err := s.reader.GetBySku(ctx, NormalizeSku(sku))
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("product not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("failed get product by sku", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return product, nil
+}
+
+// GetByBarcode returns the product or the variant with the EAN-13 barcode.
+func (s *productService) GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	op := "services.productService.GetByBarcode"
+
+	if !IsEAN13(barcode) {
+		s.logger.Debug("barcode is not an EAN-13", "op", op)
+		return nil, fmt.Errorf("%s: barcode %q: %w", op, barcode, crud_errors.ErrInvalidParam)
+	}
+
+	product, err := s.reader.GetByBarcode(ctx, barcode)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("product not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("failed get product by barcode", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return product, nil
+}
+
 func (s *productService) GetAll(ctx context.Context, limit, offset int) ([]domain.Product, error) {
 	op := "services.productService.GetAll"
 
@@ -157,11 +345,63 @@ func (s *productService) GetById(ctx context.Context, id uuid.UUID) (*domain.Pro
 	return product, nil
 }
 
-func (s *productService) Update(ctx context.Context, id uuid.UUID, decrease int) error {
-	op := "services.productsService.Update"
-	if decrease < 0 {
-		s.logger.Error("not valid input value", "value", decrease, "op", op)
-		return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+// GetBySupplier returns a page of supplier products matched by the filter and
+// the total number of matched products.
+func (s *productService) GetBySupplier(ctx context.Context, supplierId uuid.UUID, filter domain.ProductFilter) ([]domain.Product, int, error) {
+	op := "services.productService.GetBySupplier"
+
+	if err := validateProductFilter(filter); err != nil {
+		s.logger.Debug("product filter is invalid", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	products, total, err := s.reader.GetBySupplier(ctx, supplierId, filter)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("supplier not found", "op", op)
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return products, total, nil
+}
+
+// GetByCategory returns a page of the category products matched by the filter
+// and the total number of matched products, products of subcategories are
+// included on request.
+func (s *productService) GetByCategory(ctx context.Context, categoryId uuid.UUID, includeSubcategories bool, filter domain.ProductFilter) ([]domain.Product, int, error) {
+	op := "services.productService.GetByCategory"
+
+	if err := validateProductFilter(filter); err != nil {
+		s.logger.Debug("product filter is invalid", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	products, total, err := s.reader.GetByCategory(ctx, categoryId, includeSubcategories, filter)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("category not found", "op", op)
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return products, total, nil
+}
+
+// Update changes the set fields of the product, a non-zero version of the
+// patch must match the product version.
+func (s *productService) Update(ctx context.Context, id uuid.UUID, patch *domain.ProductPatch) error {
+	op := "services.productService.Update"
+
+	if patch.IsEmpty() {
+		s.logger.Debug("nothing to update", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
 	}
 
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
@@ -178,44 +418,97 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, decrease int)
 			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
 		}
 
-		checkStock, err := productRepoRead.GetById(ctx, id)
+		productRepoWrite, ok := productRepoGen.(productWriter)
+		if !ok {
+			s.logger.Error("conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		product, err := productRepoRead.GetById(ctx, id)
 		if err != nil {
 			if errors.Is(err, crud_errors.ErrNotFound) {
-				s.logger.Warn("product not found", "op", op)
+				s.logger.Debug("product not found", "op", uowOp)
 				return fmt.Errorf("%s: %w", uowOp, err)
 			}
 
 			s.logger.Error("failed get product by id", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed get product by id: %v", uowOp, err)
+		}
+
+		if product.ParentId != nil && (patch.CategoryId != nil || patch.SupplierId != nil) {
+			s.logger.Debug("variant category or supplier is changed", "op", uowOp)
+			return fmt.Errorf("%s: variant has the category and the supplier of its parent: %w", uowOp, crud_errors.ErrInvalidParam)
+		}
+
+		patch.Apply(product)
+
+		if err := validateProduct(product); err != nil {
+			s.logger.Debug("product data is invalid", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: %w", uowOp, err)
 		}
 
-		if checkStock.AvailableStock < int64(decrease) {
-			s.logger.Warn("decrease value is greater than available stock", "op", uowOp)
-			return fmt.Errorf("%s: failed to update stock: %w", uowOp, crud_errors.ErrInvalidParam)
+		// the values have to match the schema of the new category too
+		if err := checkProductAttributes(ctx, tx, product, s.logger, uowOp); err != nil {
+			return err
 		}
 
-		productRepoWrite, ok := productRepoGen.(productWriter)
+		// without an expected version the read one is, so a write in between
+		// is not lost
+		if patch.Version != 0 {
+			product.Version = patch.Version
+		}
+
+		if err := productRepoWrite.Update(ctx, product); err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) ||
+				errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to update product", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to update product: %v", uowOp, err)
+		}
+
+		if patch.ImageId == nil {
+			return nil
+		}
+
+		productImageRepoGen, err := getReposiotry(tx, uow.ProductImageRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product image repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product image repository generator is unable: %v", uowOp, err)
+		}
+
+		productImageRepo, ok := productImageRepoGen.(productImageWriter)
 		if !ok {
-			s.logger.Error("conversion problem, not contained expected convesion", "op", op)
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
 			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
 		}
 
-		if err := productRepoWrite.Update(ctx, id, decrease); err != nil {
-			if errors.Is(err, crud_errors.ErrNotFound) {
-				s.logger.Debug("product not found", "op", uowOp)
-				return fmt.Errorf("%s, %w", uowOp, err)
-			}
+		err = productImageRepo.SetPrimary(ctx, id, *patch.ImageId)
+		if err == nil {
+			return nil
+		}
 
-			s.logger.Error("failed to update stock with product", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: failed to update stock with product: %w", uowOp, err)
+		if !errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Error("failed to set primary image", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to set primary image: %v", uowOp, err)
+		}
+
+		// the image is not in the gallery yet
+		primary := domain.ProductImage{Image: domain.Image{Id: *patch.ImageId}, Position: domain.AppendPosition, IsPrimary: true}
+		if err := productImageRepo.Attach(ctx, id, &primary); err != nil {
+			s.logger.Debug("failed to attach primary image", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to attach image: %w", uowOp, err)
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			s.logger.Warn("update initialize is unable: product not found", "op", op)
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrInvalidParam) ||
+			errors.Is(err, crud_errors.ErrVersionMismatch) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("update initialize is unable", logger.Err(err), "op", op)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -226,7 +519,210 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, decrease int)
 	return nil
 }
 
-func (s *productService) Delete(ctx context.Context, id uuid.UUID) error {
+// ChangeStock decreases, increases or sets the product stock and records the
+// movement, a non-zero version must match the product version. A decrease of a
+// product stocked in warehouses without a warehouse takes the stock from the
+// warehouse picked by the pick strategy, the configured one by default, nearest
+// without a destination picks by stock. A low
+// stock event is fired when the change takes the stock to the reorder threshold
+// or below it.
+func (s *productService) ChangeStock(ctx context.Context, movement *domain.StockMovement, pick domain.WarehousePick, version int64) error {
+	op := "services.productService.ChangeStock"
+
+	if err := validateStockMovement(movement); err != nil {
+		s.logger.Debug("stock movement is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pick.Strategy == "" {
+		pick.Strategy = s.stockStrategy
+	}
+
+	if err := validateWarehousePick(pick); err != nil {
+		s.logger.Debug("warehouse pick is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var lowStock *domain.LowStockItem
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		var err error
+		lowStock, err = changeStock(ctx, tx, movement, pick, version, s.logger, op+".uow")
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) ||
+			errors.Is(err, crud_errors.ErrInsufficientStock) || errors.Is(err, crud_errors.ErrVariantStock) ||
+			errors.Is(err, crud_errors.ErrWarehouseRequired) {
+			s.logger.Debug("stock change is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW stock change", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work stock change problem: %w", op, err)
+	}
+
+	s.logger.Info("product stock changed", "product_id", movement.ProductId, "operation", movement.Operation,
+		"reason", movement.Reason, "warehouse_id", movement.WarehouseId, "before", movement.StockBefore,
+		"after", movement.StockAfter, "op", op)
+
+	// the event is fired after the commit, a rolled back change fires nothing
+	if lowStock != nil {
+		s.alerts.Fire(ctx, domain.LowStockEvent{Item: *lowStock, Source: domain.LowStockSourceStockChange})
+	}
+
+	return nil
+}
+
+// TransferStock moves stock of the product between warehouses and records the
+// movement, the product stock is kept. A non-zero version must match the
+// product version.
+func (s *productService) TransferStock(ctx context.Context, movement *domain.StockMovement, version int64) error {
+	op := "services.productService.TransferStock"
+
+	movement.Operation, movement.Reason = domain.StockTransfer, domain.StockReasonTransfer
+
+	if err := validateStockTransfer(movement); err != nil {
+		s.logger.Debug("stock transfer is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product repository generator is unable: %v", uowOp, err)
+		}
+
+		productRepo, ok := productRepoGen.(productWriter)
+		if !ok {
+			s.logger.Error("conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := productRepo.TransferStock(ctx, movement, version); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) ||
+			errors.Is(err, crud_errors.ErrInsufficientStock) || errors.Is(err, crud_errors.ErrVariantStock) {
+			s.logger.Debug("stock transfer is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW stock transfer", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work stock transfer problem: %w", op, err)
+	}
+
+	s.logger.Info("product stock transferred", "product_id", movement.ProductId, "from", movement.WarehouseId,
+		"to", movement.ToWarehouseId, "quantity", movement.Quantity, "op", op)
+
+	return nil
+}
+
+// SetReorder sets the reorder threshold and target of the product, nil
+// removes them. A non-zero version must match the product version.
+func (s *productService) SetReorder(ctx context.Context, id uuid.UUID, policy *domain.ReorderPolicy, version int64) error {
+	op := "services.productService.SetReorder"
+
+	if policy != nil {
+		if err := validateReorderPolicy(policy); err != nil {
+			s.logger.Debug("reorder policy is invalid", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product repository generator is unable: %v", uowOp, err)
+		}
+
+		productRepo, ok := productRepoGen.(productWriter)
+		if !ok {
+			s.logger.Error("conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := productRepo.SetReorder(ctx, id, policy, version); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrVersionMismatch) ||
+			errors.Is(err, crud_errors.ErrInvalidParam) || errors.Is(err, crud_errors.ErrVariantStock) {
+			s.logger.Debug("reorder policy change is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW reorder policy change", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work reorder policy change problem: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetStockMovements returns the stock changes of the product, the latest first.
+func (s *productService) GetStockMovements(ctx context.Context, productId uuid.UUID, limit, offset int) ([]domain.StockMovement, error) {
+	op := "services.productService.GetStockMovements"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	if _, err := s.reader.GetById(ctx, productId); err != nil {
+		s.logger.Debug("product is not available", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	movements, err := s.reader.GetStockMovements(ctx, productId, limit, offset)
+	if err != nil {
+		// the product exists, so the page is just empty
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			return nil, nil
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return movements, nil
+}
+
+// GetWarehouseStock returns the stock of the product per warehouse, the list
+// is empty for a product not stocked in warehouses.
+func (s *productService) GetWarehouseStock(ctx context.Context, productId uuid.UUID) ([]domain.WarehouseStock, error) {
+	op := "services.productService.GetWarehouseStock"
+
+	stock, err := s.reader.GetWarehouseStock(ctx, productId)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("product not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stock, nil
+}
+
+// Delete removes the product, a non-zero version must match the product
+// version.
+func (s *productService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "services.productService.Delete"
 
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
@@ -244,13 +740,22 @@ func (s *productService) Delete(ctx context.Context, id uuid.UUID) error {
 		}
 
 		savepoint := `sp_delete_product`
-		err = safeDelete(ctx, tx.GetTX(), id, productRepo.Delete, s.logger, uowOp, savepoint)
+		remove := func(ctx context.Context, id uuid.UUID) error {
+			return productRepo.Delete(ctx, id, version)
+		}
+
+		err = safeDelete(ctx, tx.GetTX(), id, remove, s.logger, uowOp, savepoint)
 		if err != nil {
 			if errors.Is(err, crud_errors.ErrNotFound) {
 				s.logger.Debug("product not found", "op", op)
 				return nil
 			}
 
+			if errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("product is changed", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
 			s.logger.Error("unable to safe delete product", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: unable to safe delete product: %v", uowOp, err)
 		}
@@ -259,9 +764,146 @@ func (s *productService) Delete(ctx context.Context, id uuid.UUID) error {
 	})
 
 	if err != nil {
+		if errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Debug("delete initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		s.logger.Error("something wrong with UOW deleting", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: unit of work delete problem: %v", op, err)
 	}
 
 	return nil
 }
+
+func (s *productService) GetImages(ctx context.Context, productId uuid.UUID) ([]domain.ProductImage, error) {
+	op := "services.productService.GetImages"
+
+	product, err := s.GetById(ctx, productId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return product.Images, nil
+}
+
+func (s *productService) AttachImage(ctx context.Context, productId uuid.UUID, productImage *domain.ProductImage) error {
+	op := "services.productService.AttachImage"
+
+	if productImage.Position < domain.AppendPosition {
+		s.logger.Debug("invalid image position", "position", productImage.Position, "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		productImageRepoGen, err := getReposiotry(tx, uow.ProductImageRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product image repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product image repository generator is unable: %v", uowOp, err)
+		}
+
+		productImageRepo, ok := productImageRepoGen.(productImageWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := productImageRepo.Attach(ctx, productId, productImage); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("attach initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW attaching", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work attach problem: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *productService) DetachImage(ctx context.Context, productId, imageId uuid.UUID) error {
+	op := "services.productService.DetachImage"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		productImageRepoGen, err := getReposiotry(tx, uow.ProductImageRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product image repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product image repository generator is unable: %v", uowOp, err)
+		}
+
+		productImageRepo, ok := productImageRepoGen.(productImageWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := productImageRepo.Detach(ctx, productId, imageId); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("detach initialize is unable: image is not attached", "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW detaching", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work detach problem: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *productService) ReorderImages(ctx context.Context, productId uuid.UUID, imageIds []uuid.UUID) error {
+	op := "services.productService.ReorderImages"
+
+	if len(imageIds) == 0 {
+		s.logger.Debug("empty image order", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		productImageRepoGen, err := getReposiotry(tx, uow.ProductImageRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get product image repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get product image repository generator is unable: %v", uowOp, err)
+		}
+
+		productImageRepo, ok := productImageRepoGen.(productImageWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := productImageRepo.Reorder(ctx, productId, imageIds); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrInvalidParam) {
+			s.logger.Debug("reorder initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW reordering", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work reorder problem: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type promotionEvaluator interface {
+	GetProducts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]domain.PromotionProduct, error)
+	GetCandidates(ctx context.Context, codes []string, clientId *uuid.UUID, lock bool) ([]domain.PromotionCandidate, error)
+}
+
+var couponPattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// normalizeCoupons upper-cases the coupon codes and drops repeated ones.
+func normalizeCoupons(codes []string) ([]string, error) {
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !couponPattern.MatchString(code) {
+			return nil, &domain.ValidationError{Fields: []domain.FieldError{
+				{Field: "coupons", Code: "coupon", Message: "coupon code must be 3 to 32 letters, digits, '-' or '_'"},
+			}}
+		}
+
+		if !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+
+	return normalized, nil
+}
+
+// evaluatePromotions reads the products of the items and the promotion
+// candidates and evaluates them. With lock the usage of limited promotions
+// holds until the end of the transaction.
+func evaluatePromotions(ctx context.Context, repo promotionEvaluator, items []domain.PromotionItem, clientId *uuid.UUID, codes []string, lock bool) (*domain.PromotionEvaluation, error) {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductId
+	}
+
+	products, err := repo.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("products: %w", err)
+	}
+
+	candidates, err := repo.GetCandidates(ctx, codes, clientId, lock)
+	if err != nil {
+		return nil, fmt.Errorf("candidates: %w", err)
+	}
+
+	return applyPromotions(items, products, candidates, clientId, codes), nil
+}
+
+// applyPromotions gives every item the eligible promotion with the largest
+// discount, the earliest one wins a tie. Promotions do not stack on an item.
+// Notes explain the coupons and the matching promotions which are not applied.
+func applyPromotions(items []domain.PromotionItem, products map[uuid.UUID]domain.PromotionProduct,
+	candidates []domain.PromotionCandidate, clientId *uuid.UUID, codes []string) *domain.PromotionEvaluation {
+	evaluation := &domain.PromotionEvaluation{ClientId: clientId}
+	notes := promotionNotes{seen: make(map[string]bool)}
+
+	for _, code := range codes {
+		known := false
+		for _, candidate := range candidates {
+			known = known || candidate.CouponCode == code
+		}
+
+		if !known {
+			notes.add(domain.PromotionNote{
+				CouponCode: code,
+				Reason:     domain.PromotionReasonUnknownCoupon,
+				Message:    fmt.Sprintf("coupon %s is unknown", code),
+			})
+		}
+	}
+
+	matched := make([]bool, len(candidates))
+
+	for _, item := range items {
+		product := products[item.ProductId]
+		line := domain.PromotionLine{
+			ProductId: product.Id,
+			Name:      product.Name,
+			Price:     product.Price,
+			Quantity:  item.Quantity,
+		}
+
+		best := -1
+		var eligible []int
+
+		for i := range candidates {
+			candidate := &candidates[i]
+			if !candidate.Matches(product) {
+				continue
+			}
+
+			matched[i] = true
+
+			if reason, message := promotionBlocker(candidate, clientId); reason != "" {
+				notes.add(promotionNote(candidate, nil, reason, message))
+				continue
+			}
+
+			if item.Quantity < candidate.MinQuantity {
+				notes.add(promotionNote(candidate, &line.ProductId, domain.PromotionReasonMinQuantity,
+					fmt.Sprintf("at least %d units of %s are required", candidate.MinQuantity, product.Name)))
+				continue
+			}
+
+			discount := candidate.Discount(product.Price, item.Quantity)
+			if discount <= 0 {
+				continue
+			}
+
+			eligible = append(eligible, i)
+			if best < 0 || discount > line.Discount {
+				best, line.Discount = i, discount
+			}
+		}
+
+		if best >= 0 {
+			applied := &candidates[best]
+			line.Promotion = &domain.AppliedPromotion{
+				Id:          applied.Id,
+				Name:        applied.Name,
+				CouponCode:  applied.CouponCode,
+				Explanation: explainPromotion(&applied.Promotion),
+			}
+
+			for _, i := range eligible {
+				if i == best {
+					continue
+				}
+
+				notes.add(promotionNote(&candidates[i], &line.ProductId, domain.PromotionReasonBetterDiscount,
+					fmt.Sprintf("%s gets a larger discount from %s", product.Name, applied.Name)))
+			}
+		}
+
+		evaluation.Lines = append(evaluation.Lines, line)
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		if matched[i] || candidate.CouponCode == "" {
+			continue
+		}
+
+		if reason, message := promotionBlocker(candidate, clientId); reason != "" {
+			notes.add(promotionNote(candidate, nil, reason, message))
+			continue
+		}
+
+		notes.add(promotionNote(candidate, nil, domain.PromotionReasonNoMatchingItems, "no item is eligible for the promotion"))
+	}
+
+	evaluation.Notes = notes.list
+
+	return evaluation
+}
+
+// promotionBlocker returns the reason the promotion cannot apply to any item
+// of the client, an empty reason when it can.
+func promotionBlocker(candidate *domain.PromotionCandidate, clientId *uuid.UUID) (string, string) {
+	switch {
+	case !candidate.Active:
+		return domain.PromotionReasonInactive, "promotion is not active"
+	case !candidate.Started:
+		return domain.PromotionReasonNotStarted, "promotion has not started yet"
+	case candidate.Ended:
+		return domain.PromotionReasonEnded, "promotion has ended"
+	case candidate.UsageLimit != nil && candidate.Used >= *candidate.UsageLimit:
+		return domain.PromotionReasonUsageLimit, "promotion usage limit is reached"
+	case candidate.PerClientLimit != nil && clientId == nil:
+		return domain.PromotionReasonClientRequired, "promotion is limited per client, a client is required"
+	case candidate.PerClientLimit != nil && candidate.UsedByClient >= *candidate.PerClientLimit:
+		return domain.PromotionReasonClientLimit, fmt.Sprintf("client has used the promotion %d times already", candidate.UsedByClient)
+	}
+
+	return "", ""
+}
+
+// explainPromotion tells the applied promotion in words.
+func explainPromotion(promotion *domain.Promotion) string {
+	explanation := promotion.Name + ": " + promotion.Describe()
+
+	if promotion.MinQuantity > 1 {
+		explanation += fmt.Sprintf(" when buying at least %d", promotion.MinQuantity)
+	}
+
+	if promotion.CouponCode != "" {
+		explanation += " with coupon " + promotion.CouponCode
+	}
+
+	return explanation
+}
+
+func promotionNote(candidate *domain.PromotionCandidate, productId *uuid.UUID, reason, message string) domain.PromotionNote {
+	id := candidate.Id
+	return domain.PromotionNote{
+		PromotionId: &id,
+		Name:        candidate.Name,
+		CouponCode:  candidate.CouponCode,
+		ProductId:   productId,
+		Reason:      reason,
+		Message:     message,
+	}
+}
+
+// promotionNotes keeps one note of a promotion, a reason and a product.
+type promotionNotes struct {
+	list []domain.PromotionNote
+	seen map[string]bool
+}
+
+func (n *promotionNotes) add(note domain.PromotionNote) {
+	key := note.CouponCode + "|" + note.Reason
+	if note.PromotionId != nil {
+		key += "|" + note.PromotionId.String()
+	}
+
+	if note.ProductId != nil {
+		key += "|" + note.ProductId.String()
+	}
+
+	if n.seen[key] {
+		return
+	}
+
+	n.seen[key] = true
+	n.list = append(n.list, note)
+}
+
+// validatePromotionItems requires items with positive quantities and merges
+// repeated products.
+func validatePromotionItems(items []domain.PromotionItem) ([]domain.PromotionItem, error) {
+	if len(items) == 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "items", Code: "min", Message: "items must not be empty"},
+		}}
+	}
+
+	merged := make([]domain.PromotionItem, 0, len(items))
+	index := make(map[uuid.UUID]int, len(items))
+
+	for _, item := range items {
+		if err := validateCartQuantity(item.Quantity); err != nil {
+			return nil, err
+		}
+
+		if i, ok := index[item.ProductId]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+
+		index[item.ProductId] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged, nil
+}
+
+// couponNotApplied returns the first coupon discounting no line of the
+// evaluation, an empty string when every coupon is applied.
+func couponNotApplied(evaluation *domain.PromotionEvaluation, codes []string) string {
+	for _, code := range codes {
+		if !evaluation.CouponApplied(code) {
+			return code
+		}
+	}
+
+	return ""
+}
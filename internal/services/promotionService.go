@@ -0,0 +1,272 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type promotionReader interface {
+	promotionEvaluator
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Promotion, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Promotion, error)
+}
+
+type promotionWriter interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Promotion, error)
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	Update(ctx context.Context, promotion *domain.Promotion) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+// promotionRejections are errors of promotion requests passed to the caller
+// as is.
+var promotionRejections = []error{
+	crud_errors.ErrNotFound,
+	crud_errors.ErrInvalidParam,
+	crud_errors.ErrVersionMismatch,
+	crud_errors.ErrDuplicateKeyValue,
+}
+
+func isPromotionRejection(err error) bool {
+	for _, rejection := range promotionRejections {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type promotionService struct {
+	uow    uow.UOW
+	reader promotionReader
+	logger *logger.Logger
+}
+
+func NewPromotionService(reader promotionReader, unit uow.UOW, logger *logger.Logger) *promotionService {
+	logger.Debug("promotion service is created")
+	return &promotionService{
+		uow:    unit,
+		reader: reader,
+		logger: logger,
+	}
+}
+
+// Create validates and stores the promotion, a zero MinQuantity is 1.
+func (s *promotionService) Create(ctx context.Context, promotion *domain.Promotion) error {
+	op := "services.promotionService.Create"
+
+	if promotion.MinQuantity == 0 {
+		promotion.MinQuantity = 1
+	}
+
+	if err := validatePromotion(promotion); err != nil {
+		s.logger.Debug("promotion data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		promotionRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := promotionRepo.Create(ctx, promotion); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return s.fail(op, "creating", err)
+	}
+
+	s.logger.Info("promotion created", "id", promotion.Id, "coupon_code", promotion.CouponCode, "op", op)
+
+	return nil
+}
+
+// GetAll returns a page of promotions, the latest first.
+func (s *promotionService) GetAll(ctx context.Context, limit, offset int) ([]domain.Promotion, error) {
+	op := "services.promotionService.GetAll"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	promotions, err := s.reader.GetAll(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("No content", "op", op)
+			return nil, fmt.Errorf("%s: No content (%w)", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return promotions, nil
+}
+
+func (s *promotionService) GetById(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	op := "services.promotionService.GetById"
+	promotion, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("promotion not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return promotion, nil
+}
+
+// Update changes the set promotion fields and returns the changed promotion.
+// The discount, the scope and the coupon code cannot be changed, redemptions
+// refer to them.
+func (s *promotionService) Update(ctx context.Context, id uuid.UUID, patch *domain.PromotionPatch) (*domain.Promotion, error) {
+	op := "services.promotionService.Update"
+
+	if patch.Name == nil && patch.Active == nil && patch.StartsAt == nil && patch.EndsAt == nil &&
+		patch.UsageLimit == nil && patch.PerClientLimit == nil {
+		s.logger.Debug("nothing to update", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	var promotion *domain.Promotion
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		promotionRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		promotion, err = promotionRepo.GetById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		patch.Apply(promotion)
+
+		if err := validatePromotion(promotion); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		promotion.Version = patch.Version
+		if err := promotionRepo.Update(ctx, promotion); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNoContent) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil, s.fail(op, "updating", err)
+	}
+
+	return promotion, nil
+}
+
+// Delete removes the promotion with its redemptions, a non-zero version must
+// match the promotion version.
+func (s *promotionService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "services.promotionService.Delete"
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		promotionRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := promotionRepo.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return s.fail(op, "deleting", err)
+	}
+
+	return nil
+}
+
+// Evaluate discounts the items by the automatic promotions and the promotions
+// of the coupons without redeeming them. Usage limits are checked for the
+// client when it is set, ErrNotFound is returned for a missing client or
+// product.
+func (s *promotionService) Evaluate(ctx context.Context, items []domain.PromotionItem, clientId *uuid.UUID, coupons []string) (*domain.PromotionEvaluation, error) {
+	op := "services.promotionService.Evaluate"
+
+	items, err := validatePromotionItems(items)
+	if err != nil {
+		s.logger.Debug("promotion items are invalid", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	codes, err := normalizeCoupons(coupons)
+	if err != nil {
+		s.logger.Debug("coupons are invalid", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	evaluation, err := evaluatePromotions(ctx, s.reader, items, clientId, codes, false)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("client or product not found", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %v", op, err)
+	}
+
+	return evaluation, nil
+}
+
+// fail wraps the error of the unit of work, rejections keep their sentinel.
+func (s *promotionService) fail(op, action string, err error) error {
+	if isPromotionRejection(err) {
+		s.logger.Debug("promotion request is rejected", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.logger.Error("something wrong with UOW "+action, logger.Err(err), "op", op)
+	return fmt.Errorf("%s: unit of work %s problem: %v", op, action, err)
+}
+
+func (s *promotionService) repository(tx uow.Transaction, uowOp string) (promotionWriter, error) {
+	promotionRepoGen, err := getReposiotry(tx, uow.PromotionRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get promotion repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get promotion repository generator is unable: %v", uowOp, err)
+	}
+
+	promotionRepo, ok := promotionRepoGen.(promotionWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return promotionRepo, nil
+}
@@ -15,27 +15,44 @@ import (
 type supplierReader interface {
 	GetAll(ctx context.Context, limit, offset int) ([]domain.Supplier, error)
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Supplier, error)
+	GetByName(ctx context.Context, name string) (*domain.Supplier, error)
+	GetStats(ctx context.Context, id uuid.UUID) (*domain.SupplierStats, error)
 }
 
 type supplierWriter interface {
 	Create(ctx context.Context, supplier *domain.Supplier) error
-	Update(ctx context.Context, id, newAddress uuid.UUID) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, supplier *domain.Supplier) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
 }
 
 type supplierService struct {
-	uow    uow.UOW
-	reader supplierReader
-	logger *logger.Logger
+	uow        uow.UOW
+	reader     supplierReader
+	locations  *addressBook
+	normalizer *AddressNormalizer
+	logger     *logger.Logger
 }
 
-func NewSupplierService(reader supplierReader, unit uow.UOW, logger *logger.Logger) *supplierService {
+func NewSupplierService(reader supplierReader, unit uow.UOW, normalizer *AddressNormalizer, logger *logger.Logger) *supplierService {
 	logger.Debug("Supplier service is created")
-	return &supplierService{
-		uow:    unit,
-		reader: reader,
-		logger: logger,
+	service := &supplierService{
+		uow:        unit,
+		reader:     reader,
+		normalizer: normalizer,
+		logger:     logger,
 	}
+
+	service.locations = &addressBook{
+		uow:        unit,
+		repoName:   uow.SupplierLocationRepoName,
+		labels:     supplierLocationLabels,
+		keepLast:   true,
+		entries:    service.GetLocations,
+		normalizer: normalizer,
+		logger:     logger,
+	}
+
+	return service
 }
 
 func (s *supplierService) Create(ctx context.Context, supplier *domain.Supplier) error {
@@ -46,9 +63,35 @@ func (s *supplierService) Create(ctx context.Context, supplier *domain.Supplier)
 		return fmt.Errorf("%s: %w", op, crud_errors.ErrAddressIsEmpty)
 	}
 
+	if err := validateSupplier(supplier); err != nil {
+		s.logger.Debug("supplier data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.normalizer.Normalize(ctx, supplier.Address); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
 
+		supplierRepoGen, err := getReposiotry(tx, uow.SupplierRepoName, s.logger)
+		if err != nil {
+			s.logger.Error("get supplier repository generator is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: get supplier repository generator is unable: %v", uowOp, err)
+		}
+
+		supplierRepo, ok := supplierRepoGen.(supplierWriter)
+		if !ok {
+			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
+			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		}
+
+		if err := supplierRepo.Create(ctx, supplier); err != nil {
+			s.logger.Error("failed to create supplier", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to create supplier: %w", uowOp, err)
+		}
+
 		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
 		if err != nil {
 			s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
@@ -67,23 +110,24 @@ func (s *supplierService) Create(ctx context.Context, supplier *domain.Supplier)
 			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
 		}
 
-		supplierRepoGen, err := getReposiotry(tx, uow.SupplierRepoName, s.logger)
+		bookRepo, err := s.locations.repository(tx, uowOp)
 		if err != nil {
-			s.logger.Error("get supplier repository generator is unable", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: get supplier repository generator is unable: %v", uowOp, err)
+			return err
 		}
 
-		supplierRepo, ok := supplierRepoGen.(supplierWriter)
-		if !ok {
-			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
-			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		entry := domain.AddressBookEntry{
+			Address:   *supplier.Address,
+			Label:     domain.AddressLabelOffice,
+			IsDefault: true,
 		}
 
-		if err := supplierRepo.Create(ctx, supplier); err != nil {
-			s.logger.Error("failed to create supplier", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: failed to create supplier: %w", uowOp, err)
+		if err := bookRepo.Add(ctx, supplier.Id, &entry); err != nil {
+			s.logger.Error("failed to add supplier location", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to add supplier location: %v", uowOp, err)
 		}
 
+		supplier.Locations = []domain.AddressBookEntry{entry}
+
 		return nil
 	})
 
@@ -138,27 +182,72 @@ func (s *supplierService) GetById(ctx context.Context, id uuid.UUID) (*domain.Su
 	return supplier, nil
 }
 
-func (s *supplierService) UpdateAddress(ctx context.Context, id uuid.UUID, address *domain.Address) error {
-	op := "services.supplierService.UpdateAddress"
-	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
-		uowOp := op + ".uow"
-		addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
-		if err != nil {
-			s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+func (s *supplierService) GetByName(ctx context.Context, name string) (*domain.Supplier, error) {
+	op := "services.supplierService.GetByName"
+
+	if name == "" {
+		s.logger.Debug("empty supplier name", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	supplier, err := s.reader.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("supplier not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		addressRepo, ok := addressRepoGen.(addressWriter)
-		if !ok {
-			s.logger.Error("Conversion problem, not contained expected convesion", "op", op)
-			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return supplier, nil
+}
+
+// GetStats returns the stock summary of the supplier products.
+func (s *supplierService) GetStats(ctx context.Context, id uuid.UUID) (*domain.SupplierStats, error) {
+	op := "services.supplierService.GetStats"
+	stats, err := s.reader.GetStats(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("supplier not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		s.logger.Debug("gived data", "address", address)
-		err = addressRepo.Create(ctx, address)
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return stats, nil
+}
+
+// Update changes the set supplier fields, a set address replaces the default
+// location.
+func (s *supplierService) Update(ctx context.Context, id uuid.UUID, patch *domain.SupplierPatch) error {
+	op := "services.supplierService.Update"
+
+	if !patch.HasProfile() && patch.Address == nil {
+		s.logger.Debug("nothing to update", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	if patch.Address != nil {
+		if err := s.normalizer.Normalize(ctx, patch.Address); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		supplier, err := s.reader.GetById(ctx, id)
 		if err != nil {
-			s.logger.Error("unable to create address", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("Supplier not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("Check supplier failed", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %v", uowOp, err)
 		}
 
 		supplierRepoGen, err := getReposiotry(tx, uow.SupplierRepoName, s.logger)
@@ -173,51 +262,99 @@ func (s *supplierService) UpdateAddress(ctx context.Context, id uuid.UUID, addre
 			return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
 		}
 
-		supplier, err := s.reader.GetById(ctx, id)
-		if err != nil {
-			if errors.Is(err, crud_errors.ErrNotFound) {
-				s.logger.Debug("Supplier not found", "op", op)
-				return fmt.Errorf("%s: %w", uowOp, err)
-			}
+		patch.Apply(supplier)
 
-			s.logger.Error("Check supplier failed", logger.Err(err), "op", op)
-			return fmt.Errorf("%s: %v", uowOp, err)
+		if err := validateSupplier(supplier); err != nil {
+			s.logger.Debug("supplier data is invalid", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
 		}
 
-		if err := supplierRepo.Update(ctx, id, address.Id); err != nil {
-			if errors.Is(err, crud_errors.ErrNotFound) {
+		// the profile is rewritten on address changes too, it increments the version
+		supplier.Version = patch.Version
+		if err := supplierRepo.Update(ctx, supplier); err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) ||
+				errors.Is(err, crud_errors.ErrVersionMismatch) {
 				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
 				return fmt.Errorf("%s: %w", uowOp, err)
 			}
 
-			s.logger.Error("failed to update address with supplier", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: failed to update address with supplier: %v", uowOp, err)
+			s.logger.Error("failed to update supplier", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to update supplier: %v", uowOp, err)
 		}
 
-		savepoint := `sp_delete_address`
-		err = safeDelete(ctx, tx.GetTX(), supplier.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
-		if err != nil {
-			s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+		if patch.Address == nil {
+			return nil
 		}
 
-		return nil
+		return s.replaceAddress(ctx, tx, uowOp, supplier, patch.Address)
 	})
 
 	if err != nil {
-		if errors.Is(err, crud_errors.ErrNotFound) {
-			s.logger.Debug("update initialize is unable: supplier not found", logger.Err(err), "op", op)
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) ||
+			errors.Is(err, crud_errors.ErrInvalidParam) || errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Debug("update initialize is unable", logger.Err(err), "op", op)
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		s.logger.Debug("something wrong with UOW creating", logger.Err(err), "op", op)
-		return fmt.Errorf("%s: unit of work creating problem: %w", op, err)
+		s.logger.Error("something wrong with UOW updating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work updating problem: %v", op, err)
 	}
 
 	return nil
 }
 
-func (s *supplierService) Delete(ctx context.Context, id uuid.UUID) error {
+// replaceAddress stores the address as the default supplier location and
+// deletes the previous default address if nothing refers to it anymore.
+func (s *supplierService) replaceAddress(ctx context.Context, tx uow.Transaction, uowOp string, supplier *domain.Supplier, address *domain.Address) error {
+	addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+	}
+
+	addressRepo, ok := addressRepoGen.(addressWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	if err := addressRepo.Create(ctx, address); err != nil {
+		s.logger.Error("unable to create address", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+	}
+
+	bookRepo, err := s.locations.repository(tx, uowOp)
+	if err != nil {
+		return err
+	}
+
+	if err := bookRepo.ReplaceDefault(ctx, supplier.Id, address.Id, domain.AddressLabelOffice); err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		s.logger.Error("failed to update address with supplier", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: failed to update address with supplier: %v", uowOp, err)
+	}
+
+	if supplier.Address == nil || supplier.Address.Id == address.Id {
+		return nil
+	}
+
+	savepoint := `sp_delete_address`
+	err = safeDelete(ctx, tx.GetTX(), supplier.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
+	if err != nil {
+		s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+		return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+	}
+
+	return nil
+}
+
+// Delete removes the supplier and its locations unless other entities use
+// them, a non-zero version must match the supplier version.
+func (s *supplierService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 	op := "services.supplierService.Delete"
 	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
 		uowOp := op + ".uow"
@@ -244,7 +381,12 @@ func (s *supplierService) Delete(ctx context.Context, id uuid.UUID) error {
 			return fmt.Errorf("%s: unable to get supplier data: %v", uowOp, err)
 		}
 
-		if err := supplierRepo.Delete(ctx, id); err != nil {
+		if err := supplierRepo.Delete(ctx, id, version); err != nil {
+			if errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("supplier is changed", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
 			s.logger.Error("unable to delete supplier", logger.Err(err), "op", uowOp)
 			return fmt.Errorf("%s: unable to delete supplier: %v", uowOp, err)
 		}
@@ -262,19 +404,67 @@ func (s *supplierService) Delete(ctx context.Context, id uuid.UUID) error {
 		}
 
 		savepoint := `sp_delete_address`
-		err = safeDelete(ctx, tx.GetTX(), suppler.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
-		if err != nil {
-			s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
-			return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+		for _, entry := range suppler.Locations {
+			err = safeDelete(ctx, tx.GetTX(), entry.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
+			if err != nil {
+				s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+			}
 		}
 
 		return nil
 	})
 
 	if err != nil {
+		if errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Debug("delete initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		s.logger.Error("something wrong with UOW deleting", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: unit of work delete problem: %v", op, err)
 	}
 
 	return nil
 }
+
+func (s *supplierService) GetLocations(ctx context.Context, id uuid.UUID) ([]domain.AddressBookEntry, error) {
+	op := "services.supplierService.GetLocations"
+
+	supplier, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return supplier.Locations, nil
+}
+
+func (s *supplierService) AddLocation(ctx context.Context, id uuid.UUID, entry *domain.AddressBookEntry) error {
+	op := "services.supplierService.AddLocation"
+
+	if err := s.locations.Add(ctx, id, entry); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *supplierService) UpdateLocation(ctx context.Context, id, addressId uuid.UUID, patch *domain.AddressBookPatch) error {
+	op := "services.supplierService.UpdateLocation"
+
+	if err := s.locations.Update(ctx, id, addressId, patch); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *supplierService) RemoveLocation(ctx context.Context, id, addressId uuid.UUID, version int64) error {
+	op := "services.supplierService.RemoveLocation"
+
+	if err := s.locations.Remove(ctx, id, addressId, version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
 	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
 	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
 	"context"
@@ -40,6 +41,11 @@ func safeDelete(
 			return nil
 		}
 
+		if errors.Is(err, crud_errors.ErrVersionMismatch) {
+			log.Debug("entity is changed, delete is rejected", "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		log.Debug("unexpected error during delete: rollback to SAVEPOINT is unavailable", logger.Err(err), "op", op)
 		return fmt.Errorf("%s: unexpected error from delete: %v", op, err)
 	}
@@ -47,6 +53,59 @@ func safeDelete(
 	return nil
 }
 
+// changeStock changes the product stock in the transaction and records the
+// movement. A decrease without a warehouse takes the stock from the warehouse
+// picked by pick. The low stock item is returned when the change flags the
+// product.
+func changeStock(
+	ctx context.Context,
+	tx uow.Transaction,
+	movement *domain.StockMovement,
+	pick domain.WarehousePick,
+	version int64,
+	log *logger.Logger,
+	op string,
+) (*domain.LowStockItem, error) {
+	productRepoGen, err := getReposiotry(tx, uow.ProductRepoName, log)
+	if err != nil {
+		log.Error("get product repository generator is unable", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get product repository generator is unable: %v", op, err)
+	}
+
+	productRepo, ok := productRepoGen.(productWriter)
+	if !ok {
+		log.Error("conversion problem, not contained expected convesion", "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrConversionProblem)
+	}
+
+	if movement.Operation == domain.StockDecrease && movement.WarehouseId == nil {
+		warehouseRepoGen, err := getReposiotry(tx, uow.WarehouseRepoName, log)
+		if err != nil {
+			log.Error("get warehouse repository generator is unable", logger.Err(err), "op", op)
+			return nil, fmt.Errorf("%s: get warehouse repository generator is unable: %v", op, err)
+		}
+
+		warehouseRepo, ok := warehouseRepoGen.(warehousePicker)
+		if !ok {
+			log.Error("conversion problem, not contained expected convesion", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrConversionProblem)
+		}
+
+		// nil keeps the stock of a product not stocked in warehouses
+		movement.WarehouseId, err = warehouseRepo.Pick(ctx, movement.ProductId, movement.Quantity, pick)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	lowStock, err := productRepo.ChangeStock(ctx, movement, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lowStock, nil
+}
+
 func getReposiotry(tx uow.Transaction, name uow.RepositoryName, log *logger.Logger) (uow.Repository, error) {
 	repo, err := tx.Get(name)
 	if err != nil {
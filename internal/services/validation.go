@@ -0,0 +1,485 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var allowedGenders = map[string]bool{
+	"male":   true,
+	"female": true,
+}
+
+// normalizeEmail lowercases the address so uniqueness does not depend on case.
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", nil
+	}
+
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != email {
+		return "", fmt.Errorf("email %q: %w", email, crud_errors.ErrInvalidParam)
+	}
+
+	return strings.ToLower(email), nil
+}
+
+// normalizePhone removes separators and keeps a leading plus, the result must
+// contain from 7 to 15 digits.
+func normalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", nil
+	}
+
+	var b strings.Builder
+	digits := 0
+
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			digits++
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return "", fmt.Errorf("phone %q: %w", phone, crud_errors.ErrInvalidParam)
+		}
+	}
+
+	if digits < 7 || digits > 15 {
+		return "", fmt.Errorf("phone %q: %w", phone, crud_errors.ErrInvalidParam)
+	}
+
+	return b.String(), nil
+}
+
+// normalizePhoneE164 converts an international number to E.164: a plus and
+// from 7 to 15 digits without separators, the country code cannot start with
+// zero. The 00 international prefix is accepted instead of the plus, national
+// numbers are rejected since the country is unknown.
+func normalizePhoneE164(phone string) (string, error) {
+	trimmed := strings.TrimSpace(phone)
+	if strings.HasPrefix(trimmed, "00") {
+		trimmed = "+" + trimmed[2:]
+	}
+
+	if !strings.HasPrefix(trimmed, "+") {
+		return "", fmt.Errorf("phone %q is not international: %w", phone, crud_errors.ErrInvalidParam)
+	}
+
+	normalized, err := normalizePhone(trimmed)
+	if err != nil {
+		return "", err
+	}
+
+	if normalized[1] == '0' {
+		return "", fmt.Errorf("phone %q: %w", phone, crud_errors.ErrInvalidParam)
+	}
+
+	return normalized, nil
+}
+
+// IsPhone reports whether the phone is accepted as a phone of a client.
+func IsPhone(phone string) bool {
+	_, err := normalizePhone(phone)
+	return err == nil
+}
+
+// IsInternationalPhone reports whether the phone can be converted to E.164.
+func IsInternationalPhone(phone string) bool {
+	_, err := normalizePhoneE164(phone)
+	return err == nil
+}
+
+// normalizeWebsite accepts an absolute http or https URL, a bare host gets
+// the https scheme.
+func normalizeWebsite(website string) (string, error) {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return "", nil
+	}
+
+	if !strings.Contains(website, "://") {
+		website = "https://" + website
+	}
+
+	parsed, err := url.Parse(website)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return "", fmt.Errorf("website %q: %w", website, crud_errors.ErrInvalidParam)
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	return parsed.String(), nil
+}
+
+// normalizeTaxId removes separators and upper cases the identifier, the result
+// must contain from 5 to 20 letters or digits.
+func normalizeTaxId(taxId string) (string, error) {
+	var b strings.Builder
+
+	for _, r := range strings.TrimSpace(taxId) {
+		switch {
+		case r >= '0' && r <= '9', r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r == ' ' || r == '-' || r == '.' || r == '/':
+		default:
+			return "", fmt.Errorf("tax id %q: %w", taxId, crud_errors.ErrInvalidParam)
+		}
+	}
+
+	if b.Len() != 0 && (b.Len() < 5 || b.Len() > 20) {
+		return "", fmt.Errorf("tax id %q: %w", taxId, crud_errors.ErrInvalidParam)
+	}
+
+	return b.String(), nil
+}
+
+// validateSupplier checks the profile fields and normalizes contacts in place.
+func validateSupplier(supplier *domain.Supplier) error {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.ContactPerson = strings.TrimSpace(supplier.ContactPerson)
+
+	if supplier.Name == "" {
+		return fmt.Errorf("name is required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	phone, err := normalizePhoneE164(supplier.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	email, err := normalizeEmail(supplier.Email)
+	if err != nil {
+		return err
+	}
+
+	website, err := normalizeWebsite(supplier.Website)
+	if err != nil {
+		return err
+	}
+
+	taxId, err := normalizeTaxId(supplier.TaxId)
+	if err != nil {
+		return err
+	}
+
+	supplier.PhoneNumber = phone
+	supplier.Email = email
+	supplier.Website = website
+	supplier.TaxId = taxId
+
+	return nil
+}
+
+// validateWarehouse trims the name and requires it.
+func validateWarehouse(warehouse *domain.Warehouse) error {
+	warehouse.Name = strings.TrimSpace(warehouse.Name)
+
+	if warehouse.Name == "" {
+		return fmt.Errorf("name is required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+// validateClient checks the profile fields and normalizes contacts in place.
+func validateClient(client *domain.Client) error {
+	if strings.TrimSpace(client.Name) == "" || strings.TrimSpace(client.Surname) == "" {
+		return fmt.Errorf("name and surname are required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	if !allowedGenders[client.Gender] {
+		return fmt.Errorf("gender %q: %w", client.Gender, crud_errors.ErrInvalidParam)
+	}
+
+	if client.Birthday.After(time.Now()) {
+		return fmt.Errorf("birthday in future: %w", crud_errors.ErrInvalidParam)
+	}
+
+	email, err := normalizeEmail(client.Email)
+	if err != nil {
+		return err
+	}
+
+	phone, err := normalizePhone(client.Phone)
+	if err != nil {
+		return err
+	}
+
+	client.Email = email
+	client.Phone = phone
+
+	return nil
+}
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._/-]{0,63}$`)
+
+// NormalizeSku uppercases the SKU, SKUs are compared in upper case.
+func NormalizeSku(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+// IsSku reports whether the value is a SKU of up to 64 letters, digits, dots,
+// slashes, underscores and hyphens starting with a letter or a digit.
+func IsSku(value string) bool {
+	return skuPattern.MatchString(NormalizeSku(value))
+}
+
+// IsEAN13 reports whether the value is 13 digits with a valid check digit.
+func IsEAN13(value string) bool {
+	if len(value) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		digit := int(r - '0')
+		if i == 12 {
+			return (10-sum%10)%10 == digit
+		}
+
+		// digits at odd positions counted from one weigh 1, at even ones 3
+		if i%2 == 1 {
+			digit *= 3
+		}
+
+		sum += digit
+	}
+
+	return false
+}
+
+// validateProduct checks the product fields and trims names in place, the SKU
+// is normalized.
+func validateProduct(product *domain.Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	product.Sku = NormalizeSku(product.Sku)
+	product.Barcode = strings.TrimSpace(product.Barcode)
+
+	if product.Name == "" || product.Category.Id == uuid.Nil {
+		return fmt.Errorf("name and category are required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	if product.Price <= 0 {
+		return fmt.Errorf("price %v: %w", product.Price, crud_errors.ErrInvalidParam)
+	}
+
+	if product.Sku != "" && !IsSku(product.Sku) {
+		return fmt.Errorf("sku %q: %w", product.Sku, crud_errors.ErrInvalidParam)
+	}
+
+	if product.Barcode != "" && !IsEAN13(product.Barcode) {
+		return fmt.Errorf("barcode %q is not an EAN-13: %w", product.Barcode, crud_errors.ErrInvalidParam)
+	}
+
+	if product.ParentId != nil && product.Sku == "" {
+		return fmt.Errorf("sku of a variant is required: %w", crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+// validateProductFilter checks the page, the price range and the sort key of
+// a product listing.
+func validateProductFilter(filter domain.ProductFilter) error {
+	if filter.Limit <= 0 || filter.Offset < 0 {
+		return fmt.Errorf("limit %d, offset %d: %w", filter.Limit, filter.Offset, crud_errors.ErrInvalidParam)
+	}
+
+	if filter.PriceMin != nil && filter.PriceMax != nil && *filter.PriceMin > *filter.PriceMax {
+		return fmt.Errorf("price_min is greater than price_max: %w", crud_errors.ErrInvalidParam)
+	}
+
+	switch filter.SortBy {
+	case "", domain.ProductSortName, domain.ProductSortCategory, domain.ProductSortPrice,
+		domain.ProductSortStock, domain.ProductSortLastUpdateDate:
+	default:
+		return fmt.Errorf("sort %q: %w", filter.SortBy, crud_errors.ErrInvalidParam)
+	}
+
+	return nil
+}
+
+var allowedStockReasons = map[string]bool{
+	domain.StockReasonSale:       true,
+	domain.StockReasonReturn:     true,
+	domain.StockReasonRestock:    true,
+	domain.StockReasonDamage:     true,
+	domain.StockReasonLoss:       true,
+	domain.StockReasonCorrection: true,
+}
+
+// validateStockMovement checks the operation, its quantity and reason. Decrease
+// and increase take a positive quantity, set takes the new non-negative stock.
+func validateStockMovement(movement *domain.StockMovement) error {
+	switch movement.Operation {
+	case domain.StockDecrease, domain.StockIncrease:
+		if movement.Quantity <= 0 {
+			return fmt.Errorf("quantity %d: %w", movement.Quantity, crud_errors.ErrInvalidParam)
+		}
+	case domain.StockSet:
+		if movement.Quantity < 0 {
+			return fmt.Errorf("quantity %d: %w", movement.Quantity, crud_errors.ErrInvalidParam)
+		}
+	default:
+		return fmt.Errorf("operation %q: %w", movement.Operation, crud_errors.ErrInvalidParam)
+	}
+
+	if !allowedStockReasons[movement.Reason] {
+		return fmt.Errorf("reason %q: %w", movement.Reason, crud_errors.ErrInvalidParam)
+	}
+
+	movement.Note = strings.TrimSpace(movement.Note)
+
+	return nil
+}
+
+// validateStockTransfer requires a positive quantity and different source and
+// destination warehouses.
+func validateStockTransfer(movement *domain.StockMovement) error {
+	var fields []domain.FieldError
+
+	if movement.Quantity <= 0 {
+		fields = append(fields, domain.FieldError{Field: "quantity", Code: "gt", Message: "quantity must be greater than 0"})
+	}
+
+	if movement.WarehouseId == nil || movement.ToWarehouseId == nil {
+		fields = append(fields, domain.FieldError{Field: "to_warehouse_id", Code: "required", Message: "both warehouses are required"})
+	} else if *movement.WarehouseId == *movement.ToWarehouseId {
+		fields = append(fields, domain.FieldError{Field: "to_warehouse_id", Code: "nefield", Message: "to_warehouse_id must differ from from_warehouse_id"})
+	}
+
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+
+	movement.Note = strings.TrimSpace(movement.Note)
+
+	return nil
+}
+
+// validateWarehousePick checks the strategy of picking the warehouse.
+func validateWarehousePick(pick domain.WarehousePick) error {
+	switch pick.Strategy {
+	case domain.StockStrategyMostStock, domain.StockStrategyNearest:
+		return nil
+	}
+
+	return &domain.ValidationError{Fields: []domain.FieldError{
+		{Field: "strategy", Code: "oneof", Message: "strategy must be one of most_stock, nearest"},
+	}}
+}
+
+// validateCartQuantity requires a positive quantity of a cart item.
+func validateCartQuantity(quantity int64) error {
+	if quantity > 0 {
+		return nil
+	}
+
+	return &domain.ValidationError{Fields: []domain.FieldError{
+		{Field: "quantity", Code: "gt", Message: "quantity must be greater than 0"},
+	}}
+}
+
+// validateReorderPolicy requires a non-negative threshold and a target above
+// it.
+func validateReorderPolicy(policy *domain.ReorderPolicy) error {
+	var fields []domain.FieldError
+
+	if policy.Threshold < 0 {
+		fields = append(fields, domain.FieldError{Field: "threshold", Code: "gte", Message: "threshold must be greater than or equal to 0"})
+	}
+
+	if policy.Target <= policy.Threshold {
+		fields = append(fields, domain.FieldError{Field: "target", Code: "gt", Message: "target must be greater than threshold"})
+	}
+
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+
+	return nil
+}
+
+// validatePromotion checks the discount, the scope, the window and the limits
+// of the promotion, trims the name and normalizes the coupon code in place.
+func validatePromotion(promotion *domain.Promotion) error {
+	var fields []domain.FieldError
+
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" {
+		fields = append(fields, domain.FieldError{Field: "name", Code: "required", Message: "name is required"})
+	}
+
+	switch promotion.Kind {
+	case domain.PromotionPercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			fields = append(fields, domain.FieldError{Field: "value", Code: "range", Message: "percentage must be greater than 0 and at most 100"})
+		}
+	case domain.PromotionFixed:
+		if promotion.Value <= 0 {
+			fields = append(fields, domain.FieldError{Field: "value", Code: "gt", Message: "value must be greater than 0"})
+		}
+	default:
+		fields = append(fields, domain.FieldError{Field: "kind", Code: "oneof", Message: "kind must be one of percentage, fixed"})
+	}
+
+	scopes := 0
+	for _, scope := range []*uuid.UUID{promotion.ProductId, promotion.CategoryId, promotion.SupplierId} {
+		if scope != nil {
+			scopes++
+		}
+	}
+
+	if scopes > 1 {
+		fields = append(fields, domain.FieldError{Field: "scope", Code: "excluded_with", Message: "only one of product_id, category_id, supplier_id can be set"})
+	}
+
+	if promotion.MinQuantity < 1 {
+		fields = append(fields, domain.FieldError{Field: "min_quantity", Code: "gte", Message: "min_quantity must be greater than or equal to 1"})
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.StartsAt.Before(*promotion.EndsAt) {
+		fields = append(fields, domain.FieldError{Field: "ends_at", Code: "gtfield", Message: "ends_at must be after starts_at"})
+	}
+
+	if promotion.UsageLimit != nil && *promotion.UsageLimit <= 0 {
+		fields = append(fields, domain.FieldError{Field: "usage_limit", Code: "gt", Message: "usage_limit must be greater than 0"})
+	}
+
+	if promotion.PerClientLimit != nil && *promotion.PerClientLimit <= 0 {
+		fields = append(fields, domain.FieldError{Field: "per_client_limit", Code: "gt", Message: "per_client_limit must be greater than 0"})
+	}
+
+	if promotion.CouponCode != "" {
+		promotion.CouponCode = strings.ToUpper(strings.TrimSpace(promotion.CouponCode))
+		if !couponPattern.MatchString(promotion.CouponCode) {
+			fields = append(fields, domain.FieldError{Field: "coupon_code", Code: "coupon", Message: "coupon code must be 3 to 32 letters, digits, '-' or '_'"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+
+	return nil
+}
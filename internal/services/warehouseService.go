@@ -0,0 +1,349 @@
+package services
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type warehouseReader interface {
+	GetAll(ctx context.Context, limit, offset int) ([]domain.Warehouse, error)
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	GetStock(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.WarehouseStock, error)
+}
+
+type warehouseWriter interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
+
+type warehouseService struct {
+	uow        uow.UOW
+	reader     warehouseReader
+	normalizer *AddressNormalizer
+	logger     *logger.Logger
+}
+
+func NewWarehouseService(reader warehouseReader, unit uow.UOW, normalizer *AddressNormalizer, logger *logger.Logger) *warehouseService {
+	logger.Debug("Warehouse service is created")
+	return &warehouseService{
+		uow:        unit,
+		reader:     reader,
+		normalizer: normalizer,
+		logger:     logger,
+	}
+}
+
+// Create stores the normalized address of the warehouse and the warehouse.
+func (s *warehouseService) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	op := "services.warehouseService.Create"
+
+	if warehouse.Address == nil {
+		s.logger.Debug("Address is empty", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrAddressIsEmpty)
+	}
+
+	if err := validateWarehouse(warehouse); err != nil {
+		s.logger.Debug("warehouse data is invalid", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.normalizer.Normalize(ctx, warehouse.Address); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+
+		addressRepo, err := s.addressRepository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := addressRepo.Create(ctx, warehouse.Address); err != nil {
+			s.logger.Error("address creation is unavailable", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+		}
+
+		warehouseRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := warehouseRepo.Create(ctx, warehouse); err != nil {
+			s.logger.Error("failed to create warehouse", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to create warehouse: %w", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrDuplicateKeyValue) {
+			s.logger.Debug("invalid payload recevied from user: cannot create duplicate", "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW creating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work creating problem: %v", op, err)
+	}
+
+	return nil
+}
+
+func (s *warehouseService) GetAll(ctx context.Context, limit, offset int) ([]domain.Warehouse, error) {
+	op := "services.warehouseService.GetAll"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	warehouses, err := s.reader.GetAll(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("No content", "op", op)
+			return nil, fmt.Errorf("%s: No content (%w)", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return warehouses, nil
+}
+
+func (s *warehouseService) GetById(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	op := "services.warehouseService.GetById"
+	warehouse, err := s.reader.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("warehouse not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error detected", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: get unable: %v", op, err)
+	}
+
+	return warehouse, nil
+}
+
+// GetStock returns a page of products stocked in the warehouse.
+func (s *warehouseService) GetStock(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.WarehouseStock, error) {
+	op := "services.warehouseService.GetStock"
+
+	if limit <= 0 || offset < 0 {
+		s.logger.Debug("invalid pagination", "limit", limit, "offset", offset, "op", op)
+		return nil, fmt.Errorf("%s: %w", op, crud_errors.ErrInvalidParam)
+	}
+
+	stock, err := s.reader.GetStock(ctx, id, limit, offset)
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) {
+			s.logger.Debug("warehouse not found", "op", op)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("error recieved from repository", logger.Err(err), "op", op)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stock, nil
+}
+
+// Update changes the set warehouse fields, a set address replaces the address
+// and the previous one is deleted if nothing refers to it anymore.
+func (s *warehouseService) Update(ctx context.Context, id uuid.UUID, patch *domain.WarehousePatch) error {
+	op := "services.warehouseService.Update"
+
+	if patch.Name == nil && patch.Address == nil {
+		s.logger.Debug("nothing to update", "op", op)
+		return fmt.Errorf("%s: %w", op, crud_errors.ErrNoContent)
+	}
+
+	if patch.Address != nil {
+		if err := s.normalizer.Normalize(ctx, patch.Address); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		warehouse, err := s.reader.GetById(ctx, id)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("Warehouse not found", "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("Check warehouse failed", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %v", uowOp, err)
+		}
+
+		patch.Apply(warehouse)
+
+		if err := validateWarehouse(warehouse); err != nil {
+			s.logger.Debug("warehouse data is invalid", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: %w", uowOp, err)
+		}
+
+		addressRepo, err := s.addressRepository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		previous := warehouse.Address
+		if patch.Address != nil {
+			if err := addressRepo.Create(ctx, patch.Address); err != nil {
+				s.logger.Error("unable to create address", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: unable to create address: %v", uowOp, err)
+			}
+
+			warehouse.Address = patch.Address
+		}
+
+		warehouseRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		warehouse.Version = patch.Version
+		if err := warehouseRepo.Update(ctx, warehouse); err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) ||
+				errors.Is(err, crud_errors.ErrVersionMismatch) {
+				s.logger.Debug("update initialize is unable", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("failed to update warehouse", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: failed to update warehouse: %v", uowOp, err)
+		}
+
+		if previous.Id == warehouse.Address.Id {
+			return nil
+		}
+
+		savepoint := `sp_delete_address`
+		err = safeDelete(ctx, tx.GetTX(), previous.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
+		if err != nil {
+			s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrNotFound) || errors.Is(err, crud_errors.ErrDuplicateKeyValue) ||
+			errors.Is(err, crud_errors.ErrInvalidParam) || errors.Is(err, crud_errors.ErrVersionMismatch) {
+			s.logger.Debug("update initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW updating", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work updating problem: %v", op, err)
+	}
+
+	return nil
+}
+
+// Delete removes the warehouse and its address unless other entities use it.
+// A warehouse still holding stock is not removed, a non-zero version must
+// match the warehouse version.
+func (s *warehouseService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	op := "services.warehouseService.Delete"
+	err := s.uow.Do(ctx, func(ctx context.Context, tx uow.Transaction) error {
+		uowOp := op + ".uow"
+		warehouse, err := s.reader.GetById(ctx, id)
+		if err != nil {
+			if errors.Is(err, crud_errors.ErrNotFound) {
+				s.logger.Debug("warehouse not found", "op", uowOp)
+				return nil
+			}
+
+			s.logger.Error("unable to get warehouse data", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to get warehouse data: %v", uowOp, err)
+		}
+
+		warehouseRepo, err := s.repository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		if err := warehouseRepo.Delete(ctx, id, version); err != nil {
+			if errors.Is(err, crud_errors.ErrVersionMismatch) || errors.Is(err, crud_errors.ErrForeignKeyViolation) {
+				s.logger.Debug("warehouse cannot be deleted", logger.Err(err), "op", uowOp)
+				return fmt.Errorf("%s: %w", uowOp, err)
+			}
+
+			s.logger.Error("unable to delete warehouse", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to delete warehouse: %v", uowOp, err)
+		}
+
+		addressRepo, err := s.addressRepository(tx, uowOp)
+		if err != nil {
+			return err
+		}
+
+		savepoint := `sp_delete_address`
+		err = safeDelete(ctx, tx.GetTX(), warehouse.Address.Id, addressRepo.Delete, s.logger, uowOp, savepoint)
+		if err != nil {
+			s.logger.Error("unable to safe delete address", logger.Err(err), "op", uowOp)
+			return fmt.Errorf("%s: unable to safe delete address: %v", uowOp, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, crud_errors.ErrVersionMismatch) || errors.Is(err, crud_errors.ErrForeignKeyViolation) {
+			s.logger.Debug("delete initialize is unable", logger.Err(err), "op", op)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.logger.Error("something wrong with UOW deleting", logger.Err(err), "op", op)
+		return fmt.Errorf("%s: unit of work deleting problem: %v", op, err)
+	}
+
+	return nil
+}
+
+func (s *warehouseService) repository(tx uow.Transaction, uowOp string) (warehouseWriter, error) {
+	warehouseRepoGen, err := getReposiotry(tx, uow.WarehouseRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get warehouse repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get warehouse repository generator is unable: %v", uowOp, err)
+	}
+
+	warehouseRepo, ok := warehouseRepoGen.(warehouseWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return warehouseRepo, nil
+}
+
+func (s *warehouseService) addressRepository(tx uow.Transaction, uowOp string) (addressWriter, error) {
+	addressRepoGen, err := getReposiotry(tx, uow.AddressRepoName, s.logger)
+	if err != nil {
+		s.logger.Error("get address repository generator is unable", logger.Err(err), "op", uowOp)
+		return nil, fmt.Errorf("%s: get address repository generator is unable: %v", uowOp, err)
+	}
+
+	addressRepo, ok := addressRepoGen.(addressWriter)
+	if !ok {
+		s.logger.Error("Conversion problem, not contained expected convesion", "op", uowOp)
+		return nil, fmt.Errorf("%s: %w", uowOp, crud_errors.ErrConversionProblem)
+	}
+
+	return addressRepo, nil
+}
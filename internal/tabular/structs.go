@@ -0,0 +1,155 @@
+package tabular
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// field is a flattened struct field, index is the path to it through nested structs.
+type field struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// WriteStructs writes a slice of structs as rows. Columns are named by json tags,
+// fields of nested structs are flattened as "parent.child", slices and maps are
+// written as JSON.
+func WriteStructs(w io.Writer, format Format, rows any) error {
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		return fmt.Errorf("tabular: %T is not a slice", rows)
+	}
+
+	elem := value.Type().Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("tabular: %s is not a struct", elem)
+	}
+
+	fields := flatten(elem, "", nil)
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = field.name
+	}
+
+	writer, err := NewWriter(w, format, columns)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(fields))
+	for i := 0; i < value.Len(); i++ {
+		row := reflect.Indirect(value.Index(i))
+		for j, field := range fields {
+			values[j] = cellValue(row, field.index)
+		}
+
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+func flatten(typ reflect.Type, prefix string, index []int) []field {
+	var fields []field
+
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+
+		path := append(append([]int{}, index...), i)
+
+		fieldType := structField.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType && !reflect.PointerTo(fieldType).Implements(textMarshalerType) {
+			// fields of embedded structs are promoted as JSON does
+			if structField.Anonymous && structField.Tag.Get("json") == "" {
+				fields = append(fields, flatten(fieldType, prefix, path)...)
+				continue
+			}
+
+			fields = append(fields, flatten(fieldType, prefix+name+".", path)...)
+			continue
+		}
+
+		fields = append(fields, field{name: prefix + name, index: path})
+	}
+
+	return fields
+}
+
+// cellValue returns the value by the field path, nil when a pointer on the
+// path is nil.
+func cellValue(row reflect.Value, index []int) any {
+	value := row
+	for _, i := range index {
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return nil
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(i)
+	}
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		raw, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil
+		}
+
+		return string(raw)
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+
+	return value.Interface()
+}
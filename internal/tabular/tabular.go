@@ -0,0 +1,331 @@
+// Package tabular reads and writes flat records as CSV with a header row or as
+// JSON Lines (one JSON object per line).
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// maxLineSize limits a single JSON line.
+const maxLineSize = 1 << 20
+
+// FormatFromContentType resolves the format of a media type.
+func FormatFromContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return CSV, true
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return NDJSON, true
+	}
+
+	return "", false
+}
+
+// ParseFormat resolves the format by name.
+func ParseFormat(name string) (Format, bool) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return CSV, true
+	case "ndjson", "jsonl":
+		return NDJSON, true
+	}
+
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+
+	return "application/x-ndjson"
+}
+
+// Record holds values of a row by column name.
+type Record map[string]string
+
+// RowError describes a malformed row, reading continues with the next row.
+type RowError struct {
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader returns records one by one and io.EOF after the last one. A *RowError
+// is returned for a malformed row, any other error stops reading.
+type Reader interface {
+	Read() (Record, error)
+}
+
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case CSV:
+		return newCSVReader(r)
+	case NDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	}
+
+	return nil, fmt.Errorf("tabular: unknown format %q", format)
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("tabular: header row is missing")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tabular: invalid header row: %w", err)
+	}
+
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	return &csvReader{reader: reader, header: header}, nil
+}
+
+func (r *csvReader) Read() (Record, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &RowError{Err: parseErr.Err}
+		}
+
+		return nil, err
+	}
+
+	record := make(Record, len(fields))
+	for i, value := range fields {
+		record[r.header[i]] = strings.TrimSpace(value)
+	}
+
+	return record, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *ndjsonReader) Read() (Record, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			return nil, &RowError{Err: fmt.Errorf("invalid JSON: %v", err)}
+		}
+
+		record := make(Record, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+				record[strings.ToLower(key)] = ""
+			case string:
+				record[strings.ToLower(key)] = strings.TrimSpace(v)
+			case json.Number:
+				record[strings.ToLower(key)] = v.String()
+			case bool:
+				record[strings.ToLower(key)] = strconv.FormatBool(v)
+			default:
+				return nil, &RowError{Err: fmt.Errorf("field %s is not a scalar", key)}
+			}
+		}
+
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Writer writes rows with values in the column order.
+type Writer interface {
+	Write(values []any) error
+	Flush() error
+}
+
+func NewWriter(w io.Writer, format Format, columns []string) (Writer, error) {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return nil, err
+		}
+
+		return &csvWriter{writer: writer, row: make([]string, len(columns))}, nil
+	case NDJSON:
+		keys := make([][]byte, len(columns))
+		for i, column := range columns {
+			key, err := json.Marshal(column)
+			if err != nil {
+				return nil, err
+			}
+
+			keys[i] = key
+		}
+
+		return &ndjsonWriter{writer: bufio.NewWriter(w), keys: keys}, nil
+	}
+
+	return nil, fmt.Errorf("tabular: unknown format %q", format)
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	row    []string
+}
+
+func (w *csvWriter) Write(values []any) error {
+	for i, value := range values {
+		w.row[i] = formatValue(value)
+	}
+
+	return w.writer.Write(w.row)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	writer *bufio.Writer
+	keys   [][]byte
+}
+
+func (w *ndjsonWriter) Write(values []any) error {
+	w.writer.WriteByte('{')
+
+	for i, value := range values {
+		if i > 0 {
+			w.writer.WriteByte(',')
+		}
+
+		raw, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			return err
+		}
+
+		w.writer.Write(w.keys[i])
+		w.writer.WriteByte(':')
+		w.writer.Write(raw)
+	}
+
+	w.writer.WriteString("}\n")
+
+	return nil
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// jsonValue replaces empty optional values with null.
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+	case *float64:
+		if v == nil {
+			return nil
+		}
+	case uuid.UUID:
+		if v == uuid.Nil {
+			return nil
+		}
+	}
+
+	return value
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+
+		return v.Format(time.RFC3339)
+	case uuid.UUID:
+		if v == uuid.Nil {
+			return ""
+		}
+
+		return v.String()
+	}
+
+	return fmt.Sprint(value)
+}
@@ -12,11 +12,20 @@ type Repository any
 type RepositoryGenerator func(tx pgx.Tx, log *logger.Logger) Repository
 
 const (
-	AddressRepoName  = RepositoryName("address")
-	ClientRepoName   = RepositoryName("client")
-	SupplierRepoName = RepositoryName("supplier")
-	ProductRepoName  = RepositoryName("product")
-	ImageRepoName    = RepositoryName("image")
+	AddressRepoName   = RepositoryName("address")
+	ClientRepoName    = RepositoryName("client")
+	SupplierRepoName  = RepositoryName("supplier")
+	ProductRepoName   = RepositoryName("product")
+	ImageRepoName     = RepositoryName("image")
+	CategoryRepoName  = RepositoryName("category")
+	WarehouseRepoName = RepositoryName("warehouse")
+	CartRepoName      = RepositoryName("cart")
+	OrderRepoName     = RepositoryName("order")
+	PromotionRepoName = RepositoryName("promotion")
+
+	ProductImageRepoName     = RepositoryName("product_image")
+	ClientAddressRepoName    = RepositoryName("client_address")
+	SupplierLocationRepoName = RepositoryName("supplier_location")
 )
 
 type CommandTag interface {
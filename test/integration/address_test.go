@@ -0,0 +1,326 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	repository "CRUD-HOME-APPLIANCE-STORE/internal/repositories"
+	"CRUD-HOME-APPLIANCE-STORE/internal/repositories/postgres"
+	"CRUD-HOME-APPLIANCE-STORE/internal/services"
+	"CRUD-HOME-APPLIANCE-STORE/internal/uow"
+	"CRUD-HOME-APPLIANCE-STORE/pkg/logger"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *TestSuite) TestAddressNormalization() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	variants := []dto.Address{
+		{Country: " russia ", City: "  Moscow", Street: "Tverskaya   street"},
+		{Country: "RU", City: "moscow", Street: "TVERSKAYA STREET"},
+		{Country: "rus", City: "MOSCOW ", Street: "tverskaya street"},
+		{Country: "Russian Federation", City: "Moscow", Street: "Tverskaya Street"},
+	}
+
+	for i, address := range variants {
+		resp, err := sendJSON(http.MethodPost, baseUrl, dto.ClientRequest{
+			Name:     "Gopher",
+			Surname:  fmt.Sprintf("Number %d", i),
+			Birthday: "2001-01-01",
+			Gender:   "male",
+			Address:  &address,
+		})
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+		var client dto.ClientResponse
+		s.Require().NoError(decodeJSON(resp, &client))
+		s.Require().Equal("RU", client.Address.Country)
+		s.Require().Equal("Moscow", client.Address.City)
+		s.Require().Equal("Tverskaya street", client.Address.Street)
+	}
+
+	var count int
+	err := s.db.QueryRow(context.Background(), `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+
+	invalid := []dto.Address{
+		{Country: "Narnia", City: "Cair Paravel", Street: "Main"},
+		{Country: "RU", City: "Moscow", Street: "Arbat", PostalCode: "12"},
+		{Country: "RU", City: "Moscow", Street: "Arbat", Latitude: new(float64)},
+		{Country: " ", City: "Moscow", Street: "Arbat"},
+	}
+
+	for _, address := range invalid {
+		resp, err := sendJSON(http.MethodPost, baseUrl, dto.ClientRequest{
+			Name:     "Gopher",
+			Surname:  "Invalid",
+			Birthday: "2001-01-01",
+			Gender:   "male",
+			Address:  &address,
+		})
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode, address)
+	}
+
+	latitude, longitude := 55.7520, 37.5925
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.ClientRequest{
+		Name:     "Gopher",
+		Surname:  "Located",
+		Birthday: "2001-01-01",
+		Gender:   "male",
+		Address: &dto.Address{
+			Country:    "ru",
+			City:       "Moscow",
+			Street:     "Arbat",
+			PostalCode: "119002",
+			Latitude:   &latitude,
+			Longitude:  &longitude,
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var located dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &located))
+	s.Require().NotNil(located.Address.Latitude)
+	s.Require().NotNil(located.Address.Longitude)
+	s.Require().Equal(latitude, *located.Address.Latitude)
+	s.Require().Equal(longitude, *located.Address.Longitude)
+}
+
+func (s *TestSuite) TestAddressMergeDuplicates() {
+	s.CleanTable()
+
+	ctx := context.Background()
+
+	insertAddress := func(country, city, street string) uuid.UUID {
+		var id uuid.UUID
+		err := s.db.QueryRow(ctx, `INSERT INTO address(country, city, street) VALUES ($1, $2, $3) RETURNING id`,
+			country, city, street).Scan(&id)
+		s.Require().NoError(err)
+		return id
+	}
+
+	insertClient := func(surname string) uuid.UUID {
+		var id uuid.UUID
+		err := s.db.QueryRow(ctx, `INSERT INTO client(name, surname, gender) VALUES ('Gopher', $1, 'male') RETURNING id`,
+			surname).Scan(&id)
+		s.Require().NoError(err)
+		return id
+	}
+
+	link := func(clientId, addressId uuid.UUID, label string, isDefault bool) {
+		_, err := s.db.Exec(ctx, `INSERT INTO client_address(client_id, address_id, label, is_default) VALUES ($1, $2, $3, $4)`,
+			clientId, addressId, label, isDefault)
+		s.Require().NoError(err)
+	}
+
+	first := insertAddress("Russia", "Moscow", "Tverskaya")
+	second := insertAddress("RU", "moscow", " Tverskaya ")
+	other := insertAddress("Japan", "Tokyo", "Godzilla")
+	broken := insertAddress("Narnia", "Cair Paravel", "Main")
+
+	both := insertClient("Both")
+	link(both, first, "home", false)
+	link(both, second, "billing", true)
+
+	single := insertClient("Single")
+	link(single, second, "home", true)
+
+	unit := repository.NewUnitOfWork(s.db, s.logger)
+	generators := map[uow.RepositoryName]uow.RepositoryGenerator{
+		uow.AddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewAddressRepository(tx, log)
+		},
+		uow.ClientAddressRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewClientAddressRepository(tx, log)
+		},
+		uow.SupplierLocationRepoName: func(tx pgx.Tx, log *logger.Logger) uow.Repository {
+			return postgres.NewSupplierLocationRepository(tx, log)
+		},
+	}
+	for name, gen := range generators {
+		s.Require().NoError(unit.Register(name, gen))
+	}
+
+	normalizer := services.NewAddressNormalizer(nil, s.logger, services.DefaultAddressValidators()...)
+	merger := services.NewAddressMergeService(unit, normalizer, s.logger)
+
+	report, err := merger.MergeDuplicates(ctx, true)
+	s.Require().NoError(err)
+	s.Require().Equal(4, report.Scanned)
+	s.Require().Equal(1, report.Merged)
+	s.Require().Equal([]uuid.UUID{broken}, report.Invalid)
+
+	var count int
+	err = s.db.QueryRow(ctx, `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(4, count)
+
+	report, err = merger.MergeDuplicates(ctx, false)
+	s.Require().NoError(err)
+	s.Require().Equal(1, report.Merged)
+	s.Require().Equal(2, report.Updated)
+
+	kept, removed := first, second
+	if second.String() < first.String() {
+		kept, removed = second, first
+	}
+
+	var country, street string
+	err = s.db.QueryRow(ctx, `SELECT country, street FROM address WHERE id = $1`, kept).Scan(&country, &street)
+	s.Require().NoError(err)
+	s.Require().Equal("RU", country)
+	s.Require().Equal("Tverskaya", street)
+
+	err = s.db.QueryRow(ctx, `SELECT COUNT(id) FROM address WHERE id = $1`, removed).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(0, count)
+
+	err = s.db.QueryRow(ctx, `SELECT country FROM address WHERE id = $1`, other).Scan(&country)
+	s.Require().NoError(err)
+	s.Require().Equal("JP", country)
+
+	var isDefault bool
+	err = s.db.QueryRow(ctx, `SELECT COUNT(*), bool_or(is_default) FROM client_address WHERE client_id = $1`, both).
+		Scan(&count, &isDefault)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+	s.Require().True(isDefault)
+
+	var addressId uuid.UUID
+	err = s.db.QueryRow(ctx, `SELECT address_id FROM client_address WHERE client_id = $1 AND is_default`, single).
+		Scan(&addressId)
+	s.Require().NoError(err)
+	s.Require().Equal(kept, addressId)
+}
+
+func (s *TestSuite) TestAddressResource() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	address := dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"}
+
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/clients", dto.ClientRequest{
+		Name:     "Gopher",
+		Surname:  "Client",
+		Birthday: "2001-01-01",
+		Gender:   "male",
+		Address:  &address,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var client dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &client))
+
+	resp, err = sendJSON(http.MethodPost, baseUrl+"/suppliers", dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address:     &address,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &supplier))
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/addresses?limit=10&offset=0", nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var addresses []dto.AddressResponse
+	s.Require().NoError(decodeJSON(resp, &addresses))
+	s.Require().Len(addresses, 1)
+	s.Require().Equal(address, addresses[0].Address)
+
+	addressUrl := fmt.Sprintf("%s/addresses/%s", baseUrl, addresses[0].Id)
+
+	resp, err = sendJSON(http.MethodGet, addressUrl, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var found dto.AddressResponse
+	s.Require().NoError(decodeJSON(resp, &found))
+	s.Require().Equal(addresses[0], found)
+
+	resp, err = sendJSON(http.MethodGet, addressUrl+"/references", nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var references []dto.AddressReferenceResponse
+	s.Require().NoError(decodeJSON(resp, &references))
+	s.Require().ElementsMatch([]dto.AddressReferenceResponse{
+		{OwnerType: "client", OwnerId: client.Id, Label: "home", IsDefault: true},
+		{OwnerType: "supplier", OwnerId: supplier.Id, Label: "office", IsDefault: true},
+	}, references)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/addresses/%s", baseUrl, uuid.New()), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/addresses/aboba/references", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/addresses?limit=0", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestAddressCollector() {
+	s.CleanTable()
+
+	ctx := context.Background()
+
+	var used uuid.UUID
+	err := s.db.QueryRow(ctx, `INSERT INTO address(country, city, street) VALUES ('JP', 'Tokyo', 'Godzilla') RETURNING id`).
+		Scan(&used)
+	s.Require().NoError(err)
+
+	var clientId uuid.UUID
+	err = s.db.QueryRow(ctx, `INSERT INTO client(name, surname, gender) VALUES ('Gopher', 'Client', 'male') RETURNING id`).
+		Scan(&clientId)
+	s.Require().NoError(err)
+
+	_, err = s.db.Exec(ctx, `INSERT INTO client_address(client_id, address_id, label, is_default) VALUES ($1, $2, 'home', TRUE)`,
+		clientId, used)
+	s.Require().NoError(err)
+
+	for i := range 5 {
+		_, err := s.db.Exec(ctx, `INSERT INTO address(country, city, street) VALUES ('JP', 'Tokyo', $1)`,
+			fmt.Sprintf("Orphan %d", i))
+		s.Require().NoError(err)
+	}
+
+	collector := services.NewAddressCollector(postgres.NewAddressRepository(s.db, s.logger), time.Hour, 2, s.logger)
+
+	deleted, err := collector.Collect(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(5, deleted)
+
+	var count int
+	err = s.db.QueryRow(ctx, `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+
+	err = s.db.QueryRow(ctx, `SELECT COUNT(id) FROM address WHERE id = $1`, used).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+
+	deleted, err = collector.Collect(ctx)
+	s.Require().NoError(err)
+	s.Require().Zero(deleted)
+}
@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// defineAttribute posts the attribute definition to the category and checks
+// the response status.
+func (s *TestSuite) defineAttribute(category uuid.UUID, request dto.AttributeRequest, status int) {
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("http://%s:%s/api/v1/categories/%s/attributes",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, category), request)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(status, resp.StatusCode, request.Key)
+}
+
+// attributeSchema creates appliances with the required energy class and the
+// warranty, and fridges in it with the capacity. It returns the fridges.
+func (s *TestSuite) attributeSchema() uuid.UUID {
+	appliances := s.category("Appliances")
+	fridges := s.createCategory(dto.CategoryRequest{Name: "Fridges", ParentId: &appliances})
+
+	minCapacity := 0.0
+	s.defineAttribute(appliances, dto.AttributeRequest{Key: "energy_class", Name: "Energy class", Type: "enum", Options: []string{"A+++", "A++", "A+", "A"}, Required: true}, http.StatusCreated)
+	s.defineAttribute(appliances, dto.AttributeRequest{Key: "warranty_months", Name: "Warranty", Type: "integer", Unit: "months"}, http.StatusCreated)
+	s.defineAttribute(fridges.Id, dto.AttributeRequest{Key: "capacity", Name: "Capacity", Type: "number", Unit: "l", Min: &minCapacity}, http.StatusCreated)
+
+	return fridges.Id
+}
+
+// attributeProducts creates three fridges with attributes.
+func (s *TestSuite) attributeProducts(fridges uuid.UUID) {
+	products := []dto.ProductRequest{
+		{Name: "Big Fridge", Attributes: dto.Attributes{"energy_class": "A++", "capacity": 350, "warranty_months": 24}},
+		{Name: "Small Fridge", Attributes: dto.Attributes{"energy_class": "A+", "capacity": 120}},
+		{Name: "Green Fridge", Attributes: dto.Attributes{"energy_class": "A++", "capacity": 280}},
+	}
+	for i := range products {
+		products[i].CategoryId, products[i].Price, products[i].AvailableStock = fridges, 500, 1
+	}
+
+	s.createProducts(s.createSupplier(), products...)
+}
+
+func (s *TestSuite) TestAttributeSchema() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	fridges := s.attributeSchema()
+
+	resp, err := http.Get(fmt.Sprintf("%s/categories/%s/attributes", baseUrl, fridges))
+	s.Require().NoError(err)
+
+	var schema []dto.AttributeResponse
+	s.Require().NoError(decodeJSON(resp, &schema))
+
+	keys := make([]string, len(schema))
+	for i, definition := range schema {
+		keys[i] = definition.Key
+	}
+
+	// attributes of the parent go first
+	s.Require().Equal([]string{"energy_class", "warranty_months", "capacity"}, keys)
+}
+
+func (s *TestSuite) TestAttributeDefinitionInvalid() {
+	s.CleanTable()
+	fridges := s.attributeSchema()
+
+	// defined by the parent already
+	s.defineAttribute(fridges, dto.AttributeRequest{Key: "energy_class", Name: "Class", Type: "string"}, http.StatusConflict)
+	s.defineAttribute(fridges, dto.AttributeRequest{Key: "color", Name: "Color", Type: "enum"}, http.StatusBadRequest)
+	s.defineAttribute(fridges, dto.AttributeRequest{Key: "Color", Name: "Color", Type: "string"}, http.StatusBadRequest)
+}
+
+func (s *TestSuite) TestAttributeValidation() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	fridges := s.attributeSchema()
+
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/products", dto.ProductRequest{
+		Name:           "Fridge",
+		CategoryId:     fridges,
+		Price:          500,
+		AvailableStock: 1,
+		SupplierId:     s.createSupplier(),
+		Attributes:     dto.Attributes{"energy_class": "B", "capacity": -1, "warranty_months": 1.5, "color": "white"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal("/problems/validation", problem.Type)
+
+	fields := make(map[string]string)
+	for _, field := range problem.Errors {
+		fields[field.Field] = field.Code
+	}
+
+	s.Require().Equal(map[string]string{
+		"attributes.energy_class":    "oneof",
+		"attributes.capacity":        "gte",
+		"attributes.warranty_months": "type",
+		"attributes.color":           "unknown",
+	}, fields)
+}
+
+func (s *TestSuite) TestAttributeFilters() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	fridges := s.attributeSchema()
+	s.attributeProducts(fridges)
+
+	cases := []struct {
+		filters []string
+		names   []string
+	}{
+		{[]string{"energy_class=A++"}, []string{"Big Fridge", "Green Fridge"}},
+		{[]string{"capacity>=300"}, []string{"Big Fridge"}},
+		{[]string{"energy_class=A++", "capacity<300"}, []string{"Green Fridge"}},
+		{[]string{"warranty_months=24"}, []string{"Big Fridge"}},
+	}
+
+	for _, tc := range cases {
+		query := url.Values{"attr": tc.filters}
+		resp, err := http.Get(fmt.Sprintf("%s/categories/%s/products?%s", baseUrl, fridges, query.Encode()))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode, tc.filters)
+
+		var products []dto.ProductResponse
+		s.Require().NoError(decodeJSON(resp, &products))
+
+		names := make([]string, len(products))
+		for i, product := range products {
+			names[i] = product.Name
+		}
+
+		s.Require().Equal(tc.names, names, tc.filters)
+
+		if tc.names[0] == "Big Fridge" {
+			s.Require().Equal("A++", products[0].Attributes["energy_class"])
+			s.Require().EqualValues(350, products[0].Attributes["capacity"])
+		}
+	}
+}
+
+func (s *TestSuite) TestAttributeFilterInvalid() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	fridges := s.attributeSchema()
+
+	// an enum is not ordered
+	resp, err := http.Get(fmt.Sprintf("%s/categories/%s/products?attr=energy_class>A", baseUrl, fridges))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestAttributeDelete() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	fridges := s.attributeSchema()
+	s.attributeProducts(fridges)
+
+	resp, err := sendJSON(http.MethodDelete, fmt.Sprintf("%s/categories/%s/attributes/capacity", baseUrl, fridges), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	// the values of the removed attribute are dropped
+	resp, err = http.Get(fmt.Sprintf("%s/categories/%s/products?attr=energy_class=A%%2B", baseUrl, fridges))
+	s.Require().NoError(err)
+
+	var products []dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &products))
+	s.Require().Len(products, 1)
+	s.Require().Equal(dto.Attributes{"energy_class": "A+"}, products[0].Attributes)
+}
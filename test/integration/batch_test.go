@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func batchOperation(ref, method, entity, id string, body any) dto.BatchOperation {
+	operation := dto.BatchOperation{Ref: ref, Method: method, Entity: entity, Id: id}
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		operation.Body = raw
+	}
+
+	return operation
+}
+
+func batchSupplier(name string) dto.SupplierRequest {
+	return dto.SupplierRequest{
+		Name:        name,
+		PhoneNumber: "+78005553535",
+		Address: &dto.Address{
+			Country: "JP",
+			City:    "Tokyo",
+			Street:  "Godzilla",
+		},
+	}
+}
+
+// sendBatch runs the batch and checks the response status.
+func (s *TestSuite) sendBatch(request dto.BatchRequest, status int) dto.BatchResponse {
+	url := fmt.Sprintf("http://%s:%s/api/v1/batch", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, url, request)
+	s.Require().NoError(err)
+	s.Require().Equal(status, resp.StatusCode)
+
+	var output dto.BatchResponse
+	s.Require().NoError(decodeJSON(resp, &output))
+	return output
+}
+
+func (s *TestSuite) countSuppliers() int {
+	var count int
+	err := s.db.QueryRow(context.Background(), `SELECT COUNT(*) FROM supplier`).Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *TestSuite) TestBatchReferences() {
+	s.CleanTable()
+
+	product := map[string]any{
+		"name":            "Fridge",
+		"category_id":     s.category("kitchen"),
+		"price":           499.9,
+		"available_stock": 3,
+		"supplier_id":     "$sup",
+	}
+
+	output := s.sendBatch(dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("sup", "create", "suppliers", "", batchSupplier("Aboba Inc.")),
+			batchOperation("fridge", "create", "products", "", product),
+			batchOperation("", "update", "products", "$fridge", map[string]string{"name": "Fridge XL"}),
+			batchOperation("", "update", "suppliers", "$sup", map[string]string{"contact_person": "Amogus"}),
+		},
+	}, http.StatusOK)
+	s.Require().True(output.Atomic)
+	s.Require().Equal(4, output.Succeeded)
+	s.Require().Equal(http.StatusCreated, output.Results[0].Status)
+	s.Require().NotNil(output.Results[1].Id)
+
+	var (
+		name       string
+		supplierId string
+	)
+
+	err := s.db.QueryRow(context.Background(), `SELECT name, supplier_id FROM product WHERE id = $1`, *output.Results[1].Id).Scan(&name, &supplierId)
+	s.Require().NoError(err)
+	s.Require().Equal("Fridge XL", name)
+	s.Require().Equal(output.Results[0].Id.String(), supplierId)
+}
+
+func (s *TestSuite) TestBatchAtomicRollback() {
+	s.CleanTable()
+
+	s.sendBatch(dto.BatchRequest{
+		Operations: []dto.BatchOperation{batchOperation("", "create", "suppliers", "", batchSupplier("Aboba Inc."))},
+	}, http.StatusOK)
+
+	// the duplicate name fails the batch, the first supplier is rolled back
+	output := s.sendBatch(dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("sus", "create", "suppliers", "", batchSupplier("Sus Ltd.")),
+			batchOperation("", "create", "suppliers", "", batchSupplier("Aboba Inc.")),
+			batchOperation("", "delete", "suppliers", "$sus", nil),
+		},
+	}, http.StatusUnprocessableEntity)
+	s.Require().Equal(http.StatusFailedDependency, output.Results[0].Status)
+	s.Require().Equal(http.StatusConflict, output.Results[1].Status)
+	s.Require().Equal(http.StatusFailedDependency, output.Results[2].Status)
+	s.Require().Equal(1, s.countSuppliers())
+}
+
+func (s *TestSuite) TestBatchNotAtomic() {
+	s.CleanTable()
+
+	s.sendBatch(dto.BatchRequest{
+		Operations: []dto.BatchOperation{batchOperation("", "create", "suppliers", "", batchSupplier("Aboba Inc."))},
+	}, http.StatusOK)
+
+	// without atomicity only the failed operation is undone
+	atomic := false
+	output := s.sendBatch(dto.BatchRequest{
+		Atomic: &atomic,
+		Operations: []dto.BatchOperation{
+			batchOperation("sus", "create", "suppliers", "", batchSupplier("Sus Ltd.")),
+			batchOperation("", "create", "suppliers", "", batchSupplier("Aboba Inc.")),
+		},
+	}, http.StatusMultiStatus)
+	s.Require().Equal(1, output.Succeeded)
+	s.Require().Equal(1, output.Failed)
+	s.Require().Equal(2, s.countSuppliers())
+}
+
+func (s *TestSuite) TestBatchUnknownReference() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/batch", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, url, dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("", "delete", "suppliers", "$unknown", nil),
+		},
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Require().Zero(s.countSuppliers())
+}
@@ -0,0 +1,321 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// shop is a client and the products of a kitchen: 5 kettles at 12.5 and 2
+// toasters at 40.
+type shop struct {
+	baseUrl string
+	client  uuid.UUID
+	kettle  uuid.UUID
+	toaster uuid.UUID
+}
+
+func (s *TestSuite) shop() shop {
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	kitchen := s.category("Kitchen")
+	products := s.createProducts(s.createSupplier(),
+		dto.ProductRequest{Name: "Kettle", CategoryId: kitchen, Price: 12.5, AvailableStock: 5},
+		dto.ProductRequest{Name: "Toaster", CategoryId: kitchen, Price: 40, AvailableStock: 2},
+	)
+
+	return shop{
+		baseUrl: baseUrl,
+		client:  s.createClient("Gopher", "Buyer", "male"),
+		kettle:  products["Kettle"],
+		toaster: products["Toaster"],
+	}
+}
+
+// createCart creates the cart of the client, an anonymous one for nil.
+func (s *TestSuite) createCart(baseUrl string, client *uuid.UUID) dto.CartResponse {
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/carts", dto.CartRequest{ClientId: client})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var cart dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &cart))
+	return cart
+}
+
+func (s *TestSuite) addCartItem(cartUrl string, product uuid.UUID, quantity int64) *http.Response {
+	resp, err := sendJSON(http.MethodPost, cartUrl+"/items", dto.CartItemRequest{ProductId: product, Quantity: quantity})
+	s.Require().NoError(err)
+	return resp
+}
+
+// fillCart adds the quantities of the products to the cart.
+func (s *TestSuite) fillCart(cartUrl string, quantities map[uuid.UUID]int64) {
+	for product, quantity := range quantities {
+		resp := s.addCartItem(cartUrl, product, quantity)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, product)
+	}
+}
+
+// cartProblem checks the response is the conflict of the problem type.
+func (s *TestSuite) cartProblem(resp *http.Response, problemType string) {
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal(problemType, problem.Type)
+}
+
+func (s *TestSuite) TestCartCreate() {
+	s.CleanTable()
+	shop := s.shop()
+
+	cart := s.createCart(shop.baseUrl, &shop.client)
+	s.Require().Equal(&shop.client, cart.ClientId)
+	s.Require().Empty(cart.Items)
+
+	// the client has a cart already
+	resp, err := sendJSON(http.MethodPost, shop.baseUrl+"/carts", dto.CartRequest{ClientId: &shop.client})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	anonymous := s.createCart(shop.baseUrl, nil)
+	s.Require().Nil(anonymous.ClientId)
+}
+
+func (s *TestSuite) TestCartAddItem() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+
+	resp := s.addCartItem(cartUrl, shop.kettle, 2)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal(`"2"`, resp.Header.Get("ETag"))
+
+	// the quantity is added to the item in the cart
+	resp = s.addCartItem(cartUrl, shop.kettle, 1)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var cart dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &cart))
+	s.Require().Len(cart.Items, 1)
+	s.Require().Equal(int64(3), cart.Items[0].Quantity)
+	s.Require().Equal(37.5, cart.Items[0].Subtotal)
+}
+
+func (s *TestSuite) TestCartAddItemInsufficientStock() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 3})
+
+	// 3 kettles are in the cart already
+	s.cartProblem(s.addCartItem(cartUrl, shop.kettle, 3), "/problems/insufficient-stock")
+
+	resp, err := http.Get(cartUrl)
+	s.Require().NoError(err)
+
+	var cart dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &cart))
+	s.Require().Equal(int64(3), cart.Items[0].Quantity)
+}
+
+func (s *TestSuite) TestCartAddItemInvalid() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+
+	resp := s.addCartItem(cartUrl, shop.kettle, 0)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCartSetItem() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 3})
+
+	resp, err := sendJSON(http.MethodPut, fmt.Sprintf("%s/items/%s", cartUrl, shop.toaster), dto.CartItemUpdateRequest{Quantity: 1})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var cart dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &cart))
+	s.Require().Len(cart.Items, 2)
+	s.Require().Equal(77.5, cart.Total)
+}
+
+func (s *TestSuite) TestCartSetItemStale() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 3})
+
+	// the cart is changed by the kettles
+	resp, err := sendJSONWithHeader(http.MethodPut, fmt.Sprintf("%s/items/%s", cartUrl, shop.toaster), "If-Match", `"1"`, dto.CartItemUpdateRequest{Quantity: 1})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCartRemoveItem() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 3, shop.toaster: 1})
+
+	resp, err := sendJSON(http.MethodDelete, fmt.Sprintf("%s/items/%s", cartUrl, shop.toaster), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var cart dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &cart))
+	s.Require().Len(cart.Items, 1)
+	s.Require().Equal(shop.kettle, cart.Items[0].ProductId)
+}
+
+func (s *TestSuite) TestCartMerge() {
+	s.CleanTable()
+	shop := s.shop()
+	cart := s.createCart(shop.baseUrl, &shop.client)
+	s.fillCart(fmt.Sprintf("%s/carts/%s", shop.baseUrl, cart.Id), map[uuid.UUID]int64{shop.kettle: 3, shop.toaster: 1})
+
+	// the visitor fills an anonymous cart before signing in
+	anonymousUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, nil).Id)
+	s.fillCart(anonymousUrl, map[uuid.UUID]int64{shop.kettle: 2, shop.toaster: 2})
+
+	resp, err := sendJSON(http.MethodPost, anonymousUrl+"/merge", dto.CartMergeRequest{ClientId: shop.client})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var merged dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &merged))
+	s.Require().Equal(cart.Id, merged.Id)
+
+	// merged quantities are limited by the stock
+	quantities := make(map[string]int64)
+	for _, item := range merged.Items {
+		quantities[item.Name] = item.Quantity
+	}
+	s.Require().Equal(map[string]int64{"Kettle": 5, "Toaster": 2}, quantities)
+	s.Require().Equal([]dto.CartAdjustmentResponse{{ProductId: shop.toaster, Requested: 3, Quantity: 2}}, merged.Adjustments)
+
+	resp, err = http.Get(anonymousUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCartMergeOutOfStock() {
+	s.CleanTable()
+	shop := s.shop()
+	cart := s.createCart(shop.baseUrl, &shop.client)
+	s.fillCart(fmt.Sprintf("%s/carts/%s", shop.baseUrl, cart.Id), map[uuid.UUID]int64{shop.kettle: 1})
+
+	anonymousUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, nil).Id)
+	s.fillCart(anonymousUrl, map[uuid.UUID]int64{shop.kettle: 1})
+
+	// the kettles are sold out before the visitor signs in
+	_, err := s.db.Exec(context.Background(), `UPDATE product SET available_stock = 0 WHERE id = $1`, shop.kettle)
+	s.Require().NoError(err)
+
+	resp, err := sendJSON(http.MethodPost, anonymousUrl+"/merge", dto.CartMergeRequest{ClientId: shop.client})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var merged dto.CartResponse
+	s.Require().NoError(decodeJSON(resp, &merged))
+	s.Require().Equal(cart.Id, merged.Id)
+	s.Require().Empty(merged.Items)
+	s.Require().Equal([]dto.CartAdjustmentResponse{{ProductId: shop.kettle, Requested: 2, Quantity: 0}}, merged.Adjustments)
+}
+
+func (s *TestSuite) TestCartCheckout() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 1})
+
+	resp := s.addCartItem(cartUrl, shop.kettle, 4)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err := sendJSONWithHeader(http.MethodPost, cartUrl+"/checkout", "If-Match", resp.Header.Get("ETag"), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var order dto.OrderResponse
+	s.Require().NoError(decodeJSON(resp, &order))
+	s.Require().Equal(&shop.client, order.ClientId)
+	s.Require().Len(order.Items, 1)
+	s.Require().Equal(int64(5), order.Items[0].Quantity)
+	s.Require().Equal(62.5, order.Total)
+
+	s.Require().Equal(int64(0), s.getProduct(shop.kettle).AvailableStock)
+
+	// the cart is gone with the checkout
+	resp, err = http.Get(fmt.Sprintf("%s/clients/%s/cart", shop.baseUrl, shop.client))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("%s/clients/%s/orders", shop.baseUrl, shop.client))
+	s.Require().NoError(err)
+
+	var orders []dto.OrderResponse
+	s.Require().NoError(decodeJSON(resp, &orders))
+	s.Require().Len(orders, 1)
+	s.Require().Equal(order.Id, orders[0].Id)
+
+	resp, err = http.Get(fmt.Sprintf("%s/orders/%s", shop.baseUrl, order.Id))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCartCheckoutAnonymous() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, nil).Id)
+	s.fillCart(cartUrl, map[uuid.UUID]int64{shop.kettle: 2})
+
+	resp, err := sendJSON(http.MethodPost, cartUrl+"/checkout", nil)
+	s.Require().NoError(err)
+	s.cartProblem(resp, "/problems/cart-client-required")
+	s.Require().Equal(int64(5), s.getProduct(shop.kettle).AvailableStock)
+}
+
+func (s *TestSuite) TestCartCheckoutEmpty() {
+	s.CleanTable()
+	shop := s.shop()
+	cartUrl := fmt.Sprintf("%s/carts/%s", shop.baseUrl, s.createCart(shop.baseUrl, &shop.client).Id)
+
+	resp, err := sendJSON(http.MethodPost, cartUrl+"/checkout", nil)
+	s.Require().NoError(err)
+	s.cartProblem(resp, "/problems/cart-empty")
+}
+
+func (s *TestSuite) TestCartExpired() {
+	s.CleanTable()
+	shop := s.shop()
+	cart := s.createCart(shop.baseUrl, &shop.client)
+
+	_, err := s.db.Exec(context.Background(), `UPDATE cart SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, cart.Id)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(fmt.Sprintf("%s/carts/%s", shop.baseUrl, cart.Id))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	// an expired cart is gone and the client may start a new one
+	s.Require().NotEqual(cart.Id, s.createCart(shop.baseUrl, &shop.client).Id)
+}
@@ -0,0 +1,174 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// categoryTree is home appliances with kitchen at position 1 and laundry,
+// fridges are in kitchen.
+type categoryTree struct {
+	appliances dto.CategoryResponse
+	kitchen    dto.CategoryResponse
+	laundry    dto.CategoryResponse
+	fridges    dto.CategoryResponse
+}
+
+func (s *TestSuite) createCategory(request dto.CategoryRequest) dto.CategoryResponse {
+	var category dto.CategoryResponse
+	s.create("/categories", request, &category)
+	return category
+}
+
+func (s *TestSuite) categoryTree() categoryTree {
+	var tree categoryTree
+
+	tree.appliances = s.createCategory(dto.CategoryRequest{Name: "Home Appliances"})
+	tree.kitchen = s.createCategory(dto.CategoryRequest{Name: "Kitchen", ParentId: &tree.appliances.Id, Position: 1})
+	tree.laundry = s.createCategory(dto.CategoryRequest{Name: "Laundry", ParentId: &tree.appliances.Id})
+	tree.fridges = s.createCategory(dto.CategoryRequest{Name: "Fridges", Slug: "fridges", ParentId: &tree.kitchen.Id})
+
+	return tree
+}
+
+// categoryProducts creates a blender in kitchen and a fridge in fridges, it
+// returns their supplier.
+func (s *TestSuite) categoryProducts(tree categoryTree) uuid.UUID {
+	supplier := s.createSupplier()
+
+	s.createProducts(supplier,
+		dto.ProductRequest{Name: "Blender", CategoryId: tree.kitchen.Id, Price: 40, AvailableStock: 1},
+		dto.ProductRequest{Name: "Fridge", CategoryId: tree.fridges.Id, Price: 500, AvailableStock: 1},
+	)
+
+	return supplier
+}
+
+func (s *TestSuite) TestCategoryCreate() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+
+	s.Require().Equal("home-appliances", tree.appliances.Slug)
+	s.Require().Equal(2, tree.fridges.Depth)
+	s.Require().Equal("home-appliances/kitchen/fridges", tree.fridges.Path)
+
+	// the slug of the name is taken
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/categories", dto.CategoryRequest{Name: "kitchen!"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCategoryTreeOrder() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+
+	resp, err := http.Get(baseUrl + "/categories")
+	s.Require().NoError(err)
+
+	var categories []dto.CategoryResponse
+	s.Require().NoError(decodeJSON(resp, &categories))
+
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.Id
+	}
+
+	// depth-first, siblings by position
+	s.Require().Equal([]uuid.UUID{tree.appliances.Id, tree.laundry.Id, tree.kitchen.Id, tree.fridges.Id}, ids)
+}
+
+func (s *TestSuite) TestCategoryCycle() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+
+	resp, err := sendJSON(http.MethodPatch, fmt.Sprintf("%s/categories/%s", baseUrl, tree.appliances.Id), map[string]any{"parent_id": tree.fridges.Id})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCategoryMoveToRoot() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+
+	resp, err := sendJSON(http.MethodPatch, fmt.Sprintf("%s/categories/%s", baseUrl, tree.fridges.Id), map[string]any{"parent_id": ""})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var moved dto.CategoryResponse
+	s.Require().NoError(decodeJSON(resp, &moved))
+	s.Require().Nil(moved.ParentId)
+	s.Require().Equal(0, moved.Depth)
+	s.Require().Equal("fridges", moved.Path)
+}
+
+func (s *TestSuite) TestCategoryProducts() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+	supplier := s.categoryProducts(tree)
+
+	cases := []struct {
+		url   string
+		names []string
+	}{
+		{fmt.Sprintf("%s/categories/%s/products", baseUrl, tree.kitchen.Id), []string{"Blender"}},
+		{fmt.Sprintf("%s/categories/%s/products?include_subcategories=true", baseUrl, tree.kitchen.Id), []string{"Blender", "Fridge"}},
+		{fmt.Sprintf("%s/categories/%s/products?include_subcategories=true", baseUrl, tree.laundry.Id), []string{}},
+		{fmt.Sprintf("%s/suppliers/%s/products?category=home-appliances", baseUrl, supplier), []string{"Blender", "Fridge"}},
+		{fmt.Sprintf("%s/suppliers/%s/products?category=%s", baseUrl, supplier, tree.fridges.Id), []string{"Fridge"}},
+	}
+
+	for _, tc := range cases {
+		resp, err := http.Get(tc.url)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode, tc.url)
+		s.Require().Equal(fmt.Sprint(len(tc.names)), resp.Header.Get("X-Total-Count"), tc.url)
+
+		var products []dto.ProductResponse
+		s.Require().NoError(decodeJSON(resp, &products))
+
+		names := make([]string, len(products))
+		for i, product := range products {
+			names[i] = product.Name
+		}
+
+		s.Require().Equal(tc.names, names, tc.url)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/categories/%s/products", baseUrl, uuid.New()))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestCategoryDelete() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	tree := s.categoryTree()
+	s.categoryProducts(tree)
+
+	// kitchen has products
+	resp, err := sendJSON(http.MethodDelete, fmt.Sprintf("%s/categories/%s", baseUrl, tree.kitchen.Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/categories/%s", baseUrl, tree.laundry.Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("%s/categories/%s", baseUrl, tree.laundry.Id))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
@@ -40,7 +40,7 @@ func (s *TestSuite) TestCreateClient() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -135,7 +135,7 @@ func (s *TestSuite) TestCreateClientWithoutAddress() {
 func (s *TestSuite) TestCreateClientWithOneAddress() {
 	s.CleanTable()
 	commonAddress := dto.Address{
-		Country: "Japan",
+		Country: "JP",
 		City:    "Tokyo",
 		Street:  "Godzilla",
 	}
@@ -224,7 +224,7 @@ func (s *TestSuite) TestCreateClientWithOneAddress() {
 func (s *TestSuite) TestGetClient() {
 	s.CleanTable()
 	commonAddress := dto.Address{
-		Country: "Japan",
+		Country: "JP",
 		City:    "Tokyo",
 		Street:  "Godzilla",
 	}
@@ -300,7 +300,7 @@ func (s *TestSuite) TestGetClient() {
 func (s *TestSuite) TestGetClientByNameAndSurname() {
 	s.CleanTable()
 	commonAddress := dto.Address{
-		Country: "Japan",
+		Country: "JP",
 		City:    "Tokyo",
 		Street:  "Godzilla",
 	}
@@ -373,7 +373,7 @@ func (s *TestSuite) TestGetClientByNameAndSurname() {
 func (s *TestSuite) TestGetClientByNameAndSurnameMulty() {
 	s.CleanTable()
 	commonAddress := dto.Address{
-		Country: "Japan",
+		Country: "JP",
 		City:    "Tokyo",
 		Street:  "Godzilla",
 	}
@@ -474,7 +474,7 @@ func (s *TestSuite) TestClientUpdateAddress() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -486,7 +486,7 @@ func (s *TestSuite) TestClientUpdateAddress() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -525,7 +525,7 @@ func (s *TestSuite) TestClientUpdateAddress() {
 
 	url := fmt.Sprintf("http://%s:%s/api/v1/clients/%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
 	payload, err := json.Marshal(dto.Address{
-		Country: "Korea",
+		Country: "KR",
 		City:    "Seoul",
 		Street:  "Gangnam",
 	})
@@ -547,9 +547,9 @@ func (s *TestSuite) TestClientUpdateAddress() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(2, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Korea",
+		"newCountry": "KR",
 		"newCity":    "Seoul",
 		"newStreet":  "Gangnam",
 	}
@@ -565,7 +565,7 @@ func (s *TestSuite) TestClientUpdateAddress() {
 
 	check := mapper.AddressToDto(temp)
 	s.Require().Equal(check, dto.Address{
-		Country: "Korea",
+		Country: "KR",
 		City:    "Seoul",
 		Street:  "Gangnam",
 	})
@@ -595,7 +595,7 @@ func (s *TestSuite) TestClientUpdateAddress() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Gangnam",
 		},
@@ -612,7 +612,7 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -624,7 +624,7 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -685,9 +685,9 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(1, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Korea",
+		"newCountry": "KR",
 		"newCity":    "Seoul",
 		"newStreet":  "Gangnam",
 	}
@@ -732,7 +732,7 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty_2() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -744,7 +744,7 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty_2() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -799,9 +799,9 @@ func (s *TestSuite) TestClientUpdateAddressOnEmpty_2() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(1, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Korea",
+		"newCountry": "KR",
 		"newCity":    "Seoul",
 		"newStreet":  "Gangnam",
 	}
@@ -846,7 +846,7 @@ func (s *TestSuite) TestClientDelete() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -858,7 +858,7 @@ func (s *TestSuite) TestClientDelete() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -913,9 +913,9 @@ func (s *TestSuite) TestClientDelete() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(1, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Japan",
+		"newCountry": "JP",
 		"newCity":    "Tokyo",
 		"newStreet":  "Godzilla",
 	}
@@ -956,7 +956,7 @@ func (s *TestSuite) TestClientDeleteGhost() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -968,7 +968,7 @@ func (s *TestSuite) TestClientDeleteGhost() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -1017,9 +1017,9 @@ func (s *TestSuite) TestClientDeleteGhost() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(1, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Japan",
+		"newCountry": "JP",
 		"newCity":    "Tokyo",
 		"newStreet":  "Godzilla",
 	}
@@ -1066,7 +1066,7 @@ func (s *TestSuite) TestClientDeleteWithAddress() {
 		Birthday: "2001-01-01",
 		Gender:   "female",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -1078,7 +1078,7 @@ func (s *TestSuite) TestClientDeleteWithAddress() {
 		Birthday: "2005-01-01",
 		Gender:   "male",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Jingu-dori",
 		},
@@ -1140,9 +1140,9 @@ func (s *TestSuite) TestClientDeleteWithAddress() {
 	s.Require().NoError(err)
 	s.Require().EqualValues(1, count)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args := pgx.NamedArgs{
-		"newCountry": "Japan",
+		"newCountry": "JP",
 		"newCity":    "Tokyo",
 		"newStreet":  "Jingu-dori",
 	}
@@ -1156,9 +1156,9 @@ func (s *TestSuite) TestClientDeleteWithAddress() {
 	)
 	s.Require().ErrorIs(err, pgx.ErrNoRows)
 
-	query = `SELECT * FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
+	query = `SELECT id, country, city, street FROM address WHERE country=@newCountry AND city=@newCity AND street=@newStreet`
 	args = pgx.NamedArgs{
-		"newCountry": "Japan",
+		"newCountry": "JP",
 		"newCity":    "Tokyo",
 		"newStreet":  "Godzilla",
 	}
@@ -1189,3 +1189,379 @@ func (s *TestSuite) TestClientDeleteWithAddress() {
 	s.Require().Contains(clients, first)
 	s.Require().Contains(clients, third)
 }
+
+func (s *TestSuite) TestClientProfile() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	first := dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+		Email:    "Adrianna@Example.com",
+		Phone:    "+7 (900) 123-45-67",
+	}
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, first)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var created dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &created))
+	s.Require().Equal("adrianna@example.com", created.Email)
+	s.Require().Equal("+79001234567", created.Phone)
+
+	second := dto.ClientRequest{
+		Name:     "Adrian",
+		Surname:  "Gopher",
+		Birthday: "2005-01-01",
+		Gender:   "male",
+		Email:    "adrianna@example.com",
+	}
+
+	resp, err = sendJSON(http.MethodPost, baseUrl, second)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	second.Email = "not an email"
+	resp, err = sendJSON(http.MethodPost, baseUrl, second)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	clientUrl := fmt.Sprintf("%s/%s", baseUrl, created.Id)
+
+	resp, err = sendJSON(http.MethodGet, clientUrl, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var got dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &got))
+	s.Require().Equal(created, got)
+
+	surname := "Rustacean"
+	resp, err = sendJSON(http.MethodPatch, clientUrl, dto.ClientUpdateRequest{Surname: &surname})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, clientUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &got))
+	s.Require().Equal(surname, got.Surname)
+	s.Require().Equal(created.Name, got.Name)
+	s.Require().Equal(created.Email, got.Email)
+
+	gender := "unknown"
+	resp, err = sendJSON(http.MethodPatch, clientUrl, dto.ClientUpdateRequest{Gender: &gender})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPatch, clientUrl, dto.ClientUpdateRequest{})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	replace := dto.ClientRequest{
+		Name:     "Kazui",
+		Surname:  "Franclin",
+		Birthday: "1999-09-09",
+		Gender:   "male",
+	}
+
+	resp, err = sendJSON(http.MethodPut, clientUrl, replace)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, clientUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &got))
+	s.Require().Equal(replace, clientResponseToRequest(got))
+	s.Require().Empty(got.Email)
+	s.Require().Empty(got.Phone)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s", baseUrl, uuid.New()), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestClientSearch() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	dataBank := []dto.ClientRequest{
+		{
+			Name:     "Adrianna",
+			Surname:  "Gopherson",
+			Birthday: "2001-01-01",
+			Gender:   "female",
+			Address:  &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+		},
+		{
+			Name:     "Adrian",
+			Surname:  "Gopher",
+			Birthday: "2005-01-01",
+			Gender:   "male",
+			Address:  &dto.Address{Country: "KR", City: "Seoul", Street: "Gangnam"},
+		},
+		{
+			Name:     "Kazui",
+			Surname:  "Franclin",
+			Birthday: "1990-01-01",
+			Gender:   "male",
+		},
+	}
+
+	for _, data := range dataBank {
+		s.Require().NoError(createObject(data, baseUrl))
+	}
+
+	search := func(query string) ([]dto.ClientResponse, string) {
+		resp, err := http.Get(fmt.Sprintf("%s/search?%s", baseUrl, query))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var clients []dto.ClientResponse
+		s.Require().NoError(decodeJSON(resp, &clients))
+
+		return clients, resp.Header.Get("X-Total-Count")
+	}
+
+	clients, total := search("q=GOPH")
+	s.Require().Len(clients, 2)
+	s.Require().Equal("2", total)
+
+	clients, _ = search("q=franklin")
+	s.Require().Len(clients, 1)
+	s.Require().Equal("Franclin", clients[0].Surname)
+
+	clients, _ = search("city=seoul")
+	s.Require().Len(clients, 1)
+	s.Require().Equal("Adrian", clients[0].Name)
+
+	clients, _ = search("gender=male&birthday_from=1995-01-01")
+	s.Require().Len(clients, 1)
+	s.Require().Equal("Adrian", clients[0].Name)
+
+	clients, total = search("sort=birthday&order=desc&limit=1&offset=1")
+	s.Require().Len(clients, 1)
+	s.Require().Equal("3", total)
+	s.Require().Equal("Adrianna", clients[0].Name)
+
+	for _, query := range []string{"sort=unknown", "gender=other", "birthday_from=01.01.2001", "limit=0", "limit=101", "sort=relevance"} {
+		resp, err := http.Get(fmt.Sprintf("%s/search?%s", baseUrl, query))
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/search?q=nobody", baseUrl))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal("0", resp.Header.Get("X-Total-Count"))
+
+	var nobody []dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &nobody))
+	s.Require().Empty(nobody)
+}
+
+func (s *TestSuite) TestClientSearchPastEnd() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	for _, data := range []dto.ClientRequest{
+		{Name: "Adrianna", Surname: "Gopherson", Birthday: "2001-01-01", Gender: "female"},
+		{Name: "Adrian", Surname: "Gopher", Birthday: "2005-01-01", Gender: "male"},
+	} {
+		s.Require().NoError(createObject(data, baseUrl))
+	}
+
+	// the page is empty, the total still counts the matched clients
+	resp, err := http.Get(fmt.Sprintf("%s/search?q=gopher&limit=10&offset=5", baseUrl))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal("2", resp.Header.Get("X-Total-Count"))
+
+	var clients []dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &clients))
+	s.Require().Empty(clients)
+}
+
+func (s *TestSuite) TestClientSearchCountryName() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	for _, data := range []dto.ClientRequest{
+		{Name: "Adrianna", Surname: "Gopherson", Birthday: "2001-01-01", Gender: "female",
+			Address: &dto.Address{Country: "Russia", City: "Moscow", Street: "Arbat"}},
+		{Name: "Adrian", Surname: "Gopher", Birthday: "2005-01-01", Gender: "male",
+			Address: &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"}},
+	} {
+		s.Require().NoError(createObject(data, baseUrl))
+	}
+
+	// the address country is stored as RU
+	for _, country := range []string{"Russia", "russia", "RUS", "ru"} {
+		resp, err := http.Get(fmt.Sprintf("%s/search?country=%s", baseUrl, country))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode, country)
+
+		var clients []dto.ClientResponse
+		s.Require().NoError(decodeJSON(resp, &clients))
+		s.Require().Len(clients, 1, country)
+		s.Require().Equal("Adrianna", clients[0].Name, country)
+		s.Require().Equal("RU", clients[0].Address.Country, country)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/search?country=Atlantis", baseUrl))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestClientSearchRelevance() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	for _, data := range []dto.ClientRequest{
+		{Name: "Adrianna", Surname: "Gopherson", Birthday: "2001-01-01", Gender: "female"},
+		{Name: "Adrian", Surname: "Gopher", Birthday: "2005-01-01", Gender: "male"},
+	} {
+		s.Require().NoError(createObject(data, baseUrl))
+	}
+
+	search := func(query string) []dto.ClientResponse {
+		resp, err := http.Get(fmt.Sprintf("%s/search?%s", baseUrl, query))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var clients []dto.ClientResponse
+		s.Require().NoError(decodeJSON(resp, &clients))
+
+		return clients
+	}
+
+	clients := search("q=gopher")
+	s.Require().Len(clients, 2)
+	s.Require().Equal("Adrian", clients[0].Name)
+
+	clients = search("q=gopher&sort=relevance&order=asc")
+	s.Require().Len(clients, 2)
+	s.Require().Equal("Adrianna", clients[0].Name)
+
+	// wildcards in the query match literally
+	for _, query := range []string{"q=%25", "q=_", "q=%5C"} {
+		s.Require().Empty(search(query), query)
+	}
+}
+
+func (s *TestSuite) TestClientAddressBook() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+		Address:  &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var client dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &client))
+
+	addressesUrl := fmt.Sprintf("%s/%s/addresses", baseUrl, client.Id)
+
+	billing := dto.AddressBookEntryRequest{
+		Label: "billing",
+		Address: dto.Address{
+			Country:    "JP",
+			City:       "Tokyo",
+			Street:     "Godzilla",
+			PostalCode: "100-0001",
+			Building:   "7",
+			Apartment:  "42",
+		},
+	}
+
+	resp, err = sendJSON(http.MethodPost, addressesUrl, billing)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var added dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &added))
+	s.Require().False(added.IsDefault)
+	s.Require().Equal(billing.Address, added.Address)
+
+	resp, err = sendJSON(http.MethodPost, addressesUrl, billing)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	billing.Label = "office"
+	resp, err = sendJSON(http.MethodPost, addressesUrl, billing)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var count int
+	err = s.db.QueryRow(context.Background(), `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(2, count)
+
+	isDefault := true
+	resp, err = sendJSON(http.MethodPatch, fmt.Sprintf("%s/%s", addressesUrl, added.Id), dto.AddressBookEntryUpdateRequest{IsDefault: &isDefault})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, addressesUrl, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var entries []dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &entries))
+	s.Require().Len(entries, 2)
+	s.Require().Equal(added.Id, entries[0].Id)
+	s.Require().True(entries[0].IsDefault)
+	s.Require().False(entries[1].IsDefault)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s", baseUrl, client.Id), nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &client))
+	s.Require().Equal(&billing.Address, client.Address)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/%s", addressesUrl, added.Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, addressesUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &entries))
+	s.Require().Len(entries, 1)
+	s.Require().True(entries[0].IsDefault)
+	s.Require().Equal("home", entries[0].Label)
+
+	err = s.db.QueryRow(context.Background(), `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/%s", addressesUrl, uuid.New()), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,197 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *TestSuite) TestClientIfMatch() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, url, dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var client dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &client))
+	clientUrl := fmt.Sprintf("%s/%s", url, client.Id)
+
+	resp, err = http.Get(clientUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(`"1"`, resp.Header.Get("ETag"))
+
+	name := "Amogus"
+	resp, err = sendJSONWithHeader(http.MethodPatch, clientUrl, "If-Match", `"1"`, dto.ClientUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(clientUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(`"2"`, resp.Header.Get("ETag"))
+
+	name = "Aboba"
+	resp, err = sendJSONWithHeader(http.MethodPatch, clientUrl, "If-Match", `"1"`, dto.ClientUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodPatch, clientUrl, "If-Match", "1", dto.ClientUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodDelete, clientUrl, "If-Match", `"1"`, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodDelete, clientUrl, "If-Match", `"2"`, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *TestSuite) TestClientAddressIfMatch() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, url, dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+		Address:  &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var client dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &client))
+	addressesUrl := fmt.Sprintf("%s/%s/addresses", url, client.Id)
+
+	resp, err = sendJSON(http.MethodPost, addressesUrl, dto.AddressBookEntryRequest{
+		Label:   "billing",
+		Address: dto.Address{Country: "JP", City: "Tokyo", Street: "Shibuya"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	s.Require().Equal(`"1"`, resp.Header.Get("ETag"))
+
+	var billing dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &billing))
+	s.Require().Equal(int64(1), billing.Version)
+	billingUrl := fmt.Sprintf("%s/%s", addressesUrl, billing.Id)
+
+	label := "shipping"
+	resp, err = sendJSONWithHeader(http.MethodPatch, billingUrl, "If-Match", `"1"`, dto.AddressBookEntryUpdateRequest{Label: &label})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal(`"2"`, resp.Header.Get("ETag"))
+
+	label = "home"
+	resp, err = sendJSONWithHeader(http.MethodPatch, billingUrl, "If-Match", `"1"`, dto.AddressBookEntryUpdateRequest{Label: &label})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	// the previous default entry changes too
+	isDefault := true
+	resp, err = sendJSONWithHeader(http.MethodPatch, billingUrl, "If-Match", `"2"`, dto.AddressBookEntryUpdateRequest{IsDefault: &isDefault})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal(`"3"`, resp.Header.Get("ETag"))
+
+	resp, err = http.Get(addressesUrl)
+	s.Require().NoError(err)
+
+	var entries []dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &entries))
+	s.Require().Len(entries, 2)
+	s.Require().Equal(billing.Id, entries[0].Id)
+	s.Require().Equal(int64(3), entries[0].Version)
+	s.Require().Equal("home", entries[1].Label)
+	s.Require().Equal(int64(2), entries[1].Version)
+
+	homeUrl := fmt.Sprintf("%s/%s", addressesUrl, entries[1].Id)
+
+	resp, err = sendJSONWithHeader(http.MethodDelete, homeUrl, "If-Match", `"1"`, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodDelete, homeUrl, "If-Match", `"2"`, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodDelete, homeUrl, "If-Match", `"2"`, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestProductUpdateDate() {
+	s.CleanTable()
+	batchUrl := fmt.Sprintf("http://%s:%s/api/v1/batch", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, batchUrl, dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("sup", "create", "suppliers", "", dto.SupplierRequest{
+				Name:        "Aboba Inc.",
+				PhoneNumber: "+78005553535",
+				Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+			}),
+			batchOperation("fridge", "create", "products", "", map[string]any{
+				"name":            "Fridge",
+				"category_id":     s.category("kitchen"),
+				"price":           499.9,
+				"available_stock": 10,
+				"supplier_id":     "$sup",
+			}),
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var output dto.BatchResponse
+	s.Require().NoError(decodeJSON(resp, &output))
+	productId := *output.Results[1].Id
+
+	_, err = s.db.Exec(context.Background(), `UPDATE product SET last_update_date = NOW() - INTERVAL '1 day' WHERE id = $1`, productId)
+	s.Require().NoError(err)
+
+	url := fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease", s.cfg.CrudService.Address, s.cfg.CrudService.Port, productId)
+	decrease := dto.ProductStockRequest{Quantity: 1, Reason: "sale"}
+	resp, err = sendJSONWithHeader(http.MethodPost, url, "If-Match", `"2"`, decrease)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, err = sendJSONWithHeader(http.MethodPost, url, "If-Match", `"1"`, decrease)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var (
+		updated time.Time
+		version int64
+	)
+	err = s.db.QueryRow(context.Background(), `SELECT last_update_date, version FROM product WHERE id = $1`, productId).Scan(&updated, &version)
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), version)
+	s.Require().WithinDuration(time.Now(), updated, time.Hour)
+}
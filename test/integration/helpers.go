@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
@@ -10,9 +11,13 @@ import (
 	"image/jpeg"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
 func createObject(data any, url string) error {
@@ -33,6 +38,57 @@ func createObject(data any, url string) error {
 	return nil
 }
 
+// create posts the request to the path of the API, requires it to be created
+// and decodes the response into output.
+func (s *TestSuite) create(path string, request, output any) {
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("http://%s:%s/api/v1%s",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, path), request)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode, path)
+	s.Require().NoError(decodeJSON(resp, output))
+}
+
+// createSupplier creates the Aboba Inc. supplier in Tokyo.
+func (s *TestSuite) createSupplier() uuid.UUID {
+	var supplier dto.SupplierResponse
+	s.create("/suppliers", dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	}, &supplier)
+
+	return supplier.Id
+}
+
+// createClient creates the client living in Tokyo.
+func (s *TestSuite) createClient(name, surname, gender string) uuid.UUID {
+	var client dto.ClientResponse
+	s.create("/clients", dto.ClientRequest{
+		Name:     name,
+		Surname:  surname,
+		Birthday: "2001-01-01",
+		Gender:   gender,
+		Address:  &dto.Address{Country: "JP", City: "Tokyo", Street: "Shibuya"},
+	}, &client)
+
+	return client.Id
+}
+
+// createProducts creates the products of the supplier, it returns their ids
+// by name.
+func (s *TestSuite) createProducts(supplier uuid.UUID, products ...dto.ProductRequest) map[string]uuid.UUID {
+	output := make(map[string]uuid.UUID)
+	for _, product := range products {
+		product.SupplierId = supplier
+
+		var created dto.ProductResponse
+		s.create("/products", product, &created)
+		output[product.Name] = created.Id
+	}
+
+	return output
+}
+
 // func createFormData(file, filename, fieldname string) (*multipart.Writer, *bytes.Buffer, error) {
 // 	buf, err := os.Open(file)
 // 	if err != nil {
@@ -80,3 +136,62 @@ func hashBytes(data []byte) string {
 	outputHash := sha256.Sum256(data)
 	return hex.EncodeToString(outputHash[:])
 }
+
+func sendJSON(method, url string, data any) (*http.Response, error) {
+	return sendJSONWithHeader(method, url, "", "", data)
+}
+
+// sendJSONWithHeader sends data as JSON with an extra header, an empty name
+// adds nothing.
+func sendJSONWithHeader(method, url, name, value string, data any) (*http.Response, error) {
+	var body io.Reader
+
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if name != "" {
+		req.Header.Set(name, value)
+	}
+
+	return http.DefaultClient.Do(req)
+}
+
+func decodeJSON(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, out)
+}
+
+func uploadImage(url, path string) (*http.Response, error) {
+	buf, err := extractImageData(path, filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Image-Title", filepath.Base(path))
+
+	return http.DefaultClient.Do(req)
+}
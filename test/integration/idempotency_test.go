@@ -0,0 +1,201 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func sendWithKey(method, url, key string, data any) (*http.Response, error) {
+	var body io.Reader
+
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+
+	return http.DefaultClient.Do(req)
+}
+
+func (s *TestSuite) TestIdempotentCreate() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	client := dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+	}
+
+	resp, err := sendWithKey(http.MethodPost, url, "create-adrianna", client)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var first dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &first))
+
+	resp, err = sendWithKey(http.MethodPost, url, "create-adrianna", client)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	s.Require().Equal("true", resp.Header.Get("Idempotent-Replayed"))
+
+	var second dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &second))
+	s.Require().Equal(first.Id, second.Id)
+
+	var count int
+	err = s.db.QueryRow(context.Background(), `SELECT COUNT(*) FROM client`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().Equal(1, count)
+
+	client.Name = "Amogus"
+	resp, err = sendWithKey(http.MethodPost, url, "create-adrianna", client)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func (s *TestSuite) TestIdempotentStockDecrease() {
+	s.CleanTable()
+	batchUrl := fmt.Sprintf("http://%s:%s/api/v1/batch", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	supplier := dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address: &dto.Address{
+			Country: "JP",
+			City:    "Tokyo",
+			Street:  "Godzilla",
+		},
+	}
+
+	product := map[string]any{
+		"name":            "Fridge",
+		"category_id":     s.category("kitchen"),
+		"price":           499.9,
+		"available_stock": 10,
+		"supplier_id":     "$sup",
+	}
+
+	resp, err := sendJSON(http.MethodPost, batchUrl, dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("sup", "create", "suppliers", "", supplier),
+			batchOperation("fridge", "create", "products", "", product),
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var output dto.BatchResponse
+	s.Require().NoError(decodeJSON(resp, &output))
+	s.Require().NotNil(output.Results[1].Id)
+	productId := *output.Results[1].Id
+
+	url := fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease", s.cfg.CrudService.Address, s.cfg.CrudService.Port, productId)
+	for i := 0; i < 3; i++ {
+		resp, err := sendWithKey(http.MethodPost, url, "decrease-fridge", dto.ProductStockRequest{Quantity: 3, Reason: "sale"})
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+	}
+
+	var stock int64
+	err = s.db.QueryRow(context.Background(), `SELECT available_stock FROM product WHERE id = $1`, productId).Scan(&stock)
+	s.Require().NoError(err)
+	s.Require().Equal(int64(7), stock)
+}
+
+func (s *TestSuite) TestIdempotencyKeyLease() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	client := dto.ClientRequest{
+		Name:     "Adrianna",
+		Surname:  "Gopher",
+		Birthday: "2001-01-01",
+		Gender:   "female",
+	}
+
+	// a request with the key runs on another instance
+	_, err := s.db.Exec(context.Background(), `INSERT INTO idempotency_key (key, fingerprint, expires_at)
+		VALUES ('running', 'fingerprint', NOW() + INTERVAL '1 minute'), ('crashed', 'fingerprint', NOW() - INTERVAL '1 second')`)
+	s.Require().NoError(err)
+
+	resp, err := sendWithKey(http.MethodPost, url, "running", client)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	// the lease of a request which is never completed expires
+	resp, err = sendWithKey(http.MethodPost, url, "crashed", client)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	// the completed response is kept for the ttl, not for the lease
+	var kept bool
+	err = s.db.QueryRow(context.Background(), `SELECT completed AND expires_at > NOW() + INTERVAL '1 hour'
+		FROM idempotency_key WHERE key = 'crashed'`).Scan(&kept)
+	s.Require().NoError(err)
+	s.Require().True(kept)
+}
+
+func (s *TestSuite) TestIdempotentReplayHeaders() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	var (
+		created dto.PromotionResponse
+		etags   []string
+	)
+
+	for range 2 {
+		resp, err := sendWithKey(http.MethodPost, base+"/promotions", "create-sale", dto.PromotionRequest{Name: "Sale", Kind: "percentage", Value: 10})
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusCreated, resp.StatusCode)
+		s.Require().NoError(decodeJSON(resp, &created))
+
+		etags = append(etags, resp.Header.Get("ETag"))
+	}
+
+	s.Require().NotEmpty(etags[0])
+	s.Require().Equal(etags[0], etags[1])
+
+	// the replayed ETag is good for the next update
+	name := "Big sale"
+	resp, err := sendJSONWithHeader(http.MethodPatch, fmt.Sprintf("%s/promotions/%s", base, created.Id), "If-Match", etags[1], dto.PromotionUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var locations []string
+	for range 2 {
+		resp, err = sendWithKey(http.MethodPost, base+"/jobs/export/suppliers?format=csv", "export-suppliers", nil)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+		locations = append(locations, resp.Header.Get("Location"))
+	}
+
+	s.Require().Equal("true", resp.Header.Get("Idempotent-Replayed"))
+	s.Require().NotEmpty(locations[0])
+	s.Require().Equal(locations[0], locations[1])
+}
@@ -7,6 +7,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"math/rand/v2"
 	"net/http"
@@ -332,7 +335,7 @@ func (s *TestSuite) TestGetByIdImage() {
 		s.Require().Equal(http.StatusCreated, postResp.StatusCode)
 	}
 
-	sql := `SELECT * FROM image`
+	sql := `SELECT id, title, data FROM image`
 	rows, err := s.db.Query(context.Background(), sql)
 	s.Require().NoError(err)
 
@@ -484,7 +487,7 @@ func (s *TestSuite) TestUpdateImage() {
 		s.Require().Equal(http.StatusCreated, postResp.StatusCode)
 	}
 
-	sqlQuery := `SELECT * FROM image`
+	sqlQuery := `SELECT id, title, data FROM image`
 	rows, err := s.db.Query(context.Background(), sqlQuery)
 	s.Require().NoError(err)
 
@@ -648,7 +651,7 @@ func (s *TestSuite) TestUpdateImageWithChangeTitle() {
 		s.Require().Equal(http.StatusCreated, postResp.StatusCode)
 	}
 
-	sqlQuery := `SELECT * FROM image`
+	sqlQuery := `SELECT id, title, data FROM image`
 	rows, err := s.db.Query(context.Background(), sqlQuery)
 	s.Require().NoError(err)
 
@@ -701,7 +704,7 @@ func (s *TestSuite) TestUpdateImageWithChangeTitle() {
 	takedImageHash := hashBytes(receivedImage.Data)
 	s.Require().Equal(expectedImageHash, takedImageHash)
 
-	sqlCheckContent := `SELECT * FROM image WHERE id=@checkId`
+	sqlCheckContent := `SELECT id, title, data FROM image WHERE id=@checkId`
 	arg := pgx.NamedArgs{
 		"checkId": updateData.Id.String(),
 	}
@@ -745,7 +748,7 @@ func (s *TestSuite) TestDeleteImage() {
 		s.Require().Equal(http.StatusCreated, postResp.StatusCode)
 	}
 
-	sqlQuery := `SELECT * FROM image`
+	sqlQuery := `SELECT id, title, data FROM image`
 	rows, err := s.db.Query(context.Background(), sqlQuery)
 	s.Require().NoError(err)
 
@@ -853,3 +856,57 @@ func (s *TestSuite) TestDeleteImageInvalidId() {
 	s.Require().NoError(err)
 	s.Require().Equal(http.StatusBadRequest, patchResp.StatusCode)
 }
+
+func (s *TestSuite) TestCreateImageMetadata() {
+	s.CleanTable()
+	buf, err := extractImageData("../data/cat.png", "cat.png")
+	s.Require().NoError(err)
+
+	decoded, err := jpeg.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	s.Require().NoError(err)
+
+	// inject an Exif APP1 segment right after SOI
+	exif := []byte{0xFF, 0xE1, 0x00, 0x0E, 'E', 'x', 'i', 'f', 0x00, 0x00, 'G', 'P', 'S', '!', 0x00, 0x00}
+	withExif := append([]byte{}, buf.Bytes()[:2]...)
+	withExif = append(withExif, exif...)
+	withExif = append(withExif, buf.Bytes()[2:]...)
+
+	url := fmt.Sprintf("http://%s:%s/api/v1/images", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(withExif))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Image-Title", "cat")
+
+	postResp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, postResp.StatusCode)
+
+	var created dto.ImageResponse
+	s.Require().NoError(decodeJSON(postResp, &created))
+
+	s.Require().Equal(decoded.Width, created.Metadata.Width)
+	s.Require().Equal(decoded.Height, created.Metadata.Height)
+	s.Require().Equal("jpeg", created.Metadata.Format)
+	s.Require().Equal(int64(buf.Len()), created.Metadata.Size)
+	s.Require().Regexp("^#[0-9a-f]{6}$", created.Metadata.DominantColor)
+	s.Require().Equal(hashBytes(buf.Bytes()), hashBytes(created.Image))
+}
+
+func (s *TestSuite) TestCreateImageTooLarge() {
+	s.CleanTable()
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, image.NewGray(image.Rect(0, 0, s.cfg.ImageService.MaxWidth+1, 1)))
+	s.Require().NoError(err)
+
+	url := fmt.Sprintf("http://%s:%s/api/v1/images", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(buf.Bytes()))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Image-Title", "wide")
+
+	postResp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer postResp.Body.Close()
+
+	s.Require().Equal(http.StatusRequestEntityTooLarge, postResp.StatusCode)
+}
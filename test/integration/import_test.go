@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const importSuppliersCSV = "name,phone_number,email,country,city,street\n" +
+	"Aboba Inc.,+78005553535,sales@aboba.com,JP,Tokyo,Godzilla\n" +
+	"Sus Ltd.,+78005553536,,JP,Osaka,Amogus\n"
+
+// products reference categories by slug or name and suppliers by name
+const importProductsNDJSON = `{"name":"Fridge","category":"kitchen","price":499.9,"available_stock":3,"supplier_name":"Aboba Inc."}
+{"name":"Kettle","category":"kitchen","price":25,"available_stock":10,"supplier_name":"Sus Ltd."}
+`
+
+// importFile posts the file to the import of the entity and checks the
+// response status.
+func (s *TestSuite) importFile(path, contentType, data string, status int) dto.ImportReportResponse {
+	resp, err := http.Post(fmt.Sprintf("http://%s:%s/api/v1/import/%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, path),
+		contentType, strings.NewReader(data))
+	s.Require().NoError(err)
+	s.Require().Equal(status, resp.StatusCode, path)
+
+	var report dto.ImportReportResponse
+	s.Require().NoError(decodeJSON(resp, &report))
+	return report
+}
+
+// importCatalog imports two suppliers with a product each.
+func (s *TestSuite) importCatalog() {
+	s.importFile("suppliers", "text/csv", importSuppliersCSV, http.StatusOK)
+
+	s.category("Kitchen")
+	s.importFile("products", "application/x-ndjson", importProductsNDJSON, http.StatusOK)
+}
+
+func (s *TestSuite) countProducts() int {
+	var count int
+	err := s.db.QueryRow(context.Background(), `SELECT COUNT(*) FROM product`).Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *TestSuite) TestImportDryRun() {
+	s.CleanTable()
+
+	report := s.importFile("suppliers?dry_run=true", "text/csv", importSuppliersCSV, http.StatusOK)
+	s.Require().True(report.DryRun)
+	s.Require().Equal(2, report.Total)
+	s.Require().Equal(2, report.Valid)
+	s.Require().Equal(0, report.Imported)
+	s.Require().Zero(s.countSuppliers())
+}
+
+func (s *TestSuite) TestImportCsv() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	report := s.importFile("suppliers", "text/csv", importSuppliersCSV, http.StatusOK)
+	s.Require().Equal(2, report.Imported)
+
+	var suppliers []dto.SupplierResponse
+	resp, err := http.Get(base + "/suppliers")
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &suppliers))
+	s.Require().Len(suppliers, 2)
+
+	for _, supplier := range suppliers {
+		s.Require().NotNil(supplier.Address)
+		s.Require().Equal("JP", supplier.Address.Country)
+	}
+}
+
+func (s *TestSuite) TestImportNdjson() {
+	s.CleanTable()
+	s.importFile("suppliers", "text/csv", importSuppliersCSV, http.StatusOK)
+	s.category("Kitchen")
+
+	report := s.importFile("products", "application/x-ndjson", importProductsNDJSON, http.StatusOK)
+	s.Require().Equal(2, report.Imported)
+	s.Require().Equal(2, s.countProducts())
+}
+
+func (s *TestSuite) TestImportRejectedRows() {
+	s.CleanTable()
+	s.importCatalog()
+
+	// one bad row rejects the whole file
+	invalidNDJSON := `{"name":"Oven","category":"kitchen","price":300,"available_stock":1,"supplier_name":"Aboba Inc."}
+{"name":"Toaster","category":"kitchen","price":-1,"available_stock":1,"supplier_name":"Aboba Inc."}
+{"name":"Mixer","category":"kitchen","price":40,"available_stock":1,"supplier_name":"Nobody"}
+{"name":"Mower","category":"Garden tools","price":200,"available_stock":1,"supplier_name":"Aboba Inc."}
+not a json
+`
+
+	report := s.importFile("products", "application/x-ndjson", invalidNDJSON, http.StatusUnprocessableEntity)
+	s.Require().Equal(5, report.Total)
+	s.Require().Equal(4, report.Failed)
+	s.Require().Equal(0, report.Imported)
+
+	rows := make([]int, len(report.Errors))
+	for i, rowErr := range report.Errors {
+		rows[i] = rowErr.Row
+	}
+
+	s.Require().ElementsMatch([]int{2, 3, 4, 5}, rows)
+	s.Require().Equal(2, s.countProducts())
+}
+
+func (s *TestSuite) TestImportUnsupported() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := http.Post(base+"/import/suppliers", "application/json", strings.NewReader("{}"))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Post(base+"/import/images", "text/csv", strings.NewReader("name\nx\n"))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestExportCsv() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	s.importCatalog()
+
+	resp, err := http.Get(base + "/export/products")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().True(strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Require().Equal("id", records[0][0])
+	s.Require().Equal("kitchen", records[1][2])
+}
+
+func (s *TestSuite) TestExportNdjson() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	s.importCatalog()
+
+	resp, err := http.Get(base + "/export/suppliers?format=ndjson")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var names []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var row map[string]any
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &row))
+		names = append(names, row["name"].(string))
+	}
+	s.Require().NoError(scanner.Err())
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	s.Require().Equal([]string{"Aboba Inc.", "Sus Ltd."}, names)
+}
@@ -0,0 +1,213 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// kitchenStock is 10 kettles reordered below 3 up to 12 and one toaster
+// reordered below 2 up to 5.
+type kitchenStock struct {
+	supplier uuid.UUID
+	kettle   uuid.UUID
+	toaster  uuid.UUID
+}
+
+func (s *TestSuite) setReorder(product uuid.UUID, policy dto.ReorderPolicyRequest) *http.Response {
+	resp, err := sendJSON(http.MethodPut, fmt.Sprintf("http://%s:%s/api/v1/products/%s/reorder",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, product), policy)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *TestSuite) sellStock(product uuid.UUID, quantity int64) {
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, product), dto.ProductStockRequest{Quantity: quantity, Reason: "sale"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *TestSuite) kitchenStock() kitchenStock {
+	kitchen, supplier := s.category("Kitchen"), s.createSupplier()
+	products := s.createProducts(supplier,
+		dto.ProductRequest{Name: "Kettle", CategoryId: kitchen, Price: 50, AvailableStock: 10},
+		dto.ProductRequest{Name: "Toaster", CategoryId: kitchen, Price: 50, AvailableStock: 1},
+	)
+
+	for name, policy := range map[string]dto.ReorderPolicyRequest{
+		"Kettle":  {Threshold: 3, Target: 12},
+		"Toaster": {Threshold: 2, Target: 5},
+	} {
+		resp := s.setReorder(products[name], policy)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode, name)
+	}
+
+	return kitchenStock{supplier: supplier, kettle: products["Kettle"], toaster: products["Toaster"]}
+}
+
+func (s *TestSuite) lowStockItems(query string) []dto.LowStockItemResponse {
+	resp, err := http.Get(fmt.Sprintf("http://%s:%s/api/v1/inventory/low-stock%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, query))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var items []dto.LowStockItemResponse
+	s.Require().NoError(decodeJSON(resp, &items))
+	s.Require().Equal(fmt.Sprint(len(items)), resp.Header.Get("X-Total-Count"))
+	return items
+}
+
+func (s *TestSuite) TestReorderPolicy() {
+	s.CleanTable()
+	stock := s.kitchenStock()
+
+	kettle := s.getProduct(stock.kettle)
+	s.Require().Equal(&dto.ReorderPolicyResponse{Threshold: 3, Target: 12}, kettle.Reorder)
+}
+
+func (s *TestSuite) TestReorderPolicyInvalid() {
+	s.CleanTable()
+	stock := s.kitchenStock()
+
+	resp := s.setReorder(stock.kettle, dto.ReorderPolicyRequest{Threshold: 3, Target: 2})
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Len(problem.Errors, 1)
+	s.Require().Equal("target", problem.Errors[0].Field)
+	s.Require().Equal("gt", problem.Errors[0].Code)
+
+	// the policy is not changed
+	s.Require().Equal(int64(12), s.getProduct(stock.kettle).Reorder.Target)
+}
+
+func (s *TestSuite) TestLowStockEvents() {
+	s.CleanTable()
+	stock := s.kitchenStock()
+
+	events := s.lowStockEvents()
+
+	// 10 -> 5 stays above the threshold, 5 -> 3 crosses it and 3 -> 2 is low already
+	for _, quantity := range []int64{5, 2, 1} {
+		s.sellStock(stock.kettle, quantity)
+	}
+
+	s.Require().Equal(events+1, s.lowStockEvents())
+}
+
+func (s *TestSuite) TestLowStockList() {
+	s.CleanTable()
+	stock := s.kitchenStock()
+	s.sellStock(stock.kettle, 8)
+
+	items := s.lowStockItems("")
+	s.Require().Len(items, 2)
+
+	s.Require().Equal("Kettle", items[0].Name)
+	s.Require().Equal(int64(2), items[0].AvailableStock)
+	s.Require().Equal(int64(10), items[0].SuggestedQuantity)
+	s.Require().NotNil(items[0].LowSince)
+
+	// the toaster was low before the threshold was set, it is listed before
+	// the evaluator flags it
+	s.Require().Equal("Toaster", items[1].Name)
+	s.Require().Equal(int64(4), items[1].SuggestedQuantity)
+
+	s.Require().Len(s.lowStockItems("?supplier_id="+stock.supplier.String()), 2)
+}
+
+func (s *TestSuite) TestLowStockRecovered() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	stock := s.kitchenStock()
+	s.sellStock(stock.kettle, 8)
+
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("%s/products/%s/stock/increase", baseUrl, stock.kettle), dto.ProductStockRequest{Quantity: 10, Reason: "restock"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/products/%s/reorder", baseUrl, stock.toaster), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	s.Require().Empty(s.lowStockItems(""))
+}
+
+func (s *TestSuite) TestReorderSuggestions() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	stock := s.kitchenStock()
+	s.sellStock(stock.kettle, 8)
+
+	resp, err := http.Get(baseUrl + "/inventory/reorder-suggestions")
+	s.Require().NoError(err)
+
+	var suggestions []dto.ReorderSuggestionResponse
+	s.Require().NoError(decodeJSON(resp, &suggestions))
+	s.Require().Len(suggestions, 1)
+	s.Require().Equal(stock.supplier, suggestions[0].SupplierId)
+	s.Require().Len(suggestions[0].Items, 2)
+	s.Require().Equal(int64(14), suggestions[0].TotalQuantity)
+}
+
+func (s *TestSuite) TestPurchaseOrderExport() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	stock := s.kitchenStock()
+	s.sellStock(stock.kettle, 8)
+
+	resp, err := http.Get(baseUrl + "/export/purchase-orders")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Require().Equal("supplier_id", records[0][0])
+	s.Require().Equal([]string{"Kettle", "10"}, []string{records[1][5], records[1][9]})
+}
+
+func (s *TestSuite) TestLowStockInvalidSupplier() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	stock := s.kitchenStock()
+
+	for _, url := range []string{
+		baseUrl + "/inventory/low-stock?supplier_id=aboba",
+		baseUrl + "/inventory/reorder-suggestions?supplier_id=aboba",
+	} {
+		resp, err := http.Get(url)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode, url)
+	}
+
+	resp, err := http.Get(fmt.Sprintf("%s/inventory/low-stock?supplier_id=%s", baseUrl, stock.kettle))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+// lowStockEvents returns the number of low stock events fired by stock
+// changes from /debug/vars.
+func (s *TestSuite) lowStockEvents() int64 {
+	resp, err := http.Get(fmt.Sprintf("http://%s:%s/debug/vars", s.cfg.CrudService.Address, s.cfg.CrudService.Port))
+	s.Require().NoError(err)
+
+	var vars struct {
+		LowStock map[string]int64 `json:"low_stock"`
+	}
+	s.Require().NoError(decodeJSON(resp, &vars))
+
+	return vars.LowStock["events_"+domain.LowStockSourceStockChange]
+}
@@ -0,0 +1,230 @@
+package integration
+
+import (
+	crud_errors "CRUD-HOME-APPLIANCE-STORE/internal/errors"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"CRUD-HOME-APPLIANCE-STORE/internal/repositories/postgres"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// waitJob polls the job until it is finished.
+func (s *TestSuite) waitJob(base string, id uuid.UUID) dto.JobResponse {
+	deadline := time.Now().Add(30 * time.Second)
+
+	for {
+		resp, err := http.Get(fmt.Sprintf("%s/jobs/%s", base, id))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var job dto.JobResponse
+		s.Require().NoError(decodeJSON(resp, &job))
+
+		if job.Status != domain.JobQueued && job.Status != domain.JobRunning {
+			return job
+		}
+
+		s.Require().True(time.Now().Before(deadline), "job %s is not finished in time", id)
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// importSuppliers starts the import of the suppliers and waits for the job.
+func (s *TestSuite) importSuppliers(base, data string) dto.JobResponse {
+	resp, err := http.Post(base+"/jobs/import/suppliers", "text/csv", strings.NewReader(data))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var job dto.JobResponse
+	s.Require().NoError(decodeJSON(resp, &job))
+	s.Require().Equal(domain.JobImport, job.Kind)
+	s.Require().Equal("/api/v1/jobs/"+job.Id.String(), resp.Header.Get("Location"))
+
+	return s.waitJob(base, job.Id)
+}
+
+// exportSuppliers starts the csv export of the suppliers and waits for the job.
+func (s *TestSuite) exportSuppliers(base string) dto.JobResponse {
+	resp, err := http.Post(base+"/jobs/export/suppliers?format=csv", "application/json", nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var job dto.JobResponse
+	s.Require().NoError(decodeJSON(resp, &job))
+
+	return s.waitJob(base, job.Id)
+}
+
+const suppliersCSV = "name,phone_number,country,city,street\n" +
+	"Aboba Inc.,+78005553535,JP,Tokyo,Godzilla\n" +
+	"Sus Ltd.,+78005553536,JP,Osaka,Amogus\n"
+
+func (s *TestSuite) TestJobImport() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	job := s.importSuppliers(base, suppliersCSV)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+	s.Require().Equal(int64(2), job.Progress)
+	s.Require().Contains(string(job.Result), `"imported":2`)
+}
+
+func (s *TestSuite) TestJobImportDuplicates() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	job := s.importSuppliers(base, suppliersCSV)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+
+	// the suppliers are imported already
+	job = s.importSuppliers(base, suppliersCSV)
+	s.Require().Equal(domain.JobFailed, job.Status)
+	s.Require().NotEmpty(job.Error)
+	s.Require().Contains(string(job.Result), `"failed":2`)
+}
+
+func (s *TestSuite) TestJobExport() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	job := s.importSuppliers(base, suppliersCSV)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+
+	job = s.exportSuppliers(base)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+	s.Require().Equal("/api/v1/jobs/"+job.Id.String()+"/output", job.ResultUrl)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s:%s%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, job.ResultUrl))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+}
+
+func (s *TestSuite) TestJobCancel() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	job := s.importSuppliers(base, suppliersCSV)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+
+	// a finished job cannot be canceled
+	resp, err := http.Post(fmt.Sprintf("%s/jobs/%s/cancel", base, job.Id), "application/json", nil)
+	s.Require().NoError(err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	resp, err = http.Post(fmt.Sprintf("%s/jobs/%s/cancel", base, uuid.New()), "application/json", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestJobInvalid() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := http.Post(base+"/jobs/export/images", "application/json", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("%s/jobs/%s", base, uuid.New()))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestJobLeaseFencing() {
+	s.CleanTable()
+	ctx := context.Background()
+
+	// the job is running under the second attempt, the lease keeps workers away
+	var id uuid.UUID
+	err := s.db.QueryRow(ctx, `INSERT INTO job (kind, status, attempts, locked_until, started_at)
+		VALUES ('address_purge', 'running', 2, NOW() + INTERVAL '1 hour', NOW()) RETURNING id`).Scan(&id)
+	s.Require().NoError(err)
+
+	repo := postgres.NewJobRepository(s.db, s.logger)
+
+	_, err = repo.Heartbeat(ctx, id, 1, 5, time.Minute)
+	s.Require().ErrorIs(err, crud_errors.ErrNotFound)
+
+	s.Require().ErrorIs(repo.Release(ctx, id, 1, 5), crud_errors.ErrNotFound)
+
+	stale := &domain.Job{Id: id, Attempts: 1, Status: domain.JobSucceeded}
+	s.Require().ErrorIs(repo.Finish(ctx, stale), crud_errors.ErrNotFound)
+
+	canceled, err := repo.Heartbeat(ctx, id, 2, 5, time.Minute)
+	s.Require().NoError(err)
+	s.Require().False(canceled)
+
+	current := &domain.Job{Id: id, Attempts: 2, Status: domain.JobSucceeded, Progress: 5}
+	s.Require().NoError(repo.Finish(ctx, current))
+
+	job, err := repo.GetById(ctx, id)
+	s.Require().NoError(err)
+	s.Require().Equal(domain.JobSucceeded, job.Status)
+	s.Require().Equal(int64(5), job.Progress)
+}
+
+func (s *TestSuite) TestImageReprocessJob() {
+	s.CleanTable()
+	base := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := uploadImage(base+"/images", "../data/bear.png")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var uploaded dto.ImageResponse
+	s.Require().NoError(decodeJSON(resp, &uploaded))
+
+	// the image looks stored before metadata extraction, another one is broken
+	_, err = s.db.Exec(context.Background(), `UPDATE image SET width = 0, height = 0, format = '', dominant_color = '' WHERE id = $1`, uploaded.Id)
+	s.Require().NoError(err)
+
+	_, err = s.db.Exec(context.Background(), `INSERT INTO image (title, data) VALUES ('broken', 'not an image')`)
+	s.Require().NoError(err)
+
+	resp, err = http.Post(base+"/jobs/image-reprocess", "application/json", nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var job dto.JobResponse
+	s.Require().NoError(decodeJSON(resp, &job))
+	s.Require().Equal(domain.JobImageReprocess, job.Kind)
+
+	job = s.waitJob(base, job.Id)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+	s.Require().Equal(int64(2), job.Progress)
+	s.Require().JSONEq(`{"updated":1,"skipped":0,"failed":1}`, string(job.Result))
+
+	resp, err = http.Get(fmt.Sprintf("%s/images/%s", base, uploaded.Id))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var image dto.ImageResponse
+	s.Require().NoError(decodeJSON(resp, &image))
+	s.Require().Equal(uploaded.Metadata, image.Metadata)
+
+	// nothing changes on the second run
+	resp, err = http.Post(base+"/jobs/image-reprocess", "application/json", nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &job))
+
+	job = s.waitJob(base, job.Id)
+	s.Require().Equal(domain.JobSucceeded, job.Status, job.Error)
+	s.Require().JSONEq(`{"updated":0,"skipped":1,"failed":1}`, string(job.Result))
+}
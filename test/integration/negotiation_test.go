@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ugorji/go/codec"
+)
+
+// negotiate sends the body of the content type and accepts the given types.
+func (s *TestSuite) negotiate(method, url, contentType, accept string, body io.Reader) *http.Response {
+	req, err := http.NewRequest(method, url, body)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", accept)
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *TestSuite) clientsUrl() string {
+	return fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+}
+
+// createYamlClient creates the client from the yaml document.
+func (s *TestSuite) createYamlClient(document string) dto.ClientResponse {
+	resp := s.negotiate(http.MethodPost, s.clientsUrl(), "application/yaml", "application/json", strings.NewReader(document))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var created dto.ClientResponse
+	s.Require().NoError(decodeJSON(resp, &created))
+	return created
+}
+
+func (s *TestSuite) countClients() int {
+	var count int
+	s.Require().NoError(s.db.QueryRow(context.Background(), `SELECT COUNT(*) FROM client`).Scan(&count))
+	return count
+}
+
+func (s *TestSuite) TestNegotiateYamlToXml() {
+	s.CleanTable()
+
+	resp := s.negotiate(http.MethodPost, s.clientsUrl(), "application/yaml", "application/xml",
+		strings.NewReader("name: Adrianna\nsurname: Gopher\nbirthday: 2001-01-01\ngender: female\n"))
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	s.Require().Equal("application/xml; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	var created dto.ClientResponse
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().NoError(xml.Unmarshal(raw, &created))
+	s.Require().Equal("Adrianna", created.Name)
+	s.Require().Equal("2001-01-01", created.Birthday)
+}
+
+func (s *TestSuite) TestNegotiateMsgpack() {
+	s.CleanTable()
+
+	var payload bytes.Buffer
+	s.Require().NoError(codec.NewEncoder(&payload, &codec.MsgpackHandle{}).Encode(map[string]any{
+		"name":     "Bob",
+		"surname":  "Gopher",
+		"birthday": "2002-02-02",
+		"gender":   "male",
+	}))
+
+	resp := s.negotiate(http.MethodPost, s.clientsUrl(), "application/msgpack", "application/msgpack;q=0.9, application/json;q=0.1", &payload)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	s.Require().Equal("application/msgpack", resp.Header.Get("Content-Type"))
+
+	handle := codec.MsgpackHandle{}
+	handle.RawToString = true
+	var decoded map[string]any
+	s.Require().NoError(codec.NewDecoder(resp.Body, &handle).Decode(&decoded))
+	resp.Body.Close()
+	s.Require().Equal("Bob", decoded["name"])
+	s.Require().NotEmpty(decoded["id"])
+}
+
+func (s *TestSuite) TestNegotiateQuality() {
+	s.CleanTable()
+	s.createYamlClient("name: Adrianna\nsurname: Gopher\nbirthday: 2001-01-01\ngender: female\n")
+
+	resp := s.negotiate(http.MethodGet, s.clientsUrl(), "", "application/json;q=0.5, application/yaml", nil)
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().Equal("application/yaml", resp.Header.Get("Content-Type"))
+	s.Require().True(strings.HasPrefix(string(raw), "- "))
+}
+
+func (s *TestSuite) TestNegotiateCsvList() {
+	s.CleanTable()
+	s.createYamlClient("name: Adrianna\nsurname: Gopher\nbirthday: 2001-01-01\ngender: female\n")
+	s.createYamlClient("name: Bob\nsurname: Gopher\nbirthday: 2002-02-02\ngender: male\n")
+
+	resp := s.negotiate(http.MethodGet, s.clientsUrl(), "", "text/csv", nil)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal("text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	resp.Body.Close()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 3)
+	s.Require().Equal([]string{"id", "name", "surname", "birthday", "gender"}, rows[0][:5])
+}
+
+func (s *TestSuite) TestNegotiateNotAcceptable() {
+	s.CleanTable()
+	created := s.createYamlClient("name: Adrianna\nsurname: Gopher\nbirthday: 2001-01-01\ngender: female\n")
+
+	// csv is offered by lists only
+	resp := s.negotiate(http.MethodGet, fmt.Sprintf("%s/%s", s.clientsUrl(), created.Id), "", "text/csv", nil)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotAcceptable, resp.StatusCode)
+
+	resp = s.negotiate(http.MethodGet, s.clientsUrl(), "", "image/png", nil)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotAcceptable, resp.StatusCode)
+	s.Require().Equal("application/problem+json", resp.Header.Get("Content-Type"))
+}
+
+func (s *TestSuite) TestNegotiateRejectedWrite() {
+	s.CleanTable()
+
+	// a write rejected by Accept is not applied
+	resp := s.negotiate(http.MethodPost, s.clientsUrl(), "application/yaml", "text/csv",
+		strings.NewReader("name: Carol\nsurname: Gopher\nbirthday: 2003-03-03\ngender: female\n"))
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotAcceptable, resp.StatusCode)
+	s.Require().Zero(s.countClients())
+
+	// the key of the rejected request is free for the retry
+	for _, attempt := range []struct {
+		accept string
+		status int
+	}{{"text/csv", http.StatusNotAcceptable}, {"application/json", http.StatusCreated}} {
+		req, err := http.NewRequest(http.MethodPost, s.clientsUrl(), strings.NewReader(`{"name":"Dave","surname":"Gopher","birthday":"2004-04-04","gender":"male"}`))
+		s.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", attempt.accept)
+		req.Header.Set("Idempotency-Key", "negotiated")
+
+		resp, err = http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(attempt.status, resp.StatusCode, attempt.accept)
+	}
+
+	s.Require().Equal(1, s.countClients())
+}
+
+func (s *TestSuite) TestNegotiateUnsupportedMediaType() {
+	s.CleanTable()
+
+	resp := s.negotiate(http.MethodPost, s.clientsUrl(), "text/plain", "application/json", strings.NewReader("Adrianna"))
+	resp.Body.Close()
+	s.Require().Equal(http.StatusUnsupportedMediaType, resp.StatusCode)
+	s.Require().Zero(s.countClients())
+}
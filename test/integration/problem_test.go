@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (s *TestSuite) TestProblemDetails() {
+	s.CleanTable()
+	url := fmt.Sprintf("http://%s:%s/api/v1/clients", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	path := fmt.Sprintf("/api/v1/clients/%s", uuid.New())
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s:%s%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, path), nil)
+	s.Require().NoError(err)
+	req.Header.Set("X-Request-Id", "trace-0001")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+	s.Require().Equal("application/problem+json", resp.Header.Get("Content-Type"))
+	s.Require().Equal("trace-0001", resp.Header.Get("X-Request-Id"))
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal("/problems/not-found", problem.Type)
+	s.Require().Equal(http.StatusNotFound, problem.Status)
+	s.Require().Equal(path, problem.Instance)
+	s.Require().Equal("trace-0001", problem.RequestId)
+	s.Require().NotEmpty(problem.Title)
+	s.Require().NotEmpty(problem.Detail)
+
+	resp, err = sendJSON(http.MethodPost, url, dto.ClientRequest{Name: "Adrianna"})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Require().NotEmpty(resp.Header.Get("X-Request-Id"))
+
+	problem = domain.Error{}
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal("/problems/validation", problem.Type)
+	s.Require().Equal(resp.Header.Get("X-Request-Id"), problem.RequestId)
+
+	fields := make(map[string]string)
+	for _, field := range problem.Errors {
+		fields[field.Field] = field.Code
+	}
+
+	s.Require().Equal(map[string]string{"surname": "required", "birthday": "required", "gender": "required"}, fields)
+
+	resp, err = http.Get(fmt.Sprintf("http://%s:%s/api/v1/unknown", s.cfg.CrudService.Address, s.cfg.CrudService.Port))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+	s.Require().Equal("application/problem+json", resp.Header.Get("Content-Type"))
+	resp.Body.Close()
+}
+
+func (s *TestSuite) TestEmptyListIsOk() {
+	s.CleanTable()
+
+	for _, entity := range []string{"clients", "suppliers", "products", "images"} {
+		resp, err := http.Get(fmt.Sprintf("http://%s:%s/api/v1/%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, entity))
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode, entity)
+
+		var page []map[string]any
+		s.Require().NoError(decodeJSON(resp, &page))
+		s.Require().NotNil(page, entity)
+		s.Require().Empty(page, entity)
+	}
+}
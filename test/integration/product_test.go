@@ -18,7 +18,7 @@ import (
 func productResponseToRequest(product dto.ProductResponse) dto.ProductRequest {
 	out := dto.ProductRequest{
 		Name:           product.Name,
-		Category:       product.Category,
+		CategoryId:     product.Category.Id,
 		Price:          product.Price,
 		AvailableStock: product.AvailableStock,
 		SupplierId:     product.Supplier.Id,
@@ -34,9 +34,9 @@ func (s *TestSuite) TestCreateProdcut() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -91,7 +91,7 @@ func (s *TestSuite) TestCreateProdcut() {
 	productPostUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	productData := dto.ProductRequest{
 		Name:           "Abiba",
-		Category:       "Cleaner",
+		CategoryId:     s.category("Cleaner"),
 		Price:          120032.23,
 		AvailableStock: 999999,
 		SupplierId:     supplierResp.Id,
@@ -118,7 +118,8 @@ func (s *TestSuite) TestCreateProdcut() {
 
 	s.Require().NotEmpty(productResp.Id)
 	s.Require().Equal(productData.Name, productResp.Name)
-	s.Require().Equal(productData.Category, productResp.Category)
+	s.Require().Equal(productData.CategoryId, productResp.Category.Id)
+	s.Require().Equal("cleaner", productResp.Category.Slug)
 	s.Require().Equal(productData.Price, productResp.Price)
 	s.Require().Equal(productData.AvailableStock, productResp.AvailableStock)
 	s.Require().NotEmpty(productResp.Supplier)
@@ -131,7 +132,7 @@ func (s *TestSuite) TestCreateProdcutWithoutImageIdAndSupplierId() {
 	productPostUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	productData := dto.ProductRequest{
 		Name:           "Abiba",
-		Category:       "Cleaner",
+		CategoryId:     s.category("Cleaner"),
 		Price:          120032.23,
 		AvailableStock: 999999,
 	}
@@ -150,9 +151,9 @@ func (s *TestSuite) TestCreateProdcutWithoutImageId() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -179,7 +180,7 @@ func (s *TestSuite) TestCreateProdcutWithoutImageId() {
 	productPostUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	productData := dto.ProductRequest{
 		Name:           "Abiba",
-		Category:       "Cleaner",
+		CategoryId:     s.category("Cleaner"),
 		Price:          120032.23,
 		AvailableStock: 999999,
 		SupplierId:     supplierResp.Id,
@@ -191,7 +192,22 @@ func (s *TestSuite) TestCreateProdcutWithoutImageId() {
 	productPostResp, err := http.Post(productPostUrl, "application/json", bytes.NewReader(productBuf))
 	s.Require().NoError(err)
 
-	s.Require().Equal(http.StatusBadRequest, productPostResp.StatusCode)
+	s.Require().Equal(http.StatusCreated, productPostResp.StatusCode)
+
+	rawProductResp, err := io.ReadAll(productPostResp.Body)
+	defer productPostResp.Body.Close()
+	s.Require().NoError(err)
+
+	var productResp dto.ProductResponse
+
+	err = json.Unmarshal(rawProductResp, &productResp)
+	s.Require().NoError(err)
+
+	s.Require().NotEmpty(productResp.Id)
+	s.Require().Equal(uuid.Nil, productResp.Image.Id)
+	s.Require().Equal("placeholder", productResp.Image.Title)
+	s.Require().NotEmpty(productResp.Image.Image)
+	s.Require().Empty(productResp.Gallery)
 }
 
 func (s *TestSuite) TestCreateProductWithoutSupplierId() {
@@ -228,7 +244,7 @@ func (s *TestSuite) TestCreateProductWithoutSupplierId() {
 	productPostUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	productData := dto.ProductRequest{
 		Name:           "Abiba",
-		Category:       "Cleaner",
+		CategoryId:     s.category("Cleaner"),
 		Price:          120032.23,
 		AvailableStock: 999999,
 		ImageId:        imageResp.Id,
@@ -263,9 +279,9 @@ func (s *TestSuite) TestGetAllProduct() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -323,7 +339,7 @@ func (s *TestSuite) TestGetAllProduct() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -371,9 +387,9 @@ func (s *TestSuite) TestGetAllProductWithLimitAndOffset() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -431,7 +447,7 @@ func (s *TestSuite) TestGetAllProductWithLimitAndOffset() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -478,9 +494,9 @@ func (s *TestSuite) TestGetProdcutById() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -538,7 +554,7 @@ func (s *TestSuite) TestGetProdcutById() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -592,9 +608,9 @@ func (s *TestSuite) TestGetProdcutByIdInvalidID() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -652,7 +668,7 @@ func (s *TestSuite) TestGetProdcutByIdInvalidID() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -681,9 +697,9 @@ func (s *TestSuite) TestGetProdcutByIdNotContained() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -741,7 +757,7 @@ func (s *TestSuite) TestGetProdcutByIdNotContained() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -770,9 +786,9 @@ func (s *TestSuite) TestUpdateProductStock() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -830,7 +846,7 @@ func (s *TestSuite) TestUpdateProductStock() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -869,11 +885,9 @@ func (s *TestSuite) TestUpdateProductStock() {
 		s.Require().Equal(http.StatusCreated, productPostResp.StatusCode)
 	}
 
-	productPatchUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s?decrease=10", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
+	productStockUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
 
-	patchReq, err := http.NewRequest(http.MethodPatch, productPatchUrl, nil)
-	s.Require().NoError(err)
-	patchResp, err := client.Do(patchReq)
+	patchResp, err := sendJSON(http.MethodPost, productStockUrl, dto.ProductStockRequest{Quantity: 10, Reason: "sale"})
 	s.Require().NoError(err)
 
 	s.Require().Equal(http.StatusOK, patchResp.StatusCode)
@@ -898,9 +912,9 @@ func (s *TestSuite) TestUpdateProductStockInvalidValue() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -958,7 +972,7 @@ func (s *TestSuite) TestUpdateProductStockInvalidValue() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -997,11 +1011,9 @@ func (s *TestSuite) TestUpdateProductStockInvalidValue() {
 		s.Require().Equal(http.StatusCreated, productPostResp.StatusCode)
 	}
 
-	productPatchUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s?decrease=-10", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
+	productStockUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
 
-	patchReq, err := http.NewRequest(http.MethodPatch, productPatchUrl, nil)
-	s.Require().NoError(err)
-	patchResp, err := client.Do(patchReq)
+	patchResp, err := sendJSON(http.MethodPost, productStockUrl, dto.ProductStockRequest{Quantity: -10, Reason: "sale"})
 	s.Require().NoError(err)
 
 	s.Require().Equal(http.StatusBadRequest, patchResp.StatusCode)
@@ -1013,9 +1025,9 @@ func (s *TestSuite) TestUpdateProductStockDecreaseGreaterThanAvailableStock() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -1073,7 +1085,7 @@ func (s *TestSuite) TestUpdateProductStockDecreaseGreaterThanAvailableStock() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -1112,14 +1124,12 @@ func (s *TestSuite) TestUpdateProductStockDecreaseGreaterThanAvailableStock() {
 		s.Require().Equal(http.StatusCreated, productPostResp.StatusCode)
 	}
 
-	productPatchUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s?decrease=10000000", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
+	productStockUrl := fmt.Sprintf("http://%s:%s/api/v1/products/%s/stock/decrease", s.cfg.CrudService.Address, s.cfg.CrudService.Port, neededId.String())
 
-	patchReq, err := http.NewRequest(http.MethodPatch, productPatchUrl, nil)
-	s.Require().NoError(err)
-	patchResp, err := client.Do(patchReq)
+	patchResp, err := sendJSON(http.MethodPost, productStockUrl, dto.ProductStockRequest{Quantity: 10000000, Reason: "sale"})
 	s.Require().NoError(err)
 
-	s.Require().Equal(http.StatusBadRequest, patchResp.StatusCode)
+	s.Require().Equal(http.StatusConflict, patchResp.StatusCode)
 }
 
 func (s *TestSuite) TestDeleteProduct() {
@@ -1128,9 +1138,9 @@ func (s *TestSuite) TestDeleteProduct() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -1188,7 +1198,7 @@ func (s *TestSuite) TestDeleteProduct() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -1302,9 +1312,9 @@ func (s *TestSuite) TestDeleteProductNoProduct() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -1362,7 +1372,7 @@ func (s *TestSuite) TestDeleteProductNoProduct() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -1461,9 +1471,9 @@ func (s *TestSuite) TestDeleteProductInvalidId() {
 	supplierPostUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	supplierData := dto.SupplierRequest{
 		Name:        "Narin Inc.",
-		PhoneNumber: "66-77-77-13-13",
+		PhoneNumber: "+76677771313",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Dongdaemun",
 		},
@@ -1521,7 +1531,7 @@ func (s *TestSuite) TestDeleteProductInvalidId() {
 	for i := 0; i < 15; i++ {
 		products[i] = dto.ProductRequest{
 			Name:           fmt.Sprintf("Abiba %02d", i+1),
-			Category:       "Cleaner",
+			CategoryId:     s.category("Cleaner"),
 			Price:          120032.23,
 			AvailableStock: 999999,
 			SupplierId:     supplierResp.Id,
@@ -1613,3 +1623,181 @@ func (s *TestSuite) TestDeleteProductInvalidId() {
 
 	s.Require().Len(productCheck, 15)
 }
+
+func (s *TestSuite) TestProductGallery() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	supplierResp, err := sendJSON(http.MethodPost, baseUrl+"/suppliers", dto.SupplierRequest{
+		Name:        "Narin Inc.",
+		PhoneNumber: "+76677771313",
+		Address: &dto.Address{
+			Country: "KR",
+			City:    "Seoul",
+			Street:  "Dongdaemun",
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, supplierResp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(supplierResp, &supplier))
+
+	var images []dto.ImageResponse
+
+	for _, path := range []string{"../data/bear.png", "../data/cat.png", "../data/miku.jpg"} {
+		imageResp, err := uploadImage(baseUrl+"/images", path)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusCreated, imageResp.StatusCode)
+
+		var image dto.ImageResponse
+		s.Require().NoError(decodeJSON(imageResp, &image))
+		images = append(images, image)
+	}
+
+	productResp, err := sendJSON(http.MethodPost, baseUrl+"/products", dto.ProductRequest{
+		Name:           "Abiba",
+		CategoryId:     s.category("Cleaner"),
+		Price:          120032.23,
+		AvailableStock: 10,
+		SupplierId:     supplier.Id,
+		ImageId:        images[0].Id,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, productResp.StatusCode)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(productResp, &product))
+	s.Require().Equal(images[0].Id, product.Image.Id)
+
+	galleryUrl := fmt.Sprintf("%s/products/%s/images", baseUrl, product.Id)
+
+	attachResp, err := sendJSON(http.MethodPost, galleryUrl, dto.ProductImageRequest{ImageId: images[1].Id})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, attachResp.StatusCode)
+	attachResp.Body.Close()
+
+	attachResp, err = sendJSON(http.MethodPost, galleryUrl, dto.ProductImageRequest{ImageId: images[2].Id, IsPrimary: true})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, attachResp.StatusCode)
+	attachResp.Body.Close()
+
+	duplicateResp, err := sendJSON(http.MethodPost, galleryUrl, dto.ProductImageRequest{ImageId: images[2].Id})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusConflict, duplicateResp.StatusCode)
+	duplicateResp.Body.Close()
+
+	getResp, err := http.Get(fmt.Sprintf("%s/products/%s", baseUrl, product.Id))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, getResp.StatusCode)
+	s.Require().NoError(decodeJSON(getResp, &product))
+
+	s.Require().Len(product.Gallery, 3)
+	s.Require().Equal(images[2].Id, product.Image.Id)
+	s.Require().Equal(images[0].Id, product.Gallery[0].Id)
+	s.Require().Equal(images[2].Id, product.Gallery[2].Id)
+
+	order := dto.ProductImageOrderRequest{ImageIds: []uuid.UUID{images[2].Id, images[1].Id, images[0].Id}}
+	reorderResp, err := sendJSON(http.MethodPut, galleryUrl, order)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, reorderResp.StatusCode)
+	reorderResp.Body.Close()
+
+	badOrder := dto.ProductImageOrderRequest{ImageIds: []uuid.UUID{images[2].Id, images[1].Id}}
+	reorderResp, err = sendJSON(http.MethodPut, galleryUrl, badOrder)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusBadRequest, reorderResp.StatusCode)
+	reorderResp.Body.Close()
+
+	detachResp, err := sendJSON(http.MethodDelete, fmt.Sprintf("%s/%s", galleryUrl, images[2].Id), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusNoContent, detachResp.StatusCode)
+	detachResp.Body.Close()
+
+	galleryResp, err := http.Get(galleryUrl)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, galleryResp.StatusCode)
+
+	var gallery []dto.ProductImageResponse
+	s.Require().NoError(decodeJSON(galleryResp, &gallery))
+
+	s.Require().Len(gallery, 2)
+	s.Require().Equal(images[1].Id, gallery[0].Id)
+	s.Require().True(gallery[0].IsPrimary)
+	s.Require().False(gallery[1].IsPrimary)
+}
+
+func (s *TestSuite) TestProductGalleryAttachAtPosition() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	supplierResp, err := sendJSON(http.MethodPost, baseUrl+"/suppliers", dto.SupplierRequest{
+		Name:        "Narin Inc.",
+		PhoneNumber: "+76677771313",
+		Address: &dto.Address{
+			Country: "KR",
+			City:    "Seoul",
+			Street:  "Dongdaemun",
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, supplierResp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(supplierResp, &supplier))
+
+	var images []dto.ImageResponse
+
+	for _, path := range []string{"../data/bear.png", "../data/cat.png", "../data/miku.jpg"} {
+		imageResp, err := uploadImage(baseUrl+"/images", path)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusCreated, imageResp.StatusCode)
+
+		var image dto.ImageResponse
+		s.Require().NoError(decodeJSON(imageResp, &image))
+		images = append(images, image)
+	}
+
+	productResp, err := sendJSON(http.MethodPost, baseUrl+"/products", dto.ProductRequest{
+		Name:           "Abiba",
+		CategoryId:     s.category("Cleaner"),
+		Price:          120032.23,
+		AvailableStock: 10,
+		SupplierId:     supplier.Id,
+		ImageId:        images[0].Id,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, productResp.StatusCode)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(productResp, &product))
+
+	galleryUrl := fmt.Sprintf("%s/products/%s/images", baseUrl, product.Id)
+
+	attachResp, err := sendJSON(http.MethodPost, galleryUrl, dto.ProductImageRequest{ImageId: images[1].Id})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, attachResp.StatusCode)
+	attachResp.Body.Close()
+
+	first := 0
+	attachResp, err = sendJSON(http.MethodPost, galleryUrl, dto.ProductImageRequest{ImageId: images[2].Id, Position: &first})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, attachResp.StatusCode)
+	attachResp.Body.Close()
+
+	galleryResp, err := http.Get(galleryUrl)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, galleryResp.StatusCode)
+
+	var gallery []dto.ProductImageResponse
+	s.Require().NoError(decodeJSON(galleryResp, &gallery))
+
+	s.Require().Len(gallery, 3)
+
+	for i, id := range []uuid.UUID{images[2].Id, images[0].Id, images[1].Id} {
+		s.Require().Equal(id, gallery[i].Id)
+		s.Require().Equal(i, gallery[i].Position)
+	}
+
+	s.Require().True(gallery[1].IsPrimary)
+}
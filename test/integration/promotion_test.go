@@ -0,0 +1,306 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// promotions are a client and 10 kettles at 50 and 10 toasters at 40 in the
+// kitchen. The kitchen sale takes 10% off, the KETTLE-8 coupon takes 8 off
+// every kettle from 2 kettles once per client.
+type promotions struct {
+	baseUrl  string
+	client   uuid.UUID
+	supplier uuid.UUID
+	kettle   uuid.UUID
+	toaster  uuid.UUID
+	sale     dto.PromotionResponse
+	coupon   dto.PromotionResponse
+}
+
+func (s *TestSuite) createPromotion(request dto.PromotionRequest) dto.PromotionResponse {
+	var promotion dto.PromotionResponse
+	s.create("/promotions", request, &promotion)
+	return promotion
+}
+
+func (s *TestSuite) promotions() promotions {
+	output := promotions{baseUrl: fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)}
+
+	output.client, output.supplier = s.createClient("Gopher", "Saver", "female"), s.createSupplier()
+
+	kitchen := s.category("Kitchen")
+	products := s.createProducts(output.supplier,
+		dto.ProductRequest{Name: "Kettle", CategoryId: kitchen, Price: 50, AvailableStock: 10},
+		dto.ProductRequest{Name: "Toaster", CategoryId: kitchen, Price: 40, AvailableStock: 10},
+	)
+
+	output.kettle, output.toaster = products["Kettle"], products["Toaster"]
+
+	limit := int64(1)
+	output.sale = s.createPromotion(dto.PromotionRequest{
+		Name: "Kitchen sale", Kind: "percentage", Value: 10, CategoryId: &kitchen,
+	})
+	output.coupon = s.createPromotion(dto.PromotionRequest{
+		Name: "Kettle deal", Kind: "fixed", Value: 8, ProductId: &output.kettle, MinQuantity: 2,
+		PerClientLimit: &limit, CouponCode: "kettle-8",
+	})
+
+	return output
+}
+
+func (s *TestSuite) evaluatePromotions(baseUrl string, request dto.PromotionEvaluationRequest) dto.PromotionEvaluationResponse {
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/promotions/evaluate", request)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var evaluation dto.PromotionEvaluationResponse
+	s.Require().NoError(decodeJSON(resp, &evaluation))
+	return evaluation
+}
+
+// promotionReasons returns the reasons of the evaluation notes.
+func promotionReasons(evaluation dto.PromotionEvaluationResponse) map[string]bool {
+	output := make(map[string]bool)
+	for _, note := range evaluation.Notes {
+		output[note.Reason] = true
+	}
+
+	return output
+}
+
+// checkoutCoupons fills a new cart of the client and checks it out with the
+// coupons.
+func (s *TestSuite) checkoutCoupons(promotions promotions, quantities map[uuid.UUID]int64, coupons []string) *http.Response {
+	cartUrl := fmt.Sprintf("%s/carts/%s", promotions.baseUrl, s.createCart(promotions.baseUrl, &promotions.client).Id)
+	s.fillCart(cartUrl, quantities)
+
+	resp, err := sendJSON(http.MethodPost, cartUrl+"/checkout", dto.CheckoutRequest{Coupons: coupons})
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *TestSuite) TestPromotionCreate() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	s.Require().True(promotions.sale.Active)
+	s.Require().Equal(int64(1), promotions.sale.MinQuantity)
+	s.Require().Equal("KETTLE-8", promotions.coupon.CouponCode)
+}
+
+func (s *TestSuite) TestPromotionCreateInvalid() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	for name, invalid := range map[string]dto.PromotionRequest{
+		"percentage over 100": {Name: "Too much", Kind: "percentage", Value: 150},
+		"two scopes":          {Name: "Both", Kind: "fixed", Value: 1, ProductId: &promotions.kettle, SupplierId: &promotions.supplier},
+		"bad coupon":          {Name: "Spaces", Kind: "fixed", Value: 1, CouponCode: "NO SPACES"},
+	} {
+		resp, err := sendJSON(http.MethodPost, promotions.baseUrl+"/promotions", invalid)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode, name)
+	}
+}
+
+func (s *TestSuite) TestPromotionCouponConflict() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	// coupon codes are compared in upper case
+	resp, err := sendJSON(http.MethodPost, promotions.baseUrl+"/promotions", dto.PromotionRequest{
+		Name: "Copy", Kind: "fixed", Value: 1, CouponCode: "Kettle-8",
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *TestSuite) TestPromotionEvaluate() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	// the coupon beats the sale on kettles, toasters get the sale
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		ClientId: &promotions.client,
+		Items:    []dto.PromotionItemRequest{{ProductId: promotions.kettle, Quantity: 2}, {ProductId: promotions.toaster, Quantity: 1}},
+		Coupons:  []string{"kettle-8", "NOPE"},
+	})
+	s.Require().Len(evaluation.Items, 2)
+	s.Require().Equal(140.0, evaluation.Subtotal)
+	s.Require().Equal(20.0, evaluation.Discount)
+	s.Require().Equal(120.0, evaluation.Total)
+	s.Require().Equal(promotions.coupon.Id, evaluation.Items[0].Promotion.Id)
+	s.Require().Equal(16.0, evaluation.Items[0].Discount)
+	s.Require().NotEmpty(evaluation.Items[0].Promotion.Explanation)
+	s.Require().Equal(promotions.sale.Id, evaluation.Items[1].Promotion.Id)
+	s.Require().Equal(4.0, evaluation.Items[1].Discount)
+	s.Require().Equal(map[string]bool{domain.PromotionReasonUnknownCoupon: true, domain.PromotionReasonBetterDiscount: true}, promotionReasons(evaluation))
+}
+
+func (s *TestSuite) TestPromotionMinQuantity() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		ClientId: &promotions.client,
+		Items:    []dto.PromotionItemRequest{{ProductId: promotions.kettle, Quantity: 1}},
+		Coupons:  []string{"KETTLE-8"},
+	})
+	s.Require().Equal(promotions.sale.Id, evaluation.Items[0].Promotion.Id)
+	s.Require().Equal(5.0, evaluation.Discount)
+	s.Require().True(promotionReasons(evaluation)[domain.PromotionReasonMinQuantity])
+}
+
+func (s *TestSuite) TestPromotionClientRequired() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	// the coupon is limited per client
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		Items:   []dto.PromotionItemRequest{{ProductId: promotions.kettle, Quantity: 2}},
+		Coupons: []string{"KETTLE-8"},
+	})
+	s.Require().True(promotionReasons(evaluation)[domain.PromotionReasonClientRequired])
+	s.Require().Equal(promotions.sale.Id, evaluation.Items[0].Promotion.Id)
+}
+
+func (s *TestSuite) TestPromotionCouponEnded() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	_, err := s.db.Exec(context.Background(), `UPDATE promotion SET ends_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, promotions.coupon.Id)
+	s.Require().NoError(err)
+
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		ClientId: &promotions.client,
+		Items:    []dto.PromotionItemRequest{{ProductId: promotions.kettle, Quantity: 2}},
+		Coupons:  []string{"KETTLE-8"},
+	})
+	s.Require().True(promotionReasons(evaluation)[domain.PromotionReasonEnded])
+	s.Require().Equal(promotions.sale.Id, evaluation.Items[0].Promotion.Id)
+
+	resp := s.checkoutCoupons(promotions, map[uuid.UUID]int64{promotions.kettle: 2}, []string{"KETTLE-8"})
+	s.cartProblem(resp, "/problems/coupon-not-applicable")
+}
+
+func (s *TestSuite) TestPromotionCheckout() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	resp := s.checkoutCoupons(promotions, map[uuid.UUID]int64{promotions.kettle: 2, promotions.toaster: 1}, []string{"kettle-8"})
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var order dto.OrderResponse
+	s.Require().NoError(decodeJSON(resp, &order))
+	s.Require().Equal(140.0, order.Subtotal)
+	s.Require().Equal(20.0, order.Discount)
+	s.Require().Equal(120.0, order.Total)
+
+	for _, item := range order.Items {
+		if *item.ProductId == promotions.kettle {
+			s.Require().Equal(&promotions.coupon.Id, item.PromotionId)
+		}
+	}
+}
+
+func (s *TestSuite) TestPromotionClientLimit() {
+	s.CleanTable()
+	promotions := s.promotions()
+
+	resp := s.checkoutCoupons(promotions, map[uuid.UUID]int64{promotions.kettle: 2}, []string{"KETTLE-8"})
+	resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	// the client has used the coupon once
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		ClientId: &promotions.client,
+		Items:    []dto.PromotionItemRequest{{ProductId: promotions.kettle, Quantity: 2}},
+		Coupons:  []string{"KETTLE-8"},
+	})
+	s.Require().True(promotionReasons(evaluation)[domain.PromotionReasonClientLimit])
+	s.Require().Equal(promotions.sale.Id, evaluation.Items[0].Promotion.Id)
+
+	resp = s.checkoutCoupons(promotions, map[uuid.UUID]int64{promotions.kettle: 2}, []string{"KETTLE-8"})
+	s.cartProblem(resp, "/problems/coupon-not-applicable")
+}
+
+func (s *TestSuite) TestPromotionUpdate() {
+	s.CleanTable()
+	promotions := s.promotions()
+	saleUrl := fmt.Sprintf("%s/promotions/%s", promotions.baseUrl, promotions.sale.Id)
+
+	resp, err := http.Get(saleUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+
+	inactive := false
+	resp, err = sendJSONWithHeader(http.MethodPatch, saleUrl, "If-Match", etag, dto.PromotionUpdateRequest{Active: &inactive})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var sale dto.PromotionResponse
+	s.Require().NoError(decodeJSON(resp, &sale))
+	s.Require().False(sale.Active)
+
+	evaluation := s.evaluatePromotions(promotions.baseUrl, dto.PromotionEvaluationRequest{
+		Items: []dto.PromotionItemRequest{{ProductId: promotions.toaster, Quantity: 1}},
+	})
+	s.Require().Nil(evaluation.Items[0].Promotion)
+	s.Require().Equal(40.0, evaluation.Total)
+}
+
+func (s *TestSuite) TestPromotionUpdateStale() {
+	s.CleanTable()
+	promotions := s.promotions()
+	saleUrl := fmt.Sprintf("%s/promotions/%s", promotions.baseUrl, promotions.sale.Id)
+
+	resp, err := http.Get(saleUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+
+	name := "Kitchen week"
+	resp, err = sendJSONWithHeader(http.MethodPatch, saleUrl, "If-Match", etag, dto.PromotionUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// the etag is taken before the rename
+	inactive := false
+	resp, err = sendJSONWithHeader(http.MethodPatch, saleUrl, "If-Match", etag, dto.PromotionUpdateRequest{Active: &inactive})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp, err = http.Get(saleUrl)
+	s.Require().NoError(err)
+
+	var sale dto.PromotionResponse
+	s.Require().NoError(decodeJSON(resp, &sale))
+	s.Require().True(sale.Active)
+}
+
+func (s *TestSuite) TestPromotionDelete() {
+	s.CleanTable()
+	promotions := s.promotions()
+	saleUrl := fmt.Sprintf("%s/promotions/%s", promotions.baseUrl, promotions.sale.Id)
+
+	resp, err := sendJSON(http.MethodDelete, saleUrl, nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(saleUrl)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
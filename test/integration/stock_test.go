@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// stockProduct creates a fridge with 10 items in stock and two suppliers, it
+// returns the product url and the supplier the fridge is not supplied by.
+func (s *TestSuite) stockProduct() (string, uuid.UUID) {
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	output := s.sendBatch(dto.BatchRequest{
+		Operations: []dto.BatchOperation{
+			batchOperation("sup", "create", "suppliers", "", dto.SupplierRequest{
+				Name:        "Aboba Inc.",
+				PhoneNumber: "+78005553535",
+				Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+			}),
+			batchOperation("other", "create", "suppliers", "", dto.SupplierRequest{
+				Name:        "Sus Ltd.",
+				PhoneNumber: "+78005553536",
+				Address:     &dto.Address{Country: "KR", City: "Seoul", Street: "Gangnam"},
+			}),
+			batchOperation("fridge", "create", "products", "", map[string]any{
+				"name":            "Fridge",
+				"category_id":     s.category("kitchen"),
+				"price":           499.9,
+				"available_stock": 10,
+				"supplier_id":     "$sup",
+			}),
+		},
+	}, http.StatusOK)
+
+	for _, result := range output.Results {
+		s.Require().Equal(http.StatusCreated, result.Status, result.Ref)
+	}
+
+	return fmt.Sprintf("%s/products/%s", baseUrl, *output.Results[2].Id), *output.Results[1].Id
+}
+
+// changeStock sends the stock change and checks the response status.
+func (s *TestSuite) changeStock(productUrl, action string, quantity int64, reason string, status int) *dto.StockMovementResponse {
+	resp, err := sendJSON(http.MethodPost, productUrl+"/stock/"+action, dto.ProductStockRequest{
+		Quantity: quantity,
+		Reason:   reason,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(status, resp.StatusCode, action)
+
+	if status != http.StatusOK {
+		resp.Body.Close()
+		return nil
+	}
+
+	var movement dto.StockMovementResponse
+	s.Require().NoError(decodeJSON(resp, &movement))
+	s.Require().Equal(reason, movement.Reason)
+	return &movement
+}
+
+func (s *TestSuite) stockMovements(productUrl string) []dto.StockMovementResponse {
+	resp, err := http.Get(productUrl + "/stock/movements")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var movements []dto.StockMovementResponse
+	s.Require().NoError(decodeJSON(resp, &movements))
+	return movements
+}
+
+func (s *TestSuite) TestProductMergePatch() {
+	s.CleanTable()
+	productUrl, otherId := s.stockProduct()
+
+	resp, err := sendJSONWithHeader(http.MethodPatch, productUrl, "Content-Type", "application/merge-patch+json", map[string]any{
+		"name":        "Fridge XL",
+		"price":       599.9,
+		"supplier_id": otherId,
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(productUrl)
+	s.Require().NoError(err)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &product))
+	s.Require().Equal("Fridge XL", product.Name)
+	s.Require().Equal("kitchen", product.Category.Slug)
+	s.Require().InDelta(599.9, product.Price, 0.01)
+	s.Require().Equal(otherId, product.Supplier.Id)
+	s.Require().EqualValues(10, product.AvailableStock)
+}
+
+func (s *TestSuite) TestProductPatchUnknownSupplier() {
+	s.CleanTable()
+	productUrl, _ := s.stockProduct()
+
+	resp, err := sendJSON(http.MethodPatch, productUrl, map[string]any{"supplier_id": "00000000-0000-0000-0000-000000000001"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *TestSuite) TestProductStockChanges() {
+	s.CleanTable()
+	productUrl, _ := s.stockProduct()
+
+	steps := []struct {
+		action   string
+		quantity int64
+		reason   string
+		after    int64
+	}{
+		{"decrease", 3, domain.StockReasonSale, 7},
+		{"increase", 5, domain.StockReasonRestock, 12},
+		{"set", 4, domain.StockReasonCorrection, 4},
+	}
+
+	for _, step := range steps {
+		movement := s.changeStock(productUrl, step.action, step.quantity, step.reason, http.StatusOK)
+		s.Require().Equal(step.after, movement.StockAfter, step.action)
+	}
+
+	movements := s.stockMovements(productUrl)
+	s.Require().Len(movements, 3)
+	s.Require().Equal("set", movements[0].Operation)
+	s.Require().EqualValues(12, movements[0].StockBefore)
+	s.Require().EqualValues(4, movements[0].StockAfter)
+}
+
+func (s *TestSuite) TestProductStockOversell() {
+	s.CleanTable()
+	productUrl, _ := s.stockProduct()
+
+	s.changeStock(productUrl, "decrease", 11, domain.StockReasonSale, http.StatusConflict)
+	s.Require().Empty(s.stockMovements(productUrl))
+
+	movement := s.changeStock(productUrl, "decrease", 10, domain.StockReasonSale, http.StatusOK)
+	s.Require().Zero(movement.StockAfter)
+}
+
+func (s *TestSuite) TestProductStockInvalid() {
+	s.CleanTable()
+	productUrl, _ := s.stockProduct()
+
+	s.changeStock(productUrl, "increase", 0, domain.StockReasonReturn, http.StatusBadRequest)
+	s.changeStock(productUrl, "set", 1, "mood", http.StatusBadRequest)
+	s.Require().Empty(s.stockMovements(productUrl))
+}
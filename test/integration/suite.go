@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/suite"
 )
@@ -44,7 +45,7 @@ func (s *TestSuite) TearDownTest() {
 }
 
 func (s *TestSuite) CleanTable() {
-	tables := []string{"client", "product", "supplier", "image", "address"}
+	tables := []string{"client", "product", "category", "supplier", "warehouse", "image", "cart", "client_order", "promotion", "address", "job", "idempotency_key"}
 
 	for _, table := range tables {
 		query := fmt.Sprintf(`TRUNCATE TABLE %s CASCADE `, table)
@@ -52,3 +53,15 @@ func (s *TestSuite) CleanTable() {
 		s.Require().NoError(err)
 	}
 }
+
+// category returns the id of the top level category with the name, the
+// category is created when it does not exist.
+func (s *TestSuite) category(name string) uuid.UUID {
+	var id uuid.UUID
+	err := s.db.QueryRow(context.Background(), `INSERT INTO category(name, slug) VALUES ($1, lower($1))
+		ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id`, name).Scan(&id)
+	s.Require().NoError(err)
+
+	return id
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/google/uuid"
@@ -17,8 +18,12 @@ import (
 
 func supplierResponseToRequest(supplier dto.SupplierResponse) dto.SupplierRequest {
 	out := dto.SupplierRequest{
-		Name:        supplier.Name,
-		PhoneNumber: supplier.PhoneNumber,
+		Name:          supplier.Name,
+		PhoneNumber:   supplier.PhoneNumber,
+		ContactPerson: supplier.ContactPerson,
+		Email:         supplier.Email,
+		Website:       supplier.Website,
+		TaxId:         supplier.TaxId,
 	}
 
 	if supplier.Address != nil {
@@ -32,9 +37,9 @@ func (s *TestSuite) TestCreateSupplier() {
 	s.CleanTable()
 	givedData := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -75,7 +80,7 @@ func (s *TestSuite) TestCreateSupplierWithoutAddress() {
 	s.CleanTable()
 	givedData := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 	}
 
 	payload, err := json.Marshal(&givedData)
@@ -91,9 +96,11 @@ func (s *TestSuite) TestCreateSupplierWithoutAddress() {
 	url = fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	getResp, err := http.Get(url)
 	s.Require().NoError(err)
-	defer getResp.Body.Close()
+	s.Require().Equal(http.StatusOK, getResp.StatusCode)
 
-	s.Require().Equal(http.StatusNotFound, getResp.StatusCode)
+	var suppliers []dto.SupplierResponse
+	s.Require().NoError(decodeJSON(getResp, &suppliers))
+	s.Require().Empty(suppliers)
 }
 
 func (s *TestSuite) TestCreateEmptySupplier() {
@@ -109,18 +116,20 @@ func (s *TestSuite) TestCreateEmptySupplier() {
 
 	getResp, err := http.Get(url)
 	s.Require().NoError(err)
-	defer getResp.Body.Close()
+	s.Require().Equal(http.StatusOK, getResp.StatusCode)
 
-	s.Require().Equal(http.StatusNotFound, getResp.StatusCode)
+	var suppliers []dto.SupplierResponse
+	s.Require().NoError(decodeJSON(getResp, &suppliers))
+	s.Require().Empty(suppliers)
 }
 
 func (s *TestSuite) TestCreateSupplierDuplicate() {
 	s.CleanTable()
 	supplier := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -128,9 +137,9 @@ func (s *TestSuite) TestCreateSupplierDuplicate() {
 
 	duplicate := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -153,7 +162,7 @@ func (s *TestSuite) TestCreateSupplierDuplicate() {
 	))
 
 	s.Require().NoError(err)
-	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
 
 	url = fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	getResp, err := http.Get(url)
@@ -176,9 +185,9 @@ func (s *TestSuite) TestCreateSupplierWithSameAddress() {
 	s.CleanTable()
 	first := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -186,9 +195,9 @@ func (s *TestSuite) TestCreateSupplierWithSameAddress() {
 
 	second := dto.SupplierRequest{
 		Name:        "Aboba Tech Inc.",
-		PhoneNumber: "8-800-532-35-35",
+		PhoneNumber: "+78005323535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -247,9 +256,9 @@ func (s *TestSuite) TestCreateSupplierWithDifferentAddress() {
 	s.CleanTable()
 	first := dto.SupplierRequest{
 		Name:        "Aboba Inc.",
-		PhoneNumber: "8-800-555-35-35",
+		PhoneNumber: "+78005553535",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -257,9 +266,9 @@ func (s *TestSuite) TestCreateSupplierWithDifferentAddress() {
 
 	second := dto.SupplierRequest{
 		Name:        "Aboba Tech Inc.",
-		PhoneNumber: "8-800-532-35-35",
+		PhoneNumber: "+78005323535",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Gangnam",
 		},
@@ -319,180 +328,180 @@ func (s *TestSuite) TestGetAllSupplier() {
 	suppliers := []dto.SupplierRequest{
 		{
 			Name:        "Global Supplies Inc.",
-			PhoneNumber: "+1-202-555-0123",
+			PhoneNumber: "+12025550123",
 			Address: &dto.Address{
-				Country: "USA",
+				Country: "US",
 				City:    "New York",
 				Street:  "5th Avenue, 101",
 			},
 		},
 		{
 			Name:        "Berlin Tech Parts",
-			PhoneNumber: "+49-30-1234567",
+			PhoneNumber: "+49301234567",
 			Address: &dto.Address{
-				Country: "Germany",
+				Country: "DE",
 				City:    "Berlin",
 				Street:  "Alexanderplatz 5",
 			},
 		},
 		{
 			Name:        "Tokyo Machinery Co.",
-			PhoneNumber: "+81-3-1234-5678",
+			PhoneNumber: "+81312345678",
 			Address: &dto.Address{
-				Country: "Japan",
+				Country: "JP",
 				City:    "Tokyo",
 				Street:  "Chiyoda 2-1-1",
 			},
 		},
 		{
 			Name:        "Paris Electronics",
-			PhoneNumber: "+33-1-2345-6789",
+			PhoneNumber: "+33123456789",
 			Address: &dto.Address{
-				Country: "France",
+				Country: "FR",
 				City:    "Paris",
 				Street:  "Rue de Rivoli 77",
 			},
 		},
 		{
 			Name:        "Sydney Auto Parts",
-			PhoneNumber: "+61-2-9876-5432",
+			PhoneNumber: "+61298765432",
 			Address: &dto.Address{
-				Country: "Australia",
+				Country: "AU",
 				City:    "Sydney",
 				Street:  "George St 55",
 			},
 		},
 		{
 			Name:        "London Office Supplies",
-			PhoneNumber: "+44-20-7946-0958",
+			PhoneNumber: "+442079460958",
 			Address: &dto.Address{
-				Country: "UK",
+				Country: "GB",
 				City:    "London",
 				Street:  "Baker Street 221B",
 			},
 		},
 		{
 			Name:        "Moscow Tools Ltd.",
-			PhoneNumber: "+7-495-123-4567",
+			PhoneNumber: "+74951234567",
 			Address: &dto.Address{
-				Country: "Russia",
+				Country: "RU",
 				City:    "Moscow",
 				Street:  "Arbat 12",
 			},
 		},
 		{
 			Name:        "Toronto Packaging",
-			PhoneNumber: "+1-416-555-7890",
+			PhoneNumber: "+14165557890",
 			Address: &dto.Address{
-				Country: "Canada",
+				Country: "CA",
 				City:    "Toronto",
 				Street:  "King St W 300",
 			},
 		},
 		{
 			Name:        "Beijing Textiles",
-			PhoneNumber: "+86-10-1234-5678",
+			PhoneNumber: "+861012345678",
 			Address: &dto.Address{
-				Country: "China",
+				Country: "CN",
 				City:    "Beijing",
 				Street:  "Chang’an Ave 200",
 			},
 		},
 		{
 			Name:        "Delhi Agro Export",
-			PhoneNumber: "+91-11-2345-6789",
+			PhoneNumber: "+911123456789",
 			Address: &dto.Address{
-				Country: "India",
+				Country: "IN",
 				City:    "Delhi",
 				Street:  "Connaught Place 19",
 			},
 		},
 		{
 			Name:        "Rome Steelworks",
-			PhoneNumber: "+39-06-1234-5678",
+			PhoneNumber: "+390612345678",
 			Address: &dto.Address{
-				Country: "Italy",
+				Country: "IT",
 				City:    "Rome",
 				Street:  "Via del Corso 15",
 			},
 		},
 		{
 			Name:        "Madrid Chemicals",
-			PhoneNumber: "+34-91-123-4567",
+			PhoneNumber: "+34911234567",
 			Address: &dto.Address{
-				Country: "Spain",
+				Country: "ES",
 				City:    "Madrid",
 				Street:  "Gran Via 42",
 			},
 		},
 		{
 			Name:        "São Paulo Imports",
-			PhoneNumber: "+55-11-91234-5678",
+			PhoneNumber: "+5511912345678",
 			Address: &dto.Address{
-				Country: "Brazil",
+				Country: "BR",
 				City:    "São Paulo",
 				Street:  "Avenida Paulista 1000",
 			},
 		},
 		{
 			Name:        "Seoul Electronics Hub",
-			PhoneNumber: "+82-2-555-1234",
+			PhoneNumber: "+8225551234",
 			Address: &dto.Address{
-				Country: "South Korea",
+				Country: "KR",
 				City:    "Seoul",
 				Street:  "Gangnam-daero 432",
 			},
 		},
 		{
 			Name:        "Cape Town Minerals",
-			PhoneNumber: "+27-21-123-4567",
+			PhoneNumber: "+27211234567",
 			Address: &dto.Address{
-				Country: "South Africa",
+				Country: "ZA",
 				City:    "Cape Town",
 				Street:  "Long Street 88",
 			},
 		},
 		{
 			Name:        "Amsterdam Bikes Co.",
-			PhoneNumber: "+31-20-123-4567",
+			PhoneNumber: "+31201234567",
 			Address: &dto.Address{
-				Country: "Netherlands",
+				Country: "NL",
 				City:    "Amsterdam",
 				Street:  "Damrak 45",
 			},
 		},
 		{
 			Name:        "Zurich Precision Tools",
-			PhoneNumber: "+41-44-123-4567",
+			PhoneNumber: "+41441234567",
 			Address: &dto.Address{
-				Country: "Switzerland",
+				Country: "CH",
 				City:    "Zurich",
 				Street:  "Bahnhofstrasse 10",
 			},
 		},
 		{
 			Name:        "Vienna Food Logistics",
-			PhoneNumber: "+43-1-2345-678",
+			PhoneNumber: "+4312345678",
 			Address: &dto.Address{
-				Country: "Austria",
+				Country: "AT",
 				City:    "Vienna",
 				Street:  "Mariahilfer Strasse 23",
 			},
 		},
 		{
 			Name:        "Stockholm CleanTech",
-			PhoneNumber: "+46-8-1234-5678",
+			PhoneNumber: "+46812345678",
 			Address: &dto.Address{
-				Country: "Sweden",
+				Country: "SE",
 				City:    "Stockholm",
 				Street:  "Sveavägen 12",
 			},
 		},
 		{
 			Name:        "Helsinki Timber Group",
-			PhoneNumber: "+358-9-1234-567",
+			PhoneNumber: "+35891234567",
 			Address: &dto.Address{
-				Country: "Finland",
+				Country: "FI",
 				City:    "Helsinki",
 				Street:  "Mannerheimintie 10",
 			},
@@ -533,180 +542,180 @@ func (s *TestSuite) TestGetAllSupplierWithLimitAndOffset() {
 	suppliers := []dto.SupplierRequest{
 		{
 			Name:        "Global Supplies Inc.",
-			PhoneNumber: "+1-202-555-0123",
+			PhoneNumber: "+12025550123",
 			Address: &dto.Address{
-				Country: "USA",
+				Country: "US",
 				City:    "New York",
 				Street:  "5th Avenue, 101",
 			},
 		},
 		{
 			Name:        "Berlin Tech Parts",
-			PhoneNumber: "+49-30-1234567",
+			PhoneNumber: "+49301234567",
 			Address: &dto.Address{
-				Country: "Germany",
+				Country: "DE",
 				City:    "Berlin",
 				Street:  "Alexanderplatz 5",
 			},
 		},
 		{
 			Name:        "Tokyo Machinery Co.",
-			PhoneNumber: "+81-3-1234-5678",
+			PhoneNumber: "+81312345678",
 			Address: &dto.Address{
-				Country: "Japan",
+				Country: "JP",
 				City:    "Tokyo",
 				Street:  "Chiyoda 2-1-1",
 			},
 		},
 		{
 			Name:        "Paris Electronics",
-			PhoneNumber: "+33-1-2345-6789",
+			PhoneNumber: "+33123456789",
 			Address: &dto.Address{
-				Country: "France",
+				Country: "FR",
 				City:    "Paris",
 				Street:  "Rue de Rivoli 77",
 			},
 		},
 		{
 			Name:        "Sydney Auto Parts",
-			PhoneNumber: "+61-2-9876-5432",
+			PhoneNumber: "+61298765432",
 			Address: &dto.Address{
-				Country: "Australia",
+				Country: "AU",
 				City:    "Sydney",
 				Street:  "George St 55",
 			},
 		},
 		{
 			Name:        "London Office Supplies",
-			PhoneNumber: "+44-20-7946-0958",
+			PhoneNumber: "+442079460958",
 			Address: &dto.Address{
-				Country: "UK",
+				Country: "GB",
 				City:    "London",
 				Street:  "Baker Street 221B",
 			},
 		},
 		{
 			Name:        "Moscow Tools Ltd.",
-			PhoneNumber: "+7-495-123-4567",
+			PhoneNumber: "+74951234567",
 			Address: &dto.Address{
-				Country: "Russia",
+				Country: "RU",
 				City:    "Moscow",
 				Street:  "Arbat 12",
 			},
 		},
 		{
 			Name:        "Toronto Packaging",
-			PhoneNumber: "+1-416-555-7890",
+			PhoneNumber: "+14165557890",
 			Address: &dto.Address{
-				Country: "Canada",
+				Country: "CA",
 				City:    "Toronto",
 				Street:  "King St W 300",
 			},
 		},
 		{
 			Name:        "Beijing Textiles",
-			PhoneNumber: "+86-10-1234-5678",
+			PhoneNumber: "+861012345678",
 			Address: &dto.Address{
-				Country: "China",
+				Country: "CN",
 				City:    "Beijing",
 				Street:  "Chang’an Ave 200",
 			},
 		},
 		{
 			Name:        "Delhi Agro Export",
-			PhoneNumber: "+91-11-2345-6789",
+			PhoneNumber: "+911123456789",
 			Address: &dto.Address{
-				Country: "India",
+				Country: "IN",
 				City:    "Delhi",
 				Street:  "Connaught Place 19",
 			},
 		},
 		{
 			Name:        "Rome Steelworks",
-			PhoneNumber: "+39-06-1234-5678",
+			PhoneNumber: "+390612345678",
 			Address: &dto.Address{
-				Country: "Italy",
+				Country: "IT",
 				City:    "Rome",
 				Street:  "Via del Corso 15",
 			},
 		},
 		{
 			Name:        "Madrid Chemicals",
-			PhoneNumber: "+34-91-123-4567",
+			PhoneNumber: "+34911234567",
 			Address: &dto.Address{
-				Country: "Spain",
+				Country: "ES",
 				City:    "Madrid",
 				Street:  "Gran Via 42",
 			},
 		},
 		{
 			Name:        "São Paulo Imports",
-			PhoneNumber: "+55-11-91234-5678",
+			PhoneNumber: "+5511912345678",
 			Address: &dto.Address{
-				Country: "Brazil",
+				Country: "BR",
 				City:    "São Paulo",
 				Street:  "Avenida Paulista 1000",
 			},
 		},
 		{
 			Name:        "Seoul Electronics Hub",
-			PhoneNumber: "+82-2-555-1234",
+			PhoneNumber: "+8225551234",
 			Address: &dto.Address{
-				Country: "South Korea",
+				Country: "KR",
 				City:    "Seoul",
 				Street:  "Gangnam-daero 432",
 			},
 		},
 		{
 			Name:        "Cape Town Minerals",
-			PhoneNumber: "+27-21-123-4567",
+			PhoneNumber: "+27211234567",
 			Address: &dto.Address{
-				Country: "South Africa",
+				Country: "ZA",
 				City:    "Cape Town",
 				Street:  "Long Street 88",
 			},
 		},
 		{
 			Name:        "Amsterdam Bikes Co.",
-			PhoneNumber: "+31-20-123-4567",
+			PhoneNumber: "+31201234567",
 			Address: &dto.Address{
-				Country: "Netherlands",
+				Country: "NL",
 				City:    "Amsterdam",
 				Street:  "Damrak 45",
 			},
 		},
 		{
 			Name:        "Zurich Precision Tools",
-			PhoneNumber: "+41-44-123-4567",
+			PhoneNumber: "+41441234567",
 			Address: &dto.Address{
-				Country: "Switzerland",
+				Country: "CH",
 				City:    "Zurich",
 				Street:  "Bahnhofstrasse 10",
 			},
 		},
 		{
 			Name:        "Vienna Food Logistics",
-			PhoneNumber: "+43-1-2345-678",
+			PhoneNumber: "+4312345678",
 			Address: &dto.Address{
-				Country: "Austria",
+				Country: "AT",
 				City:    "Vienna",
 				Street:  "Mariahilfer Strasse 23",
 			},
 		},
 		{
 			Name:        "Stockholm CleanTech",
-			PhoneNumber: "+46-8-1234-5678",
+			PhoneNumber: "+46812345678",
 			Address: &dto.Address{
-				Country: "Sweden",
+				Country: "SE",
 				City:    "Stockholm",
 				Street:  "Sveavägen 12",
 			},
 		},
 		{
 			Name:        "Helsinki Timber Group",
-			PhoneNumber: "+358-9-1234-567",
+			PhoneNumber: "+35891234567",
 			Address: &dto.Address{
-				Country: "Finland",
+				Country: "FI",
 				City:    "Helsinki",
 				Street:  "Mannerheimintie 10",
 			},
@@ -748,117 +757,117 @@ func (s *TestSuite) TestGetByIdSupplier() {
 	suppliers := []dto.SupplierRequest{
 		{
 			Name:        "Global Supplies Inc.",
-			PhoneNumber: "+1-202-555-0123",
+			PhoneNumber: "+12025550123",
 			Address: &dto.Address{
-				Country: "USA",
+				Country: "US",
 				City:    "New York",
 				Street:  "5th Avenue, 101",
 			},
 		},
 		{
 			Name:        "Berlin Tech Parts",
-			PhoneNumber: "+49-30-1234567",
+			PhoneNumber: "+49301234567",
 			Address: &dto.Address{
-				Country: "Germany",
+				Country: "DE",
 				City:    "Berlin",
 				Street:  "Alexanderplatz 5",
 			},
 		},
 		{
 			Name:        "Tokyo Machinery Co.",
-			PhoneNumber: "+81-3-1234-5678",
+			PhoneNumber: "+81312345678",
 			Address: &dto.Address{
-				Country: "Japan",
+				Country: "JP",
 				City:    "Tokyo",
 				Street:  "Chiyoda 2-1-1",
 			},
 		},
 		{
 			Name:        "Paris Electronics",
-			PhoneNumber: "+33-1-2345-6789",
+			PhoneNumber: "+33123456789",
 			Address: &dto.Address{
-				Country: "France",
+				Country: "FR",
 				City:    "Paris",
 				Street:  "Rue de Rivoli 77",
 			},
 		},
 		{
 			Name:        "Sydney Auto Parts",
-			PhoneNumber: "+61-2-9876-5432",
+			PhoneNumber: "+61298765432",
 			Address: &dto.Address{
-				Country: "Australia",
+				Country: "AU",
 				City:    "Sydney",
 				Street:  "George St 55",
 			},
 		},
 		{
 			Name:        "London Office Supplies",
-			PhoneNumber: "+44-20-7946-0958",
+			PhoneNumber: "+442079460958",
 			Address: &dto.Address{
-				Country: "UK",
+				Country: "GB",
 				City:    "London",
 				Street:  "Baker Street 221B",
 			},
 		},
 		{
 			Name:        "Moscow Tools Ltd.",
-			PhoneNumber: "+7-495-123-4567",
+			PhoneNumber: "+74951234567",
 			Address: &dto.Address{
-				Country: "Russia",
+				Country: "RU",
 				City:    "Moscow",
 				Street:  "Arbat 12",
 			},
 		},
 		{
 			Name:        "Toronto Packaging",
-			PhoneNumber: "+1-416-555-7890",
+			PhoneNumber: "+14165557890",
 			Address: &dto.Address{
-				Country: "Canada",
+				Country: "CA",
 				City:    "Toronto",
 				Street:  "King St W 300",
 			},
 		},
 		{
 			Name:        "Beijing Textiles",
-			PhoneNumber: "+86-10-1234-5678",
+			PhoneNumber: "+861012345678",
 			Address: &dto.Address{
-				Country: "China",
+				Country: "CN",
 				City:    "Beijing",
 				Street:  "Chang’an Ave 200",
 			},
 		},
 		{
 			Name:        "Delhi Agro Export",
-			PhoneNumber: "+91-11-2345-6789",
+			PhoneNumber: "+911123456789",
 			Address: &dto.Address{
-				Country: "India",
+				Country: "IN",
 				City:    "Delhi",
 				Street:  "Connaught Place 19",
 			},
 		},
 		{
 			Name:        "Rome Steelworks",
-			PhoneNumber: "+39-06-1234-5678",
+			PhoneNumber: "+390612345678",
 			Address: &dto.Address{
-				Country: "Italy",
+				Country: "IT",
 				City:    "Rome",
 				Street:  "Via del Corso 15",
 			},
 		},
 		{
 			Name:        "Madrid Chemicals",
-			PhoneNumber: "+34-91-123-4567",
+			PhoneNumber: "+34911234567",
 			Address: &dto.Address{
-				Country: "Spain",
+				Country: "ES",
 				City:    "Madrid",
 				Street:  "Gran Via 42",
 			},
 		},
 		{
 			Name:        "São Paulo Imports",
-			PhoneNumber: "+55-11-91234-5678",
+			PhoneNumber: "+5511912345678",
 			Address: &dto.Address{
-				Country: "Brazil",
+				Country: "BR",
 				City:    "São Paulo",
 				Street:  "Avenida Paulista 1000",
 			},
@@ -897,117 +906,117 @@ func (s *TestSuite) TestGetByIdGhostSupplier() {
 	suppliers := []dto.SupplierRequest{
 		{
 			Name:        "Global Supplies Inc.",
-			PhoneNumber: "+1-202-555-0123",
+			PhoneNumber: "+12025550123",
 			Address: &dto.Address{
-				Country: "USA",
+				Country: "US",
 				City:    "New York",
 				Street:  "5th Avenue, 101",
 			},
 		},
 		{
 			Name:        "Berlin Tech Parts",
-			PhoneNumber: "+49-30-1234567",
+			PhoneNumber: "+49301234567",
 			Address: &dto.Address{
-				Country: "Germany",
+				Country: "DE",
 				City:    "Berlin",
 				Street:  "Alexanderplatz 5",
 			},
 		},
 		{
 			Name:        "Tokyo Machinery Co.",
-			PhoneNumber: "+81-3-1234-5678",
+			PhoneNumber: "+81312345678",
 			Address: &dto.Address{
-				Country: "Japan",
+				Country: "JP",
 				City:    "Tokyo",
 				Street:  "Chiyoda 2-1-1",
 			},
 		},
 		{
 			Name:        "Paris Electronics",
-			PhoneNumber: "+33-1-2345-6789",
+			PhoneNumber: "+33123456789",
 			Address: &dto.Address{
-				Country: "France",
+				Country: "FR",
 				City:    "Paris",
 				Street:  "Rue de Rivoli 77",
 			},
 		},
 		{
 			Name:        "Sydney Auto Parts",
-			PhoneNumber: "+61-2-9876-5432",
+			PhoneNumber: "+61298765432",
 			Address: &dto.Address{
-				Country: "Australia",
+				Country: "AU",
 				City:    "Sydney",
 				Street:  "George St 55",
 			},
 		},
 		{
 			Name:        "London Office Supplies",
-			PhoneNumber: "+44-20-7946-0958",
+			PhoneNumber: "+442079460958",
 			Address: &dto.Address{
-				Country: "UK",
+				Country: "GB",
 				City:    "London",
 				Street:  "Baker Street 221B",
 			},
 		},
 		{
 			Name:        "Moscow Tools Ltd.",
-			PhoneNumber: "+7-495-123-4567",
+			PhoneNumber: "+74951234567",
 			Address: &dto.Address{
-				Country: "Russia",
+				Country: "RU",
 				City:    "Moscow",
 				Street:  "Arbat 12",
 			},
 		},
 		{
 			Name:        "Toronto Packaging",
-			PhoneNumber: "+1-416-555-7890",
+			PhoneNumber: "+14165557890",
 			Address: &dto.Address{
-				Country: "Canada",
+				Country: "CA",
 				City:    "Toronto",
 				Street:  "King St W 300",
 			},
 		},
 		{
 			Name:        "Beijing Textiles",
-			PhoneNumber: "+86-10-1234-5678",
+			PhoneNumber: "+861012345678",
 			Address: &dto.Address{
-				Country: "China",
+				Country: "CN",
 				City:    "Beijing",
 				Street:  "Chang’an Ave 200",
 			},
 		},
 		{
 			Name:        "Delhi Agro Export",
-			PhoneNumber: "+91-11-2345-6789",
+			PhoneNumber: "+911123456789",
 			Address: &dto.Address{
-				Country: "India",
+				Country: "IN",
 				City:    "Delhi",
 				Street:  "Connaught Place 19",
 			},
 		},
 		{
 			Name:        "Rome Steelworks",
-			PhoneNumber: "+39-06-1234-5678",
+			PhoneNumber: "+390612345678",
 			Address: &dto.Address{
-				Country: "Italy",
+				Country: "IT",
 				City:    "Rome",
 				Street:  "Via del Corso 15",
 			},
 		},
 		{
 			Name:        "Madrid Chemicals",
-			PhoneNumber: "+34-91-123-4567",
+			PhoneNumber: "+34911234567",
 			Address: &dto.Address{
-				Country: "Spain",
+				Country: "ES",
 				City:    "Madrid",
 				Street:  "Gran Via 42",
 			},
 		},
 		{
 			Name:        "São Paulo Imports",
-			PhoneNumber: "+55-11-91234-5678",
+			PhoneNumber: "+5511912345678",
 			Address: &dto.Address{
-				Country: "Brazil",
+				Country: "BR",
 				City:    "São Paulo",
 				Street:  "Avenida Paulista 1000",
 			},
@@ -1044,9 +1053,9 @@ func (s *TestSuite) TestUpdateAddressSupplier() {
 	s.CleanTable()
 	supplier := dto.SupplierRequest{
 		Name:        "Aboba Tech Inc.",
-		PhoneNumber: "1232913",
+		PhoneNumber: "+71232913",
 		Address: &dto.Address{
-			Country: "Korea",
+			Country: "KR",
 			City:    "Seoul",
 			Street:  "Myeongdong",
 		},
@@ -1067,7 +1076,7 @@ func (s *TestSuite) TestUpdateAddressSupplier() {
 	patchUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers/%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, takedData.Id)
 
 	newAddress := &dto.Address{
-		Country: "China",
+		Country: "CN",
 		City:    "Beijing",
 		Street:  "Wangfujing",
 	}
@@ -1111,9 +1120,9 @@ func (s *TestSuite) TestUpdateAddressSupplierNotLinkedAddressBehavior() {
 	s.CleanTable()
 	first := dto.SupplierRequest{
 		Name:        "Servo Inc.",
-		PhoneNumber: "+7 999 233 13 23",
+		PhoneNumber: "+79992331323",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1123,7 +1132,7 @@ func (s *TestSuite) TestUpdateAddressSupplierNotLinkedAddressBehavior() {
 		Name:        "Servo Tech Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "Japan",
+			Country: "JP",
 			City:    "Tokyo",
 			Street:  "Godzilla",
 		},
@@ -1143,7 +1152,7 @@ func (s *TestSuite) TestUpdateAddressSupplierNotLinkedAddressBehavior() {
 	s.Require().Equal(verifiable.Address, second.Address)
 
 	newAddress := dto.Address{
-		Country: "Russia",
+		Country: "RU",
 		City:    "Moscow",
 		Street:  "Tverskaya Street",
 	}
@@ -1186,9 +1195,9 @@ func (s *TestSuite) TestUpdateAddressSupplierFromSameAddress() {
 	s.CleanTable()
 	first := dto.SupplierRequest{
 		Name:        "Mech Inc.",
-		PhoneNumber: "+7 999 233 13 23",
+		PhoneNumber: "+79992331323",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1198,7 +1207,7 @@ func (s *TestSuite) TestUpdateAddressSupplierFromSameAddress() {
 		Name:        "Mech Tech Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1219,7 +1228,7 @@ func (s *TestSuite) TestUpdateAddressSupplierFromSameAddress() {
 	s.Require().Equal(checkTakedData.Address, second.Address)
 
 	newAddress := dto.Address{
-		Country: "Korea",
+		Country: "KR",
 		City:    "Seoul",
 		Street:  "Gangnam",
 	}
@@ -1262,9 +1271,9 @@ func (s *TestSuite) TestUpdateAddressSupplier2() {
 	s.CleanTable()
 	first := dto.SupplierRequest{
 		Name:        "Terra Inc.",
-		PhoneNumber: "+7 999 233 13 23",
+		PhoneNumber: "+79992331323",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1274,7 +1283,7 @@ func (s *TestSuite) TestUpdateAddressSupplier2() {
 		Name:        "Terra Tech Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "German",
+			Country: "DE",
 			City:    "Berlin",
 			Street:  "Stag",
 		},
@@ -1284,7 +1293,7 @@ func (s *TestSuite) TestUpdateAddressSupplier2() {
 		Name:        "Dominion Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "German",
+			Country: "DE",
 			City:    "Berlin",
 			Street:  "Stag",
 		},
@@ -1308,7 +1317,7 @@ func (s *TestSuite) TestUpdateAddressSupplier2() {
 	s.Require().Equal(third.Address, firstCheckConv.Address)
 
 	newAddress := dto.Address{
-		Country: "Russia",
+		Country: "RU",
 		City:    "Moscow",
 		Street:  "Tverskaya Street",
 	}
@@ -1352,9 +1361,9 @@ func (s *TestSuite) TestDeleteSupplier() {
 
 	first := dto.SupplierRequest{
 		Name:        "Terra Inc.",
-		PhoneNumber: "+7 999 233 13 23",
+		PhoneNumber: "+79992331323",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1364,7 +1373,7 @@ func (s *TestSuite) TestDeleteSupplier() {
 		Name:        "Terra Tech Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "German",
+			Country: "DE",
 			City:    "Berlin",
 			Street:  "Stag",
 		},
@@ -1374,7 +1383,7 @@ func (s *TestSuite) TestDeleteSupplier() {
 		Name:        "Dominion Inc.",
 		PhoneNumber: "+231312312312",
 		Address: &dto.Address{
-			Country: "German",
+			Country: "DE",
 			City:    "Berlin",
 			Street:  "Stag",
 		},
@@ -1468,9 +1477,9 @@ func (s *TestSuite) TestDeleteSupplier2() {
 
 	supplier := dto.SupplierRequest{
 		Name:        "Terra Inc.",
-		PhoneNumber: "+7 999 233 13 23",
+		PhoneNumber: "+79992331323",
 		Address: &dto.Address{
-			Country: "Russia",
+			Country: "RU",
 			City:    "Moscow",
 			Street:  "Tverskaya Street",
 		},
@@ -1496,7 +1505,11 @@ func (s *TestSuite) TestDeleteSupplier2() {
 	getUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
 	resp, err := http.Get(getUrl)
 	s.Require().NoError(err)
-	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var suppliers []dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &suppliers))
+	s.Require().Empty(suppliers)
 
 	query := `SELECT COUNT(*) FROM address`
 	var count int
@@ -1504,3 +1517,346 @@ func (s *TestSuite) TestDeleteSupplier2() {
 	s.Require().NoError(err)
 	s.Require().Equal(0, count)
 }
+
+func (s *TestSuite) TestSupplierLocations() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &supplier))
+
+	locationsUrl := fmt.Sprintf("%s/%s/locations", baseUrl, supplier.Id)
+
+	resp, err = sendJSON(http.MethodGet, locationsUrl, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var locations []dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &locations))
+	s.Require().Len(locations, 1)
+	s.Require().Equal("office", locations[0].Label)
+	s.Require().True(locations[0].IsDefault)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/%s", locationsUrl, locations[0].Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	warehouse := dto.AddressBookEntryRequest{
+		Label:     "warehouse",
+		IsDefault: true,
+		Address: dto.Address{
+			Country: "JP",
+			Region:  "Chiba",
+			City:    "Narita",
+			Street:  "Airport",
+			Line1:   "Gate 3",
+		},
+	}
+
+	resp, err = sendJSON(http.MethodPost, locationsUrl, warehouse)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var added dto.AddressBookEntryResponse
+	s.Require().NoError(decodeJSON(resp, &added))
+	s.Require().True(added.IsDefault)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &supplier))
+	s.Require().Equal(&warehouse.Address, supplier.Address)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/%s", locationsUrl, locations[0].Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, locationsUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &locations))
+	s.Require().Len(locations, 1)
+	s.Require().Equal(added.Id, locations[0].Id)
+
+	resp, err = sendJSON(http.MethodDelete, baseUrl+"/"+supplier.Id.String(), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	var count int
+	err = s.db.QueryRow(context.Background(), `SELECT COUNT(id) FROM address`).Scan(&count)
+	s.Require().NoError(err)
+	s.Require().EqualValues(0, count)
+}
+
+func (s *TestSuite) TestSupplierCatalog() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	productUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &supplier))
+
+	products := []dto.ProductRequest{
+		{Name: "Washer W1", CategoryId: s.category("Washer"), Price: 100, AvailableStock: 3, SupplierId: supplier.Id},
+		{Name: "Washer W2", CategoryId: s.category("Washer"), Price: 300, AvailableStock: 1, SupplierId: supplier.Id},
+		{Name: "Cleaner C1", CategoryId: s.category("Cleaner"), Price: 50, AvailableStock: 10, SupplierId: supplier.Id},
+	}
+
+	for _, product := range products {
+		resp, err = sendJSON(http.MethodPost, productUrl, product)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	}
+
+	_, err = s.db.Exec(context.Background(), `UPDATE product SET available_stock = 0 WHERE name = 'Washer W2'`)
+	s.Require().NoError(err)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/products?category=washer&sort=price&order=desc", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal("2", resp.Header.Get("X-Total-Count"))
+
+	var found []dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &found))
+	s.Require().Len(found, 2)
+	s.Require().Equal("Washer W2", found[0].Name)
+	s.Require().Equal("Washer W1", found[1].Name)
+	s.Require().Equal(supplier.Id, found[0].Supplier.Id)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/products?in_stock=true&price_min=60&limit=1", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().Equal("1", resp.Header.Get("X-Total-Count"))
+	s.Require().NoError(decodeJSON(resp, &found))
+	s.Require().Len(found, 1)
+	s.Require().Equal("Washer W1", found[0].Name)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/products?q=dryer", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NoError(decodeJSON(resp, &found))
+	s.Require().Empty(found)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/products?sort=supplier", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/products", baseUrl, uuid.New()), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/stats", baseUrl, supplier.Id), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var stats dto.SupplierStatsResponse
+	s.Require().NoError(decodeJSON(resp, &stats))
+	s.Require().Equal(3, stats.ProductCount)
+	s.Require().EqualValues(13, stats.TotalStockUnits)
+	s.Require().InDelta(800, stats.TotalStockValue, 0.001)
+	s.Require().Equal(1, stats.OutOfStockCount)
+	s.Require().NotNil(stats.LastDeliveryAt)
+
+	resp, err = sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/stats", baseUrl, uuid.New()), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/search?name="+url.QueryEscape("Aboba Inc."), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var byName dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &byName))
+	s.Require().Equal(supplier.Id, byName.Id)
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/search?name=Ghost", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, baseUrl+"/search", nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TestSuite) TestUpdateSupplierProfile() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+7 (800) 555-35-35",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &supplier))
+	s.Require().Equal("+78005553535", supplier.PhoneNumber)
+
+	resp, err = sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Biba Ltd.",
+		PhoneNumber: "8-800-555-35-35",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Biba Ltd.",
+		PhoneNumber: "0049 30 1234567",
+		Address:     &dto.Address{Country: "DE", City: "Berlin", Street: "Alexanderplatz 5"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var other dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &other))
+	s.Require().Equal("+49301234567", other.PhoneNumber)
+
+	supplierUrl := fmt.Sprintf("%s/%s", baseUrl, supplier.Id)
+	name := "Aboba Group"
+	phone := "+1 202 555 0123"
+	contact := "  Ivan Petrov "
+	email := "Sales@Aboba.example"
+	website := "Aboba.example/catalog"
+	taxId := "7707-083893"
+
+	resp, err = sendJSON(http.MethodPatch, supplierUrl, dto.SupplierUpdateRequest{
+		Name:          &name,
+		PhoneNumber:   &phone,
+		ContactPerson: &contact,
+		Email:         &email,
+		Website:       &website,
+		TaxId:         &taxId,
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, supplierUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &supplier))
+	s.Require().Equal("Aboba Group", supplier.Name)
+	s.Require().Equal("+12025550123", supplier.PhoneNumber)
+	s.Require().Equal("Ivan Petrov", supplier.ContactPerson)
+	s.Require().Equal("sales@aboba.example", supplier.Email)
+	s.Require().Equal("https://aboba.example/catalog", supplier.Website)
+	s.Require().Equal("7707083893", supplier.TaxId)
+	s.Require().Equal("Tokyo", supplier.Address.City)
+
+	duplicate := "Biba Ltd."
+	resp, err = sendJSON(http.MethodPatch, supplierUrl, dto.SupplierUpdateRequest{Name: &duplicate})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	invalid := "555-01-23"
+	resp, err = sendJSON(http.MethodPatch, supplierUrl, dto.SupplierUpdateRequest{PhoneNumber: &invalid})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPatch, supplierUrl, dto.SupplierUpdateRequest{})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPatch, fmt.Sprintf("%s/%s", baseUrl, uuid.New()), dto.SupplierUpdateRequest{Name: &name})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPatch, supplierUrl, dto.SupplierUpdateRequest{
+		Address: &dto.Address{Country: "KR", City: "Seoul", Street: "Myeongdong"},
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodGet, supplierUrl, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(decodeJSON(resp, &supplier))
+	s.Require().Equal("Aboba Group", supplier.Name)
+	s.Require().Equal("Seoul", supplier.Address.City)
+}
+
+func (s *TestSuite) TestSupplierStatsLastDelivery() {
+	s.CleanTable()
+
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1/suppliers", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	productUrl := fmt.Sprintf("http://%s:%s/api/v1/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	resp, err := sendJSON(http.MethodPost, baseUrl, dto.SupplierRequest{
+		Name:        "Aboba Inc.",
+		PhoneNumber: "+78005553535",
+		Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla"},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var supplier dto.SupplierResponse
+	s.Require().NoError(decodeJSON(resp, &supplier))
+
+	resp, err = sendJSON(http.MethodPost, productUrl, dto.ProductRequest{
+		Name: "Washer W1", CategoryId: s.category("Washer"), Price: 100, AvailableStock: 0, SupplierId: supplier.Id,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &product))
+
+	stats := func() dto.SupplierStatsResponse {
+		resp, err := sendJSON(http.MethodGet, fmt.Sprintf("%s/%s/stats", baseUrl, supplier.Id), nil)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var stats dto.SupplierStatsResponse
+		s.Require().NoError(decodeJSON(resp, &stats))
+		return stats
+	}
+
+	s.Require().Nil(stats().LastDeliveryAt)
+
+	increase := func(reason string) {
+		resp, err := sendJSON(http.MethodPost, fmt.Sprintf("%s/%s/stock/increase", productUrl, product.Id), dto.ProductStockRequest{Quantity: 2, Reason: reason})
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+	}
+
+	// a return is not a delivery
+	increase("return")
+	s.Require().Nil(stats().LastDeliveryAt)
+
+	increase("restock")
+	s.Require().NotNil(stats().LastDeliveryAt)
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (s *TestSuite) TestFieldValidation() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	cases := []struct {
+		name    string
+		url     string
+		payload any
+		want    map[string]string
+	}{
+		{
+			name: "client",
+			url:  baseUrl + "/clients",
+			payload: dto.ClientRequest{
+				Name:     strings.Repeat("a", 101),
+				Surname:  "Gopher",
+				Birthday: "2999-01-01",
+				Gender:   "unknown",
+				Email:    "not an email",
+				Phone:    "12",
+				Address:  &dto.Address{Country: "Narnia", City: "Cair Paravel"},
+			},
+			want: map[string]string{
+				"name":     "max",
+				"birthday": "notfuture",
+				"gender":   "oneof",
+				"email":    "email",
+				"phone":    "phone",
+				"country":  "country",
+				"street":   "required",
+			},
+		},
+		{
+			name: "client birthday format",
+			url:  baseUrl + "/clients",
+			payload: dto.ClientRequest{
+				Name:     "Adrianna",
+				Surname:  "Gopher",
+				Birthday: "01.01.2001",
+				Gender:   "female",
+			},
+			want: map[string]string{"birthday": "date"},
+		},
+		{
+			name: "product",
+			url:  baseUrl + "/products",
+			payload: map[string]any{
+				"name":            "Fridge",
+				"category_id":     s.category("Kitchen"),
+				"price":           0,
+				"available_stock": -1,
+				"supplier_id":     uuid.New(),
+			},
+			want: map[string]string{"price": "gt", "available_stock": "gte"},
+		},
+		{
+			name: "supplier",
+			url:  baseUrl + "/suppliers",
+			payload: dto.SupplierRequest{
+				Name:        "Acme",
+				PhoneNumber: "8005553535",
+				Address:     &dto.Address{Country: "JP", City: "Tokyo", Street: "Godzilla", PostalCode: strings.Repeat("1", 17)},
+			},
+			want: map[string]string{"phone_number": "intl_phone", "postal_code": "max"},
+		},
+	}
+
+	for _, tc := range cases {
+		resp, err := sendJSON(http.MethodPost, tc.url, tc.payload)
+		s.Require().NoError(err, tc.name)
+		s.Require().Equal(http.StatusBadRequest, resp.StatusCode, tc.name)
+
+		var problem domain.Error
+		s.Require().NoError(decodeJSON(resp, &problem), tc.name)
+		s.Require().Equal("/problems/validation", problem.Type, tc.name)
+
+		fields := make(map[string]string)
+		for _, field := range problem.Errors {
+			fields[field.Field] = field.Code
+			s.Require().NotEmpty(field.Message, tc.name)
+		}
+
+		s.Require().Equal(tc.want, fields, tc.name)
+	}
+}
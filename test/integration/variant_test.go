@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// washer is a product with two variants: 3 white ones and 2 grey ones.
+type washer struct {
+	supplier uuid.UUID
+	parent   dto.ProductResponse
+	white    dto.ProductResponse
+	grey     dto.ProductResponse
+}
+
+// createVariant posts the variant of the parent and checks the response status.
+func (s *TestSuite) createVariant(parent uuid.UUID, request dto.ProductVariantRequest, status int) dto.ProductResponse {
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("http://%s:%s/api/v1/products/%s/variants",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, parent), request)
+	s.Require().NoError(err)
+	s.Require().Equal(status, resp.StatusCode, request.Sku)
+
+	var variant dto.ProductResponse
+	if status != http.StatusCreated {
+		resp.Body.Close()
+		return variant
+	}
+
+	s.Require().NoError(decodeJSON(resp, &variant))
+	return variant
+}
+
+func (s *TestSuite) getProduct(id uuid.UUID) dto.ProductResponse {
+	resp, err := http.Get(fmt.Sprintf("http://%s:%s/api/v1/products/%s", s.cfg.CrudService.Address, s.cfg.CrudService.Port, id))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &product))
+	return product
+}
+
+func (s *TestSuite) washer() washer {
+	output := washer{supplier: s.createSupplier()}
+	s.create("/products", dto.ProductRequest{
+		Name:           "Washer",
+		CategoryId:     s.category("Laundry"),
+		Price:          400,
+		AvailableStock: 0,
+		SupplierId:     output.supplier,
+	}, &output.parent)
+
+	output.white = s.createVariant(output.parent.Id, dto.ProductVariantRequest{
+		Name: "Washer 8 kg white", Sku: "wm-8-white", Barcode: "4006381333931", Price: 450, AvailableStock: 3,
+	}, http.StatusCreated)
+	output.grey = s.createVariant(output.parent.Id, dto.ProductVariantRequest{Sku: "WM-9-GREY", Price: 500, AvailableStock: 2}, http.StatusCreated)
+
+	return output
+}
+
+func (s *TestSuite) TestVariantCreate() {
+	s.CleanTable()
+	washer := s.washer()
+
+	s.Require().Equal(washer.parent.Id, *washer.white.ParentId)
+	s.Require().Equal("WM-8-WHITE", washer.white.Sku)
+	s.Require().Equal(washer.parent.Category.Id, washer.white.Category.Id)
+
+	// a variant without a name gets the name of the parent
+	parent := s.getProduct(washer.parent.Id)
+	s.Require().Len(parent.Variants, 2)
+	s.Require().Equal("Washer", parent.Variants[0].Name)
+	s.Require().Equal(int64(5), parent.AvailableStock)
+}
+
+func (s *TestSuite) TestVariantInvalid() {
+	s.CleanTable()
+	washer := s.washer()
+
+	// the SKU is taken in another case
+	s.createVariant(washer.parent.Id, dto.ProductVariantRequest{Sku: "WM-8-WHITE", Price: 450}, http.StatusConflict)
+	// the check digit is wrong
+	s.createVariant(washer.parent.Id, dto.ProductVariantRequest{Sku: "WM-7", Barcode: "4006381333932", Price: 450}, http.StatusBadRequest)
+
+	s.Require().Len(s.getProduct(washer.parent.Id).Variants, 2)
+}
+
+func (s *TestSuite) TestVariantStock() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	washer := s.washer()
+
+	// stock of a product with variants is kept by the variants
+	resp, err := sendJSON(http.MethodPost, fmt.Sprintf("%s/products/%s/stock/increase", baseUrl, washer.parent.Id), dto.ProductStockRequest{Quantity: 1, Reason: "restock"})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal("/problems/variant-stock", problem.Type)
+
+	resp, err = sendJSON(http.MethodPost, fmt.Sprintf("%s/products/%s/stock/decrease", baseUrl, washer.white.Id), dto.ProductStockRequest{Quantity: 2, Reason: "sale"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	s.Require().Equal(int64(1), s.getProduct(washer.white.Id).AvailableStock)
+	s.Require().Equal(int64(3), s.getProduct(washer.parent.Id).AvailableStock)
+}
+
+func (s *TestSuite) TestVariantLookup() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	washer := s.washer()
+
+	lookups := []struct {
+		url    string
+		status int
+	}{
+		{baseUrl + "/products/by-sku/wm-8-white", http.StatusOK},
+		{baseUrl + "/products/by-barcode/4006381333931", http.StatusOK},
+		{baseUrl + "/products/by-sku/WM-404", http.StatusNotFound},
+		{baseUrl + "/products/by-barcode/4006381333932", http.StatusBadRequest},
+	}
+
+	for _, tc := range lookups {
+		resp, err := http.Get(tc.url)
+		s.Require().NoError(err)
+		s.Require().Equal(tc.status, resp.StatusCode, tc.url)
+
+		if tc.status != http.StatusOK {
+			resp.Body.Close()
+			continue
+		}
+
+		var found dto.ProductResponse
+		s.Require().NoError(decodeJSON(resp, &found))
+		s.Require().Equal(washer.white.Id, found.Id, tc.url)
+		s.Require().Equal(int64(3), found.AvailableStock, tc.url)
+	}
+}
+
+func (s *TestSuite) TestVariantListedWithParent() {
+	s.CleanTable()
+	washer := s.washer()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s:%s/api/v1/suppliers/%s/products", s.cfg.CrudService.Address, s.cfg.CrudService.Port, washer.supplier))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal("1", resp.Header.Get("X-Total-Count"))
+}
+
+func (s *TestSuite) TestVariantDelete() {
+	s.CleanTable()
+	washer := s.washer()
+
+	resp, err := sendJSON(http.MethodDelete, fmt.Sprintf("http://%s:%s/api/v1/products/%s",
+		s.cfg.CrudService.Address, s.cfg.CrudService.Port, washer.white.Id), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	parent := s.getProduct(washer.parent.Id)
+	s.Require().Len(parent.Variants, 1)
+	s.Require().Equal(washer.grey.Id, parent.Variants[0].Id)
+	s.Require().Equal(int64(2), parent.AvailableStock)
+}
@@ -0,0 +1,221 @@
+package integration
+
+import (
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/domain"
+	"CRUD-HOME-APPLIANCE-STORE/internal/model/dto"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// depots are the North, South and Spare warehouses and 10 kettles which are
+// not placed in any of them.
+type depots struct {
+	productUrl string
+	north      uuid.UUID
+	south      uuid.UUID
+	spare      uuid.UUID
+}
+
+func (s *TestSuite) depots() depots {
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+
+	products := s.createProducts(s.createSupplier(),
+		dto.ProductRequest{Name: "Kettle", CategoryId: s.category("Kitchen"), Price: 50, AvailableStock: 10},
+	)
+
+	warehouses := make(map[string]uuid.UUID)
+	for name, city := range map[string]string{"North": "Sapporo", "South": "Osaka", "Spare": "Nagoya"} {
+		var created dto.WarehouseResponse
+		s.create("/warehouses", dto.WarehouseRequest{
+			Name:    name,
+			Address: &dto.Address{Country: "JP", City: city, Street: "Depot"},
+		}, &created)
+		warehouses[name] = created.Id
+	}
+
+	return depots{
+		productUrl: fmt.Sprintf("%s/products/%s", baseUrl, products["Kettle"]),
+		north:      warehouses["North"],
+		south:      warehouses["South"],
+		spare:      warehouses["Spare"],
+	}
+}
+
+// stockedDepots places 15 kettles in North and 3 in South.
+func (s *TestSuite) stockedDepots() depots {
+	depots := s.depots()
+
+	s.warehouseStock(depots.productUrl, "increase", dto.ProductStockRequest{Quantity: 5, Reason: "restock", WarehouseId: &depots.north}, http.StatusOK)
+	s.warehouseStock(depots.productUrl, "increase", dto.ProductStockRequest{Quantity: 3, Reason: "restock", WarehouseId: &depots.south}, http.StatusOK)
+
+	return depots
+}
+
+// warehouseStock sends the stock request and checks the response status, the
+// movement is decoded for a successful one.
+func (s *TestSuite) warehouseStock(productUrl, action string, request any, status int) dto.StockMovementResponse {
+	resp, err := sendJSON(http.MethodPost, productUrl+"/stock/"+action, request)
+	s.Require().NoError(err)
+	s.Require().Equal(status, resp.StatusCode, action)
+
+	var movement dto.StockMovementResponse
+	if status != http.StatusOK {
+		resp.Body.Close()
+		return movement
+	}
+
+	s.Require().NoError(decodeJSON(resp, &movement))
+	return movement
+}
+
+// stockByWarehouse returns the quantities of the product by warehouse names.
+func (s *TestSuite) stockByWarehouse(productUrl string) map[string]int64 {
+	resp, err := http.Get(productUrl + "/stock/warehouses")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var stock []dto.WarehouseStockResponse
+	s.Require().NoError(decodeJSON(resp, &stock))
+
+	quantities := make(map[string]int64)
+	for _, item := range stock {
+		quantities[item.WarehouseName] = item.Quantity
+	}
+
+	return quantities
+}
+
+func (s *TestSuite) TestWarehouseCreateInvalid() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	s.depots()
+
+	// the address is required
+	resp, err := sendJSON(http.MethodPost, baseUrl+"/warehouses", dto.WarehouseRequest{Name: "East"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodPost, baseUrl+"/warehouses", dto.WarehouseRequest{
+		Name:    "North",
+		Address: &dto.Address{Country: "JP", City: "Sapporo", Street: "Depot"},
+	})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *TestSuite) TestWarehousePlacement() {
+	s.CleanTable()
+	depots := s.depots()
+
+	// the first change with a warehouse places the stock of the product in it
+	movement := s.warehouseStock(depots.productUrl, "increase", dto.ProductStockRequest{Quantity: 5, Reason: "restock", WarehouseId: &depots.north}, http.StatusOK)
+	s.Require().Equal(int64(10), movement.StockBefore)
+	s.Require().Equal(int64(15), movement.StockAfter)
+	s.Require().Equal(map[string]int64{"North": 15}, s.stockByWarehouse(depots.productUrl))
+}
+
+func (s *TestSuite) TestWarehouseRequired() {
+	s.CleanTable()
+	depots := s.stockedDepots()
+
+	resp, err := sendJSON(http.MethodPost, depots.productUrl+"/stock/increase", dto.ProductStockRequest{Quantity: 3, Reason: "restock"})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Equal("/problems/warehouse-required", problem.Type)
+}
+
+func (s *TestSuite) TestWarehouseDecrease() {
+	s.CleanTable()
+	depots := s.stockedDepots()
+
+	s.warehouseStock(depots.productUrl, "decrease", dto.ProductStockRequest{Quantity: 4, Reason: "sale", Strategy: "cheapest"}, http.StatusBadRequest)
+
+	// North holds the most stock
+	movement := s.warehouseStock(depots.productUrl, "decrease", dto.ProductStockRequest{Quantity: 4, Reason: "sale"}, http.StatusOK)
+	s.Require().Equal(&depots.north, movement.WarehouseId)
+	s.Require().Equal(int64(14), movement.StockAfter)
+	s.Require().Equal(map[string]int64{"North": 11, "South": 3}, s.stockByWarehouse(depots.productUrl))
+}
+
+func (s *TestSuite) TestWarehouseDecreaseSplitStock() {
+	s.CleanTable()
+	depots := s.stockedDepots()
+
+	// 18 kettles are in stock, no warehouse holds 16 of them
+	s.warehouseStock(depots.productUrl, "decrease", dto.ProductStockRequest{Quantity: 16, Reason: "sale"}, http.StatusConflict)
+	s.Require().Equal(map[string]int64{"North": 15, "South": 3}, s.stockByWarehouse(depots.productUrl))
+}
+
+func (s *TestSuite) TestWarehouseTransfer() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	depots := s.stockedDepots()
+
+	movement := s.warehouseStock(depots.productUrl, "transfer", dto.ProductStockTransferRequest{FromWarehouseId: depots.north, ToWarehouseId: depots.south, Quantity: 6}, http.StatusOK)
+	s.Require().Equal(string(domain.StockTransfer), movement.Operation)
+	s.Require().Equal(&depots.south, movement.ToWarehouseId)
+	s.Require().Equal(movement.StockBefore, movement.StockAfter)
+	s.Require().Equal(map[string]int64{"North": 9, "South": 9}, s.stockByWarehouse(depots.productUrl))
+
+	resp, err := http.Get(fmt.Sprintf("%s/warehouses/%s/stock", baseUrl, depots.south))
+	s.Require().NoError(err)
+
+	var stock []dto.WarehouseStockResponse
+	s.Require().NoError(decodeJSON(resp, &stock))
+	s.Require().Len(stock, 1)
+	s.Require().Equal("Kettle", stock[0].ProductName)
+	s.Require().Equal(int64(9), stock[0].Quantity)
+
+	resp, err = http.Get(depots.productUrl)
+	s.Require().NoError(err)
+
+	var product dto.ProductResponse
+	s.Require().NoError(decodeJSON(resp, &product))
+	s.Require().Equal(int64(18), product.AvailableStock)
+}
+
+func (s *TestSuite) TestWarehouseTransferInvalid() {
+	s.CleanTable()
+	depots := s.stockedDepots()
+
+	resp, err := sendJSON(http.MethodPost, depots.productUrl+"/stock/transfer", dto.ProductStockTransferRequest{FromWarehouseId: depots.north, ToWarehouseId: depots.north, Quantity: 1})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var problem domain.Error
+	s.Require().NoError(decodeJSON(resp, &problem))
+	s.Require().Len(problem.Errors, 1)
+	s.Require().Equal("to_warehouse_id", problem.Errors[0].Field)
+
+	s.warehouseStock(depots.productUrl, "transfer", dto.ProductStockTransferRequest{FromWarehouseId: depots.north, ToWarehouseId: depots.south, Quantity: 100}, http.StatusConflict)
+	s.Require().Equal(map[string]int64{"North": 15, "South": 3}, s.stockByWarehouse(depots.productUrl))
+}
+
+func (s *TestSuite) TestWarehouseDelete() {
+	s.CleanTable()
+	baseUrl := fmt.Sprintf("http://%s:%s/api/v1", s.cfg.CrudService.Address, s.cfg.CrudService.Port)
+	depots := s.stockedDepots()
+
+	// South holds stock
+	resp, err := sendJSON(http.MethodDelete, fmt.Sprintf("%s/warehouses/%s", baseUrl, depots.south), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusConflict, resp.StatusCode)
+
+	resp, err = sendJSON(http.MethodDelete, fmt.Sprintf("%s/warehouses/%s", baseUrl, depots.spare), nil)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(fmt.Sprintf("%s/warehouses/%s", baseUrl, depots.spare))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusNotFound, resp.StatusCode)
+}